@@ -20,10 +20,13 @@ type bootstrapFunc func(bindFn bootstrap.BindFunc, opts ...bootstrap.Option) err
 // New creates an new CLI instance.
 func New(version, release string, bootstrapFn bootstrapFunc) (*cobra.Command, error) {
 	var (
-		argConfigDir string
-		argLogFormat string
-		argLogLevel  string
-		rootCmd      = &cobra.Command{
+		argConfigDir            string
+		argLogFormat            string
+		argLogLevel             string
+		argPrintConfigSchema    bool
+		argPrintEffectiveConfig bool
+		argPrintConfigEnv       bool
+		rootCmd                 = &cobra.Command{
 			Use:   AppName,
 			Short: appShortDesc,
 			Long:  appLongDesc,
@@ -34,10 +37,29 @@ func New(version, release string, bootstrapFn bootstrapFunc) (*cobra.Command, er
 	rootCmd.Flags().StringVarP(&argLogFormat, "logFormat", "f", "", "Logging format: CONSOLE, JSON")
 	rootCmd.Flags().StringVarP(&argLogLevel, "logLevel", "o", "", "Log level: EMERGENCY, ALERT, CRITICAL, ERROR, WARNING, NOTICE, INFO, DEBUG")
 
-	rootCmd.RunE = func(_ *cobra.Command, _ []string) error {
+	rootCmd.Flags().BoolVar(&argPrintConfigSchema, "print-config-schema", false, "Print the JSON Schema of the configuration and exit")
+	rootCmd.Flags().BoolVar(&argPrintEffectiveConfig, "print-effective-config", false, "Print the loaded configuration, with redacted secrets, and exit")
+	rootCmd.Flags().BoolVar(&argPrintConfigEnv, "print-config-env", false, "Print the environment variables overriding the configuration keys and exit")
+
+	rootCmd.RunE = func(cmd *cobra.Command, _ []string) error {
 		// Read CLI configuration
 		cfg := &appConfig{}
 
+		switch {
+		case argPrintConfigSchema:
+			return printOutput(cmd, func() ([]byte, error) { return config.JSONSchema(cfg) })
+		case argPrintEffectiveConfig:
+			return printOutput(cmd, func() ([]byte, error) {
+				return config.EffectiveConfig(AppName, argConfigDir, appEnvPrefix, cfg)
+			})
+		case argPrintConfigEnv:
+			for _, ev := range config.EnvVars(appEnvPrefix, cfg) {
+				fmt.Fprintln(cmd.OutOrStdout(), ev.Name+"\t"+ev.Key)
+			}
+
+			return nil
+		}
+
 		err := config.Load(AppName, argConfigDir, appEnvPrefix, cfg)
 		if err != nil {
 			return fmt.Errorf("failed loading config: %w", err)
@@ -101,3 +123,15 @@ func New(version, release string, bootstrapFn bootstrapFunc) (*cobra.Command, er
 
 	return rootCmd, nil
 }
+
+// printOutput prints the data returned by the function to the command output.
+func printOutput(cmd *cobra.Command, fn func() ([]byte, error)) error {
+	data, err := fn()
+	if err != nil {
+		return fmt.Errorf("failed printing config: %w", err)
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), string(data))
+
+	return nil
+}
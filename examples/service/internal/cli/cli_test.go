@@ -77,6 +77,29 @@ func TestNew(t *testing.T) {
 			osArgs:  []string{AppName, "-c", "../../resources/test/etc/invalid/"},
 			wantErr: true,
 		},
+		{
+			name:       "print config schema",
+			osArgs:     []string{AppName, "--print-config-schema"},
+			wantErr:    false,
+			wantOutput: matchOutput(`"$schema"`),
+		},
+		{
+			name:       "print effective config",
+			osArgs:     []string{AppName, "-c", "../../resources/test/etc/gosrvlibexample/", "--print-effective-config"},
+			wantErr:    false,
+			wantOutput: matchOutput(`"shutdown_timeout"`),
+		},
+		{
+			name:    "fails print effective config with invalid configuration",
+			osArgs:  []string{AppName, "-c", "../../resources/test/etc/invalid/", "--print-effective-config"},
+			wantErr: true,
+		},
+		{
+			name:       "print config environment variables",
+			osArgs:     []string{AppName, "--print-config-env"},
+			wantErr:    false,
+			wantOutput: matchOutput("_LOG.LEVEL\tlog.level"),
+		},
 		{
 			name:   "bootstrap with valid configuration",
 			osArgs: []string{AppName, "-c", "../../resources/test/etc/gosrvlibexample/"},
@@ -143,3 +166,15 @@ func matchTestVersion(t *testing.T, out string) {
 
 	t.Errorf("A version number was expected")
 }
+
+func matchOutput(want string) func(t *testing.T, out string) {
+	return func(t *testing.T, out string) {
+		t.Helper()
+
+		if strings.Contains(out, want) {
+			return
+		}
+
+		t.Errorf("The output was expected to contain %q", want)
+	}
+}
@@ -1,6 +1,12 @@
 package cli
 
 import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"slices"
 	"testing"
 
 	"github.com/Vonage/gosrvlib/pkg/config"
@@ -185,3 +191,123 @@ func Test_appConfig_Validate(t *testing.T) {
 		})
 	}
 }
+
+// validateJSONSchema returns the errors of the value validated against the subset of
+// the JSON Schema keywords generated by config.JSONSchema.
+//
+//nolint:gocognit,cyclop
+func validateJSONSchema(schema map[string]any, value any, path string) []string {
+	var errs []string
+
+	if enum, ok := schema["enum"].([]any); ok && !slices.ContainsFunc(enum, func(e any) bool { return reflect.DeepEqual(e, value) }) {
+		errs = append(errs, fmt.Sprintf("%s: %v is not one of %v", path, value, enum))
+	}
+
+	var ok bool
+
+	switch schema["type"] {
+	case "object":
+		var obj map[string]any
+
+		if obj, ok = value.(map[string]any); !ok {
+			break
+		}
+
+		required, _ := schema["required"].([]any)
+
+		for _, name := range required {
+			if _, found := obj[name.(string)]; !found {
+				errs = append(errs, fmt.Sprintf("%s: missing required property %q", path, name))
+			}
+		}
+
+		properties, _ := schema["properties"].(map[string]any)
+
+		for k, v := range obj {
+			ps, found := properties[k].(map[string]any)
+			if !found {
+				ps, found = schema["additionalProperties"].(map[string]any)
+			}
+
+			if !found {
+				errs = append(errs, fmt.Sprintf("%s: unknown property %q", path, k))
+				continue
+			}
+
+			errs = append(errs, validateJSONSchema(ps, v, path+"."+k)...)
+		}
+	case "array":
+		var arr []any
+
+		if arr, ok = value.([]any); !ok {
+			break
+		}
+
+		items, _ := schema["items"].(map[string]any)
+
+		for i, v := range arr {
+			errs = append(errs, validateJSONSchema(items, v, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	case "string":
+		_, ok = value.(string)
+	case "boolean":
+		_, ok = value.(bool)
+	case "integer", "number":
+		var n float64
+
+		if n, ok = value.(float64); !ok || (schema["type"] == "integer" && n != math.Trunc(n)) {
+			ok = false
+			break
+		}
+
+		if limit, found := schema["minimum"].(float64); found && n < limit {
+			errs = append(errs, fmt.Sprintf("%s: %v is less than %v", path, n, limit))
+		}
+
+		if limit, found := schema["maximum"].(float64); found && n > limit {
+			errs = append(errs, fmt.Sprintf("%s: %v is greater than %v", path, n, limit))
+		}
+	default:
+		ok = true
+	}
+
+	if !ok {
+		errs = append(errs, fmt.Sprintf("%s: %v is not of type %v", path, value, schema["type"]))
+	}
+
+	return errs
+}
+
+func Test_appConfig_JSONSchema(t *testing.T) {
+	t.Parallel()
+
+	data, err := config.JSONSchema(&appConfig{})
+	require.NoError(t, err)
+
+	var schema map[string]any
+
+	require.NoError(t, json.Unmarshal(data, &schema))
+
+	for _, file := range []string{
+		"../../resources/etc/gosrvlibexample/config.json",
+		"../../resources/test/etc/gosrvlibexample/config.json",
+	} {
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+
+		var cfg map[string]any
+
+		require.NoError(t, json.Unmarshal(data, &cfg))
+		require.Empty(t, validateJSONSchema(schema, cfg, "$"), file)
+	}
+
+	invalid := map[string]any{
+		"log":      map[string]any{"level": "WRONG", "format": "JSON"},
+		"servers":  map[string]any{"public": map[string]any{"timeout": 1.5}},
+		"unknown":  true,
+		"clients":  map[string]any{},
+		"shutdown": "x",
+	}
+
+	require.Len(t, validateJSONSchema(schema, invalid, "$"), 4)
+}
@@ -68,7 +68,42 @@ highest):
  5. Any specified command-line argument overwrites the corresponding
     configuration parameter.
 
- 6. The configuration parameters are validated via the Validate() function.
+ 6. The secret placeholders are replaced with the secret values (see below).
+
+ 7. The configuration parameters are validated via the Validate() function.
+
+# Secrets:
+
+Any string configuration value can be a placeholder of a secret, resolved at
+load time, so the secrets are not stored in the configuration sources:
+
+  - "secret://aws/<name>#<jsonkey>" is replaced with the AWS Secrets Manager
+    secret "<name>", or with the "<jsonkey>" value of a JSON object secret.
+    The secrets are retrieved via the awssecretcache.Cache set with the
+    WithAWSSecrets option.
+
+  - "file:///run/secrets/x" is replaced with the content of the file, without
+    the trailing newline (e.g., Docker and Kubernetes secrets).
+    These placeholders are resolved only with the WithFileSecrets option.
+
+  - "env://VAR" is replaced with the value of the VAR environment variable.
+    These placeholders are resolved only with the WithEnvSecrets option.
+
+The local file and environment placeholders are opt-in, as they give access to
+any readable file or environment variable to whoever controls the configuration
+sources (e.g., the remote configuration provider).
+
+The resolved values are never included in the errors or logs. When the
+configuration is reloaded by the Watcher, the AWS secrets are retrieved again
+to pick up the rotated values.
+
+# Dynamic Reload:
+
+The opt-in Watcher (see NewWatcher) reloads the configuration when the local
+configuration file changes (fsnotify) or periodically (e.g., to poll the remote
+provider). Each new configuration is validated and atomically swapped with the
+current one, and the subscribers are notified with the typed old and new values
+to apply the live changes (e.g., log level, rate limits, feature flags).
 
 # Example:
 
@@ -219,15 +254,25 @@ type remoteSourceConfig struct {
 }
 
 // Load populates the configuration parameters.
-func Load(cmdName, configDir, envPrefix string, cfg Configuration) error {
+// The secret placeholders are resolved before the validation (see the "Secrets" section).
+// See also NewWatcher to reload the configuration when it changes.
+func Load(cmdName, configDir, envPrefix string, cfg Configuration, opts ...LoadOption) error {
+	_, err := load(cmdName, configDir, envPrefix, cfg, newLoadOptions(opts...))
+	return err
+}
+
+// load populates the configuration parameters and returns the path of the local configuration file used.
+func load(cmdName, configDir, envPrefix string, cfg Configuration, o *loadOptions) (string, error) {
 	localViper := viper.New()
 	remoteViper := viper.New()
 
-	return loadConfig(localViper, remoteViper, cmdName, configDir, envPrefix, cfg)
+	err := loadConfig(localViper, remoteViper, cmdName, configDir, envPrefix, cfg, o)
+
+	return localViper.ConfigFileUsed(), err
 }
 
 // loadConfig loads the configuration.
-func loadConfig(localViper, remoteViper Viper, cmdName, configDir, envPrefix string, cfg Configuration) error {
+func loadConfig(localViper, remoteViper Viper, cmdName, configDir, envPrefix string, cfg Configuration, o *loadOptions) error {
 	remoteSourceCfg, err := loadLocalConfig(localViper, cmdName, configDir, envPrefix, cfg)
 	if err != nil {
 		return fmt.Errorf("failed loading local configuration: %w", err)
@@ -238,6 +283,11 @@ func loadConfig(localViper, remoteViper Viper, cmdName, configDir, envPrefix str
 		return fmt.Errorf("failed loading remote configuration: %w", err)
 	}
 
+	err = resolveSecrets(o, cfg)
+	if err != nil {
+		return fmt.Errorf("failed resolving configuration secrets: %w", err)
+	}
+
 	err = cfg.Validate()
 	if err != nil {
 		return fmt.Errorf("failed validating configuration: %w", err)
@@ -248,17 +298,7 @@ func loadConfig(localViper, remoteViper Viper, cmdName, configDir, envPrefix str
 
 // loadLocalConfig returns the local configuration parameters.
 func loadLocalConfig(v Viper, cmdName, configDir, envPrefix string, cfg Configuration) (*remoteSourceConfig, error) {
-	// set default remote configuration values
-	v.SetDefault(keyRemoteConfigProvider, defaultRemoteConfigProvider)
-	v.SetDefault(keyRemoteConfigEndpoint, defaultRemoteConfigEndpoint)
-	v.SetDefault(keyRemoteConfigPath, defaultRemoteConfigPath)
-	v.SetDefault(keyRemoteConfigSecretKeyring, defaultRemoteConfigSecretKeyring)
-
-	// set default logging configuration values
-	v.SetDefault(keyLogFormat, defaultLogFormat)
-	v.SetDefault(keyLogLevel, defaultLogLevel)
-	v.SetDefault(keyLogAddress, defaultLogAddress)
-	v.SetDefault(keyLogNetwork, defaultLogNetwork)
+	setDefaults(v, cfg)
 
 	// set default config name and type
 	v.SetConfigName(defaultConfigName)
@@ -267,25 +307,11 @@ func loadLocalConfig(v Viper, cmdName, configDir, envPrefix string, cfg Configur
 	// add default search paths
 	configureSearchPath(v, cmdName, configDir)
 
-	// set application defaults
-	v.SetDefault(keyShutdownTimeout, defaultShutdownTimeout)
-
-	// set defaults from application configuration
-	cfg.SetDefaults(v)
-
 	// support environment variables for the remote configuration
 	v.AutomaticEnv()
-	v.SetEnvPrefix(strings.ReplaceAll(envPrefix, "-", "_")) // will be uppercased automatically
+	v.SetEnvPrefix(envVarPrefix(envPrefix))
 
-	envVar := []string{
-		keyRemoteConfigProvider,
-		keyRemoteConfigEndpoint,
-		keyRemoteConfigPath,
-		keyRemoteConfigSecretKeyring,
-		keyRemoteConfigData,
-	}
-
-	for _, ev := range envVar {
+	for _, ev := range remoteConfigKeys() {
 		_ = v.BindEnv(ev) // we ignore the error because we are always passing an argument value
 	}
 
@@ -305,6 +331,43 @@ func loadLocalConfig(v Viper, cmdName, configDir, envPrefix string, cfg Configur
 	return &rsCfg, nil
 }
 
+// setDefaults sets the default configuration values.
+func setDefaults(v Viper, cfg Configuration) {
+	// set default remote configuration values
+	v.SetDefault(keyRemoteConfigProvider, defaultRemoteConfigProvider)
+	v.SetDefault(keyRemoteConfigEndpoint, defaultRemoteConfigEndpoint)
+	v.SetDefault(keyRemoteConfigPath, defaultRemoteConfigPath)
+	v.SetDefault(keyRemoteConfigSecretKeyring, defaultRemoteConfigSecretKeyring)
+
+	// set default logging configuration values
+	v.SetDefault(keyLogFormat, defaultLogFormat)
+	v.SetDefault(keyLogLevel, defaultLogLevel)
+	v.SetDefault(keyLogAddress, defaultLogAddress)
+	v.SetDefault(keyLogNetwork, defaultLogNetwork)
+
+	// set application defaults
+	v.SetDefault(keyShutdownTimeout, defaultShutdownTimeout)
+
+	// set defaults from application configuration
+	cfg.SetDefaults(v)
+}
+
+// remoteConfigKeys returns the keys of the remote configuration parameters, set via environment variables.
+func remoteConfigKeys() []string {
+	return []string{
+		keyRemoteConfigProvider,
+		keyRemoteConfigEndpoint,
+		keyRemoteConfigPath,
+		keyRemoteConfigSecretKeyring,
+		keyRemoteConfigData,
+	}
+}
+
+// envVarPrefix returns the prefix of the environment variables.
+func envVarPrefix(envPrefix string) string {
+	return strings.ReplaceAll(envPrefix, "-", "_") // will be uppercased automatically
+}
+
 // loadRemoteConfig returns the remote configuration parameters.
 func loadRemoteConfig(lv Viper, rv Viper, rs *remoteSourceConfig, envPrefix string, cfg Configuration) error {
 	for _, k := range lv.AllKeys() {
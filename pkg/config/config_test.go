@@ -1058,7 +1058,7 @@ func Test_loadConfig(t *testing.T) {
 				t.Setenv(envKey, base64.StdEncoding.EncodeToString(tt.envDataContent))
 			}
 
-			err = loadConfig(localViper, remoteViper, "cmd", tmpConfigDir, "test", tt.targetConfig)
+			err = loadConfig(localViper, remoteViper, "cmd", tmpConfigDir, "test", tt.targetConfig, newLoadOptions())
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
 			}
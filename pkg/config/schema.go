@@ -0,0 +1,400 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Vonage/gosrvlib/pkg/redact"
+	"github.com/spf13/viper"
+)
+
+// jsonSchemaVersion is the JSON Schema draft used by JSONSchema.
+const jsonSchemaVersion = "http://json-schema.org/draft-07/schema#"
+
+// Struct tag names.
+const (
+	tagMapstructure = "mapstructure"
+	tagValidate     = "validate"
+)
+
+// EnvVar maps a configuration key to the environment variable that overrides it.
+type EnvVar struct {
+	// Key is the dot separated configuration key (e.g. "log.level").
+	Key string `json:"key"`
+
+	// Name is the environment variable name (e.g. "MYPROG_LOG.LEVEL").
+	Name string `json:"name"`
+}
+
+// JSONSchema returns the JSON Schema (draft-07) of the configuration structure, as indented JSON.
+//
+// The property names are taken from the "mapstructure" tags and the default values from SetDefaults.
+// The remote configuration parameters (e.g. "remoteConfigProvider") are included in the root properties.
+// The "required" (for the fields without a default value), "oneof", "min" and "max" rules of the "validate" tags
+// are converted to the equivalent schema keywords, while the other validation rules are only checked by Validate.
+// The "oneof" values of the "omitempty" fields include the empty value.
+func JSONSchema(cfg Configuration) ([]byte, error) {
+	v := viper.New()
+	setDefaults(v, cfg)
+
+	schema := typeSchema(reflect.TypeOf(cfg), "", v)
+	schema["$schema"] = jsonSchemaVersion
+
+	// the remote configuration parameters can also be set in the local configuration file
+	if properties, ok := schema["properties"].(map[string]any); ok {
+		remote := typeSchema(reflect.TypeFor[remoteSourceConfig](), "", v)
+
+		if rp, ok := remote["properties"].(map[string]any); ok {
+			maps.Copy(properties, rp)
+		}
+	}
+
+	return json.MarshalIndent(schema, "", "  ") //nolint:wrapcheck
+}
+
+// EffectiveConfig loads the configuration like Load and returns the resulting values as indented JSON,
+// with the values resolved from the secret placeholders and the keys containing "key", "password" or "secret" redacted.
+func EffectiveConfig(cmdName, configDir, envPrefix string, cfg Configuration, opts ...LoadOption) ([]byte, error) {
+	o := newLoadOptions(opts...)
+	o.resolved = make(map[string]bool)
+
+	if _, err := load(cmdName, configDir, envPrefix, cfg, o); err != nil {
+		return nil, err
+	}
+
+	data, err := json.MarshalIndent(valueMap(reflect.ValueOf(cfg), "", o.resolved), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed encoding the configuration: %w", err)
+	}
+
+	return []byte(redact.HTTPData(string(data))), nil
+}
+
+// EnvVars returns the environment variables that override the configuration keys, sorted by key,
+// including the remote configuration parameters.
+//
+// The environment variable names are the uppercase keys with the envPrefix and an underscore
+// (e.g. "MYPROG_LOG.LEVEL" for the "log.level" key). The nested keys keep the dot separator.
+// NOTE: The environment variables are only applied to the keys with a default value (see SetDefaults)
+// or defined in the configuration file. The map values can't be set via environment variables.
+func EnvVars(envPrefix string, cfg Configuration) []EnvVar {
+	keys := remoteConfigKeys()
+	keys = appendKeys(keys, reflect.TypeOf(cfg), "")
+
+	slices.Sort(keys)
+
+	prefix := strings.ToUpper(envVarPrefix(envPrefix))
+	if prefix != "" {
+		prefix += "_"
+	}
+
+	vars := make([]EnvVar, 0, len(keys))
+
+	for _, k := range slices.Compact(keys) {
+		vars = append(vars, EnvVar{Key: k, Name: prefix + strings.ToUpper(k)})
+	}
+
+	return vars
+}
+
+// fieldKey returns the configuration key of the struct field, and true if the field is squashed into the parent.
+func fieldKey(f reflect.StructField) (string, bool) {
+	name, opts, _ := strings.Cut(f.Tag.Get(tagMapstructure), ",")
+
+	if slices.Contains(strings.Split(opts, ","), "squash") {
+		return "", true
+	}
+
+	if name == "" {
+		name = f.Name
+	}
+
+	return name, false
+}
+
+// structFields calls fn for each exported and not ignored field of the struct type, including the squashed ones.
+func structFields(t reflect.Type, fn func(f reflect.StructField, index []int, key string)) {
+	for i := range t.NumField() {
+		f := t.Field(i)
+
+		if !f.IsExported() {
+			continue
+		}
+
+		key, squash := fieldKey(f)
+
+		switch {
+		case key == "-":
+			continue
+		case squash && derefType(f.Type).Kind() == reflect.Struct:
+			structFields(derefType(f.Type), func(sf reflect.StructField, index []int, key string) {
+				fn(sf, append([]int{i}, index...), key)
+			})
+		default:
+			fn(f, []int{i}, key)
+		}
+	}
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return t
+}
+
+// appendKeys appends the dot separated keys of the leaf fields of the type.
+func appendKeys(keys []string, t reflect.Type, prefix string) []string {
+	t = derefType(t)
+
+	if t.Kind() != reflect.Struct {
+		return keys
+	}
+
+	structFields(t, func(f reflect.StructField, _ []int, key string) {
+		key = joinPath(prefix, key)
+
+		switch derefType(f.Type).Kind() { //nolint:exhaustive
+		case reflect.Struct:
+			keys = appendKeys(keys, f.Type, key)
+		case reflect.Map:
+			// the maps can't be set via environment variables
+		default:
+			keys = append(keys, key)
+		}
+	})
+
+	return keys
+}
+
+// typeSchema returns the JSON Schema of the type.
+// The key is the dot separated configuration key used to read the default values.
+//
+//nolint:exhaustive
+func typeSchema(t reflect.Type, key string, defaults *viper.Viper) map[string]any {
+	t = derefType(t)
+	schema := map[string]any{}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		properties := map[string]any{}
+		required := []string{}
+
+		structFields(t, func(f reflect.StructField, _ []int, name string) {
+			fkey := joinPath(key, name)
+			fs := typeSchema(f.Type, fkey, defaults)
+
+			var d any
+
+			if fs["type"] != "object" {
+				d = defaults.Get(fkey)
+			}
+
+			// the fields with a default value and the structures without required fields
+			// are not required in the configuration sources
+			if applyValidateTag(fs, f.Tag.Get(tagValidate)) && d == nil && (fs["properties"] == nil || fs["required"] != nil) {
+				required = append(required, name)
+			}
+
+			if d != nil {
+				fs["default"] = d
+			}
+
+			properties[name] = fs
+		})
+
+		schema["type"] = "object"
+		schema["properties"] = properties
+		schema["additionalProperties"] = false
+
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+	case reflect.Map:
+		schema["type"] = "object"
+		schema["additionalProperties"] = typeSchema(t.Elem(), "", defaults)
+	case reflect.Slice, reflect.Array:
+		schema["type"] = "array"
+		schema["items"] = typeSchema(t.Elem(), "", defaults)
+	case reflect.String:
+		schema["type"] = "string"
+	case reflect.Bool:
+		schema["type"] = "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		schema["type"] = "integer"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		schema["type"] = "integer"
+		schema["minimum"] = 0
+	case reflect.Float32, reflect.Float64:
+		schema["type"] = "number"
+	}
+
+	return schema
+}
+
+// applyValidateTag adds the schema keywords equivalent to the validation rules, and returns true if required.
+// The rules after "dive" apply to the elements and are ignored.
+func applyValidateTag(schema map[string]any, tag string) bool {
+	var required, omitempty bool
+
+rules:
+	for rule := range strings.SplitSeq(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+
+		switch name {
+		case "dive":
+			break rules
+		case "required":
+			required = true
+		case "omitempty":
+			omitempty = true
+		case "oneof":
+			enum := []any{}
+			for v := range strings.FieldsSeq(param) {
+				enum = append(enum, schemaValue(schema, v))
+			}
+
+			schema["enum"] = enum
+		case "min", "max":
+			applyLimit(schema, name, param)
+		}
+	}
+
+	// the empty value skips the validation of the omitempty fields
+	if enum, ok := schema["enum"].([]any); ok && omitempty {
+		schema["enum"] = append([]any{zeroValue(schema)}, enum...)
+	}
+
+	return required
+}
+
+// applyLimit adds the schema keyword equivalent to the min or max validation rule.
+func applyLimit(schema map[string]any, name, param string) {
+	n, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+
+	var keyword string
+
+	switch schema["type"] {
+	case "integer", "number":
+		keyword = map[string]string{"min": "minimum", "max": "maximum"}[name]
+	case "string":
+		keyword = name + "Length"
+	case "array":
+		keyword = name + "Items"
+	case "object":
+		keyword = name + "Properties"
+	default:
+		return
+	}
+
+	schema[keyword] = n
+}
+
+// schemaValue converts the string value to the schema type.
+func schemaValue(schema map[string]any, s string) any {
+	switch schema["type"] {
+	case "integer", "number":
+		if n, err := strconv.ParseFloat(s, 64); err == nil {
+			return n
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b
+		}
+	}
+
+	return s
+}
+
+// zeroValue returns the empty value of the schema type.
+func zeroValue(schema map[string]any) any {
+	switch schema["type"] {
+	case "integer", "number":
+		return 0
+	case "boolean":
+		return false
+	default:
+		return ""
+	}
+}
+
+// valueMap converts the configuration value to generic maps and slices with the configuration keys,
+// replacing the string values at the redacted paths.
+// The paths have the same format of the secret resolver (e.g. "BaseConfig.Log.Level", "Servers[0]", "Labels.key").
+//
+//nolint:exhaustive
+func valueMap(v reflect.Value, path string, redacted map[string]bool) any {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+
+		return valueMap(v.Elem(), path, redacted)
+	case reflect.Struct:
+		m := map[string]any{}
+
+		structFields(v.Type(), func(_ reflect.StructField, index []int, key string) {
+			if f, err := v.FieldByIndexErr(index); err == nil {
+				m[key] = valueMap(f, fieldPath(v.Type(), index, path), redacted)
+			}
+		})
+
+		return m
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+
+		m := make(map[string]any, v.Len())
+
+		iter := v.MapRange()
+		for iter.Next() {
+			k := fmt.Sprint(iter.Key().Interface())
+			m[k] = valueMap(iter.Value(), joinPath(path, k), redacted)
+		}
+
+		return m
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+
+		s := make([]any, v.Len())
+		for i := range v.Len() {
+			s[i] = valueMap(v.Index(i), fmt.Sprintf("%s[%d]", path, i), redacted)
+		}
+
+		return s
+	case reflect.String:
+		if redacted[path] {
+			return redact.Redacted
+		}
+
+		return v.String()
+	case reflect.Invalid:
+		return nil
+	default:
+		return v.Interface()
+	}
+}
+
+// fieldPath returns the path of the struct field with the specified index, including the squashed parent fields.
+func fieldPath(t reflect.Type, index []int, path string) string {
+	for _, i := range index {
+		f := t.Field(i)
+		path = joinPath(path, f.Name)
+		t = derefType(f.Type)
+	}
+
+	return path
+}
@@ -0,0 +1,236 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Vonage/gosrvlib/pkg/redact"
+	"github.com/stretchr/testify/require"
+)
+
+type testSchemaServer struct {
+	Address string `mapstructure:"address" validate:"required,hostname_port"`
+	Timeout int    `mapstructure:"timeout" validate:"required,min=1,max=60"`
+}
+
+type testSchemaConfig struct {
+	BaseConfig `mapstructure:",squash" validate:"required"`
+
+	Enabled  bool                         `mapstructure:"enabled"`
+	Server   testSchemaServer             `mapstructure:"server"   validate:"required"`
+	Ptr      *testSchemaServer            `mapstructure:"ptr"`
+	Mode     string                       `mapstructure:"mode"     validate:"required,oneof=fast slow"`
+	Ratio    float64                      `mapstructure:"ratio"    validate:"omitempty,min=0.5"`
+	Level    uint8                        `mapstructure:"level"    validate:"oneof=1 2 3"`
+	Flag     bool                         `mapstructure:"flag"     validate:"oneof=true"`
+	Retries  int                          `mapstructure:"retries"  validate:"omitempty,oneof=3 5"`
+	Strict   bool                         `mapstructure:"strict"   validate:"omitempty,oneof=true"`
+	Tags     []string                     `mapstructure:"tags"     validate:"min=1,dive,min=2"`
+	Labels   map[string]string            `mapstructure:"labels"   validate:"max=3"`
+	Name     string                       `mapstructure:"name"     validate:"min=3"`
+	Password string                       `mapstructure:"password"`
+	Token    string                       `mapstructure:"token"`
+	Any      any                          `mapstructure:"any"`
+	Servers  map[string]*testSchemaServer `mapstructure:"servers"`
+	Ignored  string                       `mapstructure:"-"`
+	NoTag    string
+	Limit    int `validate:"min=x"`
+	private  string
+}
+
+func (c *testSchemaConfig) SetDefaults(v Viper) {
+	v.SetDefault("server.timeout", 10)
+	v.SetDefault("mode", "fast")
+	v.SetDefault("tags", []string{"aa"})
+}
+
+func (c *testSchemaConfig) Validate() error {
+	return nil
+}
+
+func TestJSONSchema(t *testing.T) {
+	t.Parallel()
+
+	data, err := JSONSchema(&testSchemaConfig{})
+	require.NoError(t, err)
+
+	var got map[string]any
+
+	require.NoError(t, json.Unmarshal(data, &got))
+
+	want := map[string]any{}
+
+	require.NoError(t, json.Unmarshal([]byte(`{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "additionalProperties": false,
+  "properties": {
+    "log": {
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "level": {"type": "string", "default": "DEBUG", "enum": ["EMERGENCY", "ALERT", "CRITICAL", "ERROR", "WARNING", "NOTICE", "INFO", "DEBUG"]},
+        "format": {"type": "string", "default": "JSON", "enum": ["CONSOLE", "JSON"]},
+        "network": {"type": "string", "default": "", "enum": ["", "udp", "tcp"]},
+        "address": {"type": "string", "default": ""}
+      }
+    },
+    "shutdown_timeout": {"type": "integer", "default": 30, "minimum": 1, "maximum": 3600},
+    "remoteConfigProvider": {"type": "string", "default": "", "enum": ["", "consul", "envvar", "etcd", "etcd3", "firestore", "nats"]},
+    "remoteConfigEndpoint": {"type": "string", "default": ""},
+    "remoteConfigPath": {"type": "string", "default": ""},
+    "remoteConfigSecretKeyring": {"type": "string", "default": ""},
+    "remoteConfigData": {"type": "string"},
+    "enabled": {"type": "boolean"},
+    "server": {
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "address": {"type": "string"},
+        "timeout": {"type": "integer", "default": 10, "minimum": 1, "maximum": 60}
+      },
+      "required": ["address"]
+    },
+    "ptr": {
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "address": {"type": "string"},
+        "timeout": {"type": "integer", "minimum": 1, "maximum": 60}
+      },
+      "required": ["address", "timeout"]
+    },
+    "mode": {"type": "string", "default": "fast", "enum": ["fast", "slow"]},
+    "ratio": {"type": "number", "minimum": 0.5},
+    "level": {"type": "integer", "minimum": 0, "enum": [1, 2, 3]},
+    "flag": {"type": "boolean", "enum": [true]},
+    "retries": {"type": "integer", "enum": [0, 3, 5]},
+    "strict": {"type": "boolean", "enum": [false, true]},
+    "tags": {"type": "array", "items": {"type": "string"}, "default": ["aa"], "minItems": 1},
+    "labels": {"type": "object", "additionalProperties": {"type": "string"}, "maxProperties": 3},
+    "name": {"type": "string", "minLength": 3},
+    "password": {"type": "string"},
+    "token": {"type": "string"},
+    "any": {},
+    "servers": {
+      "type": "object",
+      "additionalProperties": {
+        "type": "object",
+        "additionalProperties": false,
+        "properties": {
+          "address": {"type": "string"},
+          "timeout": {"type": "integer", "minimum": 1, "maximum": 60}
+        },
+        "required": ["address", "timeout"]
+      }
+    },
+    "NoTag": {"type": "string"},
+    "Limit": {"type": "integer"}
+  },
+  "required": ["server"]
+}`), &want))
+
+	require.Equal(t, want, got)
+}
+
+func TestEnvVars(t *testing.T) {
+	t.Parallel()
+
+	got := EnvVars("my-prog", &testSchemaConfig{})
+
+	want := []EnvVar{
+		{Key: "Limit", Name: "MY_PROG_LIMIT"},
+		{Key: "NoTag", Name: "MY_PROG_NOTAG"},
+		{Key: "any", Name: "MY_PROG_ANY"},
+		{Key: "enabled", Name: "MY_PROG_ENABLED"},
+		{Key: "flag", Name: "MY_PROG_FLAG"},
+		{Key: "level", Name: "MY_PROG_LEVEL"},
+		{Key: "log.address", Name: "MY_PROG_LOG.ADDRESS"},
+		{Key: "log.format", Name: "MY_PROG_LOG.FORMAT"},
+		{Key: "log.level", Name: "MY_PROG_LOG.LEVEL"},
+		{Key: "log.network", Name: "MY_PROG_LOG.NETWORK"},
+		{Key: "mode", Name: "MY_PROG_MODE"},
+		{Key: "name", Name: "MY_PROG_NAME"},
+		{Key: "password", Name: "MY_PROG_PASSWORD"},
+		{Key: "ptr.address", Name: "MY_PROG_PTR.ADDRESS"},
+		{Key: "ptr.timeout", Name: "MY_PROG_PTR.TIMEOUT"},
+		{Key: "ratio", Name: "MY_PROG_RATIO"},
+		{Key: "remoteConfigData", Name: "MY_PROG_REMOTECONFIGDATA"},
+		{Key: "remoteConfigEndpoint", Name: "MY_PROG_REMOTECONFIGENDPOINT"},
+		{Key: "remoteConfigPath", Name: "MY_PROG_REMOTECONFIGPATH"},
+		{Key: "remoteConfigProvider", Name: "MY_PROG_REMOTECONFIGPROVIDER"},
+		{Key: "remoteConfigSecretKeyring", Name: "MY_PROG_REMOTECONFIGSECRETKEYRING"},
+		{Key: "retries", Name: "MY_PROG_RETRIES"},
+		{Key: "server.address", Name: "MY_PROG_SERVER.ADDRESS"},
+		{Key: "server.timeout", Name: "MY_PROG_SERVER.TIMEOUT"},
+		{Key: "shutdown_timeout", Name: "MY_PROG_SHUTDOWN_TIMEOUT"},
+		{Key: "strict", Name: "MY_PROG_STRICT"},
+		{Key: "tags", Name: "MY_PROG_TAGS"},
+		{Key: "token", Name: "MY_PROG_TOKEN"},
+	}
+
+	require.Equal(t, want, got)
+
+	require.Equal(t, EnvVar{Key: "Limit", Name: "LIMIT"}, EnvVars("", &testSchemaConfig{})[0])
+}
+
+func TestEnvVars_load(t *testing.T) {
+	t.Setenv("TESTENVVARS_MODE", "slow")
+	t.Setenv("TESTENVVARS_SERVER.TIMEOUT", "20")
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"server":{"address":":80"}}`), 0o600))
+
+	cfg := &testSchemaConfig{}
+
+	err := Load("cmd", dir, "testenvvars", cfg)
+	require.NoError(t, err)
+	require.Equal(t, "slow", cfg.Mode)
+	require.Equal(t, 20, cfg.Server.Timeout)
+}
+
+func TestEffectiveConfig(t *testing.T) {
+	t.Setenv("TEST_EFFECTIVE_CONFIG_TOKEN", "t0k3n")
+
+	dir := t.TempDir()
+	data := []byte(`{
+  "server": {"address": ":80"},
+  "password": "p4ssw0rd",
+  "token": "env://TEST_EFFECTIVE_CONFIG_TOKEN",
+  "name": "t0k3n",
+  "labels": {"a": "b", "s": "env://TEST_EFFECTIVE_CONFIG_TOKEN"},
+  "servers": {"one": {"address": ":81", "timeout": 5}},
+  "any": [1, "x"]
+}`)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.json"), data, 0o600))
+
+	cfg := &testSchemaConfig{}
+
+	out, err := EffectiveConfig("cmd", dir, "testeffectiveconfig", cfg, WithEnvSecrets())
+	require.NoError(t, err)
+	require.Equal(t, "t0k3n", cfg.Token)
+	require.Equal(t, "t0k3n", cfg.Labels["s"])
+
+	require.NotContains(t, string(out), "p4ssw0rd")
+
+	var got map[string]any
+
+	require.NoError(t, json.Unmarshal(out, &got))
+	require.Equal(t, redact.Redacted, got["password"])
+	require.Equal(t, redact.Redacted, got["token"])
+	require.Equal(t, "t0k3n", got["name"], "only the values resolved from the secret placeholders are redacted")
+	require.Equal(t, "DEBUG", got["log"].(map[string]any)["level"])
+	require.Equal(t, ":80", got["server"].(map[string]any)["address"])
+	require.Equal(t, map[string]any{"a": "b", "s": redact.Redacted}, got["labels"])
+	require.Equal(t, ":81", got["servers"].(map[string]any)["one"].(map[string]any)["address"])
+	require.Equal(t, []any{float64(1), "x"}, got["any"])
+	require.Equal(t, []any{"aa"}, got["tags"])
+	require.Nil(t, got["ptr"])
+	require.NotContains(t, got, "Ignored")
+	require.NotContains(t, got, "private")
+
+	_, err = EffectiveConfig("cmd", t.TempDir(), "testeffectiveconfig", &testSchemaConfig{})
+	require.Error(t, err)
+}
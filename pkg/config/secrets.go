@@ -0,0 +1,297 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// Secret placeholder prefixes.
+const (
+	// SecretPrefixAWS references an AWS Secrets Manager secret: "secret://aws/<name>#<jsonkey>".
+	// The optional "#<jsonkey>" suffix selects a key of a JSON object secret.
+	SecretPrefixAWS = "secret://aws/"
+
+	// SecretPrefixFile references the content of a file, without the trailing newline: "file:///run/secrets/x".
+	// It is resolved only with the WithFileSecrets option.
+	SecretPrefixFile = "file://"
+
+	// SecretPrefixEnv references an environment variable: "env://VAR".
+	// It is resolved only with the WithEnvSecrets option.
+	SecretPrefixEnv = "env://"
+)
+
+// SecretsManager contains the methods of the github.com/Vonage/gosrvlib/pkg/awssecretcache Cache
+// used to resolve the AWS Secrets Manager placeholders.
+type SecretsManager interface {
+	GetSecretString(ctx context.Context, key string) (string, error)
+	Remove(key string)
+}
+
+// LoadOption is the interface that allows to set the Load options.
+type LoadOption func(o *loadOptions)
+
+type loadOptions struct {
+	ctx         context.Context //nolint:containedctx
+	awsSecrets  SecretsManager
+	fileSecrets bool
+	envSecrets  bool
+	refresh     bool
+	resolved    map[string]bool // collects the paths of the resolved values to be redacted, if not nil
+}
+
+// WithContext sets the context used to retrieve the secrets.
+func WithContext(ctx context.Context) LoadOption {
+	return func(o *loadOptions) {
+		o.ctx = ctx
+	}
+}
+
+// WithAWSSecrets sets the AWS Secrets Manager cache used to resolve the "secret://aws/" placeholders
+// (e.g. an awssecretcache.Cache instance).
+func WithAWSSecrets(sm SecretsManager) LoadOption {
+	return func(o *loadOptions) {
+		o.awsSecrets = sm
+	}
+}
+
+// WithFileSecrets enables the "file://" placeholders, replaced with the content of the local files
+// (e.g. Docker and Kubernetes secrets).
+// It should be enabled only when the configuration sources are trusted, as any readable file can be referenced.
+func WithFileSecrets() LoadOption {
+	return func(o *loadOptions) {
+		o.fileSecrets = true
+	}
+}
+
+// WithEnvSecrets enables the "env://" placeholders, replaced with the values of the environment variables.
+// It should be enabled only when the configuration sources are trusted, as any environment variable can be referenced.
+func WithEnvSecrets() LoadOption {
+	return func(o *loadOptions) {
+		o.envSecrets = true
+	}
+}
+
+func newLoadOptions(opts ...LoadOption) *loadOptions {
+	o := &loadOptions{ctx: context.Background()}
+
+	for _, applyOpt := range opts {
+		applyOpt(o)
+	}
+
+	return o
+}
+
+// secretResolver replaces the secret placeholders in the configuration values.
+// The resolved values are never included in the errors.
+type secretResolver struct {
+	opts      *loadOptions
+	refreshed map[string]bool
+}
+
+// resolveSecrets replaces the secret placeholders of all the exported string values of the configuration,
+// including the ones in nested structures, pointers, slices and maps.
+func resolveSecrets(o *loadOptions, cfg any) error {
+	r := &secretResolver{
+		opts:      o,
+		refreshed: make(map[string]bool),
+	}
+
+	return r.walk(reflect.ValueOf(cfg), "")
+}
+
+//nolint:exhaustive
+func (r *secretResolver) walk(v reflect.Value, path string) error {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil
+		}
+
+		return r.walk(v.Elem(), path)
+	case reflect.Interface:
+		if v.IsNil() || !v.CanSet() {
+			return nil
+		}
+
+		return r.walkCopy(v.Elem(), path, v.Set)
+	case reflect.Struct:
+		t := v.Type()
+
+		for i := range v.NumField() {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+
+			if err := r.walk(v.Field(i), joinPath(path, t.Field(i).Name)); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			if err := r.walk(v.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+
+		for iter.Next() {
+			k := iter.Key()
+
+			err := r.walkCopy(iter.Value(), joinPath(path, fmt.Sprint(k.Interface())), func(nv reflect.Value) {
+				v.SetMapIndex(k, nv)
+			})
+			if err != nil {
+				return err
+			}
+		}
+	case reflect.String:
+		if !v.CanSet() {
+			return nil
+		}
+
+		s, ok, err := r.resolve(v.String())
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		if ok {
+			v.SetString(s)
+
+			if r.opts.resolved != nil {
+				r.opts.resolved[path] = true
+			}
+		}
+	}
+
+	return nil
+}
+
+// walkCopy resolves a settable copy of a non-addressable value (e.g. map values) and stores it back.
+func (r *secretResolver) walkCopy(v reflect.Value, path string, set func(nv reflect.Value)) error {
+	nv := reflect.New(v.Type()).Elem()
+	nv.Set(v)
+
+	if err := r.walk(nv, path); err != nil {
+		return err
+	}
+
+	if !reflect.DeepEqual(v.Interface(), nv.Interface()) {
+		set(nv)
+	}
+
+	return nil
+}
+
+// resolve returns the value of a secret placeholder, or false if the value is not a placeholder.
+func (r *secretResolver) resolve(s string) (string, bool, error) {
+	var (
+		value string
+		err   error
+	)
+
+	switch {
+	case strings.HasPrefix(s, SecretPrefixAWS):
+		value, err = r.awsSecret(strings.TrimPrefix(s, SecretPrefixAWS))
+	case r.opts.fileSecrets && strings.HasPrefix(s, SecretPrefixFile):
+		value, err = fileSecret(strings.TrimPrefix(s, SecretPrefixFile))
+	case r.opts.envSecrets && strings.HasPrefix(s, SecretPrefixEnv):
+		value, err = envSecret(strings.TrimPrefix(s, SecretPrefixEnv))
+	default:
+		return "", false, nil
+	}
+
+	if err != nil {
+		return "", false, err
+	}
+
+	return value, true, nil
+}
+
+// awsSecret returns the AWS Secrets Manager secret or the selected key of a JSON object secret.
+// On reload each secret is removed from the cache, so the rotated values are retrieved.
+func (r *secretResolver) awsSecret(ref string) (string, error) {
+	name, key, hasKey := strings.Cut(ref, "#")
+	if name == "" {
+		return "", errors.New("the AWS secret name is required")
+	}
+
+	if r.opts.awsSecrets == nil {
+		return "", fmt.Errorf("unable to resolve the AWS secret %q: the secrets manager is not configured", name)
+	}
+
+	if r.opts.refresh && !r.refreshed[name] {
+		r.opts.awsSecrets.Remove(name)
+		r.refreshed[name] = true
+	}
+
+	value, err := r.opts.awsSecrets.GetSecretString(r.opts.ctx, name)
+	if err != nil {
+		return "", fmt.Errorf("unable to retrieve the AWS secret %q: %w", name, err)
+	}
+
+	if !hasKey {
+		return value, nil
+	}
+
+	var obj map[string]any
+
+	// the decoding error is not wrapped as it may contain part of the secret
+	if json.Unmarshal([]byte(value), &obj) != nil {
+		return "", fmt.Errorf("the AWS secret %q is not a JSON object", name)
+	}
+
+	v, ok := obj[key]
+	if !ok {
+		return "", fmt.Errorf("the AWS secret %q has no %q key", name, key)
+	}
+
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+
+	data, _ := json.Marshal(v) //nolint:errchkjson
+
+	return string(data), nil
+}
+
+// fileSecret returns the content of the file without the trailing newline.
+func fileSecret(path string) (string, error) {
+	if path == "" {
+		return "", errors.New("the secret file path is required")
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec
+	if err != nil {
+		return "", fmt.Errorf("unable to read the secret file: %w", err)
+	}
+
+	return strings.TrimSuffix(strings.TrimSuffix(string(data), "\n"), "\r"), nil
+}
+
+// envSecret returns the value of the environment variable.
+func envSecret(name string) (string, error) {
+	if name == "" {
+		return "", errors.New("the secret environment variable name is required")
+	}
+
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("the secret environment variable %q is not set", name)
+	}
+
+	return value, nil
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
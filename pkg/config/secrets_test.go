@@ -0,0 +1,325 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/Vonage/gosrvlib/pkg/awssecretcache"
+	"github.com/stretchr/testify/require"
+)
+
+var _ SecretsManager = (*awssecretcache.Cache)(nil)
+
+type testSecretsManager struct {
+	mu      sync.Mutex
+	secrets map[string]string
+	removed []string
+}
+
+func (m *testSecretsManager) GetSecretString(_ context.Context, key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	v, ok := m.secrets[key]
+	if !ok {
+		return "", errors.New("secret not found")
+	}
+
+	return v, nil
+}
+
+func (m *testSecretsManager) Remove(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.removed = append(m.removed, key)
+}
+
+type testSecretsNested struct {
+	Password string
+}
+
+type testSecretsConfig struct {
+	Plain     string
+	AWS       string
+	AWSKey    string
+	AWSNumber string
+	File      string
+	Env       string
+	Nested    testSecretsNested
+	Ptr       *testSecretsNested
+	NilPtr    *testSecretsNested
+	Slice     []string
+	Map       map[string]string
+	Any       map[string]any
+	Iface     any
+	NilIface  any
+	unexp     string
+}
+
+func TestResolveSecrets(t *testing.T) {
+	t.Setenv("TEST_CONFIG_SECRET_ENV", "env-value")
+
+	file := filepath.Join(t.TempDir(), "secret")
+	require.NoError(t, os.WriteFile(file, []byte("file-value\r\n"), 0o600))
+
+	sm := &testSecretsManager{
+		secrets: map[string]string{
+			"prod/plain": "aws-value",
+			"prod/db":    `{"password":"db-value","port":3306}`,
+		},
+	}
+
+	cfg := &testSecretsConfig{
+		Plain:     "plain",
+		AWS:       "secret://aws/prod/plain",
+		AWSKey:    "secret://aws/prod/db#password",
+		AWSNumber: "secret://aws/prod/db#port",
+		File:      "file://" + file,
+		Env:       "env://TEST_CONFIG_SECRET_ENV",
+		Nested:    testSecretsNested{Password: "env://TEST_CONFIG_SECRET_ENV"},
+		Ptr:       &testSecretsNested{Password: "env://TEST_CONFIG_SECRET_ENV"},
+		Slice:     []string{"a", "env://TEST_CONFIG_SECRET_ENV"},
+		Map:       map[string]string{"a": "a", "b": "env://TEST_CONFIG_SECRET_ENV"},
+		Any: map[string]any{
+			"a":      1,
+			"b":      "env://TEST_CONFIG_SECRET_ENV",
+			"nested": map[string]any{"c": "env://TEST_CONFIG_SECRET_ENV"},
+		},
+		Iface: "env://TEST_CONFIG_SECRET_ENV",
+		unexp: "env://TEST_CONFIG_SECRET_ENV",
+	}
+
+	err := resolveSecrets(newLoadOptions(WithAWSSecrets(sm), WithFileSecrets(), WithEnvSecrets(), WithContext(t.Context())), cfg)
+	require.NoError(t, err)
+
+	want := &testSecretsConfig{
+		Plain:     "plain",
+		AWS:       "aws-value",
+		AWSKey:    "db-value",
+		AWSNumber: "3306",
+		File:      "file-value",
+		Env:       "env-value",
+		Nested:    testSecretsNested{Password: "env-value"},
+		Ptr:       &testSecretsNested{Password: "env-value"},
+		Slice:     []string{"a", "env-value"},
+		Map:       map[string]string{"a": "a", "b": "env-value"},
+		Any: map[string]any{
+			"a":      1,
+			"b":      "env-value",
+			"nested": map[string]any{"c": "env-value"},
+		},
+		Iface: "env-value",
+		unexp: "env://TEST_CONFIG_SECRET_ENV",
+	}
+
+	require.Equal(t, want, cfg)
+	require.Empty(t, sm.removed)
+
+	// on reload the AWS secrets are removed from the cache once
+	o := newLoadOptions(WithAWSSecrets(sm))
+	o.refresh = true
+
+	cfg.AWS, cfg.AWSKey, cfg.AWSNumber = "secret://aws/prod/plain", "secret://aws/prod/db#password", "secret://aws/prod/db#port"
+
+	err = resolveSecrets(o, cfg)
+	require.NoError(t, err)
+	require.Equal(t, []string{"prod/plain", "prod/db"}, sm.removed)
+}
+
+func TestResolveSecrets_optIn(t *testing.T) {
+	t.Setenv("TEST_CONFIG_SECRET_OPTIN", "env-value")
+
+	file := filepath.Join(t.TempDir(), "secret")
+	require.NoError(t, os.WriteFile(file, []byte("file-value"), 0o600))
+
+	tests := []struct {
+		name     string
+		opts     []LoadOption
+		wantFile string
+		wantEnv  string
+	}{
+		{
+			name:     "disabled",
+			wantFile: "file://" + file,
+			wantEnv:  "env://TEST_CONFIG_SECRET_OPTIN",
+		},
+		{
+			name:     "file only",
+			opts:     []LoadOption{WithFileSecrets()},
+			wantFile: "file-value",
+			wantEnv:  "env://TEST_CONFIG_SECRET_OPTIN",
+		},
+		{
+			name:     "env only",
+			opts:     []LoadOption{WithEnvSecrets()},
+			wantFile: "file://" + file,
+			wantEnv:  "env-value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &testSecretsConfig{File: "file://" + file, Env: "env://TEST_CONFIG_SECRET_OPTIN"}
+
+			err := resolveSecrets(newLoadOptions(tt.opts...), cfg)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantFile, cfg.File)
+			require.Equal(t, tt.wantEnv, cfg.Env)
+		})
+	}
+}
+
+func TestResolveSecrets_errors(t *testing.T) {
+	t.Setenv("TEST_CONFIG_SECRET_JSON", "not-json")
+
+	sm := &testSecretsManager{
+		secrets: map[string]string{
+			"prod/text": "s3cr3t-value",
+			"prod/db":   `{"password":"db-value"}`,
+		},
+	}
+
+	tests := []struct {
+		name    string
+		value   string
+		sm      SecretsManager
+		wantErr string
+	}{
+		{
+			name:    "AWS without manager",
+			value:   "secret://aws/prod/db",
+			wantErr: "not configured",
+		},
+		{
+			name:    "AWS empty name",
+			value:   "secret://aws/#password",
+			sm:      sm,
+			wantErr: "name is required",
+		},
+		{
+			name:    "AWS missing secret",
+			value:   "secret://aws/prod/missing",
+			sm:      sm,
+			wantErr: "unable to retrieve",
+		},
+		{
+			name:    "AWS not JSON",
+			value:   "secret://aws/prod/text#password",
+			sm:      sm,
+			wantErr: "not a JSON object",
+		},
+		{
+			name:    "AWS missing key",
+			value:   "secret://aws/prod/db#user",
+			sm:      sm,
+			wantErr: `no "user" key`,
+		},
+		{
+			name:    "file empty path",
+			value:   "file://",
+			wantErr: "path is required",
+		},
+		{
+			name:    "file missing",
+			value:   "file:///missing/secret",
+			wantErr: "unable to read",
+		},
+		{
+			name:    "env empty name",
+			value:   "env://",
+			wantErr: "name is required",
+		},
+		{
+			name:    "env missing",
+			value:   "env://TEST_CONFIG_SECRET_MISSING",
+			wantErr: "is not set",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &testSecretsConfig{
+				Slice: []string{"a", tt.value},
+				Map:   map[string]string{"a": tt.value},
+				Any:   map[string]any{"a": tt.value},
+			}
+
+			opts := []LoadOption{WithAWSSecrets(tt.sm), WithFileSecrets(), WithEnvSecrets()}
+
+			err := resolveSecrets(newLoadOptions(opts...), &testSecretsConfig{Nested: testSecretsNested{Password: tt.value}})
+			require.ErrorContains(t, err, tt.wantErr)
+			require.ErrorContains(t, err, "Nested.Password")
+			require.NotContains(t, err.Error(), "s3cr3t")
+
+			for _, v := range []any{
+				&cfg.Slice,
+				&cfg.Map,
+				&cfg.Any,
+			} {
+				require.ErrorContains(t, resolveSecrets(newLoadOptions(opts...), v), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoad_secrets(t *testing.T) {
+	t.Setenv("TEST_CONFIG_SECRET_STRING", "secret-value")
+
+	dir := t.TempDir()
+	data := []byte(`{"log":{"level":"INFO","format":"JSON"},"string":"env://TEST_CONFIG_SECRET_STRING"}`)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.json"), data, 0o600))
+
+	cfg := &testConfig{}
+
+	err := Load("cmd", dir, "test", cfg)
+	require.NoError(t, err)
+	require.Equal(t, "env://TEST_CONFIG_SECRET_STRING", cfg.String, "the env placeholders are opt-in")
+
+	cfg = &testConfig{}
+
+	err = Load("cmd", dir, "test", cfg, WithEnvSecrets())
+	require.NoError(t, err)
+	require.Equal(t, "secret-value", cfg.String)
+
+	data = []byte(`{"log":{"level":"INFO","format":"JSON"},"string":"secret://aws/missing"}`)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.json"), data, 0o600))
+
+	err = Load("cmd", dir, "test", &testConfig{}, WithAWSSecrets(&testSecretsManager{}))
+	require.ErrorContains(t, err, "failed resolving configuration secrets")
+}
+
+func TestWatcher_Reload_secrets(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	data := []byte(`{"log":{"level":"INFO","format":"JSON"},"string":"secret://aws/prod/api#key"}`)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.json"), data, 0o600))
+
+	sm := &testSecretsManager{secrets: map[string]string{"prod/api": `{"key":"first"}`}}
+
+	w, err := NewWatcher("test", dir, "TESTWATCHERSECRETS", func() *testConfig { return &testConfig{} },
+		WithWatchLoadOptions(WithAWSSecrets(sm)),
+	)
+	require.NoError(t, err)
+	require.Equal(t, "first", w.Config().String)
+	require.Empty(t, sm.removed)
+
+	changes := &testChanges{}
+	w.Subscribe(changes.subscriber)
+
+	sm.mu.Lock()
+	sm.secrets["prod/api"] = `{"key":"rotated"}`
+	sm.mu.Unlock()
+
+	changed, err := w.Reload()
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Equal(t, "rotated", w.Config().String)
+	require.Equal(t, []string{"prod/api"}, sm.removed)
+	require.Equal(t, [][2]string{{"first", "rotated"}}, changes.get())
+}
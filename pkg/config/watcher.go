@@ -0,0 +1,337 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+)
+
+// defaultWatchDebounce is the default time waited after the last file change event before reloading.
+const defaultWatchDebounce = 100 * time.Millisecond
+
+// k8sDataDir is the name of the symbolic link atomically swapped by Kubernetes when a mounted ConfigMap changes.
+const k8sDataDir = "..data"
+
+// SubscriberFn is the type of function called with the old and new configuration after a change.
+type SubscriberFn[T Configuration] func(oldCfg, newCfg T)
+
+// watcherChange is a configuration change to be notified to the subscribers registered at the time of the change.
+type watcherChange[T Configuration] struct {
+	oldCfg      T
+	newCfg      T
+	subscribers []SubscriberFn[T]
+}
+
+// WatcherOption is the interface that allows to set the Watcher options.
+type WatcherOption func(c *watcherConfig)
+
+type watcherConfig struct {
+	watchFile bool
+	interval  time.Duration
+	debounce  time.Duration
+	logger    *zap.Logger
+	loadOpts  []LoadOption
+}
+
+// WithWatchFile enables or disables the watching of the local configuration file (enabled by default).
+func WithWatchFile(enabled bool) WatcherOption {
+	return func(c *watcherConfig) {
+		c.watchFile = enabled
+	}
+}
+
+// WithWatchInterval sets the interval to periodically reload the whole configuration.
+// This is required to detect the changes of the remote configuration providers.
+// A zero value disables the periodic reload (default).
+func WithWatchInterval(interval time.Duration) WatcherOption {
+	return func(c *watcherConfig) {
+		c.interval = interval
+	}
+}
+
+// WithWatchDebounce sets the time waited after the last file change event before reloading,
+// to avoid multiple reloads while the file is being written.
+func WithWatchDebounce(debounce time.Duration) WatcherOption {
+	return func(c *watcherConfig) {
+		c.debounce = debounce
+	}
+}
+
+// WithWatchLogger sets the logger used to log the reload results.
+// The configuration values are never logged.
+func WithWatchLogger(l *zap.Logger) WatcherOption {
+	return func(c *watcherConfig) {
+		c.logger = l
+	}
+}
+
+// WithWatchLoadOptions sets the Load options used on each (re)load (e.g. WithAWSSecrets).
+func WithWatchLoadOptions(opts ...LoadOption) WatcherOption {
+	return func(c *watcherConfig) {
+		c.loadOpts = append(c.loadOpts, opts...)
+	}
+}
+
+// Watcher loads the configuration like Load and reloads it when the local configuration file changes
+// or periodically, to pick up the changes without restarting the program.
+//
+// Each reloaded configuration is validated via the Configuration.Validate function
+// and atomically swapped with the current one only if valid and different.
+// The subscribers are then notified with the old and new configuration values,
+// so the live-changeable settings (e.g. log level, rate limits, feature flags) can be updated.
+// The command-line arguments are not reapplied on reload,
+// while the secret placeholders are resolved again to pick up the rotated secrets.
+type Watcher[T Configuration] struct {
+	newFn       func() T
+	loadFn      func(cfg Configuration, refresh bool) (string, error)
+	cfg         watcherConfig
+	file        string
+	current     atomic.Pointer[T]
+	mu          sync.Mutex
+	subscribers []SubscriberFn[T]
+	changes     []watcherChange[T] // Changes not yet notified.
+	notifying   bool               // True while a Reload call is notifying the changes.
+	cancel      context.CancelFunc
+	wg          sync.WaitGroup
+}
+
+// NewWatcher loads the configuration and returns a new Watcher.
+// The newFn function must return a new instance of the application configuration (e.g. &appConfig{}).
+// The other parameters are the same of Load.
+func NewWatcher[T Configuration](cmdName, configDir, envPrefix string, newFn func() T, opts ...WatcherOption) (*Watcher[T], error) {
+	if newFn == nil {
+		return nil, errors.New("the configuration constructor is required")
+	}
+
+	w := &Watcher[T]{
+		newFn: newFn,
+		cfg: watcherConfig{
+			watchFile: true,
+			debounce:  defaultWatchDebounce,
+			logger:    zap.NewNop(),
+		},
+	}
+
+	for _, applyOpt := range opts {
+		applyOpt(&w.cfg)
+	}
+
+	w.loadFn = func(cfg Configuration, refresh bool) (string, error) {
+		o := newLoadOptions(w.cfg.loadOpts...)
+		o.refresh = refresh
+
+		return load(cmdName, configDir, envPrefix, cfg, o)
+	}
+
+	cfg := newFn()
+
+	file, err := w.loadFn(cfg, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if file != "" {
+		if w.file, err = filepath.Abs(file); err != nil {
+			return nil, fmt.Errorf("failed resolving the configuration file path: %w", err)
+		}
+	}
+
+	w.current.Store(&cfg)
+
+	return w, nil
+}
+
+// Config returns the current configuration.
+// The returned value must not be modified.
+func (w *Watcher[T]) Config() T {
+	return *w.current.Load()
+}
+
+// Subscribe registers a function to be called after each configuration change.
+// The subscribers are called sequentially in the registration order, without holding the Watcher lock.
+// The changes are notified one at a time in the order they are applied,
+// so the last configuration received by each subscriber is always the current one.
+func (w *Watcher[T]) Subscribe(fn SubscriberFn[T]) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.subscribers = append(w.subscribers, fn)
+}
+
+// Reload loads and validates the configuration, and swaps it with the current one if different.
+// It returns true if the configuration has changed.
+// On error, the current configuration is preserved.
+// If the subscribers are being notified of a previous change (e.g. by a concurrent Reload call),
+// the new change is queued and notified after it, and Reload returns without waiting.
+func (w *Watcher[T]) Reload() (bool, error) {
+	w.mu.Lock()
+
+	cfg := w.newFn()
+
+	if _, err := w.loadFn(cfg, true); err != nil {
+		w.mu.Unlock()
+		return false, err
+	}
+
+	old := w.Config()
+
+	if reflect.DeepEqual(old, cfg) {
+		w.mu.Unlock()
+		return false, nil
+	}
+
+	w.current.Store(&cfg)
+
+	w.changes = append(w.changes, watcherChange[T]{
+		oldCfg:      old,
+		newCfg:      cfg,
+		subscribers: slices.Clone(w.subscribers),
+	})
+
+	if w.notifying {
+		w.mu.Unlock()
+		return true, nil
+	}
+
+	w.notifying = true
+
+	w.mu.Unlock()
+
+	w.notify()
+
+	return true, nil
+}
+
+// notify calls the subscribers for each queued change, in order.
+// The subscribers are notified without holding the lock, so they can call Subscribe or Reload.
+func (w *Watcher[T]) notify() {
+	for {
+		w.mu.Lock()
+
+		if len(w.changes) == 0 {
+			w.notifying = false
+			w.mu.Unlock()
+
+			return
+		}
+
+		c := w.changes[0]
+		w.changes = w.changes[1:]
+
+		w.mu.Unlock()
+
+		for _, fn := range c.subscribers {
+			fn(c.oldCfg, c.newCfg)
+		}
+	}
+}
+
+// Start watching the local configuration file and periodically reloading the configuration, if enabled.
+func (w *Watcher[T]) Start(ctx context.Context) error {
+	ctx, w.cancel = context.WithCancel(ctx)
+
+	if w.cfg.watchFile && w.file != "" {
+		fsw, err := fsnotify.NewWatcher()
+		if err != nil {
+			return fmt.Errorf("failed creating the file watcher: %w", err)
+		}
+
+		// the directory is watched to detect the files replaced by editors and Kubernetes
+		err = fsw.Add(filepath.Dir(w.file))
+		if err != nil {
+			_ = fsw.Close()
+			return fmt.Errorf("failed watching the configuration directory: %w", err)
+		}
+
+		w.wg.Go(func() { w.watchFile(ctx, fsw) })
+	}
+
+	if w.cfg.interval > 0 {
+		w.wg.Go(func() { w.poll(ctx) })
+	}
+
+	return nil
+}
+
+// Stop watching the configuration.
+func (w *Watcher[T]) Stop() {
+	if w.cancel != nil {
+		w.cancel()
+	}
+
+	w.wg.Wait()
+}
+
+func (w *Watcher[T]) watchFile(ctx context.Context, fsw *fsnotify.Watcher) {
+	defer func() { _ = fsw.Close() }()
+
+	var debounce <-chan time.Time
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-fsw.Events:
+			if !ok {
+				return
+			}
+
+			if w.isConfigEvent(ev) {
+				debounce = time.After(w.cfg.debounce)
+			}
+		case err, ok := <-fsw.Errors:
+			if !ok {
+				return
+			}
+
+			w.cfg.logger.Error("configuration file watcher error", zap.Error(err))
+		case <-debounce:
+			debounce = nil
+
+			w.reload("file")
+		}
+	}
+}
+
+func (w *Watcher[T]) isConfigEvent(ev fsnotify.Event) bool {
+	if ev.Op == fsnotify.Chmod {
+		return false
+	}
+
+	return filepath.Clean(ev.Name) == w.file || filepath.Base(ev.Name) == k8sDataDir
+}
+
+func (w *Watcher[T]) poll(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.reload("poll")
+		}
+	}
+}
+
+func (w *Watcher[T]) reload(trigger string) {
+	changed, err := w.Reload()
+	if err != nil {
+		w.cfg.logger.Error("failed reloading configuration", zap.String("trigger", trigger), zap.Error(err))
+		return
+	}
+
+	if changed {
+		w.cfg.logger.Info("configuration reloaded", zap.String("trigger", trigger))
+	}
+}
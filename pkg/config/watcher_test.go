@@ -0,0 +1,288 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func writeTestWatcherConfig(t *testing.T, dir, value string) {
+	t.Helper()
+
+	data := []byte(`{"log":{"level":"INFO","format":"JSON"},"string":"` + value + `"}`)
+
+	// write and rename to replace the file atomically like most editors
+	tmp := filepath.Join(dir, "config.json.tmp")
+	require.NoError(t, os.WriteFile(tmp, data, 0o600))
+	require.NoError(t, os.Rename(tmp, filepath.Join(dir, "config.json")))
+}
+
+type testChanges struct {
+	mu      sync.Mutex
+	changes [][2]string
+}
+
+func (c *testChanges) subscriber(oldCfg, newCfg *testConfig) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.changes = append(c.changes, [2]string{oldCfg.String, newCfg.String})
+}
+
+func (c *testChanges) get() [][2]string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([][2]string{}, c.changes...)
+}
+
+func TestNewWatcher(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	newFn := func() *testConfig { return &testConfig{} }
+
+	w, err := NewWatcher[*testConfig]("test", dir, "TESTNEWWATCHER", nil)
+	require.Error(t, err)
+	require.Nil(t, w)
+
+	w, err = NewWatcher("test", dir, "TESTNEWWATCHER", newFn)
+	require.Error(t, err, "missing configuration file")
+	require.Nil(t, w)
+
+	writeTestWatcherConfig(t, dir, "alpha")
+
+	w, err = NewWatcher("test", dir, "TESTNEWWATCHER", newFn, WithWatchLogger(zap.NewNop()))
+	require.NoError(t, err)
+	require.NotNil(t, w)
+	require.Equal(t, "alpha", w.Config().String)
+	require.Equal(t, filepath.Join(dir, "config.json"), w.file)
+}
+
+func TestWatcher_Reload(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeTestWatcherConfig(t, dir, "alpha")
+
+	var validateErr error
+
+	w, err := NewWatcher("test", dir, "TESTWATCHERRELOAD", func() *testConfig {
+		return &testConfig{validateErr: validateErr}
+	})
+	require.NoError(t, err)
+
+	changes := &testChanges{}
+	w.Subscribe(changes.subscriber)
+
+	changed, err := w.Reload()
+	require.NoError(t, err)
+	require.False(t, changed, "unchanged configuration")
+
+	writeTestWatcherConfig(t, dir, "beta")
+
+	changed, err = w.Reload()
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Equal(t, "beta", w.Config().String)
+
+	writeTestWatcherConfig(t, dir, "gamma")
+
+	validateErr = errors.New("invalid")
+
+	changed, err = w.Reload()
+	require.Error(t, err)
+	require.False(t, changed)
+	require.Equal(t, "beta", w.Config().String, "the invalid configuration is not applied")
+
+	require.Equal(t, [][2]string{{"alpha", "beta"}}, changes.get())
+}
+
+func TestWatcher_Reload_reentrantSubscriber(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeTestWatcherConfig(t, dir, "alpha")
+
+	w, err := NewWatcher("test", dir, "TESTWATCHERRELOADREENTRANT", func() *testConfig { return &testConfig{} })
+	require.NoError(t, err)
+
+	changes := &testChanges{}
+
+	w.Subscribe(func(_, _ *testConfig) {
+		// subscribing and reloading from a subscriber must not deadlock
+		w.Subscribe(changes.subscriber)
+
+		changed, err := w.Reload()
+		require.NoError(t, err)
+		require.False(t, changed)
+	})
+
+	writeTestWatcherConfig(t, dir, "beta")
+
+	changed, err := w.Reload()
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Empty(t, changes.get(), "the new subscriber is not notified of the current change")
+
+	writeTestWatcherConfig(t, dir, "gamma")
+
+	changed, err = w.Reload()
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Equal(t, [][2]string{{"beta", "gamma"}}, changes.get())
+}
+
+func TestWatcher_Reload_concurrent(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeTestWatcherConfig(t, dir, "0")
+
+	w, err := NewWatcher("test", dir, "TESTWATCHERRELOADCONCURRENT", func() *testConfig { return &testConfig{} })
+	require.NoError(t, err)
+
+	var version atomic.Int64
+
+	// each reload returns a different configuration
+	w.loadFn = func(cfg Configuration, _ bool) (string, error) {
+		cfg.(*testConfig).String = strconv.FormatInt(version.Add(1), 10)
+		return "", nil
+	}
+
+	// a slow subscriber widens the window between the concurrent notifications
+	w.Subscribe(func(_, _ *testConfig) { time.Sleep(time.Millisecond) })
+
+	const subscribers = 3
+
+	changes := make([]*testChanges, subscribers)
+
+	for i := range changes {
+		changes[i] = &testChanges{}
+		w.Subscribe(changes[i].subscriber)
+	}
+
+	var wg sync.WaitGroup
+
+	for range 50 {
+		wg.Go(func() {
+			_, err := w.Reload()
+			require.NoError(t, err)
+		})
+	}
+
+	wg.Wait()
+
+	for _, c := range changes {
+		got := c.get()
+		require.Len(t, got, 50)
+
+		// the changes are received in order, each one starting from the previous one
+		prev := "0"
+
+		for _, change := range got {
+			require.Equal(t, prev, change[0])
+
+			prev = change[1]
+		}
+
+		require.Equal(t, w.Config().String, prev, "the last received configuration is the current one")
+	}
+}
+
+func TestWatcher_Start(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		opts []WatcherOption
+	}{
+		{
+			name: "file",
+			opts: []WatcherOption{WithWatchDebounce(10 * time.Millisecond)},
+		},
+		{
+			name: "poll",
+			opts: []WatcherOption{WithWatchFile(false), WithWatchInterval(10 * time.Millisecond)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			writeTestWatcherConfig(t, dir, "alpha")
+
+			w, err := NewWatcher("test", dir, "TESTWATCHERSTART", func() *testConfig { return &testConfig{} }, tt.opts...)
+			require.NoError(t, err)
+
+			changes := &testChanges{}
+			w.Subscribe(changes.subscriber)
+
+			err = w.Start(context.Background())
+			require.NoError(t, err)
+
+			defer w.Stop()
+
+			writeTestWatcherConfig(t, dir, "beta")
+
+			require.Eventually(t, func() bool {
+				return len(changes.get()) == 1
+			}, 5*time.Second, 10*time.Millisecond)
+
+			require.Equal(t, [][2]string{{"alpha", "beta"}}, changes.get())
+			require.Equal(t, "beta", w.Config().String)
+		})
+	}
+}
+
+func TestWatcher_Start_error(t *testing.T) {
+	t.Parallel()
+
+	w := &Watcher[*testConfig]{
+		file: "/missing-config-dir/config.json",
+		cfg:  watcherConfig{watchFile: true},
+	}
+
+	err := w.Start(context.Background())
+	require.Error(t, err)
+
+	w.Stop()
+}
+
+func TestWatcher_isConfigEvent(t *testing.T) {
+	t.Parallel()
+
+	w := &Watcher[*testConfig]{file: "/etc/test/config.json"}
+
+	tests := []struct {
+		name string
+		ev   fsnotify.Event
+		want bool
+	}{
+		{name: "write", ev: fsnotify.Event{Name: "/etc/test/config.json", Op: fsnotify.Write}, want: true},
+		{name: "create", ev: fsnotify.Event{Name: "/etc/test/config.json", Op: fsnotify.Create}, want: true},
+		{name: "kubernetes configmap", ev: fsnotify.Event{Name: "/etc/test/..data", Op: fsnotify.Create}, want: true},
+		{name: "chmod", ev: fsnotify.Event{Name: "/etc/test/config.json", Op: fsnotify.Chmod}, want: false},
+		{name: "other file", ev: fsnotify.Event{Name: "/etc/test/other.json", Op: fsnotify.Write}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, w.isConfigEvent(tt.ev))
+		})
+	}
+}
@@ -0,0 +1,371 @@
+/*
+Package cron provides a scheduler to execute named jobs at the wall clock times
+defined by standard cron expressions, as a complement to the fixed intervals of
+the periodic package.
+
+The cron expressions support the standard five fields syntax, the predefined
+schedules (e.g. @daily) and the time zones via the CRON_TZ= prefix
+(e.g. "CRON_TZ=Europe/London 0 2 * * *" for every day at 02:00 London time).
+The daylight saving time changes are handled like the traditional cron
+implementations (see Schedule.Next).
+
+Each job has its own timeout applied via Context, and the tasks use the same
+TaskFn signature of the periodic package. The Context passed to the task
+contains a logger with the job name (see logging.FromContext).
+
+When a job is still running at the next activation time, the new run is either
+skipped (OverlapSkip, the default) or queued (OverlapQueue).
+
+When a Store is configured, the time of the last run of each job is persisted,
+so the activations missed while the service was down can be executed at start
+according to the CatchUpPolicy of each job.
+
+Each run is counted with the IncEventCounter metric ("cron" task, job name
+operation) with the "success", "timeout", "panic", "skipped", "missed" and
+"store_error" outcomes.
+
+Example:
+
+	s, err := cron.New(cron.WithLogger(logger), cron.WithMetrics(metricsClient))
+	if err != nil {
+		return err
+	}
+
+	err = s.Add("cleanup", "CRON_TZ=Europe/London 0 2 * * *", 10*time.Minute, cleanupTask)
+	if err != nil {
+		return err
+	}
+
+	err = s.Start(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer s.Stop()
+*/
+package cron
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/logging"
+	"github.com/Vonage/gosrvlib/pkg/metrics"
+	"github.com/Vonage/gosrvlib/pkg/periodic"
+	"github.com/Vonage/gosrvlib/pkg/retrier"
+	"go.uber.org/zap"
+)
+
+// maxWait is the maximum time waited before checking again the current time.
+// It limits the drift after system clock changes or suspensions.
+const maxWait = time.Minute
+
+// maxCatchUpRuns is the maximum number of missed activations executed with the CatchUpAll policy.
+const maxCatchUpRuns = 100
+
+// metricsTask is the task name used in the metrics.
+const metricsTask = "cron"
+
+// Metrics outcomes.
+const (
+	outcomeSuccess    = "success"
+	outcomeTimeout    = "timeout"
+	outcomePanic      = "panic"
+	outcomeSkipped    = "skipped"
+	outcomeMissed     = "missed"
+	outcomeStoreError = "store_error"
+)
+
+// Store is the interface used to persist the time of the last run of each job,
+// so the missed activations can be caught up after a restart.
+type Store interface {
+	// LastRun returns the scheduled time of the last run of the named job,
+	// or the zero time if the job never ran.
+	LastRun(ctx context.Context, name string) (time.Time, error)
+
+	// SetLastRun stores the scheduled time of the last run of the named job.
+	SetLastRun(ctx context.Context, name string, t time.Time) error
+}
+
+// Scheduler executes the registered jobs at the times defined by their cron expressions.
+type Scheduler struct {
+	mu       sync.Mutex
+	jobs     map[string]*job
+	location *time.Location
+	logger   *zap.Logger
+	metrics  metrics.Client
+	store    Store
+	clock    retrier.Clock
+	ctx      context.Context //nolint:containedctx
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// New creates a new Scheduler.
+func New(opts ...Option) (*Scheduler, error) {
+	s := &Scheduler{
+		jobs:     make(map[string]*job),
+		location: time.Local,
+		logger:   zap.NewNop(),
+		metrics:  &metrics.Default{},
+		clock:    retrier.SystemClock{},
+	}
+
+	for _, applyOpt := range opts {
+		if err := applyOpt(s); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// Add registers a named job to execute the task at the times defined by the cron expression (see Parse).
+// The timeout is applied to each task execution via context.
+// Jobs added after Start are started immediately.
+func (s *Scheduler) Add(name, spec string, timeout time.Duration, task periodic.TaskFn, opts ...JobOption) error {
+	if name == "" {
+		return errors.New("the job name is required")
+	}
+
+	if int64(timeout) < 1 {
+		return errors.New("timeout must be positive")
+	}
+
+	if task == nil {
+		return errors.New("nil task")
+	}
+
+	schedule, err := Parse(spec)
+	if err != nil {
+		return fmt.Errorf("job %q: %w", name, err)
+	}
+
+	j := &job{
+		name:      name,
+		schedule:  schedule,
+		timeout:   timeout,
+		task:      task,
+		location:  schedule.Location(),
+		overlap:   OverlapSkip,
+		maxQueued: 1,
+		catchUp:   CatchUpNone,
+	}
+
+	for _, applyOpt := range opts {
+		if err := applyOpt(j); err != nil {
+			return fmt.Errorf("job %q: %w", name, err)
+		}
+	}
+
+	if j.location == nil {
+		j.location = s.location
+	}
+
+	if schedule.Next(s.clock.Now().In(j.location)).IsZero() {
+		return fmt.Errorf("job %q: the schedule %q never activates", name, spec)
+	}
+
+	j.runs = make(chan time.Time, j.maxPending())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.jobs[name]; ok {
+		return fmt.Errorf("job %q already exists", name)
+	}
+
+	s.jobs[name] = j
+
+	if s.ctx != nil {
+		s.startJob(j)
+	}
+
+	return nil
+}
+
+// Start the execution of the registered jobs.
+// A Scheduler can only be started once: it returns an error if already started.
+func (s *Scheduler) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ctx != nil {
+		return errors.New("the scheduler is already started")
+	}
+
+	s.ctx, s.cancel = context.WithCancel(ctx)
+
+	for _, j := range s.jobs {
+		s.startJob(j)
+	}
+
+	return nil
+}
+
+// Stop the execution of the jobs.
+// It blocks until the running tasks return. The queued runs are discarded.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+
+	if s.cancel != nil {
+		s.cancel()
+	}
+
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+func (s *Scheduler) startJob(j *job) {
+	s.wg.Add(2)
+
+	go func() {
+		defer s.wg.Done()
+		s.loop(s.ctx, j)
+	}()
+
+	go func() {
+		defer s.wg.Done()
+		s.work(s.ctx, j)
+	}()
+}
+
+// loop dispatches the runs of the job at the scheduled times.
+func (s *Scheduler) loop(ctx context.Context, j *job) {
+	last, initial := s.lastRun(ctx, j), true
+
+	for {
+		now := s.clock.Now().In(j.location)
+
+		if due := j.due(last, now); len(due) > 0 {
+			s.dispatch(ctx, j, due, initial)
+			last = due[len(due)-1]
+		}
+
+		initial = false
+
+		next := j.schedule.Next(now)
+		if next.IsZero() {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.clock.After(min(next.Sub(now), maxWait)):
+		}
+	}
+}
+
+// lastRun returns the time of the last run of the job from the store, or the current time.
+func (s *Scheduler) lastRun(ctx context.Context, j *job) time.Time {
+	now := s.clock.Now()
+
+	if s.store == nil {
+		return now
+	}
+
+	last, err := s.store.LastRun(ctx, j.name)
+	if err != nil {
+		metrics.IncEventCounter(s.metrics, metricsTask, j.name, outcomeStoreError)
+		s.logger.Error("unable to read the last cron job run", zap.String("job", j.name), zap.Error(err))
+
+		return now
+	}
+
+	if last.IsZero() || last.After(now) {
+		return now
+	}
+
+	return last
+}
+
+// dispatch sends the due runs to the worker according to the catch-up and overlap policies.
+// On the initial call all the due runs have been missed while the service was down,
+// otherwise only the earlier ones have been missed (e.g. on system suspension).
+func (s *Scheduler) dispatch(ctx context.Context, j *job, due []time.Time, initial bool) {
+	missed := due
+
+	var regular []time.Time
+
+	if !initial {
+		missed, regular = due[:len(due)-1], due[len(due)-1:]
+	}
+
+	catchUp := j.catchUpRuns(missed, len(regular) > 0)
+
+	for _, t := range missed[:len(missed)-len(catchUp)] {
+		metrics.IncEventCounter(s.metrics, metricsTask, j.name, outcomeMissed)
+		s.logger.Info("cron job run missed", zap.String("job", j.name), zap.Time("scheduled", t))
+	}
+
+	// the catch-up runs wait for the worker regardless of the overlap policy
+	for _, t := range catchUp {
+		j.pending.Add(1)
+
+		select {
+		case <-ctx.Done():
+			return
+		case j.runs <- t:
+		}
+	}
+
+	for _, t := range regular {
+		if j.reserve() {
+			j.runs <- t
+		} else {
+			metrics.IncEventCounter(s.metrics, metricsTask, j.name, outcomeSkipped)
+			s.logger.Info("cron job run skipped, the previous run is still in progress",
+				zap.String("job", j.name),
+				zap.Time("scheduled", t),
+			)
+		}
+	}
+}
+
+// work executes the dispatched runs of the job sequentially.
+func (s *Scheduler) work(ctx context.Context, j *job) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case t := <-j.runs:
+			s.run(ctx, j, t)
+			j.pending.Add(-1)
+		}
+	}
+}
+
+// run executes the task of the job scheduled at the time t.
+func (s *Scheduler) run(ctx context.Context, j *job, t time.Time) {
+	logger := s.logger.With(zap.String("job", j.name), zap.Time("scheduled", t))
+	start := time.Now()
+
+	outcome := j.exec(logging.WithLogger(ctx, logger))
+
+	metrics.IncEventCounter(s.metrics, metricsTask, j.name, outcome)
+
+	fields := []zap.Field{
+		zap.String("outcome", outcome),
+		zap.Duration("duration", time.Since(start)),
+	}
+
+	if outcome == outcomeSuccess {
+		logger.Debug("cron job run completed", fields...)
+	} else {
+		logger.Error("cron job run failed", fields...)
+	}
+
+	if s.store == nil {
+		return
+	}
+
+	if err := s.store.SetLastRun(context.WithoutCancel(ctx), j.name, t); err != nil {
+		metrics.IncEventCounter(s.metrics, metricsTask, j.name, outcomeStoreError)
+		logger.Error("unable to store the last cron job run", zap.Error(err))
+	}
+}
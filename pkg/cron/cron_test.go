@@ -0,0 +1,471 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/logging"
+	"github.com/Vonage/gosrvlib/pkg/testutil"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type testWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+// testClock is a manual clock that only moves forward with Set.
+type testClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []testWaiter
+}
+
+func newTestClock(now time.Time) *testClock {
+	return &testClock{now: now}
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *testClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	c.waiters = append(c.waiters, testWaiter{at: c.now.Add(d), ch: ch})
+
+	return ch
+}
+
+func (c *testClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = now
+
+	waiters := c.waiters[:0]
+
+	for _, w := range c.waiters {
+		if w.at.After(now) {
+			waiters = append(waiters, w)
+			continue
+		}
+
+		w.ch <- now
+	}
+
+	c.waiters = waiters
+}
+
+func (c *testClock) waiting() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.waiters)
+}
+
+type testStore struct {
+	mu   sync.Mutex
+	last map[string]time.Time
+	err  error
+}
+
+func newTestStore() *testStore {
+	return &testStore{last: make(map[string]time.Time)}
+}
+
+func (s *testStore) LastRun(_ context.Context, name string) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.last[name], s.err
+}
+
+func (s *testStore) SetLastRun(_ context.Context, name string, t time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.last[name] = t
+
+	return s.err
+}
+
+func mustTime(t *testing.T, value string) time.Time {
+	t.Helper()
+
+	v, err := time.Parse(time.RFC3339, value)
+	require.NoError(t, err)
+
+	return v
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	s, err := New(WithLocation(time.UTC))
+	require.NoError(t, err)
+	require.NotNil(t, s)
+	require.Equal(t, time.UTC, s.location)
+
+	s, err = New(WithLocation(nil))
+	require.Error(t, err)
+	require.Nil(t, s)
+}
+
+func TestScheduler_Add(t *testing.T) {
+	t.Parallel()
+
+	task := func(_ context.Context) {}
+
+	tests := []struct {
+		name    string
+		job     string
+		spec    string
+		timeout time.Duration
+		task    func(context.Context)
+		opts    []JobOption
+		wantErr bool
+	}{
+		{name: "success", job: "job", spec: "*/5 * * * *", timeout: time.Second, task: task},
+		{
+			name:    "success with options",
+			job:     "job",
+			spec:    "@daily",
+			timeout: time.Second,
+			task:    task,
+			opts:    []JobOption{WithOverlapPolicy(OverlapQueue), WithMaxQueued(2), WithCatchUp(CatchUpOnce)},
+		},
+		{name: "empty name", spec: "* * * * *", timeout: time.Second, task: task, wantErr: true},
+		{name: "zero timeout", job: "job", spec: "* * * * *", task: task, wantErr: true},
+		{name: "nil task", job: "job", spec: "* * * * *", timeout: time.Second, wantErr: true},
+		{name: "invalid spec", job: "job", spec: "* * *", timeout: time.Second, task: task, wantErr: true},
+		{name: "never", job: "job", spec: "0 0 31 4 *", timeout: time.Second, task: task, wantErr: true},
+		{name: "duplicate", job: "dup", spec: "* * * * *", timeout: time.Second, task: task, wantErr: true},
+		{
+			name:    "invalid option",
+			job:     "job",
+			spec:    "* * * * *",
+			timeout: time.Second,
+			task:    task,
+			opts:    []JobOption{WithMaxQueued(0)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := New()
+			require.NoError(t, err)
+
+			err = s.Add("dup", "* * * * *", time.Second, task)
+			require.NoError(t, err)
+
+			err = s.Add(tt.job, tt.spec, tt.timeout, tt.task, tt.opts...)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Contains(t, s.jobs, tt.job)
+		})
+	}
+}
+
+func TestScheduler_run(t *testing.T) {
+	t.Parallel()
+
+	clock := newTestClock(mustTime(t, "2026-01-01T10:02:30Z"))
+	m := &testutil.EventMetrics{}
+	store := newTestStore()
+
+	s, err := New(WithLocation(time.UTC), WithClock(clock), WithMetrics(m), WithStore(store))
+	require.NoError(t, err)
+
+	runs := make(chan bool, 10)
+
+	err = s.Add("job", "*/5 * * * *", time.Second, func(ctx context.Context) {
+		runs <- logging.FromContext(ctx) != nil
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, s.Start(context.Background()))
+	defer s.Stop()
+
+	require.Eventually(t, func() bool { return clock.waiting() == 1 }, time.Second, time.Millisecond)
+
+	// not yet due
+	clock.Set(mustTime(t, "2026-01-01T10:04:00Z"))
+	require.Eventually(t, func() bool { return clock.waiting() == 1 }, time.Second, time.Millisecond)
+	require.Empty(t, runs)
+
+	clock.Set(mustTime(t, "2026-01-01T10:05:00Z"))
+	require.True(t, <-runs)
+
+	require.Eventually(t, func() bool { return m.Count(outcomeSuccess) == 1 }, time.Second, time.Millisecond)
+
+	last, err := store.LastRun(context.Background(), "job")
+	require.NoError(t, err)
+	require.Equal(t, mustTime(t, "2026-01-01T10:05:00Z"), last.UTC())
+}
+
+func TestScheduler_overlap(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		opts        []JobOption
+		activations int
+		wantRuns    int
+		wantSkipped int
+	}{
+		{
+			name:        "skip",
+			activations: 3,
+			wantRuns:    1,
+			wantSkipped: 2,
+		},
+		{
+			name:        "queue",
+			opts:        []JobOption{WithOverlapPolicy(OverlapQueue)},
+			activations: 3,
+			wantRuns:    2,
+			wantSkipped: 1,
+		},
+		{
+			name:        "queue more",
+			opts:        []JobOption{WithOverlapPolicy(OverlapQueue), WithMaxQueued(2)},
+			activations: 3,
+			wantRuns:    3,
+			wantSkipped: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			start := mustTime(t, "2026-01-01T10:00:00Z")
+			clock := newTestClock(start)
+			m := &testutil.EventMetrics{}
+
+			s, err := New(WithLocation(time.UTC), WithClock(clock), WithMetrics(m))
+			require.NoError(t, err)
+
+			release := make(chan struct{})
+			started := make(chan struct{}, 10)
+
+			err = s.Add("job", "* * * * *", time.Second, func(_ context.Context) {
+				started <- struct{}{}
+				<-release
+			}, tt.opts...)
+			require.NoError(t, err)
+
+			require.NoError(t, s.Start(context.Background()))
+
+			for i := 1; i <= tt.activations; i++ {
+				require.Eventually(t, func() bool { return clock.waiting() == 1 }, time.Second, time.Millisecond)
+				clock.Set(start.Add(time.Duration(i) * time.Minute))
+
+				if i == 1 {
+					<-started
+				}
+			}
+
+			require.Eventually(t, func() bool { return clock.waiting() == 1 }, time.Second, time.Millisecond)
+			require.Equal(t, tt.wantSkipped, m.Count(outcomeSkipped))
+
+			close(release)
+
+			require.Eventually(t, func() bool { return m.Count(outcomeSuccess) == tt.wantRuns }, time.Second, time.Millisecond)
+
+			s.Stop()
+
+			require.Len(t, started, tt.wantRuns-1)
+		})
+	}
+}
+
+func TestScheduler_catchUp(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		policy     CatchUpPolicy
+		lastRun    string
+		storeErr   error
+		wantRuns   int
+		wantMissed int
+	}{
+		{
+			name:       "none",
+			policy:     CatchUpNone,
+			lastRun:    "2026-01-01T09:45:00Z",
+			wantMissed: 3,
+		},
+		{
+			name:       "once",
+			policy:     CatchUpOnce,
+			lastRun:    "2026-01-01T09:45:00Z",
+			wantRuns:   1,
+			wantMissed: 2,
+		},
+		{
+			name:     "all",
+			policy:   CatchUpAll,
+			lastRun:  "2026-01-01T09:45:00Z",
+			wantRuns: 3,
+		},
+		{
+			name:     "up to date",
+			policy:   CatchUpAll,
+			lastRun:  "2026-01-01T10:00:00Z",
+			wantRuns: 0,
+		},
+		{
+			name:     "never run",
+			policy:   CatchUpAll,
+			wantRuns: 0,
+		},
+		{
+			name:     "store error",
+			policy:   CatchUpAll,
+			lastRun:  "2026-01-01T09:45:00Z",
+			storeErr: errors.New("ERROR"),
+			wantRuns: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			clock := newTestClock(mustTime(t, "2026-01-01T10:02:00Z"))
+			m := &testutil.EventMetrics{}
+			store := newTestStore()
+			store.err = tt.storeErr
+
+			if tt.lastRun != "" {
+				store.last["job"] = mustTime(t, tt.lastRun)
+			}
+
+			s, err := New(WithLocation(time.UTC), WithClock(clock), WithMetrics(m), WithStore(store))
+			require.NoError(t, err)
+
+			err = s.Add("job", "*/5 * * * *", time.Second, func(_ context.Context) {}, WithCatchUp(tt.policy))
+			require.NoError(t, err)
+
+			require.NoError(t, s.Start(context.Background()))
+
+			require.Eventually(t, func() bool { return clock.waiting() == 1 }, time.Second, time.Millisecond)
+			require.Eventually(t, func() bool { return m.Count(outcomeSuccess) == tt.wantRuns }, time.Second, time.Millisecond)
+
+			s.Stop()
+
+			require.Equal(t, tt.wantRuns, m.Count(outcomeSuccess))
+			require.Equal(t, tt.wantMissed, m.Count(outcomeMissed))
+
+			if tt.storeErr != nil {
+				require.Equal(t, 1, m.Count(outcomeStoreError))
+			}
+		})
+	}
+}
+
+func TestScheduler_addAfterStart(t *testing.T) {
+	t.Parallel()
+
+	clock := newTestClock(mustTime(t, "2026-01-01T10:00:00Z"))
+
+	s, err := New(WithLocation(time.UTC), WithClock(clock))
+	require.NoError(t, err)
+
+	require.NoError(t, s.Start(context.Background()))
+	defer s.Stop()
+
+	runs := make(chan struct{}, 1)
+
+	err = s.Add("job", "* * * * *", time.Second, func(_ context.Context) { runs <- struct{}{} })
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool { return clock.waiting() == 1 }, time.Second, time.Millisecond)
+	clock.Set(mustTime(t, "2026-01-01T10:01:00Z"))
+
+	<-runs
+}
+
+func TestJob_exec(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		task func(context.Context)
+		want string
+	}{
+		{
+			name: "success",
+			task: func(_ context.Context) {},
+			want: outcomeSuccess,
+		},
+		{
+			name: "timeout",
+			task: func(ctx context.Context) { <-ctx.Done() },
+			want: outcomeTimeout,
+		},
+		{
+			name: "panic",
+			task: func(_ context.Context) { panic("PANIC") },
+			want: outcomePanic,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			j := &job{timeout: 10 * time.Millisecond, task: tt.task}
+			require.Equal(t, tt.want, j.exec(context.Background()))
+		})
+	}
+}
+
+func TestJob_exec_panicLog(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zap.ErrorLevel)
+	ctx := logging.WithLogger(context.Background(), zap.New(core))
+
+	j := &job{timeout: time.Second, task: func(_ context.Context) { panic("PANIC") }}
+	require.Equal(t, outcomePanic, j.exec(ctx))
+
+	entries := logs.All()
+	require.Len(t, entries, 1)
+	require.Equal(t, "job panic", entries[0].Message)
+	require.Equal(t, "PANIC", entries[0].ContextMap()["err"])
+	require.Contains(t, entries[0].ContextMap()["stacktrace"], "runtime/debug.Stack")
+}
+
+func TestScheduler_startTwice(t *testing.T) {
+	t.Parallel()
+
+	s, err := New()
+	require.NoError(t, err)
+
+	require.NoError(t, s.Start(context.Background()))
+	defer s.Stop()
+
+	require.Error(t, s.Start(context.Background()))
+}
@@ -0,0 +1,29 @@
+package cron_test
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/cron"
+)
+
+func ExampleParse() {
+	// every day at 02:00 London time
+	s, err := cron.Parse("CRON_TZ=Europe/London 0 2 * * *")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	t := time.Date(2026, 3, 28, 12, 0, 0, 0, time.UTC)
+
+	for range 3 {
+		t = s.Next(t)
+		fmt.Println(t)
+	}
+
+	// Output:
+	// 2026-03-29 02:00:00 +0100 BST
+	// 2026-03-30 02:00:00 +0100 BST
+	// 2026-03-31 02:00:00 +0100 BST
+}
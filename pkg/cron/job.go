@@ -0,0 +1,137 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"runtime/debug"
+	"sync/atomic"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/logging"
+	"github.com/Vonage/gosrvlib/pkg/periodic"
+	"go.uber.org/zap"
+)
+
+// OverlapPolicy defines what happens when a job is still running at the next activation time.
+type OverlapPolicy int
+
+const (
+	// OverlapSkip skips the new run (default).
+	OverlapSkip OverlapPolicy = iota
+
+	// OverlapQueue queues the new run, to be executed when the previous one completes.
+	// The maximum number of queued runs can be set with WithMaxQueued; additional runs are skipped.
+	OverlapQueue
+)
+
+// CatchUpPolicy defines what happens with the activations missed while the service was down.
+// The missed activations are detected from the last run time persisted in the Store (see WithStore).
+type CatchUpPolicy int
+
+const (
+	// CatchUpNone ignores the missed activations (default).
+	CatchUpNone CatchUpPolicy = iota
+
+	// CatchUpOnce executes a single run if one or more activations were missed.
+	CatchUpOnce
+
+	// CatchUpAll executes one run for each missed activation, up to the 100 most recent ones.
+	CatchUpAll
+)
+
+// job is a named task registered in the Scheduler.
+type job struct {
+	name      string
+	schedule  *Schedule
+	timeout   time.Duration
+	task      periodic.TaskFn
+	location  *time.Location
+	overlap   OverlapPolicy
+	maxQueued uint
+	catchUp   CatchUpPolicy
+	runs      chan time.Time
+	pending   atomic.Int64 // Number of dispatched runs not yet completed.
+}
+
+// maxPending returns the maximum number of dispatched runs not yet completed, including the running one.
+func (j *job) maxPending() int64 {
+	if j.overlap == OverlapQueue {
+		return int64(j.maxQueued) + 1 //nolint:gosec
+	}
+
+	return 1
+}
+
+// reserve returns true if a new run can be dispatched according to the overlap policy.
+func (j *job) reserve() bool {
+	for {
+		p := j.pending.Load()
+		if p >= j.maxPending() {
+			return false
+		}
+
+		if j.pending.CompareAndSwap(p, p+1) {
+			return true
+		}
+	}
+}
+
+// due returns the activation times after last and up to now (included),
+// limited to the most recent ones that can be caught up.
+func (j *job) due(last, now time.Time) []time.Time {
+	var due []time.Time
+
+	for t := j.schedule.Next(last.In(j.location)); !t.IsZero() && !t.After(now); t = j.schedule.Next(t) {
+		if len(due) > maxCatchUpRuns {
+			due = due[1:]
+		}
+
+		due = append(due, t)
+	}
+
+	return due
+}
+
+// catchUpRuns returns the missed activations to execute according to the catch-up policy.
+// A single missed activation is not needed when a regular run is due.
+func (j *job) catchUpRuns(missed []time.Time, regular bool) []time.Time {
+	switch j.catchUp {
+	case CatchUpOnce:
+		if regular || len(missed) == 0 {
+			return nil
+		}
+
+		return missed[len(missed)-1:]
+	case CatchUpAll:
+		return missed[max(0, len(missed)-maxCatchUpRuns):]
+	case CatchUpNone:
+	}
+
+	return nil
+}
+
+// exec executes the task with the job timeout and returns the outcome.
+func (j *job) exec(ctx context.Context) (outcome string) {
+	tctx, cancel := context.WithTimeout(ctx, j.timeout)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			outcome = outcomePanic
+
+			logging.FromContext(ctx).Error(
+				"job panic",
+				zap.Any("err", r),
+				zap.String("stacktrace", string(debug.Stack())),
+			)
+		}
+	}()
+
+	j.task(tctx)
+
+	if errors.Is(tctx.Err(), context.DeadlineExceeded) {
+		return outcomeTimeout
+	}
+
+	return outcomeSuccess
+}
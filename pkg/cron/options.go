@@ -0,0 +1,141 @@
+package cron
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/metrics"
+	"github.com/Vonage/gosrvlib/pkg/retrier"
+	"go.uber.org/zap"
+)
+
+// Option is the interface that allows to set the Scheduler options.
+type Option func(s *Scheduler) error
+
+// JobOption is the interface that allows to set the options of a single job.
+type JobOption func(j *job) error
+
+// WithLocation sets the default time zone of the cron expressions without the CRON_TZ= prefix.
+// The default is the local time zone.
+func WithLocation(loc *time.Location) Option {
+	return func(s *Scheduler) error {
+		if loc == nil {
+			return errors.New("the location is required")
+		}
+
+		s.location = loc
+
+		return nil
+	}
+}
+
+// WithLogger sets the logger used to log the job runs.
+// The logger is also passed to the tasks via context, with the job name field.
+func WithLogger(l *zap.Logger) Option {
+	return func(s *Scheduler) error {
+		if l == nil {
+			return errors.New("the logger is required")
+		}
+
+		s.logger = l
+
+		return nil
+	}
+}
+
+// WithMetrics sets the metrics client used to count the job runs.
+func WithMetrics(m metrics.Client) Option {
+	return func(s *Scheduler) error {
+		if m == nil {
+			return errors.New("the metrics client is required")
+		}
+
+		s.metrics = m
+
+		return nil
+	}
+}
+
+// WithStore sets the store used to persist the time of the last run of each job.
+// It is required to catch up the activations missed while the service was down (see WithCatchUp).
+func WithStore(store Store) Option {
+	return func(s *Scheduler) error {
+		if store == nil {
+			return errors.New("the store is required")
+		}
+
+		s.store = store
+
+		return nil
+	}
+}
+
+// WithClock sets the clock used to get the current time and wait for the next activation.
+// This is mainly useful to inject a deterministic clock in tests.
+func WithClock(clock retrier.Clock) Option {
+	return func(s *Scheduler) error {
+		if clock == nil {
+			return errors.New("the clock is required")
+		}
+
+		s.clock = clock
+
+		return nil
+	}
+}
+
+// WithJobLocation sets the time zone of the job cron expression.
+// The CRON_TZ= prefix in the cron expression takes precedence.
+func WithJobLocation(loc *time.Location) JobOption {
+	return func(j *job) error {
+		if loc == nil {
+			return errors.New("the location is required")
+		}
+
+		if j.location == nil {
+			j.location = loc
+		}
+
+		return nil
+	}
+}
+
+// WithOverlapPolicy sets what happens when the job is still running at the next activation time.
+func WithOverlapPolicy(policy OverlapPolicy) JobOption {
+	return func(j *job) error {
+		if policy != OverlapSkip && policy != OverlapQueue {
+			return errors.New("invalid overlap policy")
+		}
+
+		j.overlap = policy
+
+		return nil
+	}
+}
+
+// WithMaxQueued sets the maximum number of runs queued with the OverlapQueue policy.
+// The default is 1.
+func WithMaxQueued(n uint) JobOption {
+	return func(j *job) error {
+		if n < 1 {
+			return errors.New("the maximum number of queued runs must be at least 1")
+		}
+
+		j.maxQueued = n
+
+		return nil
+	}
+}
+
+// WithCatchUp sets what happens with the activations missed while the service was down.
+func WithCatchUp(policy CatchUpPolicy) JobOption {
+	return func(j *job) error {
+		if policy < CatchUpNone || policy > CatchUpAll {
+			return errors.New("invalid catch-up policy")
+		}
+
+		j.catchUp = policy
+
+		return nil
+	}
+}
@@ -0,0 +1,139 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/metrics"
+	"github.com/Vonage/gosrvlib/pkg/retrier"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestWithLocation(t *testing.T) {
+	t.Parallel()
+
+	s := &Scheduler{}
+
+	err := WithLocation(time.UTC)(s)
+	require.NoError(t, err)
+	require.Equal(t, time.UTC, s.location)
+
+	err = WithLocation(nil)(s)
+	require.Error(t, err)
+}
+
+func TestWithLogger(t *testing.T) {
+	t.Parallel()
+
+	s := &Scheduler{}
+	l := zap.NewNop()
+
+	err := WithLogger(l)(s)
+	require.NoError(t, err)
+	require.Equal(t, l, s.logger)
+
+	err = WithLogger(nil)(s)
+	require.Error(t, err)
+}
+
+func TestWithMetrics(t *testing.T) {
+	t.Parallel()
+
+	s := &Scheduler{}
+	m := &metrics.Default{}
+
+	err := WithMetrics(m)(s)
+	require.NoError(t, err)
+	require.Equal(t, m, s.metrics)
+
+	err = WithMetrics(nil)(s)
+	require.Error(t, err)
+}
+
+func TestWithStore(t *testing.T) {
+	t.Parallel()
+
+	s := &Scheduler{}
+	st := newTestStore()
+
+	err := WithStore(st)(s)
+	require.NoError(t, err)
+	require.Equal(t, st, s.store)
+
+	err = WithStore(nil)(s)
+	require.Error(t, err)
+}
+
+func TestWithClock(t *testing.T) {
+	t.Parallel()
+
+	s := &Scheduler{}
+	c := retrier.SystemClock{}
+
+	err := WithClock(c)(s)
+	require.NoError(t, err)
+	require.Equal(t, c, s.clock)
+
+	err = WithClock(nil)(s)
+	require.Error(t, err)
+}
+
+func TestWithJobLocation(t *testing.T) {
+	t.Parallel()
+
+	j := &job{}
+
+	err := WithJobLocation(time.UTC)(j)
+	require.NoError(t, err)
+	require.Equal(t, time.UTC, j.location)
+
+	loc, err := time.LoadLocation("Europe/London")
+	require.NoError(t, err)
+
+	err = WithJobLocation(loc)(j)
+	require.NoError(t, err)
+	require.Equal(t, time.UTC, j.location, "the CRON_TZ location takes precedence")
+
+	err = WithJobLocation(nil)(j)
+	require.Error(t, err)
+}
+
+func TestWithOverlapPolicy(t *testing.T) {
+	t.Parallel()
+
+	j := &job{}
+
+	err := WithOverlapPolicy(OverlapQueue)(j)
+	require.NoError(t, err)
+	require.Equal(t, OverlapQueue, j.overlap)
+
+	err = WithOverlapPolicy(OverlapPolicy(-1))(j)
+	require.Error(t, err)
+}
+
+func TestWithMaxQueued(t *testing.T) {
+	t.Parallel()
+
+	j := &job{}
+
+	err := WithMaxQueued(3)(j)
+	require.NoError(t, err)
+	require.Equal(t, uint(3), j.maxQueued)
+
+	err = WithMaxQueued(0)(j)
+	require.Error(t, err)
+}
+
+func TestWithCatchUp(t *testing.T) {
+	t.Parallel()
+
+	j := &job{}
+
+	err := WithCatchUp(CatchUpAll)(j)
+	require.NoError(t, err)
+	require.Equal(t, CatchUpAll, j.catchUp)
+
+	err = WithCatchUp(CatchUpPolicy(3))(j)
+	require.Error(t, err)
+}
@@ -0,0 +1,402 @@
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSearchYears is the maximum number of years searched for the next activation time.
+// It covers the schedules matching only on leap days falling on a specific weekday.
+const maxSearchYears = 30
+
+// locationPrefixes are the prefixes used to set the time zone in the cron expression.
+var locationPrefixes = []string{"CRON_TZ=", "TZ="} //nolint:gochecknoglobals
+
+// descriptors are the predefined schedules.
+var descriptors = map[string]string{ //nolint:gochecknoglobals
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// bounds contains the limits and the names of the values of a cron field.
+type bounds struct {
+	name  string
+	min   uint
+	max   uint
+	names map[string]uint
+}
+
+//nolint:gochecknoglobals
+var (
+	minuteBounds = bounds{name: "minute", min: 0, max: 59}
+	hourBounds   = bounds{name: "hour", min: 0, max: 23}
+	domBounds    = bounds{name: "day of month", min: 1, max: 31}
+	monthBounds  = bounds{
+		name: "month",
+		min:  1,
+		max:  12,
+		names: map[string]uint{
+			"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+			"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+		},
+	}
+	dowBounds = bounds{
+		name: "day of week",
+		min:  0,
+		max:  7, // both 0 and 7 are Sunday
+		names: map[string]uint{
+			"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+		},
+	}
+)
+
+// bitset contains the allowed values of a cron field.
+type bitset uint64
+
+func (b bitset) has(v int) bool {
+	return b&(1<<uint(v)) != 0 //nolint:gosec
+}
+
+// Schedule is a parsed cron expression.
+type Schedule struct {
+	minute bitset
+	hour   bitset
+	dom    bitset
+	month  bitset
+	dow    bitset
+
+	// domStar and dowStar are true when the day fields start with a wildcard.
+	// When both day fields are restricted, the day matches if either field matches.
+	domStar bool
+	dowStar bool
+
+	// fixedTime is true when neither the minute nor the hour fields start with a wildcard.
+	// Fixed-time jobs run once on the days when the clocks change for the daylight saving time.
+	fixedTime bool
+
+	location *time.Location
+}
+
+// Parse parses a standard cron expression with five space-separated fields:
+//
+//	┌───────────── minute (0-59)
+//	│ ┌───────────── hour (0-23)
+//	│ │ ┌───────────── day of month (1-31)
+//	│ │ │ ┌───────────── month (1-12 or JAN-DEC)
+//	│ │ │ │ ┌───────────── day of week (0-7 or SUN-SAT, both 0 and 7 are Sunday)
+//	│ │ │ │ │
+//	* * * * *
+//
+// Each field accepts a wildcard (*), values, ranges (1-5), lists (1,3,5) and steps (*/15, 0-30/10).
+// When both the day of month and the day of week are restricted, the day matches if either field matches.
+//
+// The predefined schedules @yearly (or @annually), @monthly, @weekly, @daily (or @midnight) and @hourly
+// are also accepted.
+//
+// The time zone can be set with the CRON_TZ= (or TZ=) prefix and an IANA location name,
+// e.g. "CRON_TZ=Europe/London 0 2 * * *" for every day at 02:00 London time.
+func Parse(spec string) (*Schedule, error) {
+	s := &Schedule{}
+
+	spec = strings.TrimSpace(spec)
+
+	for _, prefix := range locationPrefixes {
+		if !strings.HasPrefix(spec, prefix) {
+			continue
+		}
+
+		name, rest, _ := strings.Cut(strings.TrimPrefix(spec, prefix), " ")
+
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time zone %q: %w", name, err)
+		}
+
+		s.location = loc
+		spec = strings.TrimSpace(rest)
+
+		break
+	}
+
+	if d, ok := descriptors[strings.ToLower(spec)]; ok {
+		spec = d
+	}
+
+	f := strings.Fields(spec)
+	if len(f) != 5 {
+		return nil, fmt.Errorf("expected 5 fields, found %d: %q", len(f), spec)
+	}
+
+	if err := s.parseFields(f); err != nil {
+		return nil, err
+	}
+
+	s.domStar = strings.HasPrefix(f[2], "*")
+	s.dowStar = strings.HasPrefix(f[4], "*")
+	s.fixedTime = !strings.HasPrefix(f[0], "*") && !strings.HasPrefix(f[1], "*")
+
+	return s, nil
+}
+
+func (s *Schedule) parseFields(f []string) error {
+	var err error
+
+	if s.minute, err = parseField(f[0], minuteBounds); err != nil {
+		return err
+	}
+
+	if s.hour, err = parseField(f[1], hourBounds); err != nil {
+		return err
+	}
+
+	if s.dom, err = parseField(f[2], domBounds); err != nil {
+		return err
+	}
+
+	if s.month, err = parseField(f[3], monthBounds); err != nil {
+		return err
+	}
+
+	if s.dow, err = parseField(f[4], dowBounds); err != nil {
+		return err
+	}
+
+	if s.dow.has(7) {
+		s.dow |= 1 // Sunday
+	}
+
+	return nil
+}
+
+// parseField parses a comma-separated list of values, ranges and steps.
+func parseField(expr string, b bounds) (bitset, error) {
+	var bits bitset
+
+	for _, part := range strings.Split(expr, ",") {
+		v, err := parseRange(part, b)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s %q: %w", b.name, expr, err)
+		}
+
+		bits |= v
+	}
+
+	return bits, nil
+}
+
+// parseRange parses a single value, range or wildcard with an optional step.
+func parseRange(expr string, b bounds) (bitset, error) {
+	rangeExpr, stepExpr, hasStep := strings.Cut(expr, "/")
+
+	var (
+		start, end uint
+		err        error
+	)
+
+	switch lo, hi, isRange := strings.Cut(rangeExpr, "-"); {
+	case rangeExpr == "*":
+		start, end = b.min, b.max
+	case isRange:
+		if start, err = parseValue(lo, b); err != nil {
+			return 0, err
+		}
+
+		if end, err = parseValue(hi, b); err != nil {
+			return 0, err
+		}
+	default:
+		if start, err = parseValue(rangeExpr, b); err != nil {
+			return 0, err
+		}
+
+		end = start
+
+		if hasStep {
+			end = b.max
+		}
+	}
+
+	if start > end {
+		return 0, fmt.Errorf("range start %d is greater than the end %d", start, end)
+	}
+
+	step := uint(1)
+
+	if hasStep {
+		if step, err = parseUint(stepExpr); err != nil {
+			return 0, err
+		}
+
+		if step == 0 {
+			return 0, errors.New("the step must be greater than zero")
+		}
+	}
+
+	var bits bitset
+
+	for v := start; v <= end; v += step {
+		bits |= 1 << v
+	}
+
+	return bits, nil
+}
+
+func parseValue(expr string, b bounds) (uint, error) {
+	if v, ok := b.names[strings.ToLower(expr)]; ok {
+		return v, nil
+	}
+
+	v, err := parseUint(expr)
+	if err != nil {
+		return 0, err
+	}
+
+	if v < b.min || v > b.max {
+		return 0, fmt.Errorf("value %d out of range [%d, %d]", v, b.min, b.max)
+	}
+
+	return v, nil
+}
+
+func parseUint(expr string) (uint, error) {
+	v, err := strconv.ParseUint(expr, 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", expr)
+	}
+
+	return uint(v), nil
+}
+
+// Location returns the time zone set in the cron expression, or nil if not set.
+func (s *Schedule) Location() *time.Location {
+	return s.location
+}
+
+// Next returns the first activation time strictly after t,
+// or the zero time if the schedule never activates (e.g. on the 30th of February).
+//
+// The schedule is evaluated in the time zone set in the cron expression, or in the location of t.
+// The daylight saving time changes are handled like the traditional cron implementations:
+//   - fixed-time jobs (with no wildcard in the minute and hour fields) scheduled in the hour skipped
+//     when the clocks go forward run at the time of the change, and the ones scheduled in the hour
+//     repeated when the clocks go back run only once;
+//   - the other jobs run at the matching times that exist on the clock, so they are not executed
+//     in the skipped hour and are executed twice in the repeated hour.
+func (s *Schedule) Next(t time.Time) time.Time {
+	loc := s.location
+	if loc == nil {
+		loc = t.Location()
+	}
+
+	t = t.In(loc).Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(maxSearchYears, 0, 0)
+
+	for t.Before(limit) {
+		if s.matchGap(t) {
+			return t
+		}
+
+		next := s.advance(t, loc)
+		if next.IsZero() {
+			return t
+		}
+
+		if !next.After(t) {
+			// guarantee the progress on unusual time zone transitions
+			next = t.Add(time.Minute)
+		}
+
+		t = next
+	}
+
+	return time.Time{}
+}
+
+// advance returns the next candidate time after t, or the zero time if t matches the schedule.
+func (s *Schedule) advance(t time.Time, loc *time.Location) time.Time {
+	switch {
+	case !s.month.has(int(t.Month())):
+		return time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+	case !s.matchDay(t):
+		return time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+	case !s.hour.has(t.Hour()):
+		// the instant arithmetic preserves the correct offset in the repeated hour
+		return t.Add(time.Duration(60-t.Minute()) * time.Minute)
+	case !s.minute.has(t.Minute()), s.repeated(t):
+		return t.Add(time.Minute)
+	}
+
+	return time.Time{}
+}
+
+func (s *Schedule) matchDay(t time.Time) bool {
+	dom := s.dom.has(t.Day())
+	dow := s.dow.has(int(t.Weekday()))
+
+	if s.domStar || s.dowStar {
+		return dom && dow
+	}
+
+	return dom || dow
+}
+
+func (s *Schedule) matchWall(w time.Time) bool {
+	return s.month.has(int(w.Month())) &&
+		s.matchDay(w) &&
+		s.hour.has(w.Hour()) &&
+		s.minute.has(w.Minute())
+}
+
+// matchGap returns true if t is the instant when the clocks went forward
+// and a fixed-time activation falls in the skipped wall clock interval.
+func (s *Schedule) matchGap(t time.Time) bool {
+	if !s.fixedTime {
+		return false
+	}
+
+	_, offset := t.Zone()
+	_, prevOffset := t.Add(-time.Minute).Zone()
+
+	gap := offset - prevOffset
+	if gap <= 0 {
+		return false
+	}
+
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, time.UTC)
+
+	for m := 1; m*60 <= gap; m++ {
+		if s.matchWall(wall.Add(-time.Duration(m) * time.Minute)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// repeated returns true if t is a fixed-time activation
+// in the second occurrence of a wall clock time repeated when the clocks went back.
+func (s *Schedule) repeated(t time.Time) bool {
+	if !s.fixedTime {
+		return false
+	}
+
+	_, offset := t.Zone()
+	_, prevOffset := t.Add(-3 * time.Hour).Zone() // longer than any daylight saving time shift
+
+	shift := prevOffset - offset
+	if shift <= 0 {
+		return false
+	}
+
+	_, firstOffset := t.Add(-time.Duration(shift) * time.Second).Zone()
+
+	return firstOffset-offset == shift
+}
@@ -0,0 +1,213 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		spec    string
+		wantLoc string
+		wantErr bool
+	}{
+		{name: "wildcards", spec: "* * * * *"},
+		{name: "values, ranges, lists and steps", spec: "0,30 */2 1-15/7 1-6 1-5"},
+		{name: "names", spec: "0 0 * jan-MAR Mon,fri"},
+		{name: "sunday as 7", spec: "0 0 * * 7"},
+		{name: "value with step", spec: "5/15 * * * *"},
+		{name: "descriptor", spec: "@daily"},
+		{name: "descriptor uppercase", spec: "@HOURLY"},
+		{name: "time zone", spec: "CRON_TZ=Europe/London 0 2 * * *", wantLoc: "Europe/London"},
+		{name: "short time zone prefix", spec: "TZ=America/New_York @weekly", wantLoc: "America/New_York"},
+		{name: "invalid time zone", spec: "CRON_TZ=Invalid/Zone 0 2 * * *", wantErr: true},
+		{name: "empty", spec: "", wantErr: true},
+		{name: "too few fields", spec: "* * * *", wantErr: true},
+		{name: "too many fields", spec: "0 * * * * *", wantErr: true},
+		{name: "unknown descriptor", spec: "@every", wantErr: true},
+		{name: "minute out of range", spec: "60 * * * *", wantErr: true},
+		{name: "hour out of range", spec: "* 24 * * *", wantErr: true},
+		{name: "day of month out of range", spec: "* * 0 * *", wantErr: true},
+		{name: "month out of range", spec: "* * * 13 *", wantErr: true},
+		{name: "day of week out of range", spec: "* * * * 8", wantErr: true},
+		{name: "invalid name", spec: "* * * foo *", wantErr: true},
+		{name: "invalid number", spec: "x * * * *", wantErr: true},
+		{name: "inverted range", spec: "30-10 * * * *", wantErr: true},
+		{name: "invalid range end", spec: "10-x * * * *", wantErr: true},
+		{name: "zero step", spec: "*/0 * * * *", wantErr: true},
+		{name: "invalid step", spec: "*/x * * * *", wantErr: true},
+		{name: "empty list item", spec: "1,,2 * * * *", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := Parse(tt.spec)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				require.Nil(t, s)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, s)
+
+			if tt.wantLoc == "" {
+				require.Nil(t, s.Location())
+
+				return
+			}
+
+			require.Equal(t, tt.wantLoc, s.Location().String())
+		})
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		spec string
+		from string
+		want []string
+	}{
+		{
+			name: "every five minutes",
+			spec: "*/5 * * * *",
+			from: "2026-01-01T10:02:30Z",
+			want: []string{"2026-01-01T10:05:00Z", "2026-01-01T10:10:00Z"},
+		},
+		{
+			name: "exact time excluded",
+			spec: "0 * * * *",
+			from: "2026-01-01T10:00:00Z",
+			want: []string{"2026-01-01T11:00:00Z", "2026-01-01T12:00:00Z"},
+		},
+		{
+			name: "end of year",
+			spec: "@yearly",
+			from: "2026-06-15T00:00:00Z",
+			want: []string{"2027-01-01T00:00:00Z", "2028-01-01T00:00:00Z"},
+		},
+		{
+			name: "last days of month",
+			spec: "0 12 31 * *",
+			from: "2026-01-31T12:00:00Z",
+			want: []string{"2026-03-31T12:00:00Z", "2026-05-31T12:00:00Z"},
+		},
+		{
+			name: "leap day",
+			spec: "0 0 29 2 *",
+			from: "2026-01-01T00:00:00Z",
+			want: []string{"2028-02-29T00:00:00Z", "2032-02-29T00:00:00Z"},
+		},
+		{
+			name: "day of month or day of week",
+			spec: "0 0 1,15 * mon",
+			from: "2026-01-01T00:00:00Z",
+			want: []string{"2026-01-05T00:00:00Z", "2026-01-12T00:00:00Z", "2026-01-15T00:00:00Z"},
+		},
+		{
+			name: "weekdays",
+			spec: "30 8 * * mon-fri",
+			from: "2026-01-02T09:00:00Z", // Friday
+			want: []string{"2026-01-05T08:30:00Z", "2026-01-06T08:30:00Z"},
+		},
+		{
+			name: "sunday as 7",
+			spec: "0 0 * * 7",
+			from: "2026-01-01T00:00:00Z",
+			want: []string{"2026-01-04T00:00:00Z", "2026-01-11T00:00:00Z"},
+		},
+		{
+			name: "time zone",
+			spec: "CRON_TZ=Europe/London 0 2 * * *",
+			from: "2026-07-01T00:00:00Z",
+			want: []string{"2026-07-01T01:00:00Z", "2026-07-02T01:00:00Z"},
+		},
+		{
+			name: "fixed time in the skipped hour runs at the change",
+			spec: "CRON_TZ=Europe/London 30 1 * * *",
+			from: "2026-03-28T12:00:00Z",
+			want: []string{"2026-03-29T01:00:00Z", "2026-03-30T00:30:00Z"},
+		},
+		{
+			name: "fixed time in the skipped hour in New York",
+			spec: "CRON_TZ=America/New_York 0 2 * * *",
+			from: "2026-03-07T12:00:00Z",
+			want: []string{"2026-03-08T07:00:00Z", "2026-03-09T06:00:00Z"},
+		},
+		{
+			name: "interval skips the missing hour",
+			spec: "CRON_TZ=Europe/London */30 * * * *",
+			from: "2026-03-29T00:20:00Z",
+			want: []string{"2026-03-29T00:30:00Z", "2026-03-29T01:00:00Z", "2026-03-29T01:30:00Z"},
+		},
+		{
+			name: "fixed time in the repeated hour runs once",
+			spec: "CRON_TZ=Europe/London 30 1 * * *",
+			from: "2026-10-24T12:00:00Z",
+			want: []string{"2026-10-25T00:30:00Z", "2026-10-26T01:30:00Z"},
+		},
+		{
+			name: "interval runs in both the repeated hours",
+			spec: "CRON_TZ=Europe/London */30 * * * *",
+			from: "2026-10-25T00:20:00Z",
+			want: []string{
+				"2026-10-25T00:30:00Z",
+				"2026-10-25T01:00:00Z",
+				"2026-10-25T01:30:00Z",
+				"2026-10-25T02:00:00Z",
+			},
+		},
+		{
+			name: "never",
+			spec: "0 0 30 2 *",
+			from: "2026-01-01T00:00:00Z",
+			want: []string{"0001-01-01T00:00:00Z"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := Parse(tt.spec)
+			require.NoError(t, err)
+
+			from, err := time.Parse(time.RFC3339, tt.from)
+			require.NoError(t, err)
+
+			for _, w := range tt.want {
+				want, err := time.Parse(time.RFC3339, w)
+				require.NoError(t, err)
+
+				from = s.Next(from)
+				require.True(t, want.Equal(from), "want %v, got %v", want, from.UTC())
+			}
+		})
+	}
+}
+
+func TestSchedule_Next_location(t *testing.T) {
+	t.Parallel()
+
+	s, err := Parse("0 2 * * *")
+	require.NoError(t, err)
+
+	loc, err := time.LoadLocation("Asia/Kolkata")
+	require.NoError(t, err)
+
+	got := s.Next(time.Date(2026, 1, 1, 0, 0, 0, 0, loc))
+	require.Equal(t, time.Date(2026, 1, 1, 2, 0, 0, 0, loc), got)
+	require.Equal(t, loc, got.Location())
+}
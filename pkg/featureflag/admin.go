@@ -0,0 +1,80 @@
+package featureflag
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Vonage/gosrvlib/pkg/httpserver"
+	"github.com/Vonage/gosrvlib/pkg/httputil"
+)
+
+// maxEvalContextSize is the maximum size of the EvalContext request body of the admin route.
+const maxEvalContextSize = 1 << 20
+
+// AdminRoutes returns the HTTP routes to inspect the flags state:
+//
+//   - GET /flags returns the flag definitions currently in use and the time of the last refresh;
+//   - GET /flags/:key returns a flag definition;
+//   - POST /flags/:key/evaluate evaluates a flag for the EvalContext in the JSON request body,
+//     without counting the evaluation in the metrics.
+//
+// The routes should be mounted with an httpserver.RouteGroup prefix and protected by an authorization middleware.
+func (c *Client) AdminRoutes() []httpserver.Route {
+	return []httpserver.Route{
+		{
+			Method:      http.MethodGet,
+			Path:        "/flags",
+			Description: "Returns the feature flags state.",
+			Response:    &State{},
+			Handler:     c.handleState,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/flags/:key",
+			Description: "Returns a feature flag definition.",
+			Response:    &Flag{},
+			Handler:     c.handleFlag,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/flags/:key/evaluate",
+			Description: "Evaluates a feature flag.",
+			Request:     &EvalContext{},
+			Response:    &Evaluation{},
+			Handler:     c.handleEvaluate,
+		},
+	}
+}
+
+func (c *Client) handleState(w http.ResponseWriter, r *http.Request) {
+	httputil.SendJSON(r.Context(), w, http.StatusOK, c.State())
+}
+
+func (c *Client) handleFlag(w http.ResponseWriter, r *http.Request) {
+	f, ok := c.Flag(httputil.PathParam(r, "key"))
+	if !ok {
+		httputil.SendStatus(r.Context(), w, http.StatusNotFound)
+		return
+	}
+
+	httputil.SendJSON(r.Context(), w, http.StatusOK, f)
+}
+
+func (c *Client) handleEvaluate(w http.ResponseWriter, r *http.Request) {
+	key := httputil.PathParam(r, "key")
+
+	if _, ok := c.Flag(key); !ok {
+		httputil.SendStatus(r.Context(), w, http.StatusNotFound)
+		return
+	}
+
+	ec := &EvalContext{}
+
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxEvalContextSize)).Decode(ec); err != nil {
+		httputil.SendStatus(r.Context(), w, http.StatusBadRequest)
+		return
+	}
+
+	// the admin evaluations are not counted in the production metrics
+	httputil.SendJSON(r.Context(), w, http.StatusOK, c.evaluate(key, ec))
+}
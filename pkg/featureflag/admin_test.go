@@ -0,0 +1,148 @@
+package featureflag
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Vonage/gosrvlib/pkg/filter"
+	"github.com/Vonage/gosrvlib/pkg/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_AdminRoutes(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t,
+		Flag{Key: "alpha", Enabled: true},
+		Flag{
+			Key:            "beta",
+			Enabled:        true,
+			DefaultVariant: VariantOff,
+			Rules: []TargetingRule{{
+				Name:    "uk",
+				Match:   [][]filter.Rule{{{Field: "country", Type: filter.TypeEqual, Value: "GB"}}},
+				Variant: VariantOn,
+			}},
+		},
+	)
+
+	tests := []struct {
+		name       string
+		method     string
+		route      string
+		path       string
+		key        string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "state",
+			method:     http.MethodGet,
+			route:      "/flags",
+			path:       "/flags",
+			wantStatus: http.StatusOK,
+			wantBody:   `"key":"alpha"`,
+		},
+		{
+			name:       "flag",
+			method:     http.MethodGet,
+			route:      "/flags/:key",
+			path:       "/flags/beta",
+			key:        "beta",
+			wantStatus: http.StatusOK,
+			wantBody:   `"default_variant":"off"`,
+		},
+		{
+			name:       "flag not found",
+			method:     http.MethodGet,
+			route:      "/flags/:key",
+			path:       "/flags/missing",
+			key:        "missing",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "evaluate",
+			method:     http.MethodPost,
+			route:      "/flags/:key/evaluate",
+			path:       "/flags/beta/evaluate",
+			key:        "beta",
+			body:       `{"key":"user","attributes":{"country":"GB"}}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `"reason":"targeting_match"`,
+		},
+		{
+			name:       "evaluate not found",
+			method:     http.MethodPost,
+			route:      "/flags/:key/evaluate",
+			path:       "/flags/missing/evaluate",
+			key:        "missing",
+			body:       `{}`,
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "evaluate invalid body",
+			method:     http.MethodPost,
+			route:      "/flags/:key/evaluate",
+			path:       "/flags/beta/evaluate",
+			key:        "beta",
+			body:       `{`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	routes := make(map[string]http.HandlerFunc)
+	for _, r := range c.AdminRoutes() {
+		routes[r.Method+" "+r.Path] = r.Handler
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler, ok := routes[tt.method+" "+tt.route]
+			require.True(t, ok)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.SetPathValue("key", tt.key)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			resp := rr.Result()
+			defer func() { _ = resp.Body.Close() }()
+
+			require.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			if tt.wantBody != "" {
+				require.Contains(t, rr.Body.String(), tt.wantBody)
+				require.True(t, json.Valid(rr.Body.Bytes()))
+			}
+		})
+	}
+}
+
+func TestClient_handleEvaluate_metrics(t *testing.T) {
+	t.Parallel()
+
+	m := &testutil.EventMetrics{}
+
+	c := newTestClient(t, Flag{Key: "alpha", Enabled: true})
+	c.metrics = m
+
+	req := httptest.NewRequest(http.MethodPost, "/flags/alpha/evaluate", strings.NewReader(`{"key":"user"}`))
+	req.SetPathValue("key", "alpha")
+
+	rr := httptest.NewRecorder()
+	c.handleEvaluate(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Contains(t, rr.Body.String(), `"reason":"default"`)
+	require.Equal(t, 0, m.Count(ReasonDefault), "the admin evaluations are not counted")
+
+	c.Evaluate("alpha", &EvalContext{Key: "user"})
+	require.Equal(t, 1, m.Count(ReasonDefault))
+}
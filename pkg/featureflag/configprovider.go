@@ -0,0 +1,43 @@
+package featureflag
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// ConfigProvider is a Provider of flags defined in the application configuration.
+//
+// The flags can be added to the configuration structure loaded by the config package:
+//
+//	type appConfig struct {
+//		FeatureFlags []featureflag.Flag `mapstructure:"feature_flags"`
+//	}
+//
+// To apply the changes without restarting the program,
+// update the provider and refresh the Client from a config.Watcher subscriber:
+//
+//	w.Subscribe(func(_, newCfg *appConfig) {
+//		provider.Set(newCfg.FeatureFlags)
+//		_ = ff.Refresh(ctx)
+//	})
+type ConfigProvider struct {
+	flags atomic.Pointer[[]Flag]
+}
+
+// NewConfigProvider creates a new Provider of the specified flags.
+func NewConfigProvider(flags []Flag) *ConfigProvider {
+	p := &ConfigProvider{}
+	p.Set(flags)
+
+	return p
+}
+
+// Set replaces the flag definitions.
+func (p *ConfigProvider) Set(flags []Flag) {
+	p.flags.Store(&flags)
+}
+
+// Load returns the flag definitions.
+func (p *ConfigProvider) Load(_ context.Context) ([]Flag, error) {
+	return *p.flags.Load(), nil
+}
@@ -0,0 +1,23 @@
+package featureflag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigProvider(t *testing.T) {
+	t.Parallel()
+
+	p := NewConfigProvider([]Flag{{Key: "alpha"}})
+
+	flags, err := p.Load(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, []Flag{{Key: "alpha"}}, flags)
+
+	p.Set(nil)
+
+	flags, err = p.Load(t.Context())
+	require.NoError(t, err)
+	require.Empty(t, flags)
+}
@@ -0,0 +1,56 @@
+package featureflag_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/Vonage/gosrvlib/pkg/featureflag"
+	"github.com/Vonage/gosrvlib/pkg/filter"
+)
+
+func ExampleClient_Evaluate() {
+	flags := []featureflag.Flag{
+		{
+			Key:            "checkout-theme",
+			Enabled:        true,
+			Variants:       map[string]any{"classic": "blue", "modern": "green"},
+			DefaultVariant: "classic",
+			OffVariant:     "classic",
+			Rules: []featureflag.TargetingRule{
+				{
+					Name: "beta-testers",
+					Match: [][]filter.Rule{
+						{{Field: "group", Type: filter.TypeEqual, Value: "beta"}},
+					},
+					Variant: "modern",
+				},
+			},
+		},
+	}
+
+	ff, err := featureflag.New(featureflag.NewConfigProvider(flags), featureflag.WithRefreshInterval(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = ff.Refresh(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ev := ff.Evaluate("checkout-theme", &featureflag.EvalContext{
+		Key:        "user-123",
+		Attributes: map[string]any{"group": "beta"},
+	})
+
+	fmt.Println(ev.Variant, ev.Value, ev.Reason, ev.Rule)
+
+	ev = ff.Evaluate("checkout-theme", &featureflag.EvalContext{Key: "user-456"})
+
+	fmt.Println(ev.Variant, ev.Value, ev.Reason)
+
+	// Output:
+	// modern green targeting_match beta-testers
+	// classic blue default
+}
@@ -0,0 +1,610 @@
+/*
+Package featureflag provides feature flags with boolean and multivariate
+variants, percentage rollouts and targeting rules.
+
+Each Flag has a set of named variants with their values. The boolean flags have
+no explicit variants and use the implicit "on" (true) and "off" (false) ones.
+
+A flag is evaluated for an EvalContext, containing the targeting key (e.g. a
+user or account ID) and the attributes used by the targeting rules:
+
+  - a disabled flag always returns the OffVariant;
+  - the targeting rules are evaluated in order, and the first matching rule
+    returns its variant or its percentage rollout;
+  - otherwise the percentage rollout of the flag is applied, if any;
+  - otherwise the DefaultVariant is returned.
+
+The targeting rules have the same semantics of the filter package: the first
+slice contains the rule sets combined with a boolean AND, and the sub-slices
+contain the rules combined with a boolean OR. The rule fields select the
+EvalContext attributes, using the dot notation for nested maps
+(e.g. "address.country"). A missing attribute does not match.
+
+The percentage rollouts assign each targeting key to one of 10000 buckets by
+hashing the flag key and the targeting key with the stringkey package. The
+assignment is stable, so increasing a percentage only adds new keys to a
+variant, and different flags have independent assignments. The rollouts are
+not applied to an EvalContext without a targeting key.
+
+The flag definitions are loaded from a Provider:
+
+  - ConfigProvider: flags defined in the application configuration;
+  - RedisProvider and ValkeyProvider: flags stored as a JSON document in a key;
+  - SQLProvider: flags stored as JSON documents in a database table.
+
+The Client keeps the flag definitions in memory, so the evaluation is fast and
+does not depend on the provider availability. The definitions are refreshed
+periodically after Start, or on demand via Refresh (e.g. from a config.Watcher
+subscriber). When a refresh fails the current definitions are preserved.
+
+Each evaluation is counted with the IncEventCounter metric ("featureflag" task,
+flag key operation, evaluation reason outcome). The evaluations of the admin
+route are not counted.
+
+The AdminRoutes method returns the HTTP routes to inspect the flags state.
+
+Example:
+
+	ff, err := featureflag.New(featureflag.NewConfigProvider(cfg.FeatureFlags))
+	if err != nil {
+		return err
+	}
+
+	err = ff.Start(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer ff.Stop()
+
+	ec := &featureflag.EvalContext{
+		Key:        userID,
+		Attributes: map[string]any{"country": "GB"},
+	}
+
+	if ff.Bool("new-checkout", ec, false) {
+		// ...
+	}
+*/
+package featureflag
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/filter"
+	"github.com/Vonage/gosrvlib/pkg/metrics"
+	"github.com/Vonage/gosrvlib/pkg/stringkey"
+	"go.uber.org/zap"
+)
+
+// DefaultRefreshInterval is the default interval to reload the flag definitions from the provider.
+const DefaultRefreshInterval = 30 * time.Second
+
+// BucketCount is the number of buckets used by the percentage rollouts.
+const BucketCount = 10000
+
+// Implicit variants of the boolean flags.
+const (
+	VariantOn  = "on"
+	VariantOff = "off"
+)
+
+// Evaluation reasons.
+const (
+	ReasonDisabled  = "disabled"
+	ReasonTargeting = "targeting_match"
+	ReasonRollout   = "rollout"
+	ReasonDefault   = "default"
+	ReasonNotFound  = "not_found"
+)
+
+// metricsTask is the task name used in the metrics.
+const metricsTask = "featureflag"
+
+// Provider is the interface to load the flag definitions.
+type Provider interface {
+	// Load returns all the flag definitions.
+	Load(ctx context.Context) ([]Flag, error)
+}
+
+// Allocation assigns a percentage of the targeting keys to a variant.
+type Allocation struct {
+	// Variant is the name of the allocated variant.
+	Variant string `json:"variant" mapstructure:"variant"`
+
+	// Percent is the percentage of the targeting keys (0 to 100, with two decimal digits).
+	Percent float64 `json:"percent" mapstructure:"percent"`
+}
+
+// TargetingRule returns a variant for the EvalContexts matching a set of filter rules.
+type TargetingRule struct {
+	// Name is an optional name of the rule, returned in the Evaluation.
+	Name string `json:"name,omitempty" mapstructure:"name"`
+
+	// Match contains the filter rules to match the EvalContext attributes.
+	// The first slice contains the rule sets combined with a boolean AND,
+	// the sub-slices contain the rules combined with a boolean OR.
+	Match [][]filter.Rule `json:"match" mapstructure:"match"`
+
+	// Variant is the variant returned for the matching EvalContexts.
+	Variant string `json:"variant,omitempty" mapstructure:"variant"`
+
+	// Rollout is the percentage rollout applied to the matching EvalContexts instead of the Variant.
+	// The EvalContexts not allocated to any variant continue with the next rules.
+	Rollout []Allocation `json:"rollout,omitempty" mapstructure:"rollout"`
+}
+
+// Flag is the definition of a feature flag.
+type Flag struct {
+	// Key is the unique flag identifier.
+	Key string `json:"key" mapstructure:"key"`
+
+	// Description is an optional description of the flag.
+	Description string `json:"description,omitempty" mapstructure:"description"`
+
+	// Enabled is false to return the OffVariant to all the EvalContexts.
+	Enabled bool `json:"enabled" mapstructure:"enabled"`
+
+	// Variants maps the variant names to their values.
+	// When empty, the flag is boolean with the "on" (true) and "off" (false) variants.
+	// NOTE: the variant names should be lowercase when loaded via the config package.
+	Variants map[string]any `json:"variants,omitempty" mapstructure:"variants"`
+
+	// DefaultVariant is returned when no rule or rollout applies ("on" by default for the boolean flags).
+	DefaultVariant string `json:"default_variant,omitempty" mapstructure:"default_variant"`
+
+	// OffVariant is returned when the flag is disabled ("off" by default for the boolean flags).
+	OffVariant string `json:"off_variant,omitempty" mapstructure:"off_variant"`
+
+	// Rules are the targeting rules evaluated in order.
+	Rules []TargetingRule `json:"rules,omitempty" mapstructure:"rules"`
+
+	// Rollout is the percentage rollout applied when no rule matches.
+	// The EvalContexts not allocated to any variant get the DefaultVariant.
+	Rollout []Allocation `json:"rollout,omitempty" mapstructure:"rollout"`
+}
+
+// EvalContext contains the information used to evaluate a flag.
+type EvalContext struct {
+	// Key is the targeting key used by the percentage rollouts (e.g. user or account ID).
+	Key string `json:"key"`
+
+	// Attributes are the values selected by the targeting rules fields.
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+// Evaluation is the result of a flag evaluation.
+type Evaluation struct {
+	// Key is the flag key.
+	Key string `json:"key"`
+
+	// Variant is the name of the returned variant.
+	Variant string `json:"variant,omitempty"`
+
+	// Value is the value of the returned variant.
+	Value any `json:"value"`
+
+	// Reason explains the returned variant (see the Reason* constants).
+	Reason string `json:"reason"`
+
+	// Rule is the name of the matching targeting rule, if any.
+	Rule string `json:"rule,omitempty"`
+}
+
+// State contains the flag definitions currently in use.
+type State struct {
+	// UpdatedAt is the time of the last successful refresh.
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Flags contains the normalized flag definitions sorted by key.
+	Flags []Flag `json:"flags"`
+}
+
+type snapshot struct {
+	updatedAt time.Time
+	flags     map[string]*Flag
+}
+
+// Client evaluates the feature flags loaded from a Provider.
+type Client struct {
+	provider Provider
+	interval time.Duration
+	logger   *zap.Logger
+	metrics  metrics.Client
+	current  atomic.Pointer[snapshot]
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// New creates a new feature flags Client.
+// The flag definitions are loaded by Start or Refresh.
+func New(provider Provider, opts ...Option) (*Client, error) {
+	if provider == nil {
+		return nil, errors.New("the provider is required")
+	}
+
+	c := &Client{
+		provider: provider,
+		interval: DefaultRefreshInterval,
+		logger:   zap.NewNop(),
+		metrics:  &metrics.Default{},
+	}
+
+	for _, applyOpt := range opts {
+		if err := applyOpt(c); err != nil {
+			return nil, err
+		}
+	}
+
+	c.current.Store(&snapshot{flags: make(map[string]*Flag)})
+
+	return c, nil
+}
+
+// Refresh loads and validates the flag definitions from the provider, and replaces the current ones.
+// On error, the current definitions are preserved.
+func (c *Client) Refresh(ctx context.Context) error {
+	flags, err := c.provider.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("featureflag: unable to load the flags: %w", err)
+	}
+
+	m := make(map[string]*Flag, len(flags))
+
+	for i := range flags {
+		f, err := compile(&flags[i])
+		if err != nil {
+			return fmt.Errorf("featureflag: invalid flag %q: %w", flags[i].Key, err)
+		}
+
+		if _, ok := m[f.Key]; ok {
+			return fmt.Errorf("featureflag: duplicate flag %q", f.Key)
+		}
+
+		m[f.Key] = f
+	}
+
+	c.current.Store(&snapshot{updatedAt: time.Now().UTC(), flags: m})
+
+	return nil
+}
+
+// Start loads the flag definitions and refreshes them periodically, if enabled.
+func (c *Client) Start(ctx context.Context) error {
+	if err := c.Refresh(ctx); err != nil {
+		return err
+	}
+
+	if c.interval <= 0 {
+		return nil
+	}
+
+	ctx, c.cancel = context.WithCancel(ctx)
+
+	c.wg.Go(func() { c.poll(ctx) })
+
+	return nil
+}
+
+// Stop the periodic refresh.
+func (c *Client) Stop() {
+	if c.cancel != nil {
+		c.cancel()
+	}
+
+	c.wg.Wait()
+}
+
+// State returns the flag definitions currently in use.
+func (c *Client) State() *State {
+	s := c.current.Load()
+
+	flags := make([]Flag, 0, len(s.flags))
+	for _, f := range s.flags {
+		flags = append(flags, *f)
+	}
+
+	slices.SortFunc(flags, func(a, b Flag) int { return strings.Compare(a.Key, b.Key) })
+
+	return &State{UpdatedAt: s.updatedAt, Flags: flags}
+}
+
+// Flag returns the normalized definition of the flag with the specified key, or false if not found.
+func (c *Client) Flag(key string) (Flag, bool) {
+	f, ok := c.current.Load().flags[key]
+	if !ok {
+		return Flag{}, false
+	}
+
+	return *f, true
+}
+
+// Evaluate returns the variant of the flag for the EvalContext.
+// The ReasonNotFound is returned with a nil value for an unknown flag.
+func (c *Client) Evaluate(key string, ec *EvalContext) *Evaluation {
+	ev := c.evaluate(key, ec)
+
+	metrics.IncEventCounter(c.metrics, metricsTask, key, ev.Reason)
+
+	return ev
+}
+
+// evaluate returns the variant of the flag for the EvalContext without counting the evaluation.
+func (c *Client) evaluate(key string, ec *EvalContext) *Evaluation {
+	if ec == nil {
+		ec = &EvalContext{}
+	}
+
+	f, ok := c.current.Load().flags[key]
+	if !ok {
+		return &Evaluation{Key: key, Reason: ReasonNotFound}
+	}
+
+	return evaluate(f, ec)
+}
+
+// Bool returns the boolean value of the flag for the EvalContext,
+// or the default value if the flag is not found or its value is not boolean.
+func (c *Client) Bool(key string, ec *EvalContext, def bool) bool {
+	if v, ok := c.Evaluate(key, ec).Value.(bool); ok {
+		return v
+	}
+
+	return def
+}
+
+// String returns the string value of the flag for the EvalContext,
+// or the default value if the flag is not found or its value is not a string.
+func (c *Client) String(key string, ec *EvalContext, def string) string {
+	if v, ok := c.Evaluate(key, ec).Value.(string); ok {
+		return v
+	}
+
+	return def
+}
+
+func (c *Client) poll(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.Refresh(ctx); err != nil {
+				metrics.IncEventCounter(c.metrics, metricsTask, "refresh", "error")
+				c.logger.Error("failed refreshing the feature flags", zap.Error(err))
+			}
+		}
+	}
+}
+
+// compile returns a validated copy of the flag with the implicit defaults applied.
+// The filter rules are initialized, so they can be evaluated concurrently.
+func compile(src *Flag) (*Flag, error) {
+	if src.Key == "" {
+		return nil, errors.New("the key is required")
+	}
+
+	f := *src
+	f.Variants = maps.Clone(src.Variants)
+
+	if len(f.Variants) == 0 {
+		f.Variants = map[string]any{VariantOn: true, VariantOff: false}
+		f.DefaultVariant = cmp.Or(f.DefaultVariant, VariantOn)
+		f.OffVariant = cmp.Or(f.OffVariant, VariantOff)
+	}
+
+	if err := f.checkVariant(f.DefaultVariant); err != nil {
+		return nil, fmt.Errorf("default variant: %w", err)
+	}
+
+	if err := f.checkVariant(f.OffVariant); err != nil {
+		return nil, fmt.Errorf("off variant: %w", err)
+	}
+
+	if err := f.checkRollout(f.Rollout); err != nil {
+		return nil, fmt.Errorf("rollout: %w", err)
+	}
+
+	f.Rollout = slices.Clone(src.Rollout)
+	f.Rules = make([]TargetingRule, len(src.Rules))
+
+	for i, r := range src.Rules {
+		if err := f.compileRule(&r); err != nil {
+			return nil, fmt.Errorf("rule %d: %w", i, err)
+		}
+
+		f.Rules[i] = r
+	}
+
+	return &f, nil
+}
+
+func (f *Flag) compileRule(r *TargetingRule) error {
+	if (r.Variant == "") == (len(r.Rollout) == 0) {
+		return errors.New("either a variant or a rollout is required")
+	}
+
+	if r.Variant != "" {
+		if err := f.checkVariant(r.Variant); err != nil {
+			return err
+		}
+	}
+
+	if err := f.checkRollout(r.Rollout); err != nil {
+		return fmt.Errorf("rollout: %w", err)
+	}
+
+	r.Rollout = slices.Clone(r.Rollout)
+
+	match := make([][]filter.Rule, len(r.Match))
+
+	for i := range r.Match {
+		match[i] = slices.Clone(r.Match[i])
+
+		for j := range match[i] {
+			// initialize the rule evaluator to validate the rule and avoid concurrent initializations
+			if _, err := match[i][j].Evaluate(nil); err != nil {
+				return fmt.Errorf("filter rule %q: %w", match[i][j].Field, err)
+			}
+		}
+	}
+
+	r.Match = match
+
+	return nil
+}
+
+func (f *Flag) checkVariant(name string) error {
+	if name == "" {
+		return errors.New("the variant is required")
+	}
+
+	if _, ok := f.Variants[name]; !ok {
+		return fmt.Errorf("unknown variant %q", name)
+	}
+
+	return nil
+}
+
+func (f *Flag) checkRollout(rollout []Allocation) error {
+	var total float64
+
+	for _, a := range rollout {
+		if err := f.checkVariant(a.Variant); err != nil {
+			return err
+		}
+
+		if a.Percent < 0 || a.Percent > 100 {
+			return fmt.Errorf("the percent of the variant %q must be between 0 and 100", a.Variant)
+		}
+
+		total += a.Percent
+	}
+
+	if total > 100 {
+		return errors.New("the total percent must not exceed 100")
+	}
+
+	return nil
+}
+
+// evaluate returns the variant of the compiled flag for the EvalContext.
+func evaluate(f *Flag, ec *EvalContext) *Evaluation {
+	ev := &Evaluation{Key: f.Key}
+
+	switch {
+	case !f.Enabled:
+		ev.Variant, ev.Reason = f.OffVariant, ReasonDisabled
+	case ev.target(f, ec):
+	default:
+		ev.Variant, ev.Reason = f.DefaultVariant, ReasonDefault
+
+		if v, ok := allocate(f, f.Rollout, ec); ok {
+			ev.Variant, ev.Reason = v, ReasonRollout
+		}
+	}
+
+	ev.Value = f.Variants[ev.Variant]
+
+	return ev
+}
+
+// target sets the variant of the first matching targeting rule, and returns true if any.
+func (ev *Evaluation) target(f *Flag, ec *EvalContext) bool {
+	for i := range f.Rules {
+		r := &f.Rules[i]
+
+		if !match(r.Match, ec) {
+			continue
+		}
+
+		if r.Variant != "" {
+			ev.Variant, ev.Reason, ev.Rule = r.Variant, ReasonTargeting, r.Name
+			return true
+		}
+
+		if v, ok := allocate(f, r.Rollout, ec); ok {
+			ev.Variant, ev.Reason, ev.Rule = v, ReasonTargeting, r.Name
+			return true
+		}
+	}
+
+	return false
+}
+
+// match returns true if the EvalContext attributes match the AND of the OR of the filter rules.
+func match(rules [][]filter.Rule, ec *EvalContext) bool {
+	for i := range rules {
+		if !slices.ContainsFunc(rules[i], func(r filter.Rule) bool {
+			v, ok := ec.attribute(r.Field)
+			if !ok {
+				return false
+			}
+
+			m, _ := r.Evaluate(v) // the rules are already initialized
+
+			return m
+		}) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// attribute returns the attribute selected by the dot separated path.
+func (ec *EvalContext) attribute(path string) (any, bool) {
+	var v any = ec.Attributes
+
+	for name := range strings.SplitSeq(path, ".") {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+
+		if v, ok = m[name]; !ok {
+			return nil, false
+		}
+	}
+
+	return v, true
+}
+
+// allocate returns the variant allocated to the bucket of the EvalContext key, if any.
+func allocate(f *Flag, rollout []Allocation, ec *EvalContext) (string, bool) {
+	if len(rollout) == 0 || ec.Key == "" {
+		return "", false
+	}
+
+	b := bucket(f.Key, ec.Key)
+
+	var upto uint64
+
+	for _, a := range rollout {
+		upto += uint64(math.Round(a.Percent * BucketCount / 100)) //nolint:gosec
+
+		if b < upto {
+			return a.Variant, true
+		}
+	}
+
+	return "", false
+}
+
+// bucket returns the stable rollout bucket of the targeting key for the flag.
+func bucket(flagKey, key string) uint64 {
+	return stringkey.New(flagKey, key).Key() % BucketCount
+}
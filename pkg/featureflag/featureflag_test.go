@@ -0,0 +1,537 @@
+package featureflag
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/filter"
+	"github.com/Vonage/gosrvlib/pkg/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+type testProvider struct {
+	flags []Flag
+	err   error
+}
+
+func (p *testProvider) Load(_ context.Context) ([]Flag, error) {
+	return p.flags, p.err
+}
+
+func newTestClient(t *testing.T, flags ...Flag) *Client {
+	t.Helper()
+
+	c, err := New(NewConfigProvider(flags), WithRefreshInterval(0))
+	require.NoError(t, err)
+
+	err = c.Refresh(t.Context())
+	require.NoError(t, err)
+
+	return c
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	c, err := New(NewConfigProvider(nil))
+	require.NoError(t, err)
+	require.NotNil(t, c)
+	require.Equal(t, DefaultRefreshInterval, c.interval)
+	require.Empty(t, c.State().Flags)
+
+	c, err = New(nil)
+	require.Error(t, err)
+	require.Nil(t, c)
+
+	c, err = New(NewConfigProvider(nil), WithRefreshInterval(-1))
+	require.Error(t, err)
+	require.Nil(t, c)
+}
+
+func TestClient_Refresh(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		flags   []Flag
+		loadErr error
+		wantErr bool
+	}{
+		{
+			name:  "boolean flag",
+			flags: []Flag{{Key: "alpha", Enabled: true}},
+		},
+		{
+			name: "multivariate flag",
+			flags: []Flag{{
+				Key:            "color",
+				Variants:       map[string]any{"red": "#f00", "green": "#0f0"},
+				DefaultVariant: "red",
+				OffVariant:     "green",
+				Rules: []TargetingRule{
+					{Match: [][]filter.Rule{{{Field: "country", Type: filter.TypeEqual, Value: "GB"}}}, Variant: "green"},
+					{Rollout: []Allocation{{Variant: "green", Percent: 100}}},
+				},
+				Rollout: []Allocation{{Variant: "red", Percent: 40.5}, {Variant: "green", Percent: 59.5}},
+			}},
+		},
+		{
+			name:    "load error",
+			loadErr: errors.New("ERROR"),
+			wantErr: true,
+		},
+		{
+			name:    "missing key",
+			flags:   []Flag{{Enabled: true}},
+			wantErr: true,
+		},
+		{
+			name:    "duplicate key",
+			flags:   []Flag{{Key: "alpha"}, {Key: "alpha"}},
+			wantErr: true,
+		},
+		{
+			name:    "missing default variant",
+			flags:   []Flag{{Key: "color", Variants: map[string]any{"red": 1}, OffVariant: "red"}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown off variant",
+			flags:   []Flag{{Key: "color", Variants: map[string]any{"red": 1}, DefaultVariant: "red", OffVariant: "blue"}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown rollout variant",
+			flags:   []Flag{{Key: "alpha", Rollout: []Allocation{{Variant: "blue", Percent: 10}}}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid rollout percent",
+			flags:   []Flag{{Key: "alpha", Rollout: []Allocation{{Variant: VariantOn, Percent: 101}}}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid rollout total",
+			flags:   []Flag{{Key: "alpha", Rollout: []Allocation{{Variant: VariantOn, Percent: 60}, {Variant: VariantOff, Percent: 60}}}},
+			wantErr: true,
+		},
+		{
+			name:    "rule without variant",
+			flags:   []Flag{{Key: "alpha", Rules: []TargetingRule{{}}}},
+			wantErr: true,
+		},
+		{
+			name:    "rule with variant and rollout",
+			flags:   []Flag{{Key: "alpha", Rules: []TargetingRule{{Variant: VariantOn, Rollout: []Allocation{{Variant: VariantOn, Percent: 1}}}}}},
+			wantErr: true,
+		},
+		{
+			name:    "rule with unknown variant",
+			flags:   []Flag{{Key: "alpha", Rules: []TargetingRule{{Variant: "blue"}}}},
+			wantErr: true,
+		},
+		{
+			name:    "rule with invalid rollout",
+			flags:   []Flag{{Key: "alpha", Rules: []TargetingRule{{Rollout: []Allocation{{Variant: VariantOn, Percent: -1}}}}}},
+			wantErr: true,
+		},
+		{
+			name: "invalid filter rule",
+			flags: []Flag{{Key: "alpha", Rules: []TargetingRule{{
+				Match:   [][]filter.Rule{{{Field: "country", Type: "invalid", Value: "GB"}}},
+				Variant: VariantOff,
+			}}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := &testProvider{flags: []Flag{{Key: "previous"}}}
+
+			c, err := New(p)
+			require.NoError(t, err)
+
+			err = c.Refresh(t.Context())
+			require.NoError(t, err)
+
+			p.flags, p.err = tt.flags, tt.loadErr
+
+			err = c.Refresh(t.Context())
+
+			if tt.wantErr {
+				require.Error(t, err)
+
+				_, ok := c.Flag("previous")
+				require.True(t, ok, "the previous flags should be preserved")
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, c.State().Flags, len(tt.flags))
+		})
+	}
+}
+
+func TestClient_Evaluate(t *testing.T) {
+	t.Parallel()
+
+	gb := [][]filter.Rule{{{Field: "country", Type: filter.TypeEqual, Value: "GB"}}}
+
+	tests := []struct {
+		name        string
+		flag        Flag
+		ec          *EvalContext
+		wantVariant string
+		wantValue   any
+		wantReason  string
+		wantRule    string
+	}{
+		{
+			name:        "boolean enabled",
+			flag:        Flag{Key: "alpha", Enabled: true},
+			wantVariant: VariantOn,
+			wantValue:   true,
+			wantReason:  ReasonDefault,
+		},
+		{
+			name:        "boolean disabled",
+			flag:        Flag{Key: "alpha"},
+			ec:          &EvalContext{Key: "user", Attributes: map[string]any{"country": "GB"}},
+			wantVariant: VariantOff,
+			wantValue:   false,
+			wantReason:  ReasonDisabled,
+		},
+		{
+			name: "targeting variant",
+			flag: Flag{
+				Key:            "alpha",
+				Enabled:        true,
+				DefaultVariant: VariantOff,
+				Rules:          []TargetingRule{{Name: "uk", Match: gb, Variant: VariantOn}},
+			},
+			ec:          &EvalContext{Attributes: map[string]any{"country": "GB"}},
+			wantVariant: VariantOn,
+			wantValue:   true,
+			wantReason:  ReasonTargeting,
+			wantRule:    "uk",
+		},
+		{
+			name: "targeting no match",
+			flag: Flag{
+				Key:            "alpha",
+				Enabled:        true,
+				DefaultVariant: VariantOff,
+				Rules:          []TargetingRule{{Name: "uk", Match: gb, Variant: VariantOn}},
+			},
+			ec:          &EvalContext{Attributes: map[string]any{"country": "FR"}},
+			wantVariant: VariantOff,
+			wantValue:   false,
+			wantReason:  ReasonDefault,
+		},
+		{
+			name: "targeting missing attribute",
+			flag: Flag{
+				Key:            "alpha",
+				Enabled:        true,
+				DefaultVariant: VariantOff,
+				Rules:          []TargetingRule{{Match: gb, Variant: VariantOn}},
+			},
+			ec:          &EvalContext{Key: "user"},
+			wantVariant: VariantOff,
+			wantValue:   false,
+			wantReason:  ReasonDefault,
+		},
+		{
+			name: "targeting nested attributes AND OR",
+			flag: Flag{
+				Key:            "color",
+				Enabled:        true,
+				Variants:       map[string]any{"red": "#f00", "green": "#0f0"},
+				DefaultVariant: "red",
+				OffVariant:     "red",
+				Rules: []TargetingRule{{
+					Name: "adults",
+					Match: [][]filter.Rule{
+						{
+							{Field: "address.country", Type: filter.TypeEqual, Value: "FR"},
+							{Field: "address.country", Type: filter.TypeEqual, Value: "GB"},
+						},
+						{{Field: "age", Type: filter.TypeGTE, Value: 18}},
+					},
+					Variant: "green",
+				}},
+			},
+			ec: &EvalContext{Attributes: map[string]any{
+				"address": map[string]any{"country": "GB"},
+				"age":     21,
+			}},
+			wantVariant: "green",
+			wantValue:   "#0f0",
+			wantReason:  ReasonTargeting,
+			wantRule:    "adults",
+		},
+		{
+			name: "targeting nested attribute not a map",
+			flag: Flag{
+				Key:            "alpha",
+				Enabled:        true,
+				DefaultVariant: VariantOff,
+				Rules: []TargetingRule{{
+					Match:   [][]filter.Rule{{{Field: "address.country", Type: filter.TypeEqual, Value: "GB"}}},
+					Variant: VariantOn,
+				}},
+			},
+			ec:          &EvalContext{Attributes: map[string]any{"address": "GB"}},
+			wantVariant: VariantOff,
+			wantValue:   false,
+			wantReason:  ReasonDefault,
+		},
+		{
+			name: "targeting rollout",
+			flag: Flag{
+				Key:            "alpha",
+				Enabled:        true,
+				DefaultVariant: VariantOff,
+				Rules: []TargetingRule{
+					{Name: "none", Rollout: []Allocation{{Variant: VariantOn, Percent: 0}}},
+					{Name: "all", Rollout: []Allocation{{Variant: VariantOn, Percent: 100}}},
+				},
+			},
+			ec:          &EvalContext{Key: "user"},
+			wantVariant: VariantOn,
+			wantValue:   true,
+			wantReason:  ReasonTargeting,
+			wantRule:    "all",
+		},
+		{
+			name: "rollout",
+			flag: Flag{
+				Key:            "alpha",
+				Enabled:        true,
+				DefaultVariant: VariantOff,
+				Rollout:        []Allocation{{Variant: VariantOn, Percent: 100}},
+			},
+			ec:          &EvalContext{Key: "user"},
+			wantVariant: VariantOn,
+			wantValue:   true,
+			wantReason:  ReasonRollout,
+		},
+		{
+			name: "rollout without key",
+			flag: Flag{
+				Key:            "alpha",
+				Enabled:        true,
+				DefaultVariant: VariantOff,
+				Rollout:        []Allocation{{Variant: VariantOn, Percent: 100}},
+			},
+			wantVariant: VariantOff,
+			wantValue:   false,
+			wantReason:  ReasonDefault,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := &testutil.EventMetrics{}
+
+			c, err := New(NewConfigProvider([]Flag{tt.flag}), WithMetrics(m))
+			require.NoError(t, err)
+
+			err = c.Refresh(t.Context())
+			require.NoError(t, err)
+
+			ev := c.Evaluate(tt.flag.Key, tt.ec)
+			require.Equal(t, tt.flag.Key, ev.Key)
+			require.Equal(t, tt.wantVariant, ev.Variant)
+			require.Equal(t, tt.wantValue, ev.Value)
+			require.Equal(t, tt.wantReason, ev.Reason)
+			require.Equal(t, tt.wantRule, ev.Rule)
+			require.Equal(t, 1, m.Count(tt.wantReason))
+		})
+	}
+}
+
+func TestClient_Evaluate_notFound(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t)
+
+	ev := c.Evaluate("missing", nil)
+	require.Equal(t, &Evaluation{Key: "missing", Reason: ReasonNotFound}, ev)
+}
+
+func TestClient_Evaluate_rollout(t *testing.T) {
+	t.Parallel()
+
+	const keys = 10000
+
+	flag := Flag{
+		Key:            "color",
+		Enabled:        true,
+		Variants:       map[string]any{"red": 1, "green": 2, "blue": 3},
+		DefaultVariant: "blue",
+		OffVariant:     "blue",
+		Rollout:        []Allocation{{Variant: "red", Percent: 10}, {Variant: "green", Percent: 30}},
+	}
+
+	c := newTestClient(t, flag)
+
+	assigned := make(map[string]string, keys)
+	counts := make(map[string]int)
+
+	for i := range keys {
+		key := "user-" + strconv.Itoa(i)
+		v := c.Evaluate("color", &EvalContext{Key: key}).Variant
+		assigned[key] = v
+		counts[v]++
+
+		// stable assignment
+		require.Equal(t, v, c.Evaluate("color", &EvalContext{Key: key}).Variant)
+	}
+
+	require.InDelta(t, 1000, counts["red"], 150)
+	require.InDelta(t, 3000, counts["green"], 300)
+	require.InDelta(t, 6000, counts["blue"], 300)
+
+	// increasing the percentages only moves the keys from the default variant
+	flag.Rollout = []Allocation{{Variant: "red", Percent: 10}, {Variant: "green", Percent: 50}}
+	c = newTestClient(t, flag)
+
+	for key, v := range assigned {
+		if v != "blue" {
+			require.Equal(t, v, c.Evaluate("color", &EvalContext{Key: key}).Variant)
+		}
+	}
+}
+
+func TestClient_Bool(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t,
+		Flag{Key: "alpha", Enabled: true},
+		Flag{Key: "beta", Variants: map[string]any{"a": "x"}, DefaultVariant: "a", OffVariant: "a"},
+	)
+
+	require.True(t, c.Bool("alpha", nil, false))
+	require.True(t, c.Bool("beta", nil, true))
+	require.False(t, c.Bool("beta", nil, false))
+	require.True(t, c.Bool("missing", nil, true))
+}
+
+func TestClient_String(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t,
+		Flag{Key: "alpha", Enabled: true},
+		Flag{Key: "beta", Enabled: true, Variants: map[string]any{"a": "x"}, DefaultVariant: "a", OffVariant: "a"},
+	)
+
+	require.Equal(t, "x", c.String("beta", nil, "def"))
+	require.Equal(t, "def", c.String("alpha", nil, "def"))
+	require.Equal(t, "def", c.String("missing", nil, "def"))
+}
+
+func TestClient_State(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t, Flag{Key: "beta"}, Flag{Key: "alpha", Enabled: true})
+
+	s := c.State()
+	require.False(t, s.UpdatedAt.IsZero())
+	require.Len(t, s.Flags, 2)
+	require.Equal(t, "alpha", s.Flags[0].Key)
+	require.Equal(t, "beta", s.Flags[1].Key)
+	require.Equal(t, VariantOn, s.Flags[0].DefaultVariant)
+	require.Equal(t, VariantOff, s.Flags[0].OffVariant)
+
+	f, ok := c.Flag("beta")
+	require.True(t, ok)
+	require.Equal(t, "beta", f.Key)
+
+	_, ok = c.Flag("missing")
+	require.False(t, ok)
+}
+
+func TestClient_StartStop(t *testing.T) {
+	t.Parallel()
+
+	p := NewConfigProvider([]Flag{{Key: "alpha"}})
+	m := &testutil.EventMetrics{}
+
+	c, err := New(p, WithRefreshInterval(10*time.Millisecond), WithMetrics(m))
+	require.NoError(t, err)
+
+	err = c.Start(t.Context())
+	require.NoError(t, err)
+
+	defer c.Stop()
+
+	require.False(t, c.Bool("alpha", nil, true))
+
+	p.Set([]Flag{{Key: "alpha", Enabled: true}})
+
+	require.Eventually(t, func() bool { return c.Bool("alpha", nil, false) }, time.Second, 5*time.Millisecond)
+
+	p.Set([]Flag{{Key: ""}})
+
+	require.Eventually(t, func() bool { return m.Count("error") > 0 }, time.Second, 5*time.Millisecond)
+	require.True(t, c.Bool("alpha", nil, false))
+}
+
+func TestClient_Start_error(t *testing.T) {
+	t.Parallel()
+
+	c, err := New(&testProvider{err: errors.New("ERROR")})
+	require.NoError(t, err)
+
+	err = c.Start(t.Context())
+	require.Error(t, err)
+
+	c.Stop()
+}
+
+func TestClient_Start_noRefresh(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t)
+
+	err := c.Start(t.Context())
+	require.NoError(t, err)
+	require.Nil(t, c.cancel)
+
+	c.Stop()
+}
+
+func TestCompile_isolated(t *testing.T) {
+	t.Parallel()
+
+	src := Flag{
+		Key:      "color",
+		Enabled:  true,
+		Variants: map[string]any{"red": 1, "green": 2},
+		Rules: []TargetingRule{{
+			Match:   [][]filter.Rule{{{Field: "country", Type: filter.TypeEqual, Value: "GB"}}},
+			Variant: "green",
+		}},
+		DefaultVariant: "red",
+		OffVariant:     "red",
+	}
+
+	f, err := compile(&src)
+	require.NoError(t, err)
+
+	src.Variants["green"] = 3
+	src.Rules[0].Match[0][0].Value = "FR"
+
+	require.Equal(t, 2, f.Variants["green"])
+	require.Equal(t, "GB", f.Rules[0].Match[0][0].Value)
+}
@@ -0,0 +1,52 @@
+package featureflag
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/metrics"
+	"go.uber.org/zap"
+)
+
+// Option is the interface that allows to set the Client options.
+type Option func(c *Client) error
+
+// WithRefreshInterval sets the interval to periodically reload the flag definitions from the provider.
+// A zero value disables the periodic refresh.
+func WithRefreshInterval(interval time.Duration) Option {
+	return func(c *Client) error {
+		if interval < 0 {
+			return errors.New("the refresh interval must not be negative")
+		}
+
+		c.interval = interval
+
+		return nil
+	}
+}
+
+// WithLogger sets the logger used to report the refresh errors.
+func WithLogger(l *zap.Logger) Option {
+	return func(c *Client) error {
+		if l == nil {
+			return errors.New("the logger is required")
+		}
+
+		c.logger = l
+
+		return nil
+	}
+}
+
+// WithMetrics sets the metrics client used to count the evaluations and the refresh errors.
+func WithMetrics(m metrics.Client) Option {
+	return func(c *Client) error {
+		if m == nil {
+			return errors.New("the metrics client is required")
+		}
+
+		c.metrics = m
+
+		return nil
+	}
+}
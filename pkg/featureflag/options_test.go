@@ -0,0 +1,55 @@
+package featureflag
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/metrics"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestWithRefreshInterval(t *testing.T) {
+	t.Parallel()
+
+	c := &Client{}
+
+	err := WithRefreshInterval(time.Minute)(c)
+	require.NoError(t, err)
+	require.Equal(t, time.Minute, c.interval)
+
+	err = WithRefreshInterval(0)(c)
+	require.NoError(t, err)
+	require.Zero(t, c.interval)
+
+	err = WithRefreshInterval(-1)(c)
+	require.Error(t, err)
+}
+
+func TestWithLogger(t *testing.T) {
+	t.Parallel()
+
+	c := &Client{}
+	l := zap.NewNop()
+
+	err := WithLogger(l)(c)
+	require.NoError(t, err)
+	require.Equal(t, l, c.logger)
+
+	err = WithLogger(nil)(c)
+	require.Error(t, err)
+}
+
+func TestWithMetrics(t *testing.T) {
+	t.Parallel()
+
+	c := &Client{}
+	m := &metrics.Default{}
+
+	err := WithMetrics(m)(c)
+	require.NoError(t, err)
+	require.Equal(t, m, c.metrics)
+
+	err = WithMetrics(nil)(c)
+	require.Error(t, err)
+}
@@ -0,0 +1,60 @@
+package featureflag
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	libredis "github.com/redis/go-redis/v9"
+)
+
+// DefaultStorageKey is the default key of the flag definitions in Redis and Valkey.
+const DefaultStorageKey = "featureflags"
+
+// RedisClient contains the methods of the github.com/Vonage/gosrvlib/pkg/redis Client used by the RedisProvider.
+type RedisClient interface {
+	Get(ctx context.Context, key string, value any) error
+}
+
+// RedisProvider is a Provider of flags stored in Redis as a JSON array in a single key,
+// written by an external management tool, for example:
+//
+//	SET featureflags '[{"key":"new-checkout","enabled":true,"default_variant":"off","rollout":[{"variant":"on","percent":10}]}]'
+type RedisProvider struct {
+	client RedisClient
+	key    string
+}
+
+// NewRedisProvider creates a new Provider of flags stored in the specified Redis key (DefaultStorageKey if empty).
+func NewRedisProvider(client RedisClient, key string) *RedisProvider {
+	return &RedisProvider{client: client, key: cmp.Or(key, DefaultStorageKey)}
+}
+
+// Load returns the flag definitions. A missing key is equivalent to no flags.
+func (p *RedisProvider) Load(ctx context.Context) ([]Flag, error) {
+	var value []byte
+
+	err := p.client.Get(ctx, p.key, &value)
+	if errors.Is(err, libredis.Nil) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("redis provider: %w", err)
+	}
+
+	return decodeFlags(value)
+}
+
+// decodeFlags decodes the JSON array of the flag definitions.
+func decodeFlags(data []byte) ([]Flag, error) {
+	var flags []Flag
+
+	if err := json.Unmarshal(data, &flags); err != nil {
+		return nil, fmt.Errorf("unable to decode the flags: %w", err)
+	}
+
+	return flags, nil
+}
@@ -0,0 +1,95 @@
+package featureflag
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	libredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+type testRedisClient struct {
+	key   string
+	value []byte
+	err   error
+}
+
+func (c *testRedisClient) Get(_ context.Context, key string, value any) error {
+	c.key = key
+
+	if c.err != nil {
+		return c.err
+	}
+
+	*value.(*[]byte) = c.value //nolint:forcetypeassert
+
+	return nil
+}
+
+func TestRedisProvider_Load(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		key       string
+		value     string
+		err       error
+		wantKey   string
+		wantFlags []Flag
+		wantErr   bool
+	}{
+		{
+			name:      "success",
+			value:     `[{"key":"alpha","enabled":true},{"key":"beta"}]`,
+			wantKey:   DefaultStorageKey,
+			wantFlags: []Flag{{Key: "alpha", Enabled: true}, {Key: "beta"}},
+		},
+		{
+			name:    "custom key",
+			key:     "flags",
+			value:   `[]`,
+			wantKey: "flags",
+		},
+		{
+			name:    "missing key",
+			err:     libredis.Nil,
+			wantKey: DefaultStorageKey,
+		},
+		{
+			name:    "error",
+			err:     errors.New("ERROR"),
+			wantKey: DefaultStorageKey,
+			wantErr: true,
+		},
+		{
+			name:    "invalid JSON",
+			value:   `{`,
+			wantKey: DefaultStorageKey,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &testRedisClient{value: []byte(tt.value), err: tt.err}
+
+			flags, err := NewRedisProvider(client, tt.key).Load(t.Context())
+			require.Equal(t, tt.wantKey, client.key)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, flags, len(tt.wantFlags))
+
+			if len(tt.wantFlags) > 0 {
+				require.Equal(t, tt.wantFlags, flags)
+			}
+		})
+	}
+}
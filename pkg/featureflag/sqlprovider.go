@@ -0,0 +1,77 @@
+package featureflag
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Vonage/gosrvlib/pkg/logging"
+)
+
+// SQLProvider is a Provider of flags stored in a database table,
+// with one row for each flag containing its JSON definition, written by an external management tool.
+// The flag_key column overrides the key in the JSON definition.
+//
+// Example of a MySQL database table that can be used with this provider:
+//
+//	CREATE TABLE IF NOT EXISTS `feature_flags` (
+//	  `flag_key` VARCHAR(128) NOT NULL,
+//	  `definition` JSON NOT NULL,
+//	  PRIMARY KEY (`flag_key`))
+//	ENGINE = InnoDB;
+//
+// Example of a PostgreSQL database table that can be used with this provider:
+//
+//	CREATE TABLE IF NOT EXISTS feature_flags (
+//	  flag_key VARCHAR(128) PRIMARY KEY,
+//	  definition TEXT NOT NULL);
+type SQLProvider struct {
+	db    *sql.DB
+	query string
+}
+
+// NewSQLProvider creates a new Provider of flags stored in the specified table.
+func NewSQLProvider(db *sql.DB, table string) *SQLProvider {
+	return &SQLProvider{
+		db:    db,
+		query: "SELECT flag_key, definition FROM " + table + " ORDER BY flag_key",
+	}
+}
+
+// Load returns the flag definitions.
+func (p *SQLProvider) Load(ctx context.Context) ([]Flag, error) {
+	rows, err := p.db.QueryContext(ctx, p.query)
+	if err != nil {
+		return nil, fmt.Errorf("sql provider: unable to read the flags: %w", err)
+	}
+
+	defer logging.Close(ctx, rows, "error closing the feature flags rows")
+
+	var flags []Flag
+
+	for rows.Next() {
+		var (
+			key  string
+			data []byte
+			f    Flag
+		)
+
+		if err := rows.Scan(&key, &data); err != nil {
+			return nil, fmt.Errorf("sql provider: unable to read the flag: %w", err)
+		}
+
+		if err := json.Unmarshal(data, &f); err != nil {
+			return nil, fmt.Errorf("sql provider: unable to decode the flag %q: %w", key, err)
+		}
+
+		f.Key = key
+		flags = append(flags, f)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("sql provider: unable to read the flags: %w", err)
+	}
+
+	return flags, nil
+}
@@ -0,0 +1,95 @@
+package featureflag
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLProvider_Load(t *testing.T) {
+	t.Parallel()
+
+	query := regexp.QuoteMeta("SELECT flag_key, definition FROM feature_flags ORDER BY flag_key")
+	columns := []string{"flag_key", "definition"}
+
+	tests := []struct {
+		name       string
+		setupMocks func(mock sqlmock.Sqlmock)
+		wantFlags  []Flag
+		wantErr    bool
+	}{
+		{
+			name: "success",
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows(columns).
+					AddRow("alpha", []byte(`{"key":"ignored","enabled":true}`)).
+					AddRow("beta", []byte(`{}`)))
+			},
+			wantFlags: []Flag{{Key: "alpha", Enabled: true}, {Key: "beta"}},
+		},
+		{
+			name: "empty",
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows(columns))
+			},
+		},
+		{
+			name: "query error",
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).WillReturnError(errors.New("ERROR"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "scan error",
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows([]string{"flag_key"}).AddRow("alpha"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid JSON",
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows(columns).AddRow("alpha", []byte(`{`)))
+			},
+			wantErr: true,
+		},
+		{
+			name: "rows error",
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows(columns).
+					AddRow("alpha", []byte(`{}`)).
+					RowError(0, errors.New("ERROR")))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer func() { _ = db.Close() }()
+
+			tt.setupMocks(mock)
+
+			flags, err := NewSQLProvider(db, "feature_flags").Load(t.Context())
+
+			require.NoError(t, mock.ExpectationsWereMet())
+
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.wantFlags, flags)
+		})
+	}
+}
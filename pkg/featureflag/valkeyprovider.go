@@ -0,0 +1,41 @@
+package featureflag
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+
+	libvalkey "github.com/valkey-io/valkey-go"
+)
+
+// ValkeyClient contains the methods of the github.com/Vonage/gosrvlib/pkg/valkey Client used by the ValkeyProvider.
+type ValkeyClient interface {
+	Get(ctx context.Context, key string) (string, error)
+}
+
+// ValkeyProvider is a Provider of flags stored in Valkey as a JSON array in a single key,
+// written by an external management tool (see RedisProvider).
+type ValkeyProvider struct {
+	client ValkeyClient
+	key    string
+}
+
+// NewValkeyProvider creates a new Provider of flags stored in the specified Valkey key (DefaultStorageKey if empty).
+func NewValkeyProvider(client ValkeyClient, key string) *ValkeyProvider {
+	return &ValkeyProvider{client: client, key: cmp.Or(key, DefaultStorageKey)}
+}
+
+// Load returns the flag definitions. A missing key is equivalent to no flags.
+func (p *ValkeyProvider) Load(ctx context.Context) ([]Flag, error) {
+	value, err := p.client.Get(ctx, p.key)
+	if errors.Is(err, libvalkey.Nil) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("valkey provider: %w", err)
+	}
+
+	return decodeFlags([]byte(value))
+}
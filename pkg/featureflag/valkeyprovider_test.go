@@ -0,0 +1,88 @@
+package featureflag
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	libvalkey "github.com/valkey-io/valkey-go"
+)
+
+type testValkeyClient struct {
+	key   string
+	value string
+	err   error
+}
+
+func (c *testValkeyClient) Get(_ context.Context, key string) (string, error) {
+	c.key = key
+	return c.value, c.err
+}
+
+func TestValkeyProvider_Load(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		key       string
+		value     string
+		err       error
+		wantKey   string
+		wantFlags []Flag
+		wantErr   bool
+	}{
+		{
+			name:      "success",
+			value:     `[{"key":"alpha","enabled":true}]`,
+			wantKey:   DefaultStorageKey,
+			wantFlags: []Flag{{Key: "alpha", Enabled: true}},
+		},
+		{
+			name:    "custom key",
+			key:     "flags",
+			value:   `[]`,
+			wantKey: "flags",
+		},
+		{
+			name:    "missing key",
+			err:     libvalkey.Nil,
+			wantKey: DefaultStorageKey,
+		},
+		{
+			name:    "error",
+			err:     errors.New("ERROR"),
+			wantKey: DefaultStorageKey,
+			wantErr: true,
+		},
+		{
+			name:    "invalid JSON",
+			value:   `{`,
+			wantKey: DefaultStorageKey,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &testValkeyClient{value: tt.value, err: tt.err}
+
+			flags, err := NewValkeyProvider(client, tt.key).Load(t.Context())
+			require.Equal(t, tt.wantKey, client.key)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, flags, len(tt.wantFlags))
+
+			if len(tt.wantFlags) > 0 {
+				require.Equal(t, tt.wantFlags, flags)
+			}
+		})
+	}
+}
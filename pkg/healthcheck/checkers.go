@@ -0,0 +1,183 @@
+package healthcheck
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// CheckerFunc is an adapter to allow the use of ordinary functions as HealthChecker.
+type CheckerFunc func(ctx context.Context) error
+
+// HealthCheck calls f(ctx).
+func (f CheckerFunc) HealthCheck(ctx context.Context) error {
+	return f(ctx)
+}
+
+// HostResolver is the interface to resolve host names.
+// It is implemented by *dnscache.Cache and *net.Resolver.
+type HostResolver interface {
+	LookupHost(ctx context.Context, host string) (addrs []string, err error)
+}
+
+// TCPDialChecker returns a HealthChecker that succeeds if a TCP connection can be established with the address (host:port).
+func TCPDialChecker(addr string) HealthChecker {
+	return CheckerFunc(func(ctx context.Context) error {
+		var d net.Dialer
+
+		conn, err := d.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return fmt.Errorf("failed dialing %s: %w", addr, err)
+		}
+
+		return conn.Close() //nolint:wrapcheck
+	})
+}
+
+// DNSChecker returns a HealthChecker that succeeds if the host resolves to at least one address.
+// The resolver can be a *dnscache.Cache to avoid hammering the DNS server.
+// If the resolver is nil, net.DefaultResolver is used.
+func DNSChecker(resolver HostResolver, host string) HealthChecker {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	return CheckerFunc(func(ctx context.Context) error {
+		addrs, err := resolver.LookupHost(ctx, host)
+		if err != nil {
+			return fmt.Errorf("failed resolving %s: %w", host, err)
+		}
+
+		if len(addrs) == 0 {
+			return fmt.Errorf("no addresses found for %s", host)
+		}
+
+		return nil
+	})
+}
+
+// DiskSpaceChecker returns a HealthChecker that fails if the free space available
+// in the file system containing the path is below minFreeBytes,
+// or below the minFreeRatio fraction (0 to 1) of the total size.
+// A zero threshold is ignored.
+func DiskSpaceChecker(path string, minFreeBytes uint64, minFreeRatio float64) HealthChecker {
+	return CheckerFunc(func(_ context.Context) error {
+		free, total, err := diskSpace(path)
+		if err != nil {
+			return err
+		}
+
+		if free < minFreeBytes {
+			return fmt.Errorf("free disk space %d bytes is below %d bytes", free, minFreeBytes)
+		}
+
+		if total > 0 && float64(free)/float64(total) < minFreeRatio {
+			return fmt.Errorf("free disk space %.2f%% is below %.2f%%", 100*float64(free)/float64(total), 100*minFreeRatio)
+		}
+
+		return nil
+	})
+}
+
+// GoroutineChecker returns a HealthChecker that fails if the number of goroutines exceeds the limit.
+func GoroutineChecker(limit int) HealthChecker {
+	return CheckerFunc(func(_ context.Context) error {
+		n := runtime.NumGoroutine()
+		if n > limit {
+			return fmt.Errorf("number of goroutines %d exceeds %d", n, limit)
+		}
+
+		return nil
+	})
+}
+
+// MemoryChecker returns a HealthChecker that fails if the allocated heap memory exceeds the limit in bytes.
+func MemoryChecker(limit uint64) HealthChecker {
+	return CheckerFunc(func(_ context.Context) error {
+		var m runtime.MemStats
+
+		runtime.ReadMemStats(&m)
+
+		if m.HeapAlloc > limit {
+			return fmt.Errorf("allocated heap memory %d bytes exceeds %d bytes", m.HeapAlloc, limit)
+		}
+
+		return nil
+	})
+}
+
+// TLSCertExpiryChecker returns a HealthChecker that connects to the remote address (host:port)
+// and fails if the TLS certificate is invalid or expires within minValidity.
+// The tlsConfig parameter is optional.
+func TLSCertExpiryChecker(addr string, minValidity time.Duration, tlsConfig *tls.Config) HealthChecker {
+	return CheckerFunc(func(ctx context.Context) error {
+		d := &tls.Dialer{Config: tlsConfig}
+
+		conn, err := d.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return fmt.Errorf("failed TLS dialing %s: %w", addr, err)
+		}
+
+		defer func() { _ = conn.Close() }()
+
+		certs := conn.(*tls.Conn).ConnectionState().PeerCertificates //nolint:forcetypeassert
+		if len(certs) == 0 {
+			return fmt.Errorf("no TLS certificates from %s", addr)
+		}
+
+		expiry := certs[0].NotAfter
+		if time.Until(expiry) < minValidity {
+			return fmt.Errorf("the TLS certificate of %s expires at %s", addr, expiry.UTC().Format(time.RFC3339))
+		}
+
+		return nil
+	})
+}
+
+// AllOf returns a HealthChecker that succeeds only if all the checkers succeed.
+// The checkers are executed concurrently.
+func AllOf(checkers ...HealthChecker) HealthChecker {
+	return CheckerFunc(func(ctx context.Context) error {
+		errs := runCheckers(ctx, checkers)
+
+		return errors.Join(errs...)
+	})
+}
+
+// AnyOf returns a HealthChecker that succeeds if at least one of the checkers succeeds.
+// The checkers are executed concurrently.
+func AnyOf(checkers ...HealthChecker) HealthChecker {
+	return CheckerFunc(func(ctx context.Context) error {
+		errs := runCheckers(ctx, checkers)
+
+		for _, err := range errs {
+			if err == nil {
+				return nil
+			}
+		}
+
+		return errors.Join(append([]error{errors.New("all checks failed")}, errs...)...)
+	})
+}
+
+// runCheckers executes the checkers concurrently and returns their errors in the same order.
+func runCheckers(ctx context.Context, checkers []HealthChecker) []error {
+	errs := make([]error, len(checkers))
+
+	var wg sync.WaitGroup
+
+	for i, c := range checkers {
+		wg.Go(func() {
+			errs[i] = c.HealthCheck(ctx)
+		})
+	}
+
+	wg.Wait()
+
+	return errs
+}
@@ -0,0 +1,169 @@
+package healthcheck
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/dnscache"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckerFunc(t *testing.T) {
+	t.Parallel()
+
+	err := CheckerFunc(func(_ context.Context) error { return errors.New("ERROR") }).HealthCheck(t.Context())
+	require.Error(t, err)
+
+	hc := New("func", CheckerFunc(func(_ context.Context) error { return nil }))
+	require.NoError(t, hc.Checker.HealthCheck(t.Context()))
+}
+
+func TestTCPDialChecker(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(http.NotFoundHandler())
+	defer ts.Close()
+
+	addr := ts.Listener.Addr().String()
+
+	require.NoError(t, TCPDialChecker(addr).HealthCheck(t.Context()))
+
+	ts.Close()
+
+	require.Error(t, TCPDialChecker(addr).HealthCheck(t.Context()))
+}
+
+type testResolver struct {
+	addrs []string
+	err   error
+}
+
+func (r *testResolver) LookupHost(_ context.Context, _ string) ([]string, error) {
+	return r.addrs, r.err
+}
+
+func TestDNSChecker(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		resolver HostResolver
+		wantErr  bool
+	}{
+		{
+			name:     "success",
+			resolver: &testResolver{addrs: []string{"192.0.2.1"}},
+		},
+		{
+			name:     "success with dnscache",
+			resolver: dnscache.New(&testResolver{addrs: []string{"192.0.2.1"}}, 1, 1*time.Minute),
+		},
+		{
+			name:     "lookup error",
+			resolver: &testResolver{err: errors.New("lookup error")},
+			wantErr:  true,
+		},
+		{
+			name:     "no addresses",
+			resolver: &testResolver{},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := DNSChecker(tt.resolver, "example.com").HealthCheck(t.Context())
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+
+	require.NotNil(t, DNSChecker(nil, "localhost"))
+}
+
+func TestDiskSpaceChecker(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	require.NoError(t, DiskSpaceChecker(dir, 0, 0).HealthCheck(t.Context()))
+	require.NoError(t, DiskSpaceChecker(dir, 1, 0.000001).HealthCheck(t.Context()))
+	require.Error(t, DiskSpaceChecker(dir, math.MaxUint64, 0).HealthCheck(t.Context()))
+	require.Error(t, DiskSpaceChecker(dir, 0, 1.1).HealthCheck(t.Context()))
+	require.Error(t, DiskSpaceChecker("/missing/path", 0, 0).HealthCheck(t.Context()))
+}
+
+func TestGoroutineChecker(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, GoroutineChecker(math.MaxInt).HealthCheck(t.Context()))
+	require.Error(t, GoroutineChecker(0).HealthCheck(t.Context()))
+}
+
+func TestMemoryChecker(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, MemoryChecker(math.MaxUint64).HealthCheck(t.Context()))
+	require.Error(t, MemoryChecker(1).HealthCheck(t.Context()))
+}
+
+func TestTLSCertExpiryChecker(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewTLSServer(http.NotFoundHandler())
+	defer ts.Close()
+
+	addr := ts.Listener.Addr().String()
+	tlsConfig := &tls.Config{RootCAs: ts.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs, ServerName: "example.com", MinVersion: tls.VersionTLS12} //nolint:forcetypeassert
+
+	require.NoError(t, TLSCertExpiryChecker(addr, 24*time.Hour, tlsConfig).HealthCheck(t.Context()))
+
+	// the httptest certificate expires in 2084
+	require.Error(t, TLSCertExpiryChecker(addr, 100*365*24*time.Hour, tlsConfig).HealthCheck(t.Context()))
+
+	// untrusted certificate
+	require.Error(t, TLSCertExpiryChecker(addr, 24*time.Hour, nil).HealthCheck(t.Context()))
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	closedAddr := ln.Addr().String()
+	require.NoError(t, ln.Close())
+
+	require.Error(t, TLSCertExpiryChecker(closedAddr, 24*time.Hour, tlsConfig).HealthCheck(t.Context()))
+}
+
+func TestAllOf(t *testing.T) {
+	t.Parallel()
+
+	ok := &testHealthChecker{}
+	ko := &testHealthChecker{err: errors.New("ERROR")}
+
+	require.NoError(t, AllOf().HealthCheck(t.Context()))
+	require.NoError(t, AllOf(ok, ok).HealthCheck(t.Context()))
+	require.Error(t, AllOf(ok, ko).HealthCheck(t.Context()))
+}
+
+func TestAnyOf(t *testing.T) {
+	t.Parallel()
+
+	ok := &testHealthChecker{}
+	ko := &testHealthChecker{err: errors.New("ERROR")}
+
+	require.NoError(t, AnyOf(ko, ok).HealthCheck(t.Context()))
+	require.Error(t, AnyOf(ko, ko).HealthCheck(t.Context()))
+	require.Error(t, AnyOf().HealthCheck(t.Context()))
+}
@@ -0,0 +1,22 @@
+//go:build linux || darwin
+
+package healthcheck
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// diskSpace returns the free space available to unprivileged users and the total size of the file system containing the path.
+func diskSpace(path string) (free, total uint64, err error) {
+	var st syscall.Statfs_t
+
+	err = syscall.Statfs(path, &st)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed reading the file system stats of %s: %w", path, err)
+	}
+
+	bsize := uint64(st.Bsize) //nolint:gosec,unconvert
+
+	return st.Bavail * bsize, st.Blocks * bsize, nil
+}
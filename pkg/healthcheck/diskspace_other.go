@@ -0,0 +1,12 @@
+//go:build !(linux || darwin)
+
+package healthcheck
+
+import (
+	"errors"
+)
+
+// diskSpace is not supported on this platform.
+func diskSpace(_ string) (free, total uint64, err error) {
+	return 0, 0, errors.New("disk space check not supported on this platform")
+}
@@ -2,15 +2,13 @@ package healthcheck
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/Vonage/gosrvlib/pkg/httputil"
-)
-
-const (
-	// StatusOK represents an OK status.
-	StatusOK = "OK"
+	"github.com/Vonage/gosrvlib/pkg/periodic"
 )
 
 // ResultWriter is a type alias for a function in charge of writing the result of the health checks.
@@ -18,9 +16,16 @@ type ResultWriter func(ctx context.Context, w http.ResponseWriter, statusCode in
 
 // Handler is the struct containng the HTTP handler function that performs the healthchecks.
 type Handler struct {
-	checks      []HealthCheck
-	checksCount int
-	writeResult ResultWriter
+	checks       []HealthCheck
+	checksCount  int
+	writeResult  ResultWriter
+	detailed     bool
+	checkTimeout time.Duration
+	interval     time.Duration
+	periodic     *periodic.Periodic
+	mux          sync.RWMutex
+	results      map[string]*CheckResult
+	subscribers  []SubscriberFn
 }
 
 // NewHandler creates a new instance of the healthcheck handler.
@@ -29,6 +34,7 @@ func NewHandler(checks []HealthCheck, opts ...HandlerOption) *Handler {
 		checks:      checks,
 		checksCount: len(checks),
 		writeResult: httputil.SendJSON,
+		results:     make(map[string]*CheckResult, len(checks)),
 	}
 
 	for _, apply := range opts {
@@ -38,45 +44,211 @@ func NewHandler(checks []HealthCheck, opts ...HandlerOption) *Handler {
 	return h
 }
 
-// ServeHTTP runs the configured health checks in parallel and collects their results.
-func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	type checkResult struct {
-		id  string
-		err error
+// Start runs the checks in the background at the interval set with WithBackgroundChecks.
+// It does nothing if the background checks are not enabled.
+func (h *Handler) Start(ctx context.Context) error {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	if h.interval <= 0 || h.periodic != nil {
+		return nil
+	}
+
+	p, err := periodic.New(h.interval, 1*time.Millisecond, h.interval, h.refresh)
+	if err != nil {
+		return fmt.Errorf("failed configuring the background health checks: %w", err)
+	}
+
+	h.periodic = p
+	h.periodic.Start(ctx)
+
+	return nil
+}
+
+// Stop stops the background checks.
+func (h *Handler) Stop() {
+	h.mux.RLock()
+	p := h.periodic
+	h.mux.RUnlock()
+
+	if p != nil {
+		p.Stop()
 	}
+}
 
-	resCh := make(chan checkResult, h.checksCount)
-	defer close(resCh)
+// ServeHTTP runs the configured health checks in parallel and collects their results (readiness probe).
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, h.checks)
+}
 
-	var wg sync.WaitGroup
+// ReadinessHandler returns the HTTP handler for the readiness probe, including all the checks.
+func (h *Handler) ReadinessHandler() http.HandlerFunc {
+	return h.ServeHTTP
+}
 
-	wg.Add(h.checksCount)
+// LivenessHandler returns the HTTP handler for the liveness probe,
+// including only the checks with the Liveness flag set.
+func (h *Handler) LivenessHandler() http.HandlerFunc {
+	checks := make([]HealthCheck, 0, h.checksCount)
 
 	for _, hc := range h.checks {
-		go func() { //nolint:contextcheck
-			defer wg.Done()
+		if hc.Liveness {
+			checks = append(checks, hc)
+		}
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		h.serve(w, r, checks)
+	}
+}
+
+func (h *Handler) serve(w http.ResponseWriter, r *http.Request, checks []HealthCheck) {
+	var data map[string]*CheckResult
+
+	if h.interval > 0 {
+		data = h.cached(checks)
+	}
+
+	if data == nil {
+		data = h.run(r.Context(), checks) //nolint:contextcheck
+	}
+
+	res := newResult(data)
+
+	if h.detailed {
+		h.writeResult(r.Context(), w, res.httpStatus(), res)
+		return
+	}
+
+	h.writeResult(r.Context(), w, res.httpStatus(), res.flat())
+}
+
+// refresh runs all the checks and stores the results.
+func (h *Handler) refresh(ctx context.Context) {
+	h.run(ctx, h.checks)
+}
+
+// cached returns the last results of the specified checks, or nil if any is missing.
+func (h *Handler) cached(checks []HealthCheck) map[string]*CheckResult {
+	h.mux.RLock()
+	defer h.mux.RUnlock()
+
+	data := make(map[string]*CheckResult, len(checks))
+
+	for _, hc := range checks {
+		res, ok := h.results[hc.ID]
+		if !ok {
+			return nil
+		}
+
+		data[hc.ID] = res
+	}
+
+	return data
+}
+
+// run executes the specified checks concurrently and stores the results.
+func (h *Handler) run(ctx context.Context, checks []HealthCheck) map[string]*CheckResult {
+	data := make(map[string]*CheckResult, len(checks))
+
+	var (
+		wg  sync.WaitGroup
+		mux sync.Mutex
+	)
+
+	for _, hc := range checks {
+		wg.Go(func() {
+			res := h.check(ctx, hc)
+
+			mux.Lock()
+			defer mux.Unlock()
 
-			resCh <- checkResult{
-				id:  hc.ID,
-				err: hc.Checker.HealthCheck(r.Context()),
-			}
-		}()
+			data[hc.ID] = res
+		})
 	}
 
 	wg.Wait()
 
-	status := http.StatusOK
-	data := make(map[string]string, h.checksCount)
+	changes := h.store(data)
 
-	for len(resCh) > 0 {
-		r := <-resCh
-		data[r.id] = StatusOK
+	h.notify(ctx, changes)
 
-		if r.err != nil {
-			status = http.StatusServiceUnavailable
-			data[r.id] = r.err.Error()
+	return data
+}
+
+// store saves the results, sets the last success time and returns the status changes.
+func (h *Handler) store(data map[string]*CheckResult) []*StatusChange {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	var changes []*StatusChange
+
+	for id, res := range data {
+		prev := h.results[id]
+
+		if res.LastSuccess == nil && prev != nil {
+			res.LastSuccess = prev.LastSuccess
 		}
+
+		if change := newStatusChange(id, prev, res); change != nil {
+			changes = append(changes, change)
+		}
+
+		h.results[id] = res
+	}
+
+	return changes
+}
+
+// check executes a single health check within its timeout.
+func (h *Handler) check(ctx context.Context, hc HealthCheck) *CheckResult {
+	timeout := hc.Timeout
+	if timeout <= 0 {
+		timeout = h.checkTimeout
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	start := time.Now().UTC()
+	err := runCheck(ctx, hc.Checker)
+
+	res := &CheckResult{
+		Status:    StatusOK,
+		Critical:  hc.Criticality == Critical,
+		Latency:   time.Since(start).String(),
+		CheckedAt: start,
 	}
 
-	h.writeResult(r.Context(), w, status, data)
+	if err != nil {
+		res.Status = StatusFail
+		res.Error = err.Error()
+
+		return res
+	}
+
+	res.LastSuccess = &start
+
+	return res
+}
+
+// runCheck executes the checker and returns as soon as the context is done,
+// even if the checker does not honor the context.
+func runCheck(ctx context.Context, checker HealthChecker) error {
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- checker.HealthCheck(ctx)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("health check timeout: %w", ctx.Err())
+	}
 }
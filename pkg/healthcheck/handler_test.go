@@ -2,11 +2,13 @@ package healthcheck
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -88,6 +90,27 @@ func TestHandler_ServeHTTP(t *testing.T) {
 			wantBody:       `{"test_31":"OK","test_32":"check error"}`,
 			wantMaxElapsed: 300 * time.Millisecond,
 		},
+		{
+			name: "degraded with non-critical failure",
+			checks: []HealthCheck{
+				New("test_41", &testHealthChecker{}),
+				New("test_42", &testHealthChecker{err: errors.New("check error")}, WithCriticality(NonCritical)),
+			},
+			wantStatus:     http.StatusOK,
+			wantBody:       `{"test_41":"OK","test_42":"check error"}`,
+			wantMaxElapsed: 100 * time.Millisecond,
+		},
+		{
+			name: "check timeout",
+			checks: []HealthCheck{
+				NewWithTimeout("test_51", &testHealthChecker{delay: 1 * time.Second}, 50*time.Millisecond),
+				New("test_52", &testHealthChecker{delay: 1 * time.Second}),
+			},
+			opts:           []HandlerOption{WithCheckTimeout(100 * time.Millisecond)},
+			wantStatus:     http.StatusServiceUnavailable,
+			wantBody:       `{"test_51":"health check timeout: context deadline exceeded","test_52":"health check timeout: context deadline exceeded"}`,
+			wantMaxElapsed: 300 * time.Millisecond,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -125,3 +148,135 @@ func TestHandler_ServeHTTP(t *testing.T) {
 		})
 	}
 }
+
+// serveResult calls the handler and returns the status code and the decoded body.
+func serveResult(t *testing.T, handler http.HandlerFunc, data any) int {
+	t.Helper()
+
+	rr := httptest.NewRecorder()
+	req, err := http.NewRequestWithContext(testutil.Context(), http.MethodGet, "/", nil)
+	require.NoError(t, err, "no error expected reading body data")
+
+	handler(rr, req)
+
+	require.Equal(t, "application/json; charset=utf-8", rr.Header().Get("Content-Type"))
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), data))
+
+	return rr.Code
+}
+
+func TestHandler_ServeHTTP_detailed(t *testing.T) {
+	t.Parallel()
+
+	h := NewHandler([]HealthCheck{
+		New("test_61", &testHealthChecker{}),
+		New("test_62", &testHealthChecker{err: errors.New("check error")}, WithCriticality(NonCritical)),
+	}, WithDetailedResult())
+
+	res := &Result{}
+	code := serveResult(t, h.ServeHTTP, res)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, StatusDegraded, res.Status)
+	require.Len(t, res.Checks, 2)
+	require.Equal(t, StatusOK, res.Checks["test_61"].Status)
+	require.True(t, res.Checks["test_61"].Critical)
+	require.Equal(t, StatusFail, res.Checks["test_62"].Status)
+	require.Equal(t, "check error", res.Checks["test_62"].Error)
+	require.False(t, res.Checks["test_62"].Critical)
+}
+
+func TestHandler_LivenessHandler(t *testing.T) {
+	t.Parallel()
+
+	h := NewHandler([]HealthCheck{
+		New("process", &testHealthChecker{}, WithLiveness()),
+		New("database", &testHealthChecker{err: errors.New("down")}),
+	})
+
+	res := map[string]string{}
+	code := serveResult(t, h.LivenessHandler(), &res)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, map[string]string{"process": StatusOK}, res)
+
+	res = map[string]string{}
+	code = serveResult(t, h.ReadinessHandler(), &res)
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, map[string]string{"process": StatusOK, "database": "down"}, res)
+}
+
+type toggleHealthChecker struct {
+	calls atomic.Int32
+	fail  atomic.Bool
+}
+
+func (c *toggleHealthChecker) HealthCheck(_ context.Context) error {
+	c.calls.Add(1)
+
+	if c.fail.Load() {
+		return errors.New("failed")
+	}
+
+	return nil
+}
+
+func TestHandler_lastSuccess(t *testing.T) {
+	t.Parallel()
+
+	checker := &toggleHealthChecker{}
+	h := NewHandler([]HealthCheck{New("toggle", checker)}, WithDetailedResult())
+
+	res := &Result{}
+	serveResult(t, h.ServeHTTP, res)
+	require.NotNil(t, res.Checks["toggle"].LastSuccess)
+	require.NotEmpty(t, res.Checks["toggle"].Latency)
+
+	lastSuccess := *res.Checks["toggle"].LastSuccess
+
+	checker.fail.Store(true)
+
+	res = &Result{}
+	code := serveResult(t, h.ServeHTTP, res)
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, StatusFail, res.Checks["toggle"].Status)
+	require.NotNil(t, res.Checks["toggle"].LastSuccess)
+	require.True(t, lastSuccess.Equal(*res.Checks["toggle"].LastSuccess))
+}
+
+func TestHandler_backgroundChecks(t *testing.T) {
+	t.Parallel()
+
+	checker := &toggleHealthChecker{}
+	h := NewHandler([]HealthCheck{New("toggle", checker)}, WithBackgroundChecks(20*time.Millisecond))
+
+	require.NoError(t, h.Start(t.Context()))
+	require.NoError(t, h.Start(t.Context()))
+
+	defer h.Stop()
+
+	require.Eventually(t, func() bool { return checker.calls.Load() > 0 }, 1*time.Second, 5*time.Millisecond)
+
+	// requests are served from the cache
+	calls := checker.calls.Load()
+
+	for range 10 {
+		code := serveResult(t, h.ServeHTTP, &map[string]string{})
+		require.Equal(t, http.StatusOK, code)
+	}
+
+	require.Less(t, checker.calls.Load(), calls+5)
+
+	checker.fail.Store(true)
+
+	require.Eventually(t, func() bool {
+		code := serveResult(t, h.ServeHTTP, &map[string]string{})
+		return code == http.StatusServiceUnavailable
+	}, 1*time.Second, 5*time.Millisecond)
+}
+
+func TestHandler_Start_disabled(t *testing.T) {
+	t.Parallel()
+
+	h := NewHandler(nil)
+	require.NoError(t, h.Start(t.Context()))
+	h.Stop()
+}
@@ -1,7 +1,28 @@
 /*
 Package healthcheck provides a simple way to define health checks for external services or components.
 
-It provides an HTTP handler to collect and return the results of the health checks concurrently.
+It provides HTTP handlers to collect and return the results of the health checks concurrently.
+
+The checks are part of the readiness probe (ServeHTTP or ReadinessHandler) and
+can also be included in the liveness probe (LivenessHandler). Each check can
+have its own timeout, and can be marked as non-critical: a failing non-critical
+check reports a degraded status without failing the probe.
+
+By default the probes return a JSON map of check IDs to "OK" or the error
+message. The overall status and the details of each check (see Result) can be
+returned instead with WithDetailedResult.
+
+Ready-made checkers are provided for HTTP endpoints (CheckHTTPStatus), TCP
+dial, DNS resolution, disk free space, goroutine count, memory usage, remote TLS
+certificate expiry, and for combining other checkers (AllOf and AnyOf).
+
+The checks can run in the background at a fixed interval (WithBackgroundChecks)
+so the probes return the cached results without hammering the dependencies.
+
+The status transitions of each check can be notified to subscribers
+(WithSubscribers). Ready-made subscribers log the changes (LogSubscriber), set
+a metrics gauge (MetricsSubscriber) and post to Slack (SlackSubscriber), and
+DampedSubscriber suppresses the notifications of flapping checks.
 
 For an implementation example, see the file examples/service/internal/cli/bind.go.
 */
@@ -9,6 +30,7 @@ package healthcheck
 
 import (
 	"context"
+	"time"
 )
 
 // HealthChecker is the interface that wraps the HealthCheck method.
@@ -16,6 +38,17 @@ type HealthChecker interface {
 	HealthCheck(ctx context.Context) error
 }
 
+// Criticality defines the impact of a failing health check on the overall status.
+type Criticality int
+
+const (
+	// Critical checks cause the probe to fail (503 Service Unavailable) when failing.
+	Critical Criticality = iota
+
+	// NonCritical checks only report a degraded status (200 OK) when failing.
+	NonCritical
+)
+
 // HealthCheck is a structure containing the configuration for a single health check.
 type HealthCheck struct {
 	// ID is a unique identifier for the healthcheck.
@@ -23,12 +56,34 @@ type HealthCheck struct {
 
 	// Checker is the function used to perform the healthchecks.
 	Checker HealthChecker
+
+	// Timeout is the maximum duration of the check.
+	// If zero, the handler default is used (see WithCheckTimeout).
+	Timeout time.Duration
+
+	// Criticality defines whether a failure of this check fails the probe (default Critical).
+	Criticality Criticality
+
+	// Liveness includes this check in the liveness probe.
+	// All the checks are always included in the readiness probe.
+	Liveness bool
 }
 
 // New creates a new instance of a health check configuration with default timeout.
-func New(id string, checker HealthChecker) HealthCheck {
-	return HealthCheck{
+func New(id string, checker HealthChecker, opts ...Option) HealthCheck {
+	hc := HealthCheck{
 		ID:      id,
 		Checker: checker,
 	}
+
+	for _, apply := range opts {
+		apply(&hc)
+	}
+
+	return hc
+}
+
+// NewWithTimeout creates a new instance of a health check configuration with the specified timeout.
+func NewWithTimeout(id string, checker HealthChecker, timeout time.Duration, opts ...Option) HealthCheck {
+	return New(id, checker, append([]Option{WithTimeout(timeout)}, opts...)...)
 }
@@ -30,3 +30,15 @@ func TestNew(t *testing.T) {
 	require.Equal(t, "hc-id_1", h.ID)
 	require.Equal(t, h.Checker, hc)
 }
+
+func TestNewWithTimeout(t *testing.T) {
+	t.Parallel()
+
+	hc := &testHealthChecker{}
+	h := NewWithTimeout("hc-id_2", hc, 2*time.Second, WithCriticality(NonCritical), WithLiveness())
+	require.Equal(t, "hc-id_2", h.ID)
+	require.Equal(t, h.Checker, hc)
+	require.Equal(t, 2*time.Second, h.Timeout)
+	require.Equal(t, NonCritical, h.Criticality)
+	require.True(t, h.Liveness)
+}
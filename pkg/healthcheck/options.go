@@ -1,5 +1,9 @@
 package healthcheck
 
+import (
+	"time"
+)
+
 // HandlerOption is a type alias for a function that configures the healthcheck HTTP handler.
 type HandlerOption func(h *Handler)
 
@@ -9,3 +13,57 @@ func WithResultWriter(w ResultWriter) HandlerOption {
 		h.writeResult = w
 	}
 }
+
+// WithDetailedResult writes the overall status and the details of each check (see Result)
+// instead of the default map of check IDs to "OK" or the error message.
+func WithDetailedResult() HandlerOption {
+	return func(h *Handler) {
+		h.detailed = true
+	}
+}
+
+// WithCheckTimeout sets the default timeout for the checks without a specific one.
+func WithCheckTimeout(timeout time.Duration) HandlerOption {
+	return func(h *Handler) {
+		h.checkTimeout = timeout
+	}
+}
+
+// WithBackgroundChecks runs the checks in the background at the specified interval after calling Start.
+// The handlers return the cached results of the last run.
+func WithBackgroundChecks(interval time.Duration) HandlerOption {
+	return func(h *Handler) {
+		h.interval = interval
+	}
+}
+
+// WithSubscribers adds functions called on the health check status transitions (see SubscriberFn).
+func WithSubscribers(fns ...SubscriberFn) HandlerOption {
+	return func(h *Handler) {
+		h.subscribers = append(h.subscribers, fns...)
+	}
+}
+
+// Option is a type alias for a function that configures a single HealthCheck.
+type Option func(hc *HealthCheck)
+
+// WithTimeout sets the maximum duration of the check.
+func WithTimeout(timeout time.Duration) Option {
+	return func(hc *HealthCheck) {
+		hc.Timeout = timeout
+	}
+}
+
+// WithCriticality sets the criticality of the check.
+func WithCriticality(c Criticality) Option {
+	return func(hc *HealthCheck) {
+		hc.Criticality = c
+	}
+}
+
+// WithLiveness includes the check in the liveness probe.
+func WithLiveness() Option {
+	return func(hc *HealthCheck) {
+		hc.Liveness = true
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -17,3 +18,59 @@ func TestWithResultWriter(t *testing.T) {
 	WithResultWriter(v)(h)
 	require.Equal(t, reflect.ValueOf(v).Pointer(), reflect.ValueOf(h.writeResult).Pointer())
 }
+
+func TestWithDetailedResult(t *testing.T) {
+	t.Parallel()
+
+	h := &Handler{}
+	WithDetailedResult()(h)
+	require.True(t, h.detailed)
+}
+
+func TestWithCheckTimeout(t *testing.T) {
+	t.Parallel()
+
+	h := &Handler{}
+	WithCheckTimeout(3 * time.Second)(h)
+	require.Equal(t, 3*time.Second, h.checkTimeout)
+}
+
+func TestWithBackgroundChecks(t *testing.T) {
+	t.Parallel()
+
+	h := &Handler{}
+	WithBackgroundChecks(5 * time.Second)(h)
+	require.Equal(t, 5*time.Second, h.interval)
+}
+
+func TestWithTimeout(t *testing.T) {
+	t.Parallel()
+
+	hc := &HealthCheck{}
+	WithTimeout(2 * time.Second)(hc)
+	require.Equal(t, 2*time.Second, hc.Timeout)
+}
+
+func TestWithCriticality(t *testing.T) {
+	t.Parallel()
+
+	hc := &HealthCheck{}
+	WithCriticality(NonCritical)(hc)
+	require.Equal(t, NonCritical, hc.Criticality)
+}
+
+func TestWithLiveness(t *testing.T) {
+	t.Parallel()
+
+	hc := &HealthCheck{}
+	WithLiveness()(hc)
+	require.True(t, hc.Liveness)
+}
+
+func TestWithSubscribers(t *testing.T) {
+	t.Parallel()
+
+	h := &Handler{}
+	WithSubscribers(LogSubscriber(), LogSubscriber())(h)
+	require.Len(t, h.subscribers, 2)
+}
@@ -0,0 +1,94 @@
+package healthcheck
+
+import (
+	"net/http"
+	"time"
+)
+
+const (
+	// StatusOK represents an OK status.
+	StatusOK = "OK"
+
+	// StatusDegraded represents the status of a probe with failing non-critical checks.
+	StatusDegraded = "DEGRADED"
+
+	// StatusFail represents a failing check or a probe with failing critical checks.
+	StatusFail = "FAIL"
+)
+
+// Result contains the overall status and the results of the single health checks.
+type Result struct {
+	// Status is the overall status: StatusOK, StatusDegraded or StatusFail.
+	Status string `json:"status"`
+
+	// Checks contains the result of each check indexed by ID.
+	Checks map[string]*CheckResult `json:"checks"`
+}
+
+// CheckResult contains the result of a single health check.
+type CheckResult struct {
+	// Status is StatusOK or StatusFail.
+	Status string `json:"status"`
+
+	// Error is the error message of a failing check.
+	Error string `json:"error,omitempty"`
+
+	// Critical is true if the failure of this check fails the probe.
+	Critical bool `json:"critical"`
+
+	// Latency is the duration of the check.
+	Latency string `json:"latency"`
+
+	// CheckedAt is the time of the check.
+	CheckedAt time.Time `json:"checked_at"`
+
+	// LastSuccess is the time of the last successful check, if any.
+	LastSuccess *time.Time `json:"last_success,omitempty"`
+}
+
+// newResult returns the overall result of the specified check results.
+func newResult(checks map[string]*CheckResult) *Result {
+	res := &Result{
+		Status: StatusOK,
+		Checks: checks,
+	}
+
+	for _, c := range checks {
+		if c.Status == StatusOK {
+			continue
+		}
+
+		if c.Critical {
+			res.Status = StatusFail
+			break
+		}
+
+		res.Status = StatusDegraded
+	}
+
+	return res
+}
+
+// flat returns the map of check IDs to StatusOK or the error message.
+func (r *Result) flat() map[string]string {
+	data := make(map[string]string, len(r.Checks))
+
+	for id, c := range r.Checks {
+		data[id] = StatusOK
+
+		if c.Status != StatusOK {
+			data[id] = c.Error
+		}
+	}
+
+	return data
+}
+
+// httpStatus returns the HTTP status code corresponding to the overall status.
+func (r *Result) httpStatus() int {
+	if r.Status == StatusFail {
+		return http.StatusServiceUnavailable
+	}
+
+	return http.StatusOK
+}
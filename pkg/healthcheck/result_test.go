@@ -0,0 +1,74 @@
+package healthcheck
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_newResult(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		checks     map[string]*CheckResult
+		wantStatus string
+		wantCode   int
+	}{
+		{
+			name:       "no checks",
+			checks:     map[string]*CheckResult{},
+			wantStatus: StatusOK,
+			wantCode:   http.StatusOK,
+		},
+		{
+			name: "all OK",
+			checks: map[string]*CheckResult{
+				"a": {Status: StatusOK, Critical: true},
+				"b": {Status: StatusOK},
+			},
+			wantStatus: StatusOK,
+			wantCode:   http.StatusOK,
+		},
+		{
+			name: "non-critical failure",
+			checks: map[string]*CheckResult{
+				"a": {Status: StatusOK, Critical: true},
+				"b": {Status: StatusFail},
+			},
+			wantStatus: StatusDegraded,
+			wantCode:   http.StatusOK,
+		},
+		{
+			name: "critical failure",
+			checks: map[string]*CheckResult{
+				"a": {Status: StatusFail, Critical: true},
+				"b": {Status: StatusFail},
+			},
+			wantStatus: StatusFail,
+			wantCode:   http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			res := newResult(tt.checks)
+			require.Equal(t, tt.wantStatus, res.Status)
+			require.Equal(t, tt.wantCode, res.httpStatus())
+		})
+	}
+}
+
+func TestResult_flat(t *testing.T) {
+	t.Parallel()
+
+	res := newResult(map[string]*CheckResult{
+		"a": {Status: StatusOK},
+		"b": {Status: StatusFail, Error: "down"},
+	})
+
+	require.Equal(t, map[string]string{"a": StatusOK, "b": "down"}, res.flat())
+}
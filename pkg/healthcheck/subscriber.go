@@ -0,0 +1,220 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/logging"
+	"github.com/Vonage/gosrvlib/pkg/metrics"
+	"go.uber.org/zap"
+)
+
+// StatusChange describes a status transition of a health check.
+type StatusChange struct {
+	// ID is the health check identifier.
+	ID string
+
+	// Previous is the previous status (StatusOK or StatusFail), or empty for the first result.
+	Previous string
+
+	// Current is the new status (StatusOK or StatusFail).
+	Current string
+
+	// Error is the error message of a failing check.
+	Error string
+
+	// Critical is true if the failure of this check fails the probe.
+	Critical bool
+
+	// Time is the time of the check.
+	Time time.Time
+}
+
+// SubscriberFn is the type of function called on the health check status transitions.
+// It is called synchronously after each check run and should return quickly.
+type SubscriberFn func(ctx context.Context, change *StatusChange)
+
+// SlackSender is the interface to send a message to Slack (see slack.Client.Send).
+type SlackSender interface {
+	Send(ctx context.Context, text, username, iconEmoji, iconURL, channel string) error
+}
+
+// newStatusChange returns the status change between the previous and current results, or nil.
+func newStatusChange(id string, prev, cur *CheckResult) *StatusChange {
+	var previous string
+
+	if prev != nil {
+		previous = prev.Status
+	}
+
+	if previous == cur.Status {
+		return nil
+	}
+
+	return &StatusChange{
+		ID:       id,
+		Previous: previous,
+		Current:  cur.Status,
+		Error:    cur.Error,
+		Critical: cur.Critical,
+		Time:     cur.CheckedAt,
+	}
+}
+
+// notify calls the subscribers for each status change.
+func (h *Handler) notify(ctx context.Context, changes []*StatusChange) {
+	for _, change := range changes {
+		for _, fn := range h.subscribers {
+			fn(ctx, change)
+		}
+	}
+}
+
+// LogSubscriber returns a subscriber that logs the status transitions with the context logger.
+// The initial OK status is not logged.
+func LogSubscriber() SubscriberFn {
+	return func(ctx context.Context, change *StatusChange) {
+		if change.Previous == "" && change.Current == StatusOK {
+			return
+		}
+
+		l := logging.FromContext(ctx).With(
+			zap.String("check", change.ID),
+			zap.String("previous_status", change.Previous),
+			zap.String("status", change.Current),
+			zap.Bool("critical", change.Critical),
+		)
+
+		switch {
+		case change.Current == StatusOK:
+			l.Info("health check recovered")
+		case change.Critical:
+			l.Error("health check failed", zap.String("error", change.Error))
+		default:
+			l.Warn("health check failed", zap.String("error", change.Error))
+		}
+	}
+}
+
+// MetricsSubscriber returns a subscriber that sets the status gauge of each check.
+// It does nothing if the client does not implement metrics.HealthCheckStatusSetter.
+func MetricsSubscriber(m metrics.Client) SubscriberFn {
+	return func(_ context.Context, change *StatusChange) {
+		metrics.SetHealthCheckStatus(m, change.ID, change.Current == StatusOK)
+	}
+}
+
+// slackQueueSize is the maximum number of Slack messages waiting to be sent.
+const slackQueueSize = 64
+
+type slackMessage struct {
+	ctx  context.Context //nolint:containedctx
+	text string
+}
+
+// SlackSubscriber returns a subscriber that posts the status transitions to Slack
+// using the default client settings.
+// The initial OK status is not posted, and the messages are sent asynchronously
+// in order by a single background worker started on the first message.
+// The messages are dropped (and logged) if the queue is full.
+// The prefix is added to the messages to identify the service.
+// Use DampedSubscriber to avoid spamming the channel with flapping checks.
+func SlackSubscriber(s SlackSender, prefix string) SubscriberFn {
+	var once sync.Once
+
+	queue := make(chan *slackMessage, slackQueueSize)
+
+	worker := func() {
+		for msg := range queue {
+			err := s.Send(msg.ctx, msg.text, "", "", "", "")
+			if err != nil {
+				logging.FromContext(msg.ctx).Error("failed sending the health check status change to Slack", zap.Error(err))
+			}
+		}
+	}
+
+	return func(ctx context.Context, change *StatusChange) {
+		if change.Previous == "" && change.Current == StatusOK {
+			return
+		}
+
+		text := fmt.Sprintf("%s health check %q changed from %s to %s", prefix, change.ID, change.Previous, change.Current)
+
+		if change.Error != "" {
+			text += ": " + change.Error
+		}
+
+		once.Do(func() { go worker() })
+
+		select {
+		case queue <- &slackMessage{ctx: context.WithoutCancel(ctx), text: text}:
+		default:
+			logging.FromContext(ctx).Error("dropped the health check status change to Slack: queue full", zap.String("check", change.ID))
+		}
+	}
+}
+
+// DampedSubscriber wraps a subscriber to suppress the notifications of flapping checks.
+// A status change is forwarded only if the new status is still current after the hold duration,
+// and it differs from the last forwarded status of the same check.
+func DampedSubscriber(fn SubscriberFn, hold time.Duration) SubscriberFn {
+	d := &damper{
+		fn:      fn,
+		hold:    hold,
+		pending: make(map[string]*time.Timer),
+		last:    make(map[string]string),
+	}
+
+	return d.subscribe
+}
+
+type damper struct {
+	mux     sync.Mutex
+	fn      SubscriberFn
+	hold    time.Duration
+	pending map[string]*time.Timer
+	last    map[string]string
+}
+
+func (d *damper) subscribe(ctx context.Context, change *StatusChange) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	if t, ok := d.pending[change.ID]; ok {
+		t.Stop()
+		delete(d.pending, change.ID)
+	}
+
+	if change.Current == d.last[change.ID] {
+		// the check flapped back to the last forwarded status
+		return
+	}
+
+	ctx = context.WithoutCancel(ctx)
+
+	var t *time.Timer
+
+	t = time.AfterFunc(d.hold, func() {
+		d.mux.Lock()
+
+		if d.pending[change.ID] != t {
+			// stopped too late: replaced by a newer change or cancelled
+			d.mux.Unlock()
+			return
+		}
+
+		delete(d.pending, change.ID)
+
+		fwd := *change
+		fwd.Previous = d.last[change.ID]
+		d.last[change.ID] = change.Current
+
+		d.mux.Unlock()
+
+		d.fn(ctx, &fwd)
+	})
+
+	d.pending[change.ID] = t
+}
@@ -0,0 +1,207 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/metrics"
+	"github.com/Vonage/gosrvlib/pkg/testutil"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type changeRecorder struct {
+	mux     sync.Mutex
+	changes []*StatusChange
+}
+
+func (r *changeRecorder) subscribe(_ context.Context, change *StatusChange) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	r.changes = append(r.changes, change)
+}
+
+func (r *changeRecorder) list() []*StatusChange {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	return append([]*StatusChange{}, r.changes...)
+}
+
+func TestHandler_subscribers(t *testing.T) {
+	t.Parallel()
+
+	rec := &changeRecorder{}
+	checker := &toggleHealthChecker{}
+	h := NewHandler([]HealthCheck{New("toggle", checker, WithCriticality(NonCritical))}, WithSubscribers(rec.subscribe))
+
+	serveResult(t, h.ServeHTTP, &map[string]string{})
+	serveResult(t, h.ServeHTTP, &map[string]string{})
+
+	checker.fail.Store(true)
+
+	serveResult(t, h.ServeHTTP, &map[string]string{})
+	serveResult(t, h.ServeHTTP, &map[string]string{})
+
+	changes := rec.list()
+	require.Len(t, changes, 2)
+
+	require.Equal(t, "toggle", changes[0].ID)
+	require.Empty(t, changes[0].Previous)
+	require.Equal(t, StatusOK, changes[0].Current)
+
+	require.Equal(t, StatusOK, changes[1].Previous)
+	require.Equal(t, StatusFail, changes[1].Current)
+	require.Equal(t, "failed", changes[1].Error)
+	require.False(t, changes[1].Critical)
+	require.False(t, changes[1].Time.IsZero())
+}
+
+func TestLogSubscriber(t *testing.T) {
+	t.Parallel()
+
+	ctx, logs := testutil.ContextWithLogObserver(zap.DebugLevel)
+	fn := LogSubscriber()
+
+	fn(ctx, &StatusChange{ID: "a", Current: StatusOK})
+	require.Equal(t, 0, logs.Len())
+
+	fn(ctx, &StatusChange{ID: "a", Previous: StatusOK, Current: StatusFail, Error: "down", Critical: true})
+	fn(ctx, &StatusChange{ID: "b", Previous: StatusOK, Current: StatusFail, Error: "down"})
+	fn(ctx, &StatusChange{ID: "a", Previous: StatusFail, Current: StatusOK})
+
+	entries := logs.All()
+	require.Len(t, entries, 3)
+	require.Equal(t, zap.ErrorLevel, entries[0].Level)
+	require.Equal(t, zap.WarnLevel, entries[1].Level)
+	require.Equal(t, zap.InfoLevel, entries[2].Level)
+	require.Equal(t, "health check recovered", entries[2].Message)
+}
+
+type testMetrics struct {
+	metrics.Default
+
+	mux    sync.Mutex
+	status map[string]bool
+}
+
+func (m *testMetrics) SetHealthCheckStatus(check string, healthy bool) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	m.status[check] = healthy
+}
+
+func TestMetricsSubscriber(t *testing.T) {
+	t.Parallel()
+
+	m := &testMetrics{status: make(map[string]bool)}
+	fn := MetricsSubscriber(m)
+
+	fn(t.Context(), &StatusChange{ID: "a", Current: StatusOK})
+	fn(t.Context(), &StatusChange{ID: "b", Current: StatusFail})
+
+	require.Equal(t, map[string]bool{"a": true, "b": false}, m.status)
+}
+
+type testSlackSender struct {
+	msgs chan string
+	err  error
+}
+
+func (s *testSlackSender) Send(_ context.Context, text, _, _, _, _ string) error {
+	s.msgs <- text
+	return s.err
+}
+
+func TestSlackSubscriber(t *testing.T) {
+	t.Parallel()
+
+	s := &testSlackSender{msgs: make(chan string, 10), err: errors.New("ERROR")}
+	fn := SlackSubscriber(s, "[svc]")
+
+	fn(testutil.Context(), &StatusChange{ID: "db", Current: StatusOK})
+	fn(testutil.Context(), &StatusChange{ID: "db", Previous: StatusOK, Current: StatusFail, Error: "timeout"})
+
+	select {
+	case msg := <-s.msgs:
+		require.Equal(t, `[svc] health check "db" changed from OK to FAIL: timeout`, msg)
+	case <-time.After(1 * time.Second):
+		t.Fatal("slack message not sent")
+	}
+
+	require.Empty(t, s.msgs)
+}
+
+type blockingSlackSender struct {
+	release chan struct{}
+	calls   atomic.Int32
+	sent    atomic.Int32
+}
+
+func (s *blockingSlackSender) Send(_ context.Context, _, _, _, _, _ string) error {
+	s.calls.Add(1)
+	<-s.release
+	s.sent.Add(1)
+
+	return nil
+}
+
+func TestSlackSubscriber_queueFull(t *testing.T) {
+	t.Parallel()
+
+	s := &blockingSlackSender{release: make(chan struct{})}
+	fn := SlackSubscriber(s, "[svc]")
+	change := &StatusChange{ID: "db", Previous: StatusOK, Current: StatusFail}
+
+	// the worker blocks on the first message
+	fn(testutil.Context(), change)
+	require.Eventually(t, func() bool { return s.calls.Load() == 1 }, 1*time.Second, time.Millisecond)
+
+	// the messages exceeding the queue size are dropped
+	for range slackQueueSize + 10 {
+		fn(testutil.Context(), change)
+	}
+
+	close(s.release)
+
+	require.Eventually(t, func() bool { return s.sent.Load() == slackQueueSize+1 }, 1*time.Second, time.Millisecond)
+
+	time.Sleep(20 * time.Millisecond)
+	require.Equal(t, int32(slackQueueSize+1), s.sent.Load())
+}
+
+func TestDampedSubscriber(t *testing.T) {
+	t.Parallel()
+
+	rec := &changeRecorder{}
+	fn := DampedSubscriber(rec.subscribe, 50*time.Millisecond)
+	ctx := t.Context()
+
+	// initial status is forwarded after the hold time
+	fn(ctx, &StatusChange{ID: "a", Current: StatusOK})
+	require.Eventually(t, func() bool { return len(rec.list()) == 1 }, 1*time.Second, 5*time.Millisecond)
+
+	// flapping changes are suppressed
+	for range 5 {
+		fn(ctx, &StatusChange{ID: "a", Previous: StatusOK, Current: StatusFail})
+		fn(ctx, &StatusChange{ID: "a", Previous: StatusFail, Current: StatusOK})
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	require.Len(t, rec.list(), 1)
+
+	// stable changes are forwarded
+	fn(ctx, &StatusChange{ID: "a", Previous: StatusOK, Current: StatusFail, Error: "down"})
+	require.Eventually(t, func() bool { return len(rec.list()) == 2 }, 1*time.Second, 5*time.Millisecond)
+
+	changes := rec.list()
+	require.Equal(t, StatusOK, changes[1].Previous)
+	require.Equal(t, StatusFail, changes[1].Current)
+	require.Equal(t, "down", changes[1].Error)
+}
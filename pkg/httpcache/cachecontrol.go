@@ -0,0 +1,64 @@
+package httpcache
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cache-Control directives.
+const (
+	ccMaxAge         = "max-age"
+	ccMaxStale       = "max-stale"
+	ccMinFresh       = "min-fresh"
+	ccMustRevalidate = "must-revalidate"
+	ccNoCache        = "no-cache"
+	ccNoStore        = "no-store"
+	ccPrivate        = "private"
+	ccPublic         = "public"
+	ccSMaxAge        = "s-maxage"
+	ccStaleIfError   = "stale-if-error"
+)
+
+// cacheControl contains the parsed Cache-Control directives with lowercase names.
+type cacheControl map[string]string
+
+// parseCacheControl parses all the Cache-Control header values.
+func parseCacheControl(h http.Header) cacheControl {
+	cc := make(cacheControl)
+
+	for _, v := range h.Values("Cache-Control") {
+		for d := range strings.SplitSeq(v, ",") {
+			name, value, _ := strings.Cut(strings.TrimSpace(d), "=")
+			if name == "" {
+				continue
+			}
+
+			cc[strings.ToLower(name)] = strings.Trim(value, `"`)
+		}
+	}
+
+	return cc
+}
+
+// has returns true if the directive is present.
+func (cc cacheControl) has(directive string) bool {
+	_, ok := cc[directive]
+	return ok
+}
+
+// duration returns the value of a delta-seconds directive.
+func (cc cacheControl) duration(directive string) (time.Duration, bool) {
+	v, ok := cc[directive]
+	if !ok {
+		return 0, false
+	}
+
+	sec, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || sec < 0 {
+		return 0, false
+	}
+
+	return time.Duration(sec) * time.Second, true
+}
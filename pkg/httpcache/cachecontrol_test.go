@@ -0,0 +1,37 @@
+package httpcache
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseCacheControl(t *testing.T) {
+	t.Parallel()
+
+	h := http.Header{}
+	h.Add("Cache-Control", `Public, max-age=60, ,private="Set-Cookie"`)
+	h.Add("Cache-Control", "s-maxage=invalid, no-cache=-1")
+
+	cc := parseCacheControl(h)
+
+	require.True(t, cc.has(ccPublic))
+	require.True(t, cc.has(ccPrivate))
+	require.False(t, cc.has(ccNoStore))
+	require.Equal(t, "Set-Cookie", cc[ccPrivate])
+
+	d, ok := cc.duration(ccMaxAge)
+	require.True(t, ok)
+	require.Equal(t, 60*time.Second, d)
+
+	_, ok = cc.duration(ccSMaxAge)
+	require.False(t, ok)
+
+	_, ok = cc.duration(ccNoCache)
+	require.False(t, ok)
+
+	_, ok = cc.duration(ccNoStore)
+	require.False(t, ok)
+}
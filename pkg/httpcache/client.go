@@ -0,0 +1,457 @@
+package httpcache
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/logging"
+	"github.com/Vonage/gosrvlib/pkg/metrics"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultClientKeyPrefix is the default prefix of the client cache keys.
+	DefaultClientKeyPrefix = "httpclientcache:"
+
+	// DefaultClientStaleTTL is the default time the stale responses with validators
+	// or the stale-if-error directive are kept in the store after their expiration.
+	DefaultClientStaleTTL = 1 * time.Hour
+
+	// DefaultClientMaxBodySize is the default maximum size of the stored response bodies.
+	DefaultClientMaxBodySize = 1 << 20
+)
+
+// ClientCache metrics outcomes.
+const (
+	clientMetricsTask  = "httpcache_client"
+	outcomeHit         = "hit"
+	outcomeMiss        = "miss"
+	outcomeRevalidated = "revalidated"
+	outcomeStale       = "stale"
+)
+
+// clientEntry is a response cached by the ClientCache.
+type clientEntry struct {
+	StatusCode   int               `json:"status"`
+	Header       http.Header       `json:"header"`
+	Body         []byte            `json:"body"`
+	Vary         map[string]string `json:"vary,omitempty"`
+	RequestTime  time.Time         `json:"request_time"`
+	ResponseTime time.Time         `json:"response_time"`
+}
+
+// ClientCache is a private HTTP client cache implementing the RFC 9111 semantics:
+// freshness, validation with ETag and Last-Modified, Vary and stale-if-error.
+type ClientCache struct {
+	store       Store
+	keyPrefix   string
+	staleTTL    time.Duration
+	maxBodySize int
+	metrics     metrics.Client
+}
+
+// NewClientCache creates a new HTTP client cache using the specified store.
+func NewClientCache(store Store, opts ...ClientOption) *ClientCache {
+	c := &ClientCache{
+		store:       store,
+		keyPrefix:   DefaultClientKeyPrefix,
+		staleTTL:    DefaultClientStaleTTL,
+		maxBodySize: DefaultClientMaxBodySize,
+		metrics:     &metrics.Default{},
+	}
+
+	for _, applyOpt := range opts {
+		applyOpt(c)
+	}
+
+	return c
+}
+
+// RoundTripper returns a caching http.RoundTripper wrapping the next one (default http.DefaultTransport).
+// It can be installed in the httpclient.Client with httpclient.WithRoundTripper(cache.RoundTripper).
+func (c *ClientCache) RoundTripper(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &cachingRoundTripper{cache: c, next: next}
+}
+
+// cachingRoundTripper is the RoundTripper returned by ClientCache.RoundTripper.
+type cachingRoundTripper struct {
+	cache *ClientCache
+	next  http.RoundTripper
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (rt *cachingRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	switch r.Method {
+	case http.MethodGet:
+		return rt.get(r)
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return rt.unsafe(r)
+	default:
+		return rt.next.RoundTrip(r) //nolint:wrapcheck
+	}
+}
+
+// get serves the GET requests from the cache or the next RoundTripper.
+func (rt *cachingRoundTripper) get(r *http.Request) (*http.Response, error) {
+	reqCC := parseCacheControl(r.Header)
+
+	// requests with preconditions or ranges are handled by the origin server
+	if reqCC.has(ccNoStore) ||
+		r.Header.Get("Range") != "" ||
+		r.Header.Get("If-None-Match") != "" ||
+		r.Header.Get("If-Modified-Since") != "" {
+		return rt.next.RoundTrip(r) //nolint:wrapcheck
+	}
+
+	key := rt.cache.keyPrefix + r.URL.String()
+
+	e := rt.lookup(r, key)
+	if e == nil {
+		return rt.fetch(r, key)
+	}
+
+	now := time.Now()
+
+	if e.fresh(r, reqCC, now) {
+		rt.count(r, outcomeHit)
+		return e.response(r, now), nil
+	}
+
+	return rt.revalidate(r, key, reqCC, e, now)
+}
+
+// unsafe invalidates the cached response for the target URI after a successful unsafe request
+// (RFC 9111 section 4.4).
+func (rt *cachingRoundTripper) unsafe(r *http.Request) (*http.Response, error) {
+	resp, err := rt.next.RoundTrip(r)
+	if err != nil || resp.StatusCode >= http.StatusBadRequest {
+		return resp, err //nolint:wrapcheck
+	}
+
+	ctx := r.Context()
+
+	// DelPrefix may also remove the URIs starting with the target one, this is harmless.
+	err = rt.cache.store.DelPrefix(ctx, rt.cache.keyPrefix+r.URL.String())
+	if err != nil {
+		logging.FromContext(ctx).Error("httpcache client invalidation failed", zap.Error(err))
+	}
+
+	return resp, nil
+}
+
+// revalidate validates the stale cached response with the origin server,
+// serving it if not modified or, when allowed by stale-if-error, if the server fails.
+func (rt *cachingRoundTripper) revalidate(r *http.Request, key string, reqCC cacheControl, e *clientEntry, now time.Time) (*http.Response, error) {
+	req := r
+	etag := e.Header.Get("ETag")
+	lastModified := e.Header.Get("Last-Modified")
+
+	if etag != "" || lastModified != "" {
+		req = r.Clone(r.Context())
+
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+
+		if lastModified != "" {
+			req.Header.Set("If-Modified-Since", lastModified)
+		}
+	}
+
+	requestTime := time.Now()
+	resp, err := rt.next.RoundTrip(req)
+
+	if err != nil || resp.StatusCode >= http.StatusInternalServerError {
+		if !e.staleIfError(reqCC, now) {
+			return resp, err //nolint:wrapcheck
+		}
+
+		discard(resp)
+		rt.count(r, outcomeStale)
+
+		return e.response(r, now), nil
+	}
+
+	if resp.StatusCode != http.StatusNotModified || req == r {
+		return rt.save(r, key, resp, requestTime)
+	}
+
+	discard(resp)
+
+	e.update(resp.Header, requestTime, time.Now())
+	rt.set(r.Context(), key, e)
+	rt.count(r, outcomeRevalidated)
+
+	return e.response(r, time.Now()), nil
+}
+
+// fetch sends the request to the next RoundTripper and stores the response if cacheable.
+func (rt *cachingRoundTripper) fetch(r *http.Request, key string) (*http.Response, error) {
+	requestTime := time.Now()
+
+	resp, err := rt.next.RoundTrip(r)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return rt.save(r, key, resp, requestTime)
+}
+
+// save stores the response if cacheable, and returns it with a replayable body.
+func (rt *cachingRoundTripper) save(r *http.Request, key string, resp *http.Response, requestTime time.Time) (*http.Response, error) {
+	rt.count(r, outcomeMiss)
+
+	defer resp.Header.Set(HeaderXCache, XCacheMiss)
+
+	if !storableResponse(r, resp) {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, int64(rt.cache.maxBodySize)+1))
+	if err != nil {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("failed reading the response body: %w", err)
+	}
+
+	if len(body) > rt.cache.maxBodySize {
+		resp.Body = &multiReadCloser{Reader: io.MultiReader(bytes.NewReader(body), resp.Body), Closer: resp.Body}
+		return resp, nil
+	}
+
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	e := &clientEntry{
+		StatusCode:   resp.StatusCode,
+		Header:       resp.Header.Clone(),
+		Body:         body,
+		Vary:         varyValues(r, resp.Header),
+		RequestTime:  requestTime,
+		ResponseTime: time.Now(),
+	}
+
+	rt.set(r.Context(), key, e)
+
+	return resp, nil
+}
+
+// lookup returns the cached response matching the request, or nil.
+func (rt *cachingRoundTripper) lookup(r *http.Request, key string) *clientEntry {
+	ctx := r.Context()
+
+	data, err := rt.cache.store.Get(ctx, key)
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			logging.FromContext(ctx).Error("httpcache client lookup failed", zap.Error(err))
+		}
+
+		return nil
+	}
+
+	e := &clientEntry{}
+
+	err = json.Unmarshal(data, e)
+	if err != nil {
+		logging.FromContext(ctx).Error("httpcache client invalid entry", zap.Error(err))
+		return nil
+	}
+
+	for name, value := range e.Vary {
+		if strings.Join(r.Header.Values(name), ",") != value {
+			return nil
+		}
+	}
+
+	return e
+}
+
+// set stores the cached response for its remaining freshness lifetime,
+// extended by the stale TTL if it can be revalidated or served on error.
+func (rt *cachingRoundTripper) set(ctx context.Context, key string, e *clientEntry) {
+	cc := parseCacheControl(e.Header)
+	ttl := e.lifetime(cc) - e.age(time.Now())
+
+	if e.Header.Get("ETag") != "" || e.Header.Get("Last-Modified") != "" || cc.has(ccStaleIfError) {
+		ttl += rt.cache.staleTTL
+	}
+
+	if ttl <= 0 {
+		return
+	}
+
+	data, err := json.Marshal(e)
+	if err == nil {
+		err = rt.cache.store.Set(ctx, key, data, ttl)
+	}
+
+	if err != nil {
+		logging.FromContext(ctx).Error("httpcache client store failed", zap.Error(err))
+	}
+}
+
+// count increments the metrics counter for the cache outcome.
+func (rt *cachingRoundTripper) count(r *http.Request, outcome string) {
+	metrics.IncEventCounter(rt.cache.metrics, clientMetricsTask, r.URL.Host, outcome)
+}
+
+// storableResponse returns true if the response to the request can be stored (RFC 9111 section 3).
+func storableResponse(r *http.Request, resp *http.Response) bool {
+	resCC := parseCacheControl(resp.Header)
+
+	if resCC.has(ccNoStore) || resp.Header.Get("Vary") == "*" || resp.Header.Get("Content-Range") != "" {
+		return false
+	}
+
+	if resCC.has(ccMaxAge) || resp.Header.Get("Expires") != "" {
+		return true
+	}
+
+	return slices.Contains(heuristicallyCacheable, resp.StatusCode) &&
+		r.Header.Get("Authorization") == ""
+}
+
+// varyValues returns the values of the request headers listed in the response Vary header.
+func varyValues(r *http.Request, h http.Header) map[string]string {
+	var vary map[string]string
+
+	for _, v := range h.Values("Vary") {
+		for name := range strings.SplitSeq(v, ",") {
+			name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+
+			if vary == nil {
+				vary = make(map[string]string)
+			}
+
+			vary[name] = strings.Join(r.Header.Values(name), ",")
+		}
+	}
+
+	return vary
+}
+
+// lifetime returns the freshness lifetime of the cached response.
+func (e *clientEntry) lifetime(cc cacheControl) time.Duration {
+	return freshnessLifetime(e.StatusCode, e.Header, cc, e.ResponseTime)
+}
+
+// age returns the current age of the cached response.
+func (e *clientEntry) age(now time.Time) time.Duration {
+	return initialAge(e.Header, e.RequestTime, e.ResponseTime) + now.Sub(e.ResponseTime)
+}
+
+// fresh returns true if the cached response can be served without validation (RFC 9111 section 4.2).
+func (e *clientEntry) fresh(r *http.Request, reqCC cacheControl, now time.Time) bool {
+	resCC := parseCacheControl(e.Header)
+
+	if reqCC.has(ccNoCache) || resCC.has(ccNoCache) || r.Header.Get("Pragma") == ccNoCache {
+		return false
+	}
+
+	age := e.age(now)
+	lifetime := e.lifetime(resCC)
+
+	if maxAge, ok := reqCC.duration(ccMaxAge); ok && age > maxAge {
+		return false
+	}
+
+	if minFresh, ok := reqCC.duration(ccMinFresh); ok {
+		lifetime -= minFresh
+	}
+
+	if age < lifetime {
+		return true
+	}
+
+	if resCC.has(ccMustRevalidate) || !reqCC.has(ccMaxStale) {
+		return false
+	}
+
+	if reqCC[ccMaxStale] == "" {
+		return true
+	}
+
+	maxStale, ok := reqCC.duration(ccMaxStale)
+
+	return ok && age-lifetime <= maxStale
+}
+
+// staleIfError returns true if the stale cached response can be served
+// when the origin server fails (RFC 5861 section 4).
+func (e *clientEntry) staleIfError(reqCC cacheControl, now time.Time) bool {
+	resCC := parseCacheControl(e.Header)
+
+	if resCC.has(ccMustRevalidate) {
+		return false
+	}
+
+	limit, ok := reqCC.duration(ccStaleIfError)
+	if !ok {
+		limit, ok = resCC.duration(ccStaleIfError)
+	}
+
+	return ok && e.age(now)-e.lifetime(resCC) <= limit
+}
+
+// update replaces the stored headers and times with the ones of the 304 Not Modified response
+// (RFC 9111 section 4.3.4).
+func (e *clientEntry) update(h http.Header, requestTime, responseTime time.Time) {
+	for name, values := range h {
+		if name != "Content-Length" {
+			e.Header[name] = values
+		}
+	}
+
+	e.RequestTime = requestTime
+	e.ResponseTime = responseTime
+}
+
+// response returns the cached response for the request.
+func (e *clientEntry) response(r *http.Request, now time.Time) *http.Response {
+	h := e.Header.Clone()
+	h.Set("Age", strconv.FormatInt(int64(e.age(now).Seconds()), 10))
+	h.Set(HeaderXCache, XCacheHit)
+
+	return &http.Response{
+		Status:        strconv.Itoa(e.StatusCode) + " " + http.StatusText(e.StatusCode),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        h,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       r,
+	}
+}
+
+// discard drains and closes the response body.
+func discard(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+}
+
+// multiReadCloser reads from the Reader and closes the Closer.
+type multiReadCloser struct {
+	io.Reader
+	io.Closer
+}
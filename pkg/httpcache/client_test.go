@@ -0,0 +1,465 @@
+package httpcache
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/httpclient"
+	"github.com/Vonage/gosrvlib/pkg/metrics"
+	"github.com/stretchr/testify/require"
+)
+
+type testOutcomeMetrics struct {
+	metrics.Default
+
+	mux      sync.Mutex
+	outcomes map[string]int
+}
+
+func (m *testOutcomeMetrics) IncEventCounter(_, _, outcome string) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if m.outcomes == nil {
+		m.outcomes = make(map[string]int)
+	}
+
+	m.outcomes[outcome]++
+}
+
+type testRequest struct {
+	method     string
+	path       string
+	header     http.Header
+	wantStatus int
+	wantBody   string
+	wantXCache string
+}
+
+//nolint:gocognit,gocyclo,cyclop
+func newTestOrigin(t *testing.T, calls *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		body := "call " + strconv.Itoa(int(n))
+		h := w.Header()
+
+		switch r.URL.Path {
+		case "/fresh":
+			h.Set("Cache-Control", "max-age=60")
+		case "/nostore":
+			h.Set("Cache-Control", "no-store")
+		case "/etag":
+			h.Set("Cache-Control", "no-cache")
+			h.Set("ETag", `"v1"`)
+
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		case "/lastmodified":
+			h.Set("Cache-Control", "max-age=0")
+			h.Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+
+			if r.Header.Get("If-Modified-Since") != "" {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		case "/vary":
+			h.Set("Cache-Control", "max-age=60")
+			h.Set("Vary", "Accept-Language")
+		case "/varystar":
+			h.Set("Cache-Control", "max-age=60")
+			h.Set("Vary", "*")
+		case "/staleiferror", "/mustrevalidate":
+			h.Set("Cache-Control", "max-age=0, stale-if-error=60")
+
+			if r.URL.Path == "/mustrevalidate" {
+				h.Set("Cache-Control", "max-age=0, stale-if-error=60, must-revalidate")
+			}
+
+			if n > 1 {
+				http.Error(w, "error", http.StatusServiceUnavailable)
+				return
+			}
+		case "/stale":
+			h.Set("Cache-Control", "max-age=0")
+			h.Set("ETag", `"v`+strconv.Itoa(int(n))+`"`)
+		case "/large":
+			h.Set("Cache-Control", "max-age=60")
+
+			body = strings.Repeat("a", 20)
+		}
+
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+		}
+
+		_, _ = w.Write([]byte(body))
+	}))
+
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func TestClientCache_RoundTripper(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		requests     []testRequest
+		wantCalls    int32
+		wantOutcomes map[string]int
+	}{
+		{
+			name: "fresh response",
+			requests: []testRequest{
+				{path: "/fresh", wantBody: "call 1", wantXCache: XCacheMiss},
+				{path: "/fresh", wantBody: "call 1", wantXCache: XCacheHit},
+				{path: "/fresh?q=1", wantBody: "call 2", wantXCache: XCacheMiss},
+			},
+			wantCalls:    2,
+			wantOutcomes: map[string]int{outcomeMiss: 2, outcomeHit: 1},
+		},
+		{
+			name: "no-store response",
+			requests: []testRequest{
+				{path: "/nostore", wantBody: "call 1", wantXCache: XCacheMiss},
+				{path: "/nostore", wantBody: "call 2", wantXCache: XCacheMiss},
+			},
+			wantCalls:    2,
+			wantOutcomes: map[string]int{outcomeMiss: 2},
+		},
+		{
+			name: "no-store request",
+			requests: []testRequest{
+				{path: "/fresh", header: http.Header{"Cache-Control": {"no-store"}}, wantBody: "call 1"},
+				{path: "/fresh", wantBody: "call 2", wantXCache: XCacheMiss},
+			},
+			wantCalls:    2,
+			wantOutcomes: map[string]int{outcomeMiss: 1},
+		},
+		{
+			name: "no-cache request",
+			requests: []testRequest{
+				{path: "/fresh", wantBody: "call 1", wantXCache: XCacheMiss},
+				{path: "/fresh", header: http.Header{"Cache-Control": {"no-cache"}}, wantBody: "call 2", wantXCache: XCacheMiss},
+			},
+			wantCalls:    2,
+			wantOutcomes: map[string]int{outcomeMiss: 2},
+		},
+		{
+			name: "revalidation with etag",
+			requests: []testRequest{
+				{path: "/etag", wantBody: "call 1", wantXCache: XCacheMiss},
+				{path: "/etag", wantBody: "call 1", wantXCache: XCacheHit},
+			},
+			wantCalls:    2,
+			wantOutcomes: map[string]int{outcomeMiss: 1, outcomeRevalidated: 1},
+		},
+		{
+			name: "revalidation with last-modified",
+			requests: []testRequest{
+				{path: "/lastmodified", wantBody: "call 1", wantXCache: XCacheMiss},
+				{path: "/lastmodified", wantBody: "call 1", wantXCache: XCacheHit},
+			},
+			wantCalls:    2,
+			wantOutcomes: map[string]int{outcomeMiss: 1, outcomeRevalidated: 1},
+		},
+		{
+			name: "client preconditions",
+			requests: []testRequest{
+				{path: "/etag", wantBody: "call 1", wantXCache: XCacheMiss},
+				{path: "/etag", header: http.Header{"If-None-Match": {`"v1"`}}, wantStatus: http.StatusNotModified},
+			},
+			wantCalls:    2,
+			wantOutcomes: map[string]int{outcomeMiss: 1},
+		},
+		{
+			name: "vary",
+			requests: []testRequest{
+				{path: "/vary", header: http.Header{"Accept-Language": {"en"}}, wantBody: "call 1", wantXCache: XCacheMiss},
+				{path: "/vary", header: http.Header{"Accept-Language": {"en"}}, wantBody: "call 1", wantXCache: XCacheHit},
+				{path: "/vary", header: http.Header{"Accept-Language": {"it"}}, wantBody: "call 2", wantXCache: XCacheMiss},
+			},
+			wantCalls:    2,
+			wantOutcomes: map[string]int{outcomeMiss: 2, outcomeHit: 1},
+		},
+		{
+			name: "vary star",
+			requests: []testRequest{
+				{path: "/varystar", wantBody: "call 1", wantXCache: XCacheMiss},
+				{path: "/varystar", wantBody: "call 2", wantXCache: XCacheMiss},
+			},
+			wantCalls:    2,
+			wantOutcomes: map[string]int{outcomeMiss: 2},
+		},
+		{
+			name: "stale-if-error",
+			requests: []testRequest{
+				{path: "/staleiferror", wantBody: "call 1", wantXCache: XCacheMiss},
+				{path: "/staleiferror", wantBody: "call 1", wantXCache: XCacheHit},
+			},
+			wantCalls:    2,
+			wantOutcomes: map[string]int{outcomeMiss: 1, outcomeStale: 1},
+		},
+		{
+			name: "must-revalidate",
+			requests: []testRequest{
+				{path: "/mustrevalidate", wantBody: "call 1", wantXCache: XCacheMiss},
+				{path: "/mustrevalidate", wantStatus: http.StatusServiceUnavailable, wantBody: "error\n"},
+			},
+			wantCalls:    2,
+			wantOutcomes: map[string]int{outcomeMiss: 1},
+		},
+		{
+			name: "max-stale request",
+			requests: []testRequest{
+				{path: "/stale", wantBody: "call 1", wantXCache: XCacheMiss},
+				{path: "/stale", header: http.Header{"Cache-Control": {"max-stale"}}, wantBody: "call 1", wantXCache: XCacheHit},
+				{path: "/stale", header: http.Header{"Cache-Control": {"max-stale=3600"}}, wantBody: "call 1", wantXCache: XCacheHit},
+				{path: "/stale", wantBody: "call 2", wantXCache: XCacheMiss},
+			},
+			wantCalls:    2,
+			wantOutcomes: map[string]int{outcomeMiss: 2, outcomeHit: 2},
+		},
+		{
+			name: "unsafe request invalidation",
+			requests: []testRequest{
+				{path: "/fresh", wantBody: "call 1", wantXCache: XCacheMiss},
+				{method: http.MethodPost, path: "/fresh", wantStatus: http.StatusCreated, wantBody: "call 2"},
+				{path: "/fresh", wantBody: "call 3", wantXCache: XCacheMiss},
+			},
+			wantCalls:    3,
+			wantOutcomes: map[string]int{outcomeMiss: 2},
+		},
+		{
+			name: "large body",
+			requests: []testRequest{
+				{path: "/large", wantBody: strings.Repeat("a", 20), wantXCache: XCacheMiss},
+				{path: "/large", wantBody: strings.Repeat("a", 20), wantXCache: XCacheMiss},
+			},
+			wantCalls:    2,
+			wantOutcomes: map[string]int{outcomeMiss: 2},
+		},
+		{
+			name: "other methods",
+			requests: []testRequest{
+				{method: http.MethodHead, path: "/fresh"},
+				{method: http.MethodHead, path: "/fresh"},
+			},
+			wantCalls:    2,
+			wantOutcomes: map[string]int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			calls := &atomic.Int32{}
+			origin := newTestOrigin(t, calls)
+			m := &testOutcomeMetrics{outcomes: map[string]int{}}
+
+			cache := NewClientCache(NewLRUStore(10), WithClientMetrics(m), WithClientMaxBodySize(10))
+			hc := &http.Client{Transport: cache.RoundTripper(nil)}
+
+			for i, tr := range tt.requests {
+				method := tr.method
+				if method == "" {
+					method = http.MethodGet
+				}
+
+				req, err := http.NewRequestWithContext(t.Context(), method, origin.URL+tr.path, nil)
+				require.NoError(t, err)
+
+				if tr.header != nil {
+					req.Header = tr.header
+				}
+
+				resp, err := hc.Do(req)
+				require.NoError(t, err)
+
+				body, err := io.ReadAll(resp.Body)
+				require.NoError(t, err)
+				require.NoError(t, resp.Body.Close())
+
+				wantStatus := tr.wantStatus
+				if wantStatus == 0 {
+					wantStatus = http.StatusOK
+				}
+
+				require.Equal(t, wantStatus, resp.StatusCode, "request %d", i)
+				require.Equal(t, tr.wantBody, string(body), "request %d", i)
+				require.Equal(t, tr.wantXCache, resp.Header.Get(HeaderXCache), "request %d", i)
+			}
+
+			require.Equal(t, tt.wantCalls, calls.Load())
+			require.Equal(t, tt.wantOutcomes, m.outcomes)
+		})
+	}
+}
+
+type testErrorStore struct {
+	getErr error
+	setErr error
+	delErr error
+	value  []byte
+}
+
+func (s *testErrorStore) Get(_ context.Context, _ string) ([]byte, error) {
+	return s.value, s.getErr
+}
+
+func (s *testErrorStore) Set(_ context.Context, _ string, _ []byte, _ time.Duration) error {
+	return s.setErr
+}
+
+func (s *testErrorStore) DelPrefix(_ context.Context, _ string) error {
+	return s.delErr
+}
+
+type testRoundTripper struct {
+	resp *http.Response
+	err  error
+}
+
+func (rt *testRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	if rt.resp != nil {
+		rt.resp.Request = r
+	}
+
+	return rt.resp, rt.err
+}
+
+func TestClientCache_RoundTripper_errors(t *testing.T) {
+	t.Parallel()
+
+	storeErr := errors.New("store error")
+
+	tests := []struct {
+		name    string
+		method  string
+		store   Store
+		next    *testRoundTripper
+		wantErr bool
+	}{
+		{
+			name:    "next error",
+			method:  http.MethodGet,
+			store:   NewLRUStore(1),
+			next:    &testRoundTripper{err: errors.New("next error")},
+			wantErr: true,
+		},
+		{
+			name:   "store errors",
+			method: http.MethodGet,
+			store:  &testErrorStore{getErr: storeErr, setErr: storeErr},
+			next: &testRoundTripper{resp: &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Cache-Control": {"max-age=60"}},
+				Body:       io.NopCloser(strings.NewReader("ok")),
+			}},
+		},
+		{
+			name:   "invalid entry",
+			method: http.MethodGet,
+			store:  &testErrorStore{value: []byte("{")},
+			next: &testRoundTripper{resp: &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{},
+				Body:       io.NopCloser(strings.NewReader("ok")),
+			}},
+		},
+		{
+			name:   "body read error",
+			method: http.MethodGet,
+			store:  NewLRUStore(1),
+			next: &testRoundTripper{resp: &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Cache-Control": {"max-age=60"}},
+				Body:       io.NopCloser(iotestErrReader{}),
+			}},
+			wantErr: true,
+		},
+		{
+			name:   "invalidation error",
+			method: http.MethodDelete,
+			store:  &testErrorStore{delErr: storeErr},
+			next: &testRoundTripper{resp: &http.Response{
+				StatusCode: http.StatusNoContent,
+				Header:     http.Header{},
+				Body:       http.NoBody,
+			}},
+		},
+		{
+			name:   "failed unsafe request",
+			method: http.MethodDelete,
+			store:  &testErrorStore{delErr: storeErr},
+			next: &testRoundTripper{resp: &http.Response{
+				StatusCode: http.StatusNotFound,
+				Header:     http.Header{},
+				Body:       http.NoBody,
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rt := NewClientCache(tt.store).RoundTripper(tt.next)
+
+			req := httptest.NewRequest(tt.method, "http://example.invalid/test", nil)
+
+			resp, err := rt.RoundTrip(req)
+			if tt.wantErr {
+				require.Error(t, err)
+				require.Nil(t, resp)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+		})
+	}
+}
+
+type iotestErrReader struct{}
+
+func (iotestErrReader) Read(_ []byte) (int, error) {
+	return 0, errors.New("read error")
+}
+
+func TestClientCache_httpclient(t *testing.T) {
+	t.Parallel()
+
+	calls := &atomic.Int32{}
+	origin := newTestOrigin(t, calls)
+
+	cache := NewClientCache(NewLRUStore(10))
+	hc := httpclient.New(httpclient.WithRoundTripper(cache.RoundTripper))
+
+	for range 3 {
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, origin.URL+"/fresh", nil)
+		require.NoError(t, err)
+
+		resp, err := hc.Do(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+	}
+
+	require.Equal(t, int32(1), calls.Load())
+}
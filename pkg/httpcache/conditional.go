@@ -0,0 +1,60 @@
+package httpcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// etagLen is the number of hash bytes used in the generated ETags.
+const etagLen = 16
+
+// etagOf returns a strong ETag computed from the response body.
+func etagOf(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:etagLen]) + `"`
+}
+
+// notModified evaluates the If-None-Match and If-Modified-Since request
+// preconditions against the validators in the response header (RFC 9110).
+func notModified(r *http.Request, h http.Header) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return etagMatch(inm, h.Get("ETag"))
+	}
+
+	ims, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+
+	lm, err := http.ParseTime(h.Get("Last-Modified"))
+	if err != nil {
+		return false
+	}
+
+	return !lm.Truncate(time.Second).After(ims)
+}
+
+// etagMatch returns true if the ETag matches any entry of the If-None-Match list using the weak comparison.
+func etagMatch(list, etag string) bool {
+	if etag == "" {
+		return false
+	}
+
+	etag = strings.TrimPrefix(etag, "W/")
+
+	for v := range strings.SplitSeq(list, ",") {
+		v = strings.TrimSpace(v)
+		if v == "*" || strings.TrimPrefix(v, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,104 @@
+package httpcache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_etagOf(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, `"2cf24dba5fb0a30e26e83b2ac5b9e29e"`, etagOf([]byte("hello")))
+	require.NotEqual(t, etagOf([]byte("hello")), etagOf([]byte("world")))
+}
+
+func Test_notModified(t *testing.T) {
+	t.Parallel()
+
+	lastModified := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	resHeader := http.Header{}
+	resHeader.Set("ETag", `"abc"`)
+	resHeader.Set("Last-Modified", lastModified.Format(http.TimeFormat))
+
+	tests := []struct {
+		name   string
+		method string
+		header map[string]string
+		want   bool
+	}{
+		{
+			name:   "no preconditions",
+			method: http.MethodGet,
+			want:   false,
+		},
+		{
+			name:   "etag match",
+			method: http.MethodGet,
+			header: map[string]string{"If-None-Match": `"xyz", W/"abc"`},
+			want:   true,
+		},
+		{
+			name:   "etag wildcard",
+			method: http.MethodHead,
+			header: map[string]string{"If-None-Match": "*"},
+			want:   true,
+		},
+		{
+			name:   "etag mismatch ignores if-modified-since",
+			method: http.MethodGet,
+			header: map[string]string{
+				"If-None-Match":     `"xyz"`,
+				"If-Modified-Since": lastModified.Format(http.TimeFormat),
+			},
+			want: false,
+		},
+		{
+			name:   "not modified since",
+			method: http.MethodGet,
+			header: map[string]string{"If-Modified-Since": lastModified.Format(http.TimeFormat)},
+			want:   true,
+		},
+		{
+			name:   "modified since",
+			method: http.MethodGet,
+			header: map[string]string{"If-Modified-Since": lastModified.Add(-time.Hour).Format(http.TimeFormat)},
+			want:   false,
+		},
+		{
+			name:   "invalid if-modified-since",
+			method: http.MethodGet,
+			header: map[string]string{"If-Modified-Since": "invalid"},
+			want:   false,
+		},
+		{
+			name:   "unsafe method",
+			method: http.MethodPost,
+			header: map[string]string{"If-None-Match": `"abc"`},
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequestWithContext(t.Context(), tt.method, "/", nil)
+			for k, v := range tt.header {
+				r.Header.Set(k, v)
+			}
+
+			require.Equal(t, tt.want, notModified(r, resHeader))
+		})
+	}
+
+	r := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/", nil)
+	r.Header.Set("If-Modified-Since", lastModified.Format(http.TimeFormat))
+	require.False(t, notModified(r, http.Header{}))
+
+	require.False(t, etagMatch("*", ""))
+}
@@ -0,0 +1,92 @@
+package httpcache
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// heuristicFraction is the fraction of the time since the last modification
+// used as heuristic freshness lifetime (RFC 9111 section 4.2.2).
+const heuristicFraction = 10
+
+// maxHeuristicLifetime is the maximum heuristic freshness lifetime.
+const maxHeuristicLifetime = 24 * time.Hour
+
+// heuristicallyCacheable contains the status codes that can be cached without explicit freshness
+// (RFC 9110 section 15.1).
+//
+//nolint:gochecknoglobals
+var heuristicallyCacheable = []int{
+	http.StatusOK,
+	http.StatusNonAuthoritativeInfo,
+	http.StatusNoContent,
+	http.StatusMultipleChoices,
+	http.StatusMovedPermanently,
+	http.StatusPermanentRedirect,
+	http.StatusNotFound,
+	http.StatusMethodNotAllowed,
+	http.StatusGone,
+	http.StatusRequestURITooLong,
+	http.StatusNotImplemented,
+}
+
+// headerTime parses an HTTP-date header, returning the zero time if missing or invalid.
+func headerTime(h http.Header, name string) time.Time {
+	t, err := http.ParseTime(h.Get(name))
+	if err != nil {
+		return time.Time{}
+	}
+
+	return t
+}
+
+// freshnessLifetime returns the freshness lifetime of a response received at the specified time
+// for a private cache (RFC 9111 section 4.2.1).
+func freshnessLifetime(status int, h http.Header, cc cacheControl, responseTime time.Time) time.Duration {
+	if maxAge, ok := cc.duration(ccMaxAge); ok {
+		return maxAge
+	}
+
+	date := headerTime(h, "Date")
+	if date.IsZero() {
+		date = responseTime
+	}
+
+	if h.Get("Expires") != "" {
+		expires := headerTime(h, "Expires")
+		if expires.IsZero() {
+			// invalid dates represent a time in the past
+			return 0
+		}
+
+		return max(0, expires.Sub(date))
+	}
+
+	lastModified := headerTime(h, "Last-Modified")
+	if lastModified.IsZero() || !slices.Contains(heuristicallyCacheable, status) {
+		return 0
+	}
+
+	return min(max(0, date.Sub(lastModified))/heuristicFraction, maxHeuristicLifetime)
+}
+
+// initialAge returns the corrected age of a response when it was received (RFC 9111 section 4.2.3).
+func initialAge(h http.Header, requestTime, responseTime time.Time) time.Duration {
+	var apparentAge time.Duration
+
+	if date := headerTime(h, "Date"); !date.IsZero() {
+		apparentAge = max(0, responseTime.Sub(date))
+	}
+
+	var ageValue time.Duration
+
+	if sec, err := strconv.ParseInt(h.Get("Age"), 10, 64); err == nil && sec > 0 {
+		ageValue = time.Duration(sec) * time.Second
+	}
+
+	correctedAge := ageValue + max(0, responseTime.Sub(requestTime))
+
+	return max(apparentAge, correctedAge)
+}
@@ -0,0 +1,128 @@
+package httpcache
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_freshnessLifetime(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		status int
+		header http.Header
+		want   time.Duration
+	}{
+		{
+			name:   "no information",
+			status: http.StatusOK,
+			header: http.Header{},
+			want:   0,
+		},
+		{
+			name:   "max-age",
+			status: http.StatusOK,
+			header: http.Header{"Cache-Control": {"max-age=60"}, "Expires": {now.Add(time.Hour).Format(http.TimeFormat)}},
+			want:   time.Minute,
+		},
+		{
+			name:   "expires",
+			status: http.StatusOK,
+			header: http.Header{"Date": {now.Format(http.TimeFormat)}, "Expires": {now.Add(time.Hour).Format(http.TimeFormat)}},
+			want:   time.Hour,
+		},
+		{
+			name:   "expires without date",
+			status: http.StatusOK,
+			header: http.Header{"Expires": {now.Add(time.Hour).Format(http.TimeFormat)}},
+			want:   time.Hour,
+		},
+		{
+			name:   "invalid expires",
+			status: http.StatusOK,
+			header: http.Header{"Expires": {"0"}},
+			want:   0,
+		},
+		{
+			name:   "past expires",
+			status: http.StatusOK,
+			header: http.Header{"Expires": {now.Add(-time.Hour).Format(http.TimeFormat)}},
+			want:   0,
+		},
+		{
+			name:   "heuristic",
+			status: http.StatusOK,
+			header: http.Header{"Last-Modified": {now.Add(-10 * time.Hour).Format(http.TimeFormat)}},
+			want:   time.Hour,
+		},
+		{
+			name:   "heuristic capped",
+			status: http.StatusOK,
+			header: http.Header{"Last-Modified": {now.Add(-1000 * time.Hour).Format(http.TimeFormat)}},
+			want:   maxHeuristicLifetime,
+		},
+		{
+			name:   "heuristic not allowed",
+			status: http.StatusCreated,
+			header: http.Header{"Last-Modified": {now.Add(-10 * time.Hour).Format(http.TimeFormat)}},
+			want:   0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := freshnessLifetime(tt.status, tt.header, parseCacheControl(tt.header), now)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_initialAge(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		header http.Header
+		want   time.Duration
+	}{
+		{
+			name:   "no headers",
+			header: http.Header{},
+			want:   time.Second,
+		},
+		{
+			name:   "age header",
+			header: http.Header{"Age": {"30"}},
+			want:   31 * time.Second,
+		},
+		{
+			name:   "date header",
+			header: http.Header{"Date": {now.Add(-time.Minute).Format(http.TimeFormat)}},
+			want:   time.Minute,
+		},
+		{
+			name:   "invalid age",
+			header: http.Header{"Age": {"-5"}},
+			want:   time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := initialAge(tt.header, now.Add(-time.Second), now)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,472 @@
+/*
+Package httpcache provides an HTTP middleware to cache the responses of
+read-heavy endpoints.
+
+The middleware computes a strong ETag from the body of the successful responses
+(unless already set by the handler) and answers the If-None-Match and
+If-Modified-Since conditional requests with 304 Not Modified.
+
+When a Store is configured (WithStore), the GET responses are also cached
+server-side, keyed by method, path, sorted query and the selected request
+headers (WithVaryHeaders), using the following format:
+
+	<prefix>GET <path>?<query>[\n<Header>:<value>...]
+
+Available stores are the in-memory LRUStore, the RedisStore backed by a
+github.com/redis/go-redis/v9 client and the ValkeyStore backed by a
+github.com/Vonage/gosrvlib/pkg/valkey client.
+
+Only 200 responses are stored. The time-to-live is taken from the response
+Cache-Control s-maxage or max-age directives, or the default TTL. Responses
+with the no-store, no-cache or private directives, a Set-Cookie header, or to
+requests with an Authorization header (unless public) are not stored. Requests
+with Cache-Control no-cache, no-store or max-age bypass or limit the cache.
+The httputil.Send* functions disable caching by default: use WithCacheControl
+to set the Cache-Control header sent to the clients.
+
+Successful POST, PUT, PATCH and DELETE requests invalidate the cached entries
+starting with "GET <path>" (see WithInvalidatePrefixFn), and Invalidate can be
+used to remove any key prefix. The invalidations of the write requests run
+before the response status code is sent, so the following reads of the client
+are not served from the stale entries. The RedisStore and ValkeyStore
+prefix invalidation scans the whole keyspace and is not supported with Redis or
+Valkey clusters.
+
+The middleware buffers the whole response, so it must not be used with
+streaming routes. It can be added to an httpserver route as:
+
+	Middleware: []httpserver.MiddlewareFn{
+		func(_ httpserver.MiddlewareArgs, next http.Handler) http.Handler {
+			return cache.Handler(next)
+		},
+	},
+
+The ClientCache is a private HTTP client cache implementing the RFC 9111
+semantics: freshness (max-age, Expires or heuristic), validation with ETag and
+Last-Modified, Vary, the request max-age, min-fresh, max-stale and no-cache
+directives and the stale-if-error extension. It uses the same stores, and it can
+be installed in the httpclient.Client as:
+
+	cache := httpcache.NewClientCache(httpcache.NewLRUStore(1000))
+	hc := httpclient.New(httpclient.WithRoundTripper(cache.RoundTripper))
+
+The cache hits and misses are counted with the metrics IncEventCounter (see
+WithClientMetrics).
+*/
+package httpcache
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/logging"
+	"go.uber.org/zap"
+)
+
+const (
+	// HeaderXCache is the response header reporting if the response was served from the store.
+	HeaderXCache = "X-Cache"
+
+	// XCacheHit is the HeaderXCache value for responses served from the store.
+	XCacheHit = "HIT"
+
+	// XCacheMiss is the HeaderXCache value for responses generated by the handler.
+	XCacheMiss = "MISS"
+
+	// DefaultTTL is the default time-to-live of the stored responses.
+	DefaultTTL = 1 * time.Minute
+
+	// DefaultKeyPrefix is the default prefix of the cache keys.
+	DefaultKeyPrefix = "httpcache:"
+)
+
+// entry is a cached response.
+type entry struct {
+	StatusCode int         `json:"status"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+	StoredAt   time.Time   `json:"stored_at"`
+}
+
+// Cache is the HTTP response caching middleware.
+type Cache struct {
+	store              Store
+	ttl                time.Duration
+	varyHeaders        []string
+	cacheControl       string
+	keyPrefix          string
+	invalidatePrefixFn InvalidatePrefixFn
+}
+
+// New creates a new HTTP response cache.
+func New(opts ...Option) *Cache {
+	c := &Cache{
+		ttl:                DefaultTTL,
+		keyPrefix:          DefaultKeyPrefix,
+		invalidatePrefixFn: DefaultInvalidatePrefixFn,
+	}
+
+	for _, applyOpt := range opts {
+		applyOpt(c)
+	}
+
+	return c
+}
+
+// DefaultInvalidatePrefixFn returns the key prefix of the GET requests for the same path.
+func DefaultInvalidatePrefixFn(r *http.Request) []string {
+	return []string{http.MethodGet + " " + r.URL.Path}
+}
+
+// Invalidate removes all the stored responses with keys starting with the specified prefix
+// (excluding the key prefix set with WithKeyPrefix), e.g. "GET /users/".
+func (c *Cache) Invalidate(ctx context.Context, prefix string) error {
+	if c.store == nil {
+		return nil
+	}
+
+	err := c.store.DelPrefix(ctx, c.keyPrefix+prefix)
+	if err != nil {
+		return fmt.Errorf("unable to invalidate the cache prefix %q: %w", prefix, err)
+	}
+
+	return nil
+}
+
+// Handler returns the caching middleware for the next handler.
+func (c *Cache) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			c.serveGet(w, r, next)
+		case http.MethodHead:
+			c.serveHead(w, r, next)
+		case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+			c.serveWrite(w, r, next)
+		default:
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// serveGet serves the GET requests from the store or the next handler.
+func (c *Cache) serveGet(w http.ResponseWriter, r *http.Request, next http.Handler) {
+	key := c.key(r)
+	reqCC := parseCacheControl(r.Header)
+
+	if e := c.lookup(r, key, reqCC); e != nil {
+		c.write(w, r, e, true)
+		return
+	}
+
+	rec := &responseRecorder{header: make(http.Header)}
+	next.ServeHTTP(rec, r)
+
+	e := c.newEntry(rec)
+
+	if ttl, ok := c.storable(r, reqCC, e); ok {
+		c.save(r.Context(), key, e, ttl)
+	}
+
+	c.write(w, r, e, false)
+}
+
+// serveHead serves the HEAD requests from the GET responses in the store, or the next handler.
+func (c *Cache) serveHead(w http.ResponseWriter, r *http.Request, next http.Handler) {
+	if e := c.lookup(r, c.key(r), parseCacheControl(r.Header)); e != nil {
+		c.write(w, r, e, true)
+		return
+	}
+
+	next.ServeHTTP(w, r)
+}
+
+// serveWrite invalidates the cache after a successful write request, before the response status code is sent.
+func (c *Cache) serveWrite(w http.ResponseWriter, r *http.Request, next http.Handler) {
+	if c.store == nil {
+		next.ServeHTTP(w, r)
+		return
+	}
+
+	sw := &statusWriter{
+		ResponseWriter: w,
+		onStatus: func(code int) {
+			if code < http.StatusBadRequest {
+				c.invalidate(r)
+			}
+		},
+	}
+
+	next.ServeHTTP(sw, r)
+
+	// the handler did not write any response
+	sw.setStatus(http.StatusOK)
+}
+
+// invalidate removes the stored responses with the key prefixes of the write request.
+func (c *Cache) invalidate(r *http.Request) {
+	for _, prefix := range c.invalidatePrefixFn(r) {
+		err := c.Invalidate(r.Context(), prefix)
+		if err != nil {
+			logging.FromContext(r.Context()).Error("httpcache invalidation failed", zap.Error(err))
+		}
+	}
+}
+
+// key returns the store key for the request.
+func (c *Cache) key(r *http.Request) string {
+	var sb strings.Builder
+
+	sb.WriteString(c.keyPrefix)
+	sb.WriteString(http.MethodGet)
+	sb.WriteString(" ")
+	sb.WriteString(r.URL.Path)
+	sb.WriteString("?")
+	sb.WriteString(r.URL.Query().Encode())
+
+	for _, name := range c.varyHeaders {
+		sb.WriteString("\n")
+		sb.WriteString(name)
+		sb.WriteString(":")
+		sb.WriteString(strings.Join(r.Header.Values(name), ","))
+	}
+
+	return sb.String()
+}
+
+// lookup returns the stored response, or nil if not found or not usable.
+func (c *Cache) lookup(r *http.Request, key string, reqCC cacheControl) *entry {
+	if c.store == nil || reqCC.has(ccNoCache) || reqCC.has(ccNoStore) {
+		return nil
+	}
+
+	ctx := r.Context()
+
+	data, err := c.store.Get(ctx, key)
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			logging.FromContext(ctx).Error("httpcache lookup failed", zap.Error(err))
+		}
+
+		return nil
+	}
+
+	e := &entry{}
+
+	err = json.Unmarshal(data, e)
+	if err != nil {
+		logging.FromContext(ctx).Error("httpcache invalid entry", zap.Error(err))
+		return nil
+	}
+
+	if maxAge, ok := reqCC.duration(ccMaxAge); ok && time.Since(e.StoredAt) > maxAge {
+		return nil
+	}
+
+	return e
+}
+
+// save stores the response.
+func (c *Cache) save(ctx context.Context, key string, e *entry, ttl time.Duration) {
+	data, err := json.Marshal(e)
+	if err == nil {
+		err = c.store.Set(ctx, key, data, ttl)
+	}
+
+	if err != nil {
+		logging.FromContext(ctx).Error("httpcache store failed", zap.Error(err))
+	}
+}
+
+// newEntry returns the response recorded from the handler, with the validators and cache headers.
+func (c *Cache) newEntry(rec *responseRecorder) *entry {
+	e := &entry{
+		StatusCode: rec.statusCode(),
+		Header:     rec.snapshot(),
+		Body:       rec.body.Bytes(),
+		StoredAt:   time.Now().UTC(),
+	}
+
+	for _, name := range c.varyHeaders {
+		if !slices.ContainsFunc(e.Header.Values("Vary"), func(v string) bool { return strings.EqualFold(v, name) }) {
+			e.Header.Add("Vary", name)
+		}
+	}
+
+	if e.StatusCode != http.StatusOK {
+		return e
+	}
+
+	if c.cacheControl != "" {
+		e.Header.Set("Cache-Control", c.cacheControl)
+		e.Header.Del("Pragma")
+		e.Header.Del("Expires")
+	}
+
+	if e.Header.Get("ETag") == "" {
+		e.Header.Set("ETag", etagOf(e.Body))
+	}
+
+	if c.store != nil && e.Header.Get("Last-Modified") == "" {
+		e.Header.Set("Last-Modified", e.StoredAt.Format(http.TimeFormat))
+	}
+
+	return e
+}
+
+// storable returns the time-to-live of the response and true if it can be stored.
+func (c *Cache) storable(r *http.Request, reqCC cacheControl, e *entry) (time.Duration, bool) {
+	if c.store == nil ||
+		e.StatusCode != http.StatusOK ||
+		reqCC.has(ccNoStore) ||
+		e.Header.Get("Set-Cookie") != "" ||
+		e.Header.Get("Vary") == "*" {
+		return 0, false
+	}
+
+	resCC := parseCacheControl(e.Header)
+
+	if resCC.has(ccNoStore) || resCC.has(ccNoCache) || resCC.has(ccPrivate) {
+		return 0, false
+	}
+
+	if r.Header.Get("Authorization") != "" && !resCC.has(ccPublic) && !resCC.has(ccSMaxAge) {
+		return 0, false
+	}
+
+	ttl, ok := resCC.duration(ccSMaxAge)
+	if !ok {
+		ttl, ok = resCC.duration(ccMaxAge)
+	}
+
+	if !ok {
+		ttl = c.ttl
+	}
+
+	return ttl, ttl > 0
+}
+
+// write sends the response, or 304 Not Modified if the request preconditions match.
+func (c *Cache) write(w http.ResponseWriter, r *http.Request, e *entry, hit bool) {
+	h := w.Header()
+	maps.Copy(h, e.Header)
+
+	if c.store != nil {
+		h.Set(HeaderXCache, XCacheMiss)
+	}
+
+	if hit {
+		h.Set(HeaderXCache, XCacheHit)
+		h.Set("Age", strconv.FormatInt(int64(time.Since(e.StoredAt).Seconds()), 10))
+	}
+
+	if e.StatusCode == http.StatusOK && notModified(r, e.Header) {
+		h.Del("Content-Length")
+		w.WriteHeader(http.StatusNotModified)
+
+		return
+	}
+
+	h.Set("Content-Length", strconv.Itoa(len(e.Body)))
+	w.WriteHeader(e.StatusCode)
+
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	_, err := w.Write(e.Body)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("httpcache write failed", zap.Error(err))
+	}
+}
+
+// responseRecorder buffers the handler response.
+type responseRecorder struct {
+	header http.Header
+	sent   http.Header
+	status int
+	body   bytes.Buffer
+}
+
+// Header returns the response header map.
+func (rr *responseRecorder) Header() http.Header {
+	return rr.header
+}
+
+// WriteHeader records the status code and the header snapshot.
+func (rr *responseRecorder) WriteHeader(code int) {
+	if rr.status != 0 {
+		return
+	}
+
+	rr.status = code
+	rr.sent = rr.header.Clone()
+}
+
+// Write buffers the response body.
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	rr.WriteHeader(http.StatusOK)
+	return rr.body.Write(b) //nolint:wrapcheck
+}
+
+// statusCode returns the recorded status code.
+func (rr *responseRecorder) statusCode() int {
+	if rr.status == 0 {
+		return http.StatusOK
+	}
+
+	return rr.status
+}
+
+// snapshot returns the header as it was when the status code was written.
+func (rr *responseRecorder) snapshot() http.Header {
+	if rr.sent == nil {
+		return rr.header.Clone()
+	}
+
+	return rr.sent
+}
+
+// statusWriter calls the onStatus function with the response status code before it is sent.
+type statusWriter struct {
+	http.ResponseWriter
+
+	onStatus    func(code int)
+	wroteHeader bool
+}
+
+// WriteHeader calls the onStatus function and writes the status code.
+func (sw *statusWriter) WriteHeader(code int) {
+	sw.setStatus(code)
+	sw.ResponseWriter.WriteHeader(code)
+}
+
+// Write calls the onStatus function with the implicit 200 status code and writes the body.
+func (sw *statusWriter) Write(b []byte) (int, error) {
+	sw.setStatus(http.StatusOK)
+	return sw.ResponseWriter.Write(b) //nolint:wrapcheck
+}
+
+// setStatus calls the onStatus function only for the first status code.
+func (sw *statusWriter) setStatus(code int) {
+	if sw.wroteHeader {
+		return
+	}
+
+	sw.wroteHeader = true
+	sw.onStatus(code)
+}
+
+// Unwrap returns the underlying ResponseWriter.
+func (sw *statusWriter) Unwrap() http.ResponseWriter {
+	return sw.ResponseWriter
+}
@@ -0,0 +1,342 @@
+package httpcache
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/httputil"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestHandler(calls *atomic.Int32) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+
+		switch r.URL.Path {
+		case "/json":
+			httputil.SendJSON(r.Context(), w, http.StatusOK, map[string]any{"path": r.URL.Path, "lang": r.Header.Get("Accept-Language")})
+		case "/cookie":
+			w.Header().Set("Set-Cookie", "a=b")
+			_, _ = w.Write([]byte("cookie"))
+		case "/maxage":
+			w.Header().Set("Cache-Control", "max-age=0")
+			_, _ = w.Write([]byte("maxage"))
+		case "/error":
+			http.Error(w, "error", http.StatusInternalServerError)
+		default:
+			_, _ = w.Write([]byte("call " + strconv.Itoa(int(n))))
+		}
+	})
+}
+
+func doRequest(t *testing.T, h http.Handler, method, target string, header map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	r := httptest.NewRequestWithContext(t.Context(), method, target, nil)
+	for k, v := range header {
+		r.Header.Set(k, v)
+	}
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, r)
+
+	return rr
+}
+
+func TestCache_conditional(t *testing.T) {
+	t.Parallel()
+
+	calls := &atomic.Int32{}
+	h := New().Handler(newTestHandler(calls))
+
+	rr := doRequest(t, h, http.MethodGet, "/json", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Empty(t, rr.Header().Get(HeaderXCache))
+	require.Empty(t, rr.Header().Get("Last-Modified"))
+
+	etag := rr.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	require.Equal(t, etagOf(rr.Body.Bytes()), etag)
+
+	rr = doRequest(t, h, http.MethodGet, "/json", map[string]string{"If-None-Match": etag})
+	require.Equal(t, http.StatusNotModified, rr.Code)
+	require.Empty(t, rr.Body.String())
+	require.Equal(t, etag, rr.Header().Get("ETag"))
+	require.Equal(t, int32(2), calls.Load())
+
+	rr = doRequest(t, h, http.MethodGet, "/error", map[string]string{"If-None-Match": "*"})
+	require.Equal(t, http.StatusInternalServerError, rr.Code)
+	require.Empty(t, rr.Header().Get("ETag"))
+
+	rr = doRequest(t, h, http.MethodHead, "/json", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, int32(4), calls.Load())
+
+	rr = doRequest(t, h, http.MethodOptions, "/json", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, int32(5), calls.Load())
+}
+
+//nolint:maintidx
+func TestCache_store(t *testing.T) {
+	t.Parallel()
+
+	calls := &atomic.Int32{}
+	store := NewLRUStore(10)
+	c := New(
+		WithStore(store),
+		WithTTL(time.Minute),
+		WithVaryHeaders("Accept-Language"),
+		WithCacheControl("public, max-age=60"),
+	)
+	h := c.Handler(newTestHandler(calls))
+
+	// miss and store
+
+	rr := doRequest(t, h, http.MethodGet, "/json?b=2&a=1", map[string]string{"Accept-Language": "en"})
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, XCacheMiss, rr.Header().Get(HeaderXCache))
+	require.Equal(t, "public, max-age=60", rr.Header().Get("Cache-Control"))
+	require.Empty(t, rr.Header().Get("Pragma"))
+	require.Equal(t, "Accept-Language", rr.Header().Get("Vary"))
+	require.NotEmpty(t, rr.Header().Get("Last-Modified"))
+
+	body := rr.Body.String()
+	etag := rr.Header().Get("ETag")
+
+	_, err := store.Get(t.Context(), "httpcache:GET /json?a=1&b=2\nAccept-Language:en")
+	require.NoError(t, err)
+
+	// hit with the same normalized query
+
+	rr = doRequest(t, h, http.MethodGet, "/json?a=1&b=2", map[string]string{"Accept-Language": "en"})
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, XCacheHit, rr.Header().Get(HeaderXCache))
+	require.Equal(t, "0", rr.Header().Get("Age"))
+	require.Equal(t, body, rr.Body.String())
+	require.Equal(t, etag, rr.Header().Get("ETag"))
+	require.Equal(t, int32(1), calls.Load())
+
+	// hit and not modified
+
+	rr = doRequest(t, h, http.MethodGet, "/json?a=1&b=2", map[string]string{
+		"Accept-Language":   "en",
+		"If-Modified-Since": time.Now().Add(time.Minute).UTC().Format(http.TimeFormat),
+	})
+	require.Equal(t, http.StatusNotModified, rr.Code)
+	require.Equal(t, XCacheHit, rr.Header().Get(HeaderXCache))
+
+	// HEAD served from the GET entry
+
+	rr = doRequest(t, h, http.MethodHead, "/json?a=1&b=2", map[string]string{"Accept-Language": "en"})
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, XCacheHit, rr.Header().Get(HeaderXCache))
+	require.Empty(t, rr.Body.String())
+	require.Equal(t, int32(1), calls.Load())
+
+	// different vary header value
+
+	rr = doRequest(t, h, http.MethodGet, "/json?a=1&b=2", map[string]string{"Accept-Language": "it"})
+	require.Equal(t, XCacheMiss, rr.Header().Get(HeaderXCache))
+	require.Equal(t, int32(2), calls.Load())
+
+	// request cache control
+
+	rr = doRequest(t, h, http.MethodGet, "/json?a=1&b=2", map[string]string{"Accept-Language": "en", "Cache-Control": "no-cache"})
+	require.Equal(t, XCacheMiss, rr.Header().Get(HeaderXCache))
+
+	rr = doRequest(t, h, http.MethodGet, "/json?a=1&b=2", map[string]string{"Accept-Language": "en", "Cache-Control": "max-age=0"})
+	require.Equal(t, XCacheMiss, rr.Header().Get(HeaderXCache))
+	require.Equal(t, int32(4), calls.Load())
+
+	// not storable responses
+
+	for _, path := range []string{"/cookie", "/error"} {
+		doRequest(t, h, http.MethodGet, path, nil)
+		doRequest(t, h, http.MethodGet, path, map[string]string{"Authorization": "Bearer x"})
+
+		_, err = store.Get(t.Context(), "httpcache:GET "+path+"?\nAccept-Language:")
+		require.ErrorIs(t, err, ErrNotFound, path)
+	}
+
+	rr = doRequest(t, h, http.MethodGet, "/text", map[string]string{"Cache-Control": "no-store"})
+	require.Equal(t, XCacheMiss, rr.Header().Get(HeaderXCache))
+
+	rr = doRequest(t, h, http.MethodGet, "/text", map[string]string{"Authorization": "Bearer x"})
+	require.Equal(t, XCacheMiss, rr.Header().Get(HeaderXCache))
+
+	rr = doRequest(t, h, http.MethodGet, "/text", nil)
+	require.Equal(t, XCacheHit, rr.Header().Get(HeaderXCache))
+
+	// invalidation on write
+
+	rr = doRequest(t, h, http.MethodPost, "/json", map[string]string{"Accept-Language": "en"})
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	rr = doRequest(t, h, http.MethodGet, "/json?a=1&b=2", map[string]string{"Accept-Language": "en"})
+	require.Equal(t, XCacheMiss, rr.Header().Get(HeaderXCache))
+
+	calls.Store(0)
+	rr = doRequest(t, h, http.MethodDelete, "/error", nil)
+	require.Equal(t, http.StatusInternalServerError, rr.Code)
+
+	rr = doRequest(t, h, http.MethodGet, "/text", nil)
+	require.Equal(t, XCacheHit, rr.Header().Get(HeaderXCache))
+
+	// explicit invalidation
+
+	require.NoError(t, c.Invalidate(t.Context(), "GET /"))
+	require.Equal(t, 0, store.Len())
+}
+
+func TestCache_responseCacheControl(t *testing.T) {
+	t.Parallel()
+
+	calls := &atomic.Int32{}
+	h := New(WithStore(NewLRUStore(10))).Handler(newTestHandler(calls))
+
+	for _, path := range []string{"/json", "/maxage", "/json", "/maxage"} {
+		rr := doRequest(t, h, http.MethodGet, path, nil)
+		require.Equal(t, XCacheMiss, rr.Header().Get(HeaderXCache))
+	}
+
+	require.Equal(t, int32(4), calls.Load())
+
+	rr := doRequest(t, h, http.MethodGet, "/text", nil)
+	require.Equal(t, XCacheMiss, rr.Header().Get(HeaderXCache))
+
+	rr = doRequest(t, h, http.MethodGet, "/text", nil)
+	require.Equal(t, XCacheHit, rr.Header().Get(HeaderXCache))
+}
+
+type errStore struct{}
+
+func (s *errStore) Get(_ context.Context, key string) ([]byte, error) {
+	if key == "httpcache:GET /invalid?" {
+		return []byte("{"), nil
+	}
+
+	return nil, errors.New("get error")
+}
+
+func (s *errStore) Set(_ context.Context, _ string, _ []byte, _ time.Duration) error {
+	return errors.New("set error")
+}
+
+func (s *errStore) DelPrefix(_ context.Context, _ string) error {
+	return errors.New("del error")
+}
+
+func TestCache_storeErrors(t *testing.T) {
+	t.Parallel()
+
+	calls := &atomic.Int32{}
+	c := New(WithStore(&errStore{}))
+	h := c.Handler(newTestHandler(calls))
+
+	rr := doRequest(t, h, http.MethodGet, "/text", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, XCacheMiss, rr.Header().Get(HeaderXCache))
+
+	rr = doRequest(t, h, http.MethodGet, "/invalid", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, XCacheMiss, rr.Header().Get(HeaderXCache))
+
+	rr = doRequest(t, h, http.MethodPut, "/text", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	require.Error(t, c.Invalidate(t.Context(), "GET /"))
+	require.NoError(t, New().Invalidate(t.Context(), "GET /"))
+}
+
+type countingStore struct {
+	*LRUStore
+
+	calls atomic.Int32
+}
+
+func (s *countingStore) DelPrefix(ctx context.Context, prefix string) error {
+	s.calls.Add(1)
+	return s.LRUStore.DelPrefix(ctx, prefix) //nolint:wrapcheck
+}
+
+// statusRecorder records the number of invalidations when the response is sent.
+type statusRecorder struct {
+	*httptest.ResponseRecorder
+
+	store *countingStore
+	calls int32
+	sent  bool
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.record()
+	r.ResponseRecorder.WriteHeader(code)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	r.record()
+	return r.ResponseRecorder.Write(b) //nolint:wrapcheck
+}
+
+func (r *statusRecorder) record() {
+	if !r.sent {
+		r.sent = true
+		r.calls = r.store.calls.Load()
+	}
+}
+
+func TestCache_invalidateBeforeResponse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		handler       http.HandlerFunc
+		wantCalls     int32
+		wantSentCalls int32
+	}{
+		{
+			name:          "explicit status",
+			handler:       func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusCreated) },
+			wantCalls:     1,
+			wantSentCalls: 1,
+		},
+		{
+			name:          "implicit status",
+			handler:       func(w http.ResponseWriter, _ *http.Request) { _, _ = w.Write([]byte("ok")) },
+			wantCalls:     1,
+			wantSentCalls: 1,
+		},
+		{
+			name:      "no response",
+			handler:   func(_ http.ResponseWriter, _ *http.Request) {},
+			wantCalls: 1,
+		},
+		{
+			name:      "error status",
+			handler:   func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusConflict) },
+			wantCalls: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := &countingStore{LRUStore: NewLRUStore(1)}
+			h := New(WithStore(store)).Handler(tt.handler)
+
+			rr := &statusRecorder{ResponseRecorder: httptest.NewRecorder(), store: store}
+			h.ServeHTTP(rr, httptest.NewRequestWithContext(t.Context(), http.MethodPut, "/users/1", nil))
+
+			require.Equal(t, tt.wantCalls, store.calls.Load())
+			require.Equal(t, tt.wantSentCalls, rr.calls, "the cache must be invalidated before the response is sent")
+		})
+	}
+}
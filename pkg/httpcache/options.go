@@ -0,0 +1,100 @@
+package httpcache
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/metrics"
+)
+
+// InvalidatePrefixFn returns the key prefixes to invalidate after a successful write request.
+type InvalidatePrefixFn func(r *http.Request) []string
+
+// Option is the interface that allows to set the cache options.
+type Option func(c *Cache)
+
+// WithStore sets the server-side store for the cached responses.
+// Without a store only the ETag and the conditional requests are handled.
+func WithStore(s Store) Option {
+	return func(c *Cache) {
+		c.store = s
+	}
+}
+
+// WithTTL sets the default time-to-live of the stored responses,
+// used when the response Cache-Control does not specify s-maxage or max-age.
+func WithTTL(ttl time.Duration) Option {
+	return func(c *Cache) {
+		c.ttl = ttl
+	}
+}
+
+// WithVaryHeaders sets the request headers to be included in the cache key.
+// The headers are also added to the response Vary header.
+func WithVaryHeaders(names ...string) Option {
+	return func(c *Cache) {
+		c.varyHeaders = make([]string, 0, len(names))
+
+		for _, name := range names {
+			c.varyHeaders = append(c.varyHeaders, http.CanonicalHeaderKey(name))
+		}
+	}
+}
+
+// WithCacheControl sets the Cache-Control header of the successful responses,
+// replacing the one set by the handler (e.g. the no-cache default of the httputil.Send* functions),
+// and removes the Pragma and Expires headers.
+func WithCacheControl(value string) Option {
+	return func(c *Cache) {
+		c.cacheControl = value
+	}
+}
+
+// WithKeyPrefix sets the prefix of all the cache keys (namespace) in the store.
+func WithKeyPrefix(prefix string) Option {
+	return func(c *Cache) {
+		c.keyPrefix = prefix
+	}
+}
+
+// WithInvalidatePrefixFn sets the function returning the key prefixes to invalidate after a successful write request.
+func WithInvalidatePrefixFn(fn InvalidatePrefixFn) Option {
+	return func(c *Cache) {
+		c.invalidatePrefixFn = fn
+	}
+}
+
+// ClientOption is the interface that allows to set the client cache options.
+type ClientOption func(c *ClientCache)
+
+// WithClientKeyPrefix sets the prefix of all the client cache keys (namespace) in the store.
+func WithClientKeyPrefix(prefix string) ClientOption {
+	return func(c *ClientCache) {
+		c.keyPrefix = prefix
+	}
+}
+
+// WithClientStaleTTL sets the time the stale responses with validators (ETag or Last-Modified)
+// or the stale-if-error directive are kept in the store after their expiration.
+func WithClientStaleTTL(ttl time.Duration) ClientOption {
+	return func(c *ClientCache) {
+		c.staleTTL = ttl
+	}
+}
+
+// WithClientMaxBodySize sets the maximum size in bytes of the stored response bodies.
+// Larger responses are not stored.
+func WithClientMaxBodySize(size int) ClientOption {
+	return func(c *ClientCache) {
+		c.maxBodySize = size
+	}
+}
+
+// WithClientMetrics sets the metrics client used to count the cache outcomes
+// with IncEventCounter (task "httpcache_client", operation set to the request host,
+// outcome "hit", "miss", "revalidated" or "stale").
+func WithClientMetrics(m metrics.Client) ClientOption {
+	return func(c *ClientCache) {
+		c.metrics = m
+	}
+}
@@ -0,0 +1,94 @@
+package httpcache
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/metrics"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithStore(t *testing.T) {
+	t.Parallel()
+
+	s := NewLRUStore(1)
+	c := &Cache{}
+	WithStore(s)(c)
+	require.Equal(t, s, c.store)
+}
+
+func TestWithTTL(t *testing.T) {
+	t.Parallel()
+
+	c := &Cache{}
+	WithTTL(17 * time.Second)(c)
+	require.Equal(t, 17*time.Second, c.ttl)
+}
+
+func TestWithVaryHeaders(t *testing.T) {
+	t.Parallel()
+
+	c := &Cache{}
+	WithVaryHeaders("accept-language", "X-Tenant")(c)
+	require.Equal(t, []string{"Accept-Language", "X-Tenant"}, c.varyHeaders)
+}
+
+func TestWithCacheControl(t *testing.T) {
+	t.Parallel()
+
+	c := &Cache{}
+	WithCacheControl("public, max-age=60")(c)
+	require.Equal(t, "public, max-age=60", c.cacheControl)
+}
+
+func TestWithKeyPrefix(t *testing.T) {
+	t.Parallel()
+
+	c := &Cache{}
+	WithKeyPrefix("test:")(c)
+	require.Equal(t, "test:", c.keyPrefix)
+}
+
+func TestWithInvalidatePrefixFn(t *testing.T) {
+	t.Parallel()
+
+	fn := func(_ *http.Request) []string { return []string{"GET /"} }
+
+	c := &Cache{}
+	WithInvalidatePrefixFn(fn)(c)
+	require.NotNil(t, c.invalidatePrefixFn)
+}
+
+func TestWithClientKeyPrefix(t *testing.T) {
+	t.Parallel()
+
+	c := &ClientCache{}
+	WithClientKeyPrefix("test:")(c)
+	require.Equal(t, "test:", c.keyPrefix)
+}
+
+func TestWithClientStaleTTL(t *testing.T) {
+	t.Parallel()
+
+	c := &ClientCache{}
+	WithClientStaleTTL(time.Minute)(c)
+	require.Equal(t, time.Minute, c.staleTTL)
+}
+
+func TestWithClientMaxBodySize(t *testing.T) {
+	t.Parallel()
+
+	c := &ClientCache{}
+	WithClientMaxBodySize(123)(c)
+	require.Equal(t, 123, c.maxBodySize)
+}
+
+func TestWithClientMetrics(t *testing.T) {
+	t.Parallel()
+
+	m := &metrics.Default{}
+	c := &ClientCache{}
+	WithClientMetrics(m)(c)
+	require.Equal(t, m, c.metrics)
+}
@@ -0,0 +1,92 @@
+package httpcache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	libredis "github.com/redis/go-redis/v9"
+)
+
+// redisScanCount is the number of keys requested for each SCAN iteration.
+const redisScanCount = 100
+
+// globEscaper escapes the special characters of the SCAN MATCH glob-style patterns.
+var globEscaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`) //nolint:gochecknoglobals
+
+// RedisClient contains the methods of the github.com/redis/go-redis/v9 client used by the RedisStore
+// (e.g. *redis.Client).
+type RedisClient interface {
+	Del(ctx context.Context, keys ...string) *libredis.IntCmd
+	Get(ctx context.Context, key string) *libredis.StringCmd
+	Scan(ctx context.Context, cursor uint64, match string, count int64) *libredis.ScanCmd
+	Set(ctx context.Context, key string, value any, expiration time.Duration) *libredis.StatusCmd
+}
+
+// RedisStore is a Store backed by Redis.
+//
+// NOTE: DelPrefix iterates the whole keyspace with SCAN, so its cost grows with
+// the total number of keys in the database, and it only covers the keys of a
+// single node: it is not supported with Redis Cluster.
+type RedisStore struct {
+	client RedisClient
+}
+
+// NewRedisStore creates a new Store backed by Redis.
+func NewRedisStore(client RedisClient) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// Get returns the value for the specified key, or ErrNotFound.
+func (s *RedisStore) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := s.client.Get(ctx, key).Bytes()
+	if errors.Is(err, libredis.Nil) {
+		return nil, ErrNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("redis store: %w", err)
+	}
+
+	return value, nil
+}
+
+// Set stores the value for the specified key with a time-to-live.
+func (s *RedisStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	err := s.client.Set(ctx, key, value, ttl).Err()
+	if err != nil {
+		return fmt.Errorf("redis store: %w", err)
+	}
+
+	return nil
+}
+
+// DelPrefix removes all the keys starting with the specified prefix.
+// The keys are iterated with SCAN, so the operation is not atomic.
+func (s *RedisStore) DelPrefix(ctx context.Context, prefix string) error {
+	match := globEscaper.Replace(prefix) + "*"
+
+	var cursor uint64
+
+	for {
+		keys, next, err := s.client.Scan(ctx, cursor, match, redisScanCount).Result()
+		if err != nil {
+			return fmt.Errorf("redis store: unable to scan the keys: %w", err)
+		}
+
+		if len(keys) > 0 {
+			err = s.client.Del(ctx, keys...).Err()
+			if err != nil {
+				return fmt.Errorf("redis store: unable to delete the keys: %w", err)
+			}
+		}
+
+		if next == 0 {
+			return nil
+		}
+
+		cursor = next
+	}
+}
@@ -0,0 +1,119 @@
+package httpcache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	libredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+var errRedisTest = errors.New("test error")
+
+// redisClientMock is an in-memory RedisClient returning errors for the "error" keys.
+type redisClientMock struct {
+	data     map[string][]byte
+	scanKeys []string
+	scanErr  error
+}
+
+func (m *redisClientMock) Del(_ context.Context, keys ...string) *libredis.IntCmd {
+	for _, key := range keys {
+		if strings.HasPrefix(key, "error") {
+			return libredis.NewIntResult(0, errRedisTest)
+		}
+
+		delete(m.data, key)
+	}
+
+	return libredis.NewIntResult(int64(len(keys)), nil)
+}
+
+func (m *redisClientMock) Get(_ context.Context, key string) *libredis.StringCmd {
+	if key == "error" {
+		return libredis.NewStringResult("", errRedisTest)
+	}
+
+	v, ok := m.data[key]
+	if !ok {
+		return libredis.NewStringResult("", libredis.Nil)
+	}
+
+	return libredis.NewStringResult(string(v), nil)
+}
+
+// Scan returns one key per iteration, using the cursor as index of the keys matching at the first iteration.
+func (m *redisClientMock) Scan(_ context.Context, cursor uint64, match string, _ int64) *libredis.ScanCmd {
+	if m.scanErr != nil {
+		return libredis.NewScanCmdResult(nil, 0, m.scanErr)
+	}
+
+	if cursor == 0 {
+		prefix := strings.ReplaceAll(strings.TrimSuffix(match, "*"), `\`, "")
+		m.scanKeys = nil
+
+		for key := range m.data {
+			if strings.HasPrefix(key, prefix) {
+				m.scanKeys = append(m.scanKeys, key)
+			}
+		}
+	}
+
+	if int(cursor) >= len(m.scanKeys) {
+		return libredis.NewScanCmdResult(nil, 0, nil)
+	}
+
+	next := cursor + 1
+	if int(next) == len(m.scanKeys) {
+		next = 0
+	}
+
+	return libredis.NewScanCmdResult(m.scanKeys[cursor:cursor+1], next, nil)
+}
+
+func (m *redisClientMock) Set(_ context.Context, key string, value any, _ time.Duration) *libredis.StatusCmd {
+	if key == "error" {
+		return libredis.NewStatusResult("", errRedisTest)
+	}
+
+	m.data[key], _ = value.([]byte)
+
+	return libredis.NewStatusResult("OK", nil)
+}
+
+func TestRedisStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	m := &redisClientMock{data: map[string][]byte{}}
+	s := NewRedisStore(m)
+
+	_, err := s.Get(ctx, "key")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	_, err = s.Get(ctx, "error")
+	require.ErrorIs(t, err, errRedisTest)
+
+	require.NoError(t, s.Set(ctx, "key", []byte("value"), time.Minute))
+	require.ErrorIs(t, s.Set(ctx, "error", []byte("value"), time.Minute), errRedisTest)
+
+	v, err := s.Get(ctx, "key")
+	require.NoError(t, err)
+	require.Equal(t, []byte("value"), v)
+
+	require.NoError(t, s.Set(ctx, "k*1", []byte("v1"), time.Minute))
+	require.NoError(t, s.Set(ctx, "k*2", []byte("v2"), time.Minute))
+	require.NoError(t, s.Set(ctx, "other", []byte("v3"), time.Minute))
+
+	require.NoError(t, s.DelPrefix(ctx, "k"))
+	require.Equal(t, map[string][]byte{"other": []byte("v3")}, m.data)
+
+	m.data["error_1"] = []byte("x")
+	require.ErrorIs(t, s.DelPrefix(ctx, "error"), errRedisTest)
+
+	m.scanErr = errRedisTest
+	require.ErrorIs(t, s.DelPrefix(ctx, "k"), errRedisTest)
+}
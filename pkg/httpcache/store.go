@@ -0,0 +1,132 @@
+package httpcache
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned by a Store when the key is not present or expired.
+var ErrNotFound = errors.New("cache entry not found")
+
+// Store is the interface of the backends used to store the cached responses.
+type Store interface {
+	// Get returns the value for the specified key, or ErrNotFound.
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// Set stores the value for the specified key with a time-to-live.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+
+	// DelPrefix removes all the keys starting with the specified prefix.
+	DelPrefix(ctx context.Context, prefix string) error
+}
+
+// lruItem is an entry of the LRUStore.
+type lruItem struct {
+	key      string
+	value    []byte
+	expireAt time.Time
+}
+
+// LRUStore is a local, thread-safe, fixed-size, in-memory Store
+// that evicts the least recently used entries first.
+type LRUStore struct {
+	mux   sync.Mutex
+	size  int
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+// NewLRUStore creates a new in-memory LRU store with the specified maximum number of entries (min = 1).
+func NewLRUStore(size int) *LRUStore {
+	if size <= 0 {
+		size = 1
+	}
+
+	return &LRUStore{
+		size:  size,
+		ll:    list.New(),
+		items: make(map[string]*list.Element, size),
+	}
+}
+
+// Len returns the number of entries in the store, including the expired ones not yet evicted.
+func (s *LRUStore) Len() int {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	return s.ll.Len()
+}
+
+// Get returns the value for the specified key, or ErrNotFound.
+func (s *LRUStore) Get(_ context.Context, key string) ([]byte, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	e, ok := s.items[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	item, _ := e.Value.(*lruItem)
+
+	if !time.Now().Before(item.expireAt) {
+		s.remove(e)
+		return nil, ErrNotFound
+	}
+
+	s.ll.MoveToFront(e)
+
+	return item.value, nil
+}
+
+// Set stores the value for the specified key with a time-to-live.
+func (s *LRUStore) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	item := &lruItem{
+		key:      key,
+		value:    value,
+		expireAt: time.Now().Add(ttl),
+	}
+
+	if e, ok := s.items[key]; ok {
+		e.Value = item
+		s.ll.MoveToFront(e)
+
+		return nil
+	}
+
+	s.items[key] = s.ll.PushFront(item)
+
+	for s.ll.Len() > s.size {
+		s.remove(s.ll.Back())
+	}
+
+	return nil
+}
+
+// DelPrefix removes all the keys starting with the specified prefix.
+func (s *LRUStore) DelPrefix(_ context.Context, prefix string) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	for key, e := range s.items {
+		if strings.HasPrefix(key, prefix) {
+			s.remove(e)
+		}
+	}
+
+	return nil
+}
+
+// remove deletes the specified list element.
+// NOTE: this is not thread-safe, it should be called within a mutex lock.
+func (s *LRUStore) remove(e *list.Element) {
+	item, _ := s.ll.Remove(e).(*lruItem)
+	delete(s.items, item.key)
+}
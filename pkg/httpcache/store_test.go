@@ -0,0 +1,64 @@
+package httpcache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRUStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	s := NewLRUStore(2)
+
+	_, err := s.Get(ctx, "a")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, s.Set(ctx, "a", []byte("1"), time.Minute))
+	require.NoError(t, s.Set(ctx, "b", []byte("2"), time.Minute))
+
+	// touch "a" so that "b" becomes the least recently used entry
+	v, err := s.Get(ctx, "a")
+	require.NoError(t, err)
+	require.Equal(t, []byte("1"), v)
+
+	require.NoError(t, s.Set(ctx, "c", []byte("3"), time.Minute))
+	require.Equal(t, 2, s.Len())
+
+	_, err = s.Get(ctx, "b")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, s.Set(ctx, "c", []byte("4"), time.Minute))
+
+	v, err = s.Get(ctx, "c")
+	require.NoError(t, err)
+	require.Equal(t, []byte("4"), v)
+
+	require.NoError(t, s.Set(ctx, "a", []byte("1"), -1))
+
+	_, err = s.Get(ctx, "a")
+	require.ErrorIs(t, err, ErrNotFound)
+	require.Equal(t, 1, s.Len())
+}
+
+func TestLRUStore_DelPrefix(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	s := NewLRUStore(0)
+	require.Equal(t, 1, s.size)
+
+	s = NewLRUStore(10)
+
+	for _, k := range []string{"GET /users?", "GET /users/1?", "GET /items?"} {
+		require.NoError(t, s.Set(ctx, k, []byte(k), time.Minute))
+	}
+
+	require.NoError(t, s.DelPrefix(ctx, "GET /users"))
+	require.Equal(t, 1, s.Len())
+
+	_, err := s.Get(ctx, "GET /items?")
+	require.NoError(t, err)
+}
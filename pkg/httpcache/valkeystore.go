@@ -0,0 +1,64 @@
+package httpcache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	libvalkey "github.com/valkey-io/valkey-go"
+)
+
+// ValkeyClient contains the methods of the github.com/Vonage/gosrvlib/pkg/valkey Client used by the ValkeyStore.
+type ValkeyClient interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value string, exp time.Duration) error
+	DelPrefix(ctx context.Context, prefix string) error
+}
+
+// ValkeyStore is a Store backed by Valkey.
+// NOTE: Valkey expiration times have a resolution of one second.
+// DelPrefix iterates the whole keyspace with SCAN (see the valkey Client DelPrefix),
+// so it is not supported with Valkey Cluster.
+type ValkeyStore struct {
+	client ValkeyClient
+}
+
+// NewValkeyStore creates a new Store backed by Valkey.
+func NewValkeyStore(client ValkeyClient) *ValkeyStore {
+	return &ValkeyStore{client: client}
+}
+
+// Get returns the value for the specified key, or ErrNotFound.
+func (s *ValkeyStore) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := s.client.Get(ctx, key)
+	if errors.Is(err, libvalkey.Nil) {
+		return nil, ErrNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("valkey store: %w", err)
+	}
+
+	return []byte(value), nil
+}
+
+// Set stores the value for the specified key with a time-to-live.
+func (s *ValkeyStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	err := s.client.Set(ctx, key, string(value), max(ttl, time.Second))
+	if err != nil {
+		return fmt.Errorf("valkey store: %w", err)
+	}
+
+	return nil
+}
+
+// DelPrefix removes all the keys starting with the specified prefix.
+func (s *ValkeyStore) DelPrefix(ctx context.Context, prefix string) error {
+	err := s.client.DelPrefix(ctx, prefix)
+	if err != nil {
+		return fmt.Errorf("valkey store: %w", err)
+	}
+
+	return nil
+}
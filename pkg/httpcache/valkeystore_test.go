@@ -0,0 +1,96 @@
+package httpcache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	libvalkey "github.com/valkey-io/valkey-go"
+)
+
+type valkeyClientMock struct {
+	getFn       func(ctx context.Context, key string) (string, error)
+	setFn       func(ctx context.Context, key string, value string, exp time.Duration) error
+	delPrefixFn func(ctx context.Context, prefix string) error
+}
+
+func (m *valkeyClientMock) Get(ctx context.Context, key string) (string, error) {
+	return m.getFn(ctx, key)
+}
+
+func (m *valkeyClientMock) Set(ctx context.Context, key string, value string, exp time.Duration) error {
+	return m.setFn(ctx, key, value, exp)
+}
+
+func (m *valkeyClientMock) DelPrefix(ctx context.Context, prefix string) error {
+	return m.delPrefixFn(ctx, prefix)
+}
+
+func TestValkeyStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	data := map[string]string{}
+	errTest := errors.New("test error")
+
+	m := &valkeyClientMock{
+		getFn: func(_ context.Context, key string) (string, error) {
+			if key == "error" {
+				return "", errTest
+			}
+
+			v, ok := data[key]
+			if !ok {
+				return "", fmt.Errorf("cannot retrieve key %s: %w", key, libvalkey.Nil)
+			}
+
+			return v, nil
+		},
+		setFn: func(_ context.Context, key string, value string, exp time.Duration) error {
+			if key == "error" {
+				return errTest
+			}
+
+			if exp < time.Second {
+				return errors.New("invalid expiration")
+			}
+
+			data[key] = value
+
+			return nil
+		},
+		delPrefixFn: func(_ context.Context, prefix string) error {
+			if prefix == "error" {
+				return errTest
+			}
+
+			clear(data)
+
+			return nil
+		},
+	}
+
+	s := NewValkeyStore(m)
+
+	_, err := s.Get(ctx, "key")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	_, err = s.Get(ctx, "error")
+	require.ErrorIs(t, err, errTest)
+
+	require.NoError(t, s.Set(ctx, "key", []byte("value"), time.Millisecond))
+	require.ErrorIs(t, s.Set(ctx, "error", []byte("value"), time.Minute), errTest)
+
+	v, err := s.Get(ctx, "key")
+	require.NoError(t, err)
+	require.Equal(t, []byte("value"), v)
+
+	require.NoError(t, s.DelPrefix(ctx, "k"))
+	require.ErrorIs(t, s.DelPrefix(ctx, "error"), errTest)
+
+	_, err = s.Get(ctx, "key")
+	require.ErrorIs(t, err, ErrNotFound)
+}
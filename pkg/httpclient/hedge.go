@@ -0,0 +1,384 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/httpretrier"
+	"github.com/Vonage/gosrvlib/pkg/metrics"
+)
+
+const (
+	// DefaultHedgeDelay is the default delay before sending a hedged request.
+	DefaultHedgeDelay = 100 * time.Millisecond
+
+	// DefaultMaxHedges is the default maximum number of hedged requests sent in addition to the original one.
+	DefaultMaxHedges = 1
+
+	// minLatencySamples is the minimum number of latency samples required to use the percentile-based delay.
+	minLatencySamples = 10
+)
+
+// Hedge metrics outcomes.
+const (
+	hedgeMetricsTask = "httpclient_hedge"
+	hedgeSent        = "hedge_sent"
+	hedgeWon         = "hedge_won"
+	primaryWon       = "primary_won"
+	allFailed        = "all_failed"
+)
+
+// HedgeIfFn is the signature of the function used to decide if a request can be hedged.
+type HedgeIfFn func(r *http.Request) bool
+
+// HedgeStats contains the hedging statistics.
+type HedgeStats struct {
+	// Requests is the number of requests eligible for hedging.
+	Requests uint64
+
+	// Hedges is the number of hedged requests sent.
+	Hedges uint64
+
+	// HedgeWins is the number of requests successfully answered by a hedged request instead of the original one.
+	HedgeWins uint64
+}
+
+// Hedge sends additional identical requests (hedges) if the previous ones have not answered
+// within a fixed or percentile-based delay, returning the first successful response
+// and canceling the others. This reduces the tail latency at the cost of extra load.
+type Hedge struct {
+	delay      time.Duration
+	maxHedges  uint
+	percentile float64
+	latencies  *latencyWindow
+	hedgeIfFn  HedgeIfFn
+	retryIfFn  httpretrier.RetryIfFn
+	metrics    metrics.Client
+	requests   atomic.Uint64
+	hedges     atomic.Uint64
+	hedgeWins  atomic.Uint64
+}
+
+// NewHedge creates a new request hedging policy.
+func NewHedge(opts ...HedgeOption) *Hedge {
+	h := &Hedge{
+		delay:     DefaultHedgeDelay,
+		maxHedges: DefaultMaxHedges,
+		hedgeIfFn: HedgeIfIdempotent,
+		retryIfFn: HedgeRetryIf,
+		metrics:   &metrics.Default{},
+	}
+
+	for _, applyOpt := range opts {
+		applyOpt(h)
+	}
+
+	return h
+}
+
+// HedgeIfIdempotent is the default HedgeIfFn, allowing only the idempotent methods
+// with a replayable body.
+func HedgeIfIdempotent(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return false
+}
+
+// HedgeRetryIf is the default httpretrier.RetryIfFn used to discard a response and wait for the other requests:
+// it returns true in case of error or 5xx status code.
+func HedgeRetryIf(r *http.Response, err error) bool {
+	return err != nil || r.StatusCode >= http.StatusInternalServerError
+}
+
+// RoundTripper returns a hedging http.RoundTripper wrapping the next one (default http.DefaultTransport).
+// It can be installed in the Client with WithRoundTripper(hedge.RoundTripper).
+func (h *Hedge) RoundTripper(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &hedgedRoundTripper{hedge: h, next: next}
+}
+
+// Stats returns the hedging statistics.
+func (h *Hedge) Stats() HedgeStats {
+	return HedgeStats{
+		Requests:  h.requests.Load(),
+		Hedges:    h.hedges.Load(),
+		HedgeWins: h.hedgeWins.Load(),
+	}
+}
+
+// hedgeDelay returns the delay before sending a hedged request.
+func (h *Hedge) hedgeDelay() time.Duration {
+	if h.latencies == nil {
+		return h.delay
+	}
+
+	d, ok := h.latencies.percentile(h.percentile)
+	if !ok {
+		return h.delay
+	}
+
+	return d
+}
+
+// hedgedRoundTripper is the RoundTripper returned by Hedge.RoundTripper.
+type hedgedRoundTripper struct {
+	hedge *Hedge
+	next  http.RoundTripper
+}
+
+// hedgeResult is the result of a single request.
+type hedgeResult struct {
+	index uint
+	resp  *http.Response
+	err   error
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (rt *hedgedRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	if rt.hedge.maxHedges == 0 || !rt.hedge.hedgeIfFn(r) || (r.Body != nil && r.Body != http.NoBody && r.GetBody == nil) {
+		return rt.next.RoundTrip(r) //nolint:wrapcheck
+	}
+
+	rt.hedge.requests.Add(1)
+
+	c := &hedgeCall{
+		rt:      rt,
+		req:     r,
+		results: make(chan hedgeResult, rt.hedge.maxHedges+1),
+		cancels: make([]context.CancelFunc, 0, rt.hedge.maxHedges+1),
+		start:   time.Now(),
+	}
+
+	return c.run()
+}
+
+// hedgeCall is the state of a single hedged request.
+type hedgeCall struct {
+	rt      *hedgedRoundTripper
+	req     *http.Request
+	results chan hedgeResult
+	cancels []context.CancelFunc
+	pending int
+	last    *hedgeResult
+	start   time.Time
+}
+
+// run sends the original request and the hedges until a successful response is received
+// or all the requests have failed.
+func (c *hedgeCall) run() (*http.Response, error) {
+	h := c.rt.hedge
+
+	c.send()
+
+	timer := time.NewTimer(h.hedgeDelay())
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			if c.canSend() {
+				c.send()
+				timer.Reset(h.hedgeDelay())
+			}
+		case res := <-c.results:
+			c.pending--
+
+			if !h.retryIfFn(res.resp, res.err) {
+				return c.win(res)
+			}
+
+			c.discard(res)
+
+			if c.pending > 0 {
+				continue
+			}
+
+			if !c.canSend() {
+				return c.win(*c.last)
+			}
+
+			// all the sent requests have failed: send the next hedge immediately
+			c.send()
+			timer.Reset(h.hedgeDelay())
+		}
+	}
+}
+
+// canSend returns true if more hedges can be sent.
+func (c *hedgeCall) canSend() bool {
+	return uint(len(c.cancels)) <= c.rt.hedge.maxHedges
+}
+
+// send sends a new copy of the request.
+func (c *hedgeCall) send() {
+	h := c.rt.hedge
+	index := uint(len(c.cancels))
+
+	ctx, cancel := context.WithCancel(c.req.Context())
+	c.cancels = append(c.cancels, cancel)
+	c.pending++
+
+	req := c.req.Clone(ctx)
+
+	if index > 0 {
+		h.hedges.Add(1)
+		metrics.IncEventCounter(h.metrics, hedgeMetricsTask, c.req.URL.Host, hedgeSent)
+	}
+
+	go func() {
+		if c.req.GetBody != nil && index > 0 {
+			body, err := c.req.GetBody()
+			if err != nil {
+				c.results <- hedgeResult{index: index, err: err}
+				return
+			}
+
+			req.Body = body
+		}
+
+		resp, err := c.rt.next.RoundTrip(req)
+		c.results <- hedgeResult{index: index, resp: resp, err: err}
+	}()
+}
+
+// discard keeps the last failed result to be returned if all the requests fail.
+func (c *hedgeCall) discard(res hedgeResult) {
+	if c.last != nil {
+		closeResult(*c.last)
+		c.cancels[c.last.index]()
+	}
+
+	c.last = &res
+}
+
+// win returns the selected response, cancels the other requests and releases their responses.
+func (c *hedgeCall) win(res hedgeResult) (*http.Response, error) {
+	h := c.rt.hedge
+
+	// the latency is measured from the original request start, as observed by the caller,
+	// so the late hedges finishing quickly don't lower the percentile-based delay
+	if h.latencies != nil && res.err == nil {
+		h.latencies.add(time.Since(c.start))
+	}
+
+	outcome := primaryWon
+
+	switch {
+	case h.retryIfFn(res.resp, res.err):
+		outcome = allFailed
+	case res.index > 0:
+		outcome = hedgeWon
+
+		h.hedgeWins.Add(1)
+	}
+
+	metrics.IncEventCounter(h.metrics, hedgeMetricsTask, c.req.URL.Host, outcome)
+
+	for i, cancel := range c.cancels {
+		if uint(i) != res.index {
+			cancel()
+		}
+	}
+
+	if c.last != nil && c.last.index != res.index {
+		closeResult(*c.last)
+	}
+
+	// release the responses of the pending requests
+	go func(pending int) {
+		for range pending {
+			closeResult(<-c.results)
+		}
+	}(c.pending)
+
+	winCancel := c.cancels[res.index]
+
+	if res.err != nil {
+		winCancel()
+		return nil, res.err
+	}
+
+	res.resp.Body = &cancelBody{ReadCloser: res.resp.Body, cancel: winCancel}
+
+	return res.resp, nil
+}
+
+// closeResult drains and closes the response body of a discarded result.
+func closeResult(res hedgeResult) {
+	if res.resp == nil {
+		return
+	}
+
+	_, _ = io.Copy(io.Discard, res.resp.Body)
+	_ = res.resp.Body.Close()
+}
+
+// cancelBody cancels the request context when the response body is closed.
+type cancelBody struct {
+	io.ReadCloser
+
+	cancel context.CancelFunc
+}
+
+// Close closes the body and cancels the request context.
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+
+	return err //nolint:wrapcheck
+}
+
+// latencyWindow keeps the most recent response latencies to compute the percentiles.
+type latencyWindow struct {
+	mux     sync.Mutex
+	samples []time.Duration
+	next    int
+}
+
+// newLatencyWindow returns a new latency window of the specified size.
+func newLatencyWindow(size int) *latencyWindow {
+	return &latencyWindow{samples: make([]time.Duration, 0, size)}
+}
+
+// add records a latency sample, replacing the oldest one when the window is full.
+func (w *latencyWindow) add(d time.Duration) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+
+	if len(w.samples) < cap(w.samples) {
+		w.samples = append(w.samples, d)
+		return
+	}
+
+	w.samples[w.next] = d
+	w.next = (w.next + 1) % len(w.samples)
+}
+
+// percentile returns the specified percentile (0 to 100) of the latencies,
+// or false if there are not enough samples.
+func (w *latencyWindow) percentile(p float64) (time.Duration, bool) {
+	w.mux.Lock()
+	sorted := slices.Clone(w.samples)
+	w.mux.Unlock()
+
+	if len(sorted) < minLatencySamples {
+		return 0, false
+	}
+
+	slices.Sort(sorted)
+
+	idx := min(int(p/100*float64(len(sorted))), len(sorted)-1)
+
+	return sorted[max(0, idx)], true
+}
@@ -0,0 +1,253 @@
+package httpclient
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/metrics"
+	"github.com/stretchr/testify/require"
+)
+
+type testHedgeMetrics struct {
+	metrics.Default
+
+	events atomic.Int32
+}
+
+func (m *testHedgeMetrics) IncEventCounter(_, _, _ string) {
+	m.events.Add(1)
+}
+
+// newHedgeTestServer returns a server that answers each request with the behaviour
+// specified by the sequence: "slow", "fast" or "fail".
+func newHedgeTestServer(t *testing.T, seq ...string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	calls := &atomic.Int32{}
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1))
+		body, _ := io.ReadAll(r.Body)
+
+		mode := "fast"
+		if n <= len(seq) {
+			mode = seq[n-1]
+		}
+
+		switch mode {
+		case "slow":
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(2 * time.Second):
+			}
+		case "fail":
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+
+		_, _ = w.Write([]byte(strconv.Itoa(n) + ":" + string(body)))
+	}))
+
+	t.Cleanup(s.Close)
+
+	return s, calls
+}
+
+func TestHedge_RoundTripper(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		seq        []string
+		opts       []HedgeOption
+		wantStatus int
+		wantBody   string
+		wantCalls  int32
+		wantStats  HedgeStats
+	}{
+		{
+			name:       "primary wins",
+			method:     http.MethodGet,
+			seq:        []string{"fast"},
+			wantStatus: http.StatusOK,
+			wantBody:   "1:",
+			wantCalls:  1,
+			wantStats:  HedgeStats{Requests: 1},
+		},
+		{
+			name:       "hedge wins",
+			method:     http.MethodGet,
+			seq:        []string{"slow", "fast"},
+			wantStatus: http.StatusOK,
+			wantBody:   "2:",
+			wantCalls:  2,
+			wantStats:  HedgeStats{Requests: 1, Hedges: 1, HedgeWins: 1},
+		},
+		{
+			name:       "hedge wins with body",
+			method:     http.MethodPut,
+			body:       "data",
+			seq:        []string{"slow", "fast"},
+			wantStatus: http.StatusOK,
+			wantBody:   "2:data",
+			wantCalls:  2,
+			wantStats:  HedgeStats{Requests: 1, Hedges: 1, HedgeWins: 1},
+		},
+		{
+			name:       "second hedge wins",
+			method:     http.MethodGet,
+			seq:        []string{"slow", "slow", "fast"},
+			opts:       []HedgeOption{WithMaxHedges(2)},
+			wantStatus: http.StatusOK,
+			wantBody:   "3:",
+			wantCalls:  3,
+			wantStats:  HedgeStats{Requests: 1, Hedges: 2, HedgeWins: 1},
+		},
+		{
+			name:       "failed primary",
+			method:     http.MethodGet,
+			seq:        []string{"fail", "fast"},
+			opts:       []HedgeOption{WithHedgeDelay(time.Minute)},
+			wantStatus: http.StatusOK,
+			wantBody:   "2:",
+			wantCalls:  2,
+			wantStats:  HedgeStats{Requests: 1, Hedges: 1, HedgeWins: 1},
+		},
+		{
+			name:       "all failed",
+			method:     http.MethodGet,
+			seq:        []string{"fail", "fail", "fail"},
+			opts:       []HedgeOption{WithMaxHedges(2)},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   "3:",
+			wantCalls:  3,
+			wantStats:  HedgeStats{Requests: 1, Hedges: 2},
+		},
+		{
+			name:       "not idempotent",
+			method:     http.MethodPost,
+			body:       "data",
+			seq:        []string{"fail"},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   "1:data",
+			wantCalls:  1,
+		},
+		{
+			name:       "disabled",
+			method:     http.MethodGet,
+			seq:        []string{"fail"},
+			opts:       []HedgeOption{WithMaxHedges(0)},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   "1:",
+			wantCalls:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server, calls := newHedgeTestServer(t, tt.seq...)
+			m := &testHedgeMetrics{}
+
+			opts := append([]HedgeOption{WithHedgeDelay(50 * time.Millisecond), WithHedgeMetrics(m)}, tt.opts...)
+			hedge := NewHedge(opts...)
+			// TestWithDialContext alters http.DefaultTransport
+			hc := New(WithRoundTripper(func(_ http.RoundTripper) http.RoundTripper {
+				return hedge.RoundTripper(&http.Transport{})
+			}))
+
+			req, err := http.NewRequestWithContext(t.Context(), tt.method, server.URL, strings.NewReader(tt.body))
+			require.NoError(t, err)
+
+			resp, err := hc.Do(req)
+			require.NoError(t, err)
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+
+			require.Equal(t, tt.wantStatus, resp.StatusCode)
+			require.Equal(t, tt.wantBody, string(body))
+			require.Equal(t, tt.wantCalls, calls.Load())
+			require.Equal(t, tt.wantStats, hedge.Stats())
+		})
+	}
+}
+
+func TestHedge_RoundTripper_transportError(t *testing.T) {
+	t.Parallel()
+
+	hedge := NewHedge(WithHedgeDelay(time.Millisecond))
+	require.NotNil(t, hedge.RoundTripper(nil))
+
+	rt := hedge.RoundTripper(&http.Transport{})
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://127.0.0.1:1", nil)
+	require.NoError(t, err)
+
+	resp, err := rt.RoundTrip(req) //nolint:bodyclose
+	require.Error(t, err)
+	require.Nil(t, resp)
+	require.Equal(t, HedgeStats{Requests: 1, Hedges: 1}, hedge.Stats())
+}
+
+func TestHedge_RoundTripper_latency(t *testing.T) {
+	t.Parallel()
+
+	server, _ := newHedgeTestServer(t, "slow", "fast")
+
+	hedge := NewHedge(WithHedgeDelay(50*time.Millisecond), WithHedgePercentile(90, 10))
+	rt := hedge.RoundTripper(&http.Transport{})
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	// the winning hedge latency includes the delay before it was sent
+	require.Len(t, hedge.latencies.samples, 1)
+	require.GreaterOrEqual(t, hedge.latencies.samples[0], 50*time.Millisecond)
+}
+
+func TestHedge_hedgeDelay(t *testing.T) {
+	t.Parallel()
+
+	h := NewHedge(WithHedgeDelay(time.Second), WithHedgePercentile(90, 5))
+	require.Equal(t, time.Second, h.hedgeDelay())
+
+	for i := range 20 {
+		h.latencies.add(time.Duration(i+1) * time.Millisecond)
+	}
+
+	// only the most recent 10 samples (11..20 ms) are kept
+	require.Equal(t, 20*time.Millisecond, h.hedgeDelay())
+
+	h.percentile = 50
+	require.Equal(t, 16*time.Millisecond, h.hedgeDelay())
+
+	h.percentile = 0
+	require.Equal(t, 11*time.Millisecond, h.hedgeDelay())
+}
+
+func TestHedgeIfIdempotent(t *testing.T) {
+	t.Parallel()
+
+	for _, method := range []string{http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete} {
+		require.True(t, HedgeIfIdempotent(httptest.NewRequest(method, "/", nil)), method)
+	}
+
+	for _, method := range []string{http.MethodPost, http.MethodPatch, http.MethodConnect} {
+		require.False(t, HedgeIfIdempotent(httptest.NewRequest(method, "/", nil)), method)
+	}
+}
@@ -3,5 +3,13 @@ Package httpclient provides a configurable and instrumented HTTP client with
 common options. It includes support for trace ID headers and common logging
 capabilities, such as the ability to dump redacted request and response
 messages.
+
+For tail-latency-critical requests, the Hedge RoundTripper sends additional
+identical requests when the previous ones have not answered within a fixed or
+percentile-based delay, returning the first successful response and canceling
+the others. It can be installed as:
+
+	hedge := httpclient.NewHedge(httpclient.WithHedgePercentile(95, 1000))
+	hc := httpclient.New(httpclient.WithRoundTripper(hedge.RoundTripper))
 */
 package httpclient
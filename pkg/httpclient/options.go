@@ -5,6 +5,9 @@ import (
 	"net"
 	"net/http"
 	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/httpretrier"
+	"github.com/Vonage/gosrvlib/pkg/metrics"
 )
 
 // InstrumentRoundTripper is an alias for a RoundTripper function.
@@ -72,3 +75,57 @@ func WithDialContext(fn DialContextFunc) Option {
 		}
 	}
 }
+
+// HedgeOption is the interface that allows to set the request hedging options.
+type HedgeOption func(h *Hedge)
+
+// WithHedgeDelay sets the fixed delay before sending each hedged request.
+// When the percentile-based delay is enabled, this is used until enough latency samples are collected.
+func WithHedgeDelay(delay time.Duration) HedgeOption {
+	return func(h *Hedge) {
+		h.delay = delay
+	}
+}
+
+// WithHedgePercentile sets the delay before sending each hedged request to the specified percentile
+// (e.g. 95) of the latencies of the most recent successful responses, tracked in a window of the specified size.
+// The latencies are measured from the start of the original request, including the hedging delays.
+func WithHedgePercentile(percentile float64, window int) HedgeOption {
+	return func(h *Hedge) {
+		h.percentile = percentile
+		h.latencies = newLatencyWindow(max(window, minLatencySamples))
+	}
+}
+
+// WithMaxHedges sets the maximum number of hedged requests sent in addition to the original one.
+func WithMaxHedges(n uint) HedgeOption {
+	return func(h *Hedge) {
+		h.maxHedges = n
+	}
+}
+
+// WithHedgeIfFn sets the function used to decide if a request can be hedged.
+// The default HedgeIfIdempotent function only allows the idempotent methods.
+// Requests with a body are only hedged if the GetBody function is set.
+func WithHedgeIfFn(fn HedgeIfFn) HedgeOption {
+	return func(h *Hedge) {
+		h.hedgeIfFn = fn
+	}
+}
+
+// WithHedgeRetryIfFn sets the function used to discard a response and wait for the other requests
+// (default HedgeRetryIf). If all the requests are discarded, the last response is returned.
+func WithHedgeRetryIfFn(fn httpretrier.RetryIfFn) HedgeOption {
+	return func(h *Hedge) {
+		h.retryIfFn = fn
+	}
+}
+
+// WithHedgeMetrics sets the metrics client used to count the hedged requests and the winners
+// with IncEventCounter (task "httpclient_hedge", operation set to the request host,
+// outcome "hedge_sent", "hedge_won", "primary_won" or "all_failed").
+func WithHedgeMetrics(m metrics.Client) HedgeOption {
+	return func(h *Hedge) {
+		h.metrics = m
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Vonage/gosrvlib/pkg/httpretrier"
+	"github.com/Vonage/gosrvlib/pkg/metrics"
 	"github.com/stretchr/testify/require"
 )
 
@@ -80,3 +82,53 @@ func TestWithDialContext(t *testing.T) {
 	require.Error(t, err)
 	require.Nil(t, out)
 }
+
+func TestWithHedgeDelay(t *testing.T) {
+	t.Parallel()
+
+	h := &Hedge{}
+	WithHedgeDelay(time.Second)(h)
+	require.Equal(t, time.Second, h.delay)
+}
+
+func TestWithHedgePercentile(t *testing.T) {
+	t.Parallel()
+
+	h := &Hedge{}
+	WithHedgePercentile(95, 100)(h)
+	require.InDelta(t, 95.0, h.percentile, 0)
+	require.Equal(t, 100, cap(h.latencies.samples))
+}
+
+func TestWithMaxHedges(t *testing.T) {
+	t.Parallel()
+
+	h := &Hedge{}
+	WithMaxHedges(3)(h)
+	require.Equal(t, uint(3), h.maxHedges)
+}
+
+func TestWithHedgeIfFn(t *testing.T) {
+	t.Parallel()
+
+	h := &Hedge{}
+	WithHedgeIfFn(func(_ *http.Request) bool { return true })(h)
+	require.NotNil(t, h.hedgeIfFn)
+}
+
+func TestWithHedgeRetryIfFn(t *testing.T) {
+	t.Parallel()
+
+	h := &Hedge{}
+	WithHedgeRetryIfFn(httpretrier.RetryIfForReadRequests)(h)
+	require.NotNil(t, h.retryIfFn)
+}
+
+func TestWithHedgeMetrics(t *testing.T) {
+	t.Parallel()
+
+	m := &metrics.Default{}
+	h := &Hedge{}
+	WithHedgeMetrics(m)(h)
+	require.Equal(t, m, h.metrics)
+}
@@ -0,0 +1,48 @@
+package httpretrier
+
+import (
+	"sync"
+)
+
+// RetryBudget is a token bucket, shared across the HTTPRetrier instances of a client,
+// that limits the retries to a percentage of the requests.
+// Each request adds a fraction of token (percent/100) up to the maximum number of tokens,
+// and each retry takes one token. When the bucket is empty the retries are not performed.
+type RetryBudget struct {
+	mux       sync.Mutex
+	ratio     float64
+	tokens    float64
+	maxTokens float64
+}
+
+// NewRetryBudget creates a new retry budget allowing retries up to the specified percentage of the requests,
+// with a bucket of maxTokens tokens (initially full) to allow bursts of retries.
+func NewRetryBudget(percent float64, maxTokens uint) *RetryBudget {
+	return &RetryBudget{
+		ratio:     percent / 100,
+		tokens:    float64(maxTokens),
+		maxTokens: float64(maxTokens),
+	}
+}
+
+// deposit adds the token fraction of a request.
+func (b *RetryBudget) deposit() {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	b.tokens = min(b.tokens+b.ratio, b.maxTokens)
+}
+
+// withdraw takes one token for a retry and returns false if the budget is exhausted.
+func (b *RetryBudget) withdraw() bool {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	if b.tokens < 1 {
+		return false
+	}
+
+	b.tokens--
+
+	return true
+}
@@ -0,0 +1,34 @@
+package httpretrier
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryBudget(t *testing.T) {
+	t.Parallel()
+
+	b := NewRetryBudget(50, 2)
+
+	// the bucket is initially full
+	require.True(t, b.withdraw())
+	require.True(t, b.withdraw())
+	require.False(t, b.withdraw())
+
+	// each request adds half token
+	b.deposit()
+	require.False(t, b.withdraw())
+
+	b.deposit()
+	require.True(t, b.withdraw())
+
+	// the tokens are capped
+	for range 10 {
+		b.deposit()
+	}
+
+	require.True(t, b.withdraw())
+	require.True(t, b.withdraw())
+	require.False(t, b.withdraw())
+}
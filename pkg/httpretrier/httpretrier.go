@@ -16,18 +16,40 @@ Additionally, it allows you to set the maximum number of retries, the delay
 after the first failed attempt, the time multiplication factor to determine the
 successive delay value, and the jitter used to introduce randomness and avoid
 request collisions.
+
+The Retry-After header of the 429 and 503 responses (in seconds or HTTP-date
+format) is honoured up to a maximum delay (see WithMaxRetryAfter).
+
+A RetryBudget can be shared across the retriers of a client to keep the retries
+under a percentage of the traffic (see WithRetryBudget).
+
+The request body is replayed on each attempt using the request GetBody
+function. If GetBody is not set, the body is read in memory before the first
+attempt.
+
+Each attempt is logged at debug level with the request context logger and
+counted with the metrics IncEventCounter (see WithMetrics), using the attempt
+number as operation and the response status code (or "error") as outcome.
+
+The delays can also be computed by a retrier.Backoff strategy (WithBackoff),
+capped (WithMaxDelay) and limited by the total elapsed time
+(WithMaxElapsedTime).
 */
 package httpretrier
 
 import (
-	"context"
+	"bytes"
 	"fmt"
 	"io"
 	"math/rand"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/Vonage/gosrvlib/pkg/logging"
+	"github.com/Vonage/gosrvlib/pkg/metrics"
+	"github.com/Vonage/gosrvlib/pkg/retrier"
+	"go.uber.org/zap"
 )
 
 const (
@@ -42,8 +64,14 @@ const (
 
 	// DefaultJitter is the maximum random Jitter time between retries.
 	DefaultJitter = 100 * time.Millisecond
+
+	// DefaultMaxRetryAfter is the default maximum delay requested by the Retry-After response header.
+	DefaultMaxRetryAfter = 1 * time.Minute
 )
 
+// metricsTask is the task name used for the metrics events.
+const metricsTask = "httpretrier"
+
 // RetryIfFn is the signature of the function used to decide when retry.
 type RetryIfFn func(r *http.Response, err error) bool
 
@@ -58,25 +86,31 @@ type HTTPRetrier struct {
 	delayFactor       float64
 	delay             time.Duration
 	jitter            time.Duration
+	maxRetryAfter     time.Duration
 	attempts          uint
 	remainingAttempts uint
 	retryIfFn         RetryIfFn
 	httpClient        HTTPClient
-	timer             *time.Timer
-	resetTimer        chan time.Duration
-	cancel            context.CancelFunc
+	budget            *RetryBudget
+	metrics           metrics.Client
+	maxDelay          time.Duration
+	maxElapsedTime    time.Duration
+	backoff           retrier.Backoff
+	clock             retrier.Clock
 	doResponse        *http.Response
 	doError           error
 }
 
 func defaultHTTPRetrier() *HTTPRetrier {
 	return &HTTPRetrier{
-		attempts:    DefaultAttempts,
-		delay:       DefaultDelay,
-		delayFactor: DefaultDelayFactor,
-		jitter:      DefaultJitter,
-		retryIfFn:   defaultRetryIf,
-		resetTimer:  make(chan time.Duration, 1),
+		attempts:      DefaultAttempts,
+		delay:         DefaultDelay,
+		delayFactor:   DefaultDelayFactor,
+		jitter:        DefaultJitter,
+		maxRetryAfter: DefaultMaxRetryAfter,
+		retryIfFn:     defaultRetryIf,
+		metrics:       &metrics.Default{},
+		clock:         retrier.SystemClock{},
 	}
 }
 
@@ -100,15 +134,39 @@ func New(httpClient HTTPClient, opts ...Option) (*HTTPRetrier, error) {
 func (c *HTTPRetrier) Do(r *http.Request) (*http.Response, error) {
 	c.nextDelay = float64(c.delay)
 	c.remainingAttempts = c.attempts
-	ctx, cancel := context.WithCancel(context.Background())
-	c.cancel = cancel
 
-	go c.retry(r)
+	if c.budget != nil {
+		c.budget.deposit()
+	}
+
+	err := setGetBody(r)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := r.Context()
+	start := c.clock.Now()
+
+	var delay time.Duration
+
+	for attempt := uint(1); ; attempt++ {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("request context has been canceled: %w", ctx.Err())
+		}
+
+		var retry bool
 
-	// wait for completion
-	<-ctx.Done()
+		retry, delay = c.run(r, attempt, delay, start)
+		if !retry {
+			return c.doResponse, c.doError
+		}
 
-	return c.doResponse, c.doError
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("request context has been canceled: %w", ctx.Err())
+		case <-c.clock.After(delay):
+		}
+	}
 }
 
 // defaultRetryIf is the default function to check the retry condition.
@@ -169,70 +227,112 @@ func RetryIfFnByHTTPMethod(httpMethod string) RetryIfFn {
 	return RetryIfForWriteRequests
 }
 
-func (c *HTTPRetrier) setTimer(d time.Duration) {
-	if !c.timer.Stop() {
-		// make sure to drain timer channel before reset
-		select {
-		case <-c.timer.C:
-		default:
+// run performs a single attempt and returns true with the delay if the request should be retried.
+func (c *HTTPRetrier) run(r *http.Request, attempt uint, prev time.Duration, start time.Time) (bool, time.Duration) {
+	req := r.Clone(r.Context())
+
+	if r.GetBody != nil {
+		body, err := r.GetBody()
+		if err != nil {
+			c.doError = fmt.Errorf("error while reading request body: %w", err)
+			return false, 0
 		}
+
+		req.Body = body
 	}
 
-	c.timer.Reset(d)
-}
+	c.doResponse, c.doError = c.httpClient.Do(req) //nolint:bodyclose
+
+	c.remainingAttempts--
 
-func (c *HTTPRetrier) retry(r *http.Request) {
-	defer c.cancel()
+	retry := c.remainingAttempts > 0 && c.retryIfFn(c.doResponse, c.doError)
 
-	c.timer = time.NewTimer(1 * time.Nanosecond)
+	var delay time.Duration
 
-	for {
-		select {
-		case <-r.Context().Done():
-			c.doError = fmt.Errorf("request context has been canceled: %w", r.Context().Err())
-			return
-		case d := <-c.resetTimer:
-			c.setTimer(d)
-		case <-c.timer.C:
-			if c.run(r) {
-				return
-			}
-		}
+	if retry {
+		delay = max(c.nextRetryDelay(attempt, prev), retryAfter(c.doResponse, c.maxRetryAfter))
+		retry = (c.maxElapsedTime <= 0 || c.clock.Now().Sub(start)+delay <= c.maxElapsedTime) && c.withdraw(r)
+	}
+
+	c.observe(r, attempt, retry, delay)
+
+	if retry && c.doError == nil {
+		// we only close the body between attempts
+		logging.Close(r.Context(), c.doResponse.Body, "error while closing response body")
 	}
+
+	return retry, delay
 }
 
-func (c *HTTPRetrier) run(r *http.Request) bool {
-	var (
-		bodyRC io.ReadCloser
-		err    error
-	)
+// nextRetryDelay returns the delay before the specified retry, capped to the maximum delay.
+func (c *HTTPRetrier) nextRetryDelay(retry uint, prev time.Duration) time.Duration {
+	var d time.Duration
 
-	if r.GetBody != nil {
-		bodyRC, err = r.GetBody()
-		if err != nil {
-			c.doError = fmt.Errorf("error while reading request body: %w", err)
-			return true
-		}
+	if c.backoff != nil {
+		d = c.backoff.Delay(retry, prev)
+	} else {
+		d = time.Duration(int64(c.nextDelay) + rand.Int63n(int64(c.jitter))) //nolint:gosec
+		c.nextDelay *= c.delayFactor
 	}
 
-	c.doResponse, c.doError = c.httpClient.Do(r) //nolint:bodyclose
+	if c.maxDelay > 0 {
+		d = min(d, c.maxDelay)
+	}
 
-	c.remainingAttempts--
-	if c.remainingAttempts == 0 || !c.retryIfFn(c.doResponse, c.doError) {
+	return d
+}
+
+// withdraw takes a token from the retry budget, if any, and returns false if the budget is exhausted.
+func (c *HTTPRetrier) withdraw(r *http.Request) bool {
+	if c.budget == nil || c.budget.withdraw() {
 		return true
 	}
 
+	logging.FromContext(r.Context()).Debug("http_retrier_budget_exhausted")
+	metrics.IncEventCounter(c.metrics, metricsTask, "budget", "exhausted")
+
+	return false
+}
+
+// observe logs and counts the attempt.
+func (c *HTTPRetrier) observe(r *http.Request, attempt uint, retry bool, delay time.Duration) {
+	outcome := "error"
 	if c.doError == nil {
-		// we only close the body between attempts
-		logging.Close(r.Context(), c.doResponse.Body, "error while closing response body")
+		outcome = strconv.Itoa(c.doResponse.StatusCode)
 	}
 
-	// set the original body for the next request
-	r.Body = bodyRC
+	metrics.IncEventCounter(c.metrics, metricsTask, strconv.FormatUint(uint64(attempt), 10), outcome)
+
+	fields := []zap.Field{
+		zap.Uint("attempt", attempt),
+		zap.String("outcome", outcome),
+		zap.Bool("retry", retry),
+	}
 
-	c.resetTimer <- time.Duration(int64(c.nextDelay) + rand.Int63n(int64(c.jitter))) //nolint:gosec
+	if retry {
+		fields = append(fields, zap.Duration("delay", delay))
+	}
 
-	c.nextDelay *= c.delayFactor
+	logging.FromContext(r.Context()).Debug("http_retrier_attempt", fields...)
+}
 
-	return false
+// setGetBody reads the request body in memory and sets the GetBody function,
+// if not already set, so the body can be replayed on each attempt.
+func setGetBody(r *http.Request) error {
+	if r.GetBody != nil || r.Body == nil || r.Body == http.NoBody {
+		return nil
+	}
+
+	body, err := io.ReadAll(r.Body)
+	_ = r.Body.Close()
+
+	if err != nil {
+		return fmt.Errorf("error while reading request body: %w", err)
+	}
+
+	r.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+
+	return nil
 }
@@ -8,8 +8,11 @@ import (
 	"io"
 	"net/http"
 	"testing"
+	"testing/iotest"
 	"time"
 
+	"github.com/Vonage/gosrvlib/pkg/metrics"
+	"github.com/Vonage/gosrvlib/pkg/retrier"
 	"github.com/Vonage/gosrvlib/pkg/testutil"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
@@ -467,15 +470,206 @@ func TestHTTPRetrier_Do(t *testing.T) {
 	}
 }
 
-func TestHTTPRetrier_setTimer(t *testing.T) {
+type testAttemptMetrics struct {
+	metrics.Default
+
+	events []string
+}
+
+func (m *testAttemptMetrics) IncEventCounter(_, operation, outcome string) {
+	m.events = append(m.events, operation+":"+outcome)
+}
+
+func TestHTTPRetrier_Do_retryAfterBudgetAndBody(t *testing.T) {
+	t.Parallel()
+
+	newResp := func(status int, retryAfter string) *http.Response {
+		return &http.Response{
+			StatusCode: status,
+			Header:     http.Header{"Retry-After": {retryAfter}},
+			Body:       io.NopCloser(bytes.NewReader([]byte{})),
+		}
+	}
+
+	tests := []struct {
+		name       string
+		budget     *RetryBudget
+		responses  []*http.Response
+		wantStatus int
+		wantEvents []string
+		wantMinDur time.Duration
+	}{
+		{
+			name:       "honours capped Retry-After",
+			responses:  []*http.Response{newResp(http.StatusTooManyRequests, "5"), newResp(http.StatusOK, "")},
+			wantStatus: http.StatusOK,
+			wantEvents: []string{"1:429", "2:200"},
+			wantMinDur: 200 * time.Millisecond,
+		},
+		{
+			name:       "retry budget exhausted",
+			budget:     NewRetryBudget(10, 0),
+			responses:  []*http.Response{newResp(http.StatusServiceUnavailable, "")},
+			wantStatus: http.StatusServiceUnavailable,
+			wantEvents: []string{"budget:exhausted", "1:503"},
+		},
+		{
+			name:       "retry budget available",
+			budget:     NewRetryBudget(10, 1),
+			responses:  []*http.Response{newResp(http.StatusServiceUnavailable, ""), newResp(http.StatusServiceUnavailable, "")},
+			wantStatus: http.StatusServiceUnavailable,
+			wantEvents: []string{"1:503", "budget:exhausted", "2:503"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockHTTP := NewMockHTTPClient(ctrl)
+
+			for _, resp := range tt.responses {
+				mockHTTP.EXPECT().Do(gomock.Any()).DoAndReturn(func(r *http.Request) (*http.Response, error) {
+					body, err := io.ReadAll(r.Body)
+					require.NoError(t, err)
+					require.Equal(t, "payload", string(body))
+
+					return resp, nil
+				})
+			}
+
+			m := &testAttemptMetrics{}
+
+			opts := []Option{
+				WithRetryIfFn(RetryIfForWriteRequests),
+				WithAttempts(3),
+				WithDelay(time.Millisecond),
+				WithJitter(time.Millisecond),
+				WithMaxRetryAfter(200 * time.Millisecond),
+				WithMetrics(m),
+			}
+
+			if tt.budget != nil {
+				opts = append(opts, WithRetryBudget(tt.budget))
+			}
+
+			retrier, err := New(mockHTTP, opts...)
+			require.NoError(t, err)
+
+			// the body is not replayable by itself
+			r, err := http.NewRequestWithContext(t.Context(), http.MethodPost, "/", io.NopCloser(bytes.NewReader([]byte("payload"))))
+			require.NoError(t, err)
+			require.Nil(t, r.GetBody)
+
+			start := time.Now()
+
+			resp, err := retrier.Do(r)
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+
+			require.Equal(t, tt.wantStatus, resp.StatusCode)
+			require.Equal(t, tt.wantEvents, m.events)
+			require.GreaterOrEqual(t, time.Since(start), tt.wantMinDur)
+		})
+	}
+}
+
+func TestHTTPRetrier_Do_bodyReadError(t *testing.T) {
 	t.Parallel()
 
-	c := &HTTPRetrier{
-		timer: time.NewTimer(1 * time.Millisecond),
+	retrier, err := New(NewMockHTTPClient(gomock.NewController(t)))
+	require.NoError(t, err)
+
+	r, err := http.NewRequestWithContext(t.Context(), http.MethodPost, "/", io.NopCloser(iotest.ErrReader(errors.New("read error"))))
+	require.NoError(t, err)
+
+	resp, err := retrier.Do(r) //nolint:bodyclose
+	require.Error(t, err)
+	require.Nil(t, resp)
+}
+
+// testClock is a deterministic retrier.Clock that advances the time instantly on After.
+type testClock struct {
+	now    time.Time
+	delays []time.Duration
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func (c *testClock) After(d time.Duration) <-chan time.Time {
+	c.now = c.now.Add(d)
+	c.delays = append(c.delays, d)
+
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+
+	return ch
+}
+
+func TestHTTPRetrier_Do_backoff(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		opts       []Option
+		retryAfter string
+		wantDelays []time.Duration
+	}{
+		{
+			name:       "fibonacci backoff",
+			opts:       []Option{WithBackoff(retrier.FibonacciBackoff(time.Second))},
+			wantDelays: []time.Duration{time.Second, time.Second, 2 * time.Second, 3 * time.Second},
+		},
+		{
+			name:       "max delay",
+			opts:       []Option{WithBackoff(retrier.ExponentialBackoff(time.Second, 2, retrier.NoJitter)), WithMaxDelay(3 * time.Second)},
+			wantDelays: []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second},
+		},
+		{
+			name:       "max elapsed time",
+			opts:       []Option{WithBackoff(retrier.LinearBackoff(time.Second, time.Second)), WithMaxElapsedTime(5 * time.Second)},
+			wantDelays: []time.Duration{time.Second, 2 * time.Second},
+		},
+		{
+			name:       "retry after precedence",
+			opts:       []Option{WithBackoff(retrier.ConstantBackoff(time.Second))},
+			retryAfter: "2",
+			wantDelays: []time.Duration{2 * time.Second, 2 * time.Second, 2 * time.Second, 2 * time.Second},
+		},
 	}
 
-	time.Sleep(2 * time.Millisecond)
-	c.setTimer(2 * time.Millisecond)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
 
-	<-c.timer.C
+			ctrl := gomock.NewController(t)
+			mockHTTP := NewMockHTTPClient(ctrl)
+
+			mockHTTP.EXPECT().Do(gomock.Any()).DoAndReturn(func(_ *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: http.StatusServiceUnavailable,
+					Header:     http.Header{"Retry-After": {tt.retryAfter}},
+					Body:       io.NopCloser(bytes.NewReader([]byte{})),
+				}, nil
+			}).MinTimes(1)
+
+			clock := &testClock{now: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}
+			opts := append([]Option{WithRetryIfFn(RetryIfForWriteRequests), WithAttempts(5), WithClock(clock)}, tt.opts...)
+
+			hr, err := New(mockHTTP, opts...)
+			require.NoError(t, err)
+
+			r, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "/", nil)
+			require.NoError(t, err)
+
+			resp, err := hr.Do(r)
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+			require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+			require.Equal(t, tt.wantDelays, clock.delays)
+		})
+	}
 }
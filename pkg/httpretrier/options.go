@@ -3,6 +3,9 @@ package httpretrier
 import (
 	"errors"
 	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/metrics"
+	"github.com/Vonage/gosrvlib/pkg/retrier"
 )
 
 // Option is the interface that allows to set the options.
@@ -75,3 +78,100 @@ func WithJitter(jitter time.Duration) Option {
 		return nil
 	}
 }
+
+// WithMaxRetryAfter sets the maximum delay honoured from the Retry-After header of the 429 and 503 responses.
+// Longer delays are capped to this value. A zero value disables the Retry-After handling.
+func WithMaxRetryAfter(maxDelay time.Duration) Option {
+	return func(r *HTTPRetrier) error {
+		if maxDelay < 0 {
+			return errors.New("the maximum Retry-After delay must not be negative")
+		}
+
+		r.maxRetryAfter = maxDelay
+
+		return nil
+	}
+}
+
+// WithRetryBudget sets the retry budget, which should be shared across the retriers of the same client
+// to keep the retries under a percentage of the traffic.
+func WithRetryBudget(budget *RetryBudget) Option {
+	return func(r *HTTPRetrier) error {
+		if budget == nil {
+			return errors.New("the retry budget is required")
+		}
+
+		r.budget = budget
+
+		return nil
+	}
+}
+
+// WithMetrics sets the metrics client used to count the attempts.
+func WithMetrics(m metrics.Client) Option {
+	return func(r *HTTPRetrier) error {
+		if m == nil {
+			return errors.New("the metrics client is required")
+		}
+
+		r.metrics = m
+
+		return nil
+	}
+}
+
+// WithBackoff sets the strategy used to compute the delay before each retry.
+// It overrides the WithDelay, WithDelayFactor and WithJitter options.
+// The Retry-After response header still takes precedence when longer.
+func WithBackoff(backoff retrier.Backoff) Option {
+	return func(r *HTTPRetrier) error {
+		if backoff == nil {
+			return errors.New("the backoff is required")
+		}
+
+		r.backoff = backoff
+
+		return nil
+	}
+}
+
+// WithMaxDelay sets the maximum delay between retries computed by the backoff strategy.
+func WithMaxDelay(maxDelay time.Duration) Option {
+	return func(r *HTTPRetrier) error {
+		if int64(maxDelay) < 1 {
+			return errors.New("the maximum delay must be greater than zero")
+		}
+
+		r.maxDelay = maxDelay
+
+		return nil
+	}
+}
+
+// WithMaxElapsedTime sets the maximum total time spent retrying.
+// No more retries are attempted if the next delay would exceed this time since the first attempt.
+func WithMaxElapsedTime(maxElapsedTime time.Duration) Option {
+	return func(r *HTTPRetrier) error {
+		if int64(maxElapsedTime) < 1 {
+			return errors.New("the maximum elapsed time must be greater than zero")
+		}
+
+		r.maxElapsedTime = maxElapsedTime
+
+		return nil
+	}
+}
+
+// WithClock sets the clock used to measure the elapsed time and wait between the attempts.
+// This is mainly useful to inject a deterministic clock in tests.
+func WithClock(clock retrier.Clock) Option {
+	return func(r *HTTPRetrier) error {
+		if clock == nil {
+			return errors.New("the clock is required")
+		}
+
+		r.clock = clock
+
+		return nil
+	}
+}
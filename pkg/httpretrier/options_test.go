@@ -7,6 +7,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Vonage/gosrvlib/pkg/metrics"
+	"github.com/Vonage/gosrvlib/pkg/retrier"
 	"github.com/stretchr/testify/require"
 )
 
@@ -98,3 +100,102 @@ func TestWithJitter(t *testing.T) {
 	err = WithJitter(v)(c)
 	require.Error(t, err)
 }
+
+func TestWithMaxRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	c := defaultHTTPRetrier()
+
+	err := WithMaxRetryAfter(7 * time.Second)(c)
+	require.NoError(t, err)
+	require.Equal(t, 7*time.Second, c.maxRetryAfter)
+
+	err = WithMaxRetryAfter(0)(c)
+	require.NoError(t, err)
+	require.Equal(t, time.Duration(0), c.maxRetryAfter)
+
+	err = WithMaxRetryAfter(-1)(c)
+	require.Error(t, err)
+}
+
+func TestWithRetryBudget(t *testing.T) {
+	t.Parallel()
+
+	c := defaultHTTPRetrier()
+	b := NewRetryBudget(10, 5)
+
+	err := WithRetryBudget(b)(c)
+	require.NoError(t, err)
+	require.Equal(t, b, c.budget)
+
+	err = WithRetryBudget(nil)(c)
+	require.Error(t, err)
+}
+
+func TestWithMetrics(t *testing.T) {
+	t.Parallel()
+
+	c := defaultHTTPRetrier()
+	m := &metrics.Default{}
+
+	err := WithMetrics(m)(c)
+	require.NoError(t, err)
+	require.Equal(t, m, c.metrics)
+
+	err = WithMetrics(nil)(c)
+	require.Error(t, err)
+}
+
+func TestWithBackoff(t *testing.T) {
+	t.Parallel()
+
+	c := defaultHTTPRetrier()
+	v := retrier.ConstantBackoff(time.Second)
+
+	err := WithBackoff(v)(c)
+	require.NoError(t, err)
+	require.Equal(t, v, c.backoff)
+
+	err = WithBackoff(nil)(c)
+	require.Error(t, err)
+}
+
+func TestWithMaxDelay(t *testing.T) {
+	t.Parallel()
+
+	c := defaultHTTPRetrier()
+
+	err := WithMaxDelay(3 * time.Second)(c)
+	require.NoError(t, err)
+	require.Equal(t, 3*time.Second, c.maxDelay)
+
+	err = WithMaxDelay(0)(c)
+	require.Error(t, err)
+}
+
+func TestWithMaxElapsedTime(t *testing.T) {
+	t.Parallel()
+
+	c := defaultHTTPRetrier()
+
+	err := WithMaxElapsedTime(time.Minute)(c)
+	require.NoError(t, err)
+	require.Equal(t, time.Minute, c.maxElapsedTime)
+
+	err = WithMaxElapsedTime(0)(c)
+	require.Error(t, err)
+}
+
+func TestWithClock(t *testing.T) {
+	t.Parallel()
+
+	c := defaultHTTPRetrier()
+	v := &testClock{}
+
+	err := WithClock(v)(c)
+	require.NoError(t, err)
+	require.Equal(t, v, c.clock)
+
+	err = WithClock(nil)(c)
+	require.Error(t, err)
+}
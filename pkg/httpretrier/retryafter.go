@@ -0,0 +1,31 @@
+package httpretrier
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// retryAfter returns the delay requested by the Retry-After header of 429 and 503 responses,
+// in seconds or HTTP-date format, capped to maxDelay.
+func retryAfter(resp *http.Response, maxDelay time.Duration) time.Duration {
+	if resp == nil || maxDelay <= 0 ||
+		(resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable) {
+		return 0
+	}
+
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+
+	var d time.Duration
+
+	if sec, err := strconv.ParseInt(v, 10, 64); err == nil {
+		d = time.Duration(min(sec, int64(maxDelay/time.Second)+1)) * time.Second
+	} else if t, err := http.ParseTime(v); err == nil {
+		d = time.Until(t)
+	}
+
+	return min(max(0, d), maxDelay)
+}
@@ -0,0 +1,91 @@
+package httpretrier
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_retryAfter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		resp     *http.Response
+		maxDelay time.Duration
+		want     time.Duration
+		wantMin  time.Duration
+	}{
+		{
+			name:     "nil response",
+			maxDelay: time.Minute,
+		},
+		{
+			name:     "disabled",
+			resp:     &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"3"}}},
+			maxDelay: 0,
+		},
+		{
+			name:     "other status code",
+			resp:     &http.Response{StatusCode: http.StatusInternalServerError, Header: http.Header{"Retry-After": {"3"}}},
+			maxDelay: time.Minute,
+		},
+		{
+			name:     "missing header",
+			resp:     &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}},
+			maxDelay: time.Minute,
+		},
+		{
+			name:     "seconds",
+			resp:     &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"3"}}},
+			maxDelay: time.Minute,
+			want:     3 * time.Second,
+		},
+		{
+			name:     "capped seconds",
+			resp:     &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{"Retry-After": {"99999999999999999"}}},
+			maxDelay: time.Minute,
+			want:     time.Minute,
+		},
+		{
+			name:     "negative seconds",
+			resp:     &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{"Retry-After": {"-5"}}},
+			maxDelay: time.Minute,
+		},
+		{
+			name:     "past date",
+			resp:     &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{"Retry-After": {"Mon, 02 Jan 2006 15:04:05 GMT"}}},
+			maxDelay: time.Minute,
+		},
+		{
+			name:     "future date",
+			resp:     &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{"Retry-After": {time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)}}},
+			maxDelay: 2 * time.Hour,
+			wantMin:  58 * time.Minute,
+		},
+		{
+			name:     "invalid value",
+			resp:     &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{"Retry-After": {"soon"}}},
+			maxDelay: time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := retryAfter(tt.resp, tt.maxDelay)
+
+			if tt.wantMin > 0 {
+				require.GreaterOrEqual(t, got, tt.wantMin)
+				require.LessOrEqual(t, got, tt.maxDelay)
+
+				return
+			}
+
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
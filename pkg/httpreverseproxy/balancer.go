@@ -0,0 +1,214 @@
+package httpreverseproxy
+
+import (
+	"hash/fnv"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// LoadBalancing is the algorithm used to select the upstream for each request.
+type LoadBalancing int
+
+const (
+	// RoundRobin selects the available upstreams in turn.
+	RoundRobin LoadBalancing = iota
+
+	// LeastConnections selects the available upstream with the least in-flight requests.
+	LeastConnections
+
+	// ConsistentHash selects the upstream using a consistent hash ring on the request key (see HashKeyFunc),
+	// so requests with the same key are sent to the same upstream while it is available.
+	ConsistentHash
+)
+
+// ringReplicas is the number of virtual nodes of each upstream in the consistent hash ring.
+const ringReplicas = 100
+
+// HashKeyFunc returns the key used by the ConsistentHash load balancing.
+type HashKeyFunc func(r *http.Request) string
+
+// DefaultHashKeyFunc returns the client IP address as consistent hash key.
+func DefaultHashKeyFunc(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// ringNode is a virtual node of the consistent hash ring.
+type ringNode struct {
+	hash     uint64
+	upstream *upstream
+}
+
+// balancer selects the upstream for each request.
+type balancer struct {
+	upstreams     []*upstream
+	lb            LoadBalancing
+	hashKeyFn     HashKeyFunc
+	ring          []ringNode
+	counter       atomic.Uint64
+	maxFailures   int64
+	ejectDuration time.Duration
+}
+
+// newBalancer returns a new balancer for the specified upstreams.
+func newBalancer(upstreams []*upstream, lb LoadBalancing, hashKeyFn HashKeyFunc, maxFailures int64, ejectDuration time.Duration) *balancer {
+	b := &balancer{
+		upstreams:     upstreams,
+		lb:            lb,
+		hashKeyFn:     hashKeyFn,
+		maxFailures:   maxFailures,
+		ejectDuration: ejectDuration,
+	}
+
+	if lb == ConsistentHash {
+		b.ring = newRing(upstreams)
+	}
+
+	return b
+}
+
+// newRing returns the sorted consistent hash ring of the upstreams.
+func newRing(upstreams []*upstream) []ringNode {
+	ring := make([]ringNode, 0, len(upstreams)*ringReplicas)
+
+	for _, u := range upstreams {
+		for i := range ringReplicas {
+			ring = append(ring, ringNode{hash: hashKey(u.name + "#" + strconv.Itoa(i)), upstream: u})
+		}
+	}
+
+	slices.SortFunc(ring, func(a, b ringNode) int {
+		switch {
+		case a.hash < b.hash:
+			return -1
+		case a.hash > b.hash:
+			return 1
+		}
+
+		return 0
+	})
+
+	return ring
+}
+
+// hashKey returns the 64-bit FNV-1a hash of the key,
+// with the MurmurHash3 finalizer to spread similar keys across the ring.
+func hashKey(key string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+
+	x := h.Sum64()
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+
+	return x
+}
+
+// pick selects an upstream excluding the ones already tried.
+// If no upstream is available, the unavailable ones are also considered.
+func (b *balancer) pick(r *http.Request, exclude []*upstream) *upstream {
+	now := time.Now().UnixNano()
+	candidates := make([]*upstream, 0, len(b.upstreams))
+
+	for _, u := range b.upstreams {
+		if u.available(now) && !slices.Contains(exclude, u) {
+			candidates = append(candidates, u)
+		}
+	}
+
+	if len(candidates) == 0 {
+		for _, u := range b.upstreams {
+			if !slices.Contains(exclude, u) {
+				candidates = append(candidates, u)
+			}
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	switch b.lb {
+	case LeastConnections:
+		return b.pickLeastConnections(candidates)
+	case ConsistentHash:
+		return b.pickConsistentHash(r, candidates)
+	default:
+		return b.pickRoundRobin(candidates)
+	}
+}
+
+// pickRoundRobin selects the next candidate in turn.
+func (b *balancer) pickRoundRobin(candidates []*upstream) *upstream {
+	return candidates[(b.counter.Add(1)-1)%uint64(len(candidates))]
+}
+
+// pickLeastConnections selects the candidate with the least in-flight requests,
+// starting from a rotating offset to spread the ties.
+func (b *balancer) pickLeastConnections(candidates []*upstream) *upstream {
+	n := len(candidates)
+	offset := int((b.counter.Add(1) - 1) % uint64(n))
+	selected := candidates[offset]
+
+	for i := 1; i < n; i++ {
+		u := candidates[(offset+i)%n]
+		if u.inFlight.Load() < selected.inFlight.Load() {
+			selected = u
+		}
+	}
+
+	return selected
+}
+
+// pickConsistentHash selects the first candidate on the ring after the request key hash.
+func (b *balancer) pickConsistentHash(r *http.Request, candidates []*upstream) *upstream {
+	h := hashKey(b.hashKeyFn(r))
+
+	idx, _ := slices.BinarySearchFunc(b.ring, h, func(n ringNode, h uint64) int {
+		switch {
+		case n.hash < h:
+			return -1
+		case n.hash > h:
+			return 1
+		}
+
+		return 0
+	})
+
+	for i := range b.ring {
+		u := b.ring[(idx+i)%len(b.ring)].upstream
+		if slices.Contains(candidates, u) {
+			return u
+		}
+	}
+
+	return candidates[0]
+}
+
+// success records a successful request.
+func (b *balancer) success(u *upstream) {
+	u.failures.Store(0)
+}
+
+// failure records a failed request and returns true if the upstream has been ejected.
+func (b *balancer) failure(u *upstream) bool {
+	if b.maxFailures <= 0 || u.failures.Add(1) < b.maxFailures {
+		return false
+	}
+
+	u.failures.Store(0)
+	u.ejectedUntil.Store(time.Now().Add(b.ejectDuration).UnixNano())
+
+	return true
+}
@@ -0,0 +1,121 @@
+package httpreverseproxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testUpstreams(t *testing.T, n int) []*upstream {
+	t.Helper()
+
+	ups := make([]*upstream, 0, n)
+
+	for i := range n {
+		u, err := newUpstream("http://upstream" + strconv.Itoa(i) + ".invalid:8080/")
+		require.NoError(t, err)
+
+		ups = append(ups, u)
+	}
+
+	return ups
+}
+
+func TestDefaultHashKeyFunc(t *testing.T) {
+	t.Parallel()
+
+	r := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/", nil)
+
+	r.RemoteAddr = "10.0.0.1:1234"
+	require.Equal(t, "10.0.0.1", DefaultHashKeyFunc(r))
+
+	r.RemoteAddr = "invalid"
+	require.Equal(t, "invalid", DefaultHashKeyFunc(r))
+}
+
+func TestBalancer_roundRobin(t *testing.T) {
+	t.Parallel()
+
+	ups := testUpstreams(t, 3)
+	b := newBalancer(ups, RoundRobin, DefaultHashKeyFunc, 0, 0)
+	r := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/", nil)
+
+	for i := range 6 {
+		require.Equal(t, ups[i%3], b.pick(r, nil))
+	}
+
+	require.Equal(t, ups[2], b.pick(r, ups[:2]))
+	require.Nil(t, b.pick(r, ups))
+}
+
+func TestBalancer_leastConnections(t *testing.T) {
+	t.Parallel()
+
+	ups := testUpstreams(t, 3)
+	b := newBalancer(ups, LeastConnections, DefaultHashKeyFunc, 0, 0)
+	r := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/", nil)
+
+	ups[0].inFlight.Store(2)
+	ups[1].inFlight.Store(1)
+	ups[2].inFlight.Store(3)
+
+	for range 3 {
+		require.Equal(t, ups[1], b.pick(r, nil))
+	}
+
+	require.Equal(t, ups[0], b.pick(r, ups[1:2]))
+}
+
+func TestBalancer_consistentHash(t *testing.T) {
+	t.Parallel()
+
+	ups := testUpstreams(t, 4)
+	b := newBalancer(ups, ConsistentHash, func(r *http.Request) string { return r.Header.Get("X-User") }, 0, 0)
+	require.Len(t, b.ring, 4*ringReplicas)
+
+	selected := make(map[*upstream]int)
+
+	for i := range 100 {
+		r := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/", nil)
+		r.Header.Set("X-User", "user"+strconv.Itoa(i))
+
+		u := b.pick(r, nil)
+		require.Equal(t, u, b.pick(r, nil))
+
+		selected[u]++
+
+		// the key is moved to another upstream when the selected one is excluded
+		other := b.pick(r, []*upstream{u})
+		require.NotEqual(t, u, other)
+	}
+
+	require.Len(t, selected, 4)
+}
+
+func TestBalancer_ejection(t *testing.T) {
+	t.Parallel()
+
+	ups := testUpstreams(t, 2)
+	b := newBalancer(ups, RoundRobin, DefaultHashKeyFunc, 2, time.Minute)
+	r := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/", nil)
+
+	require.False(t, b.failure(ups[0]))
+	b.success(ups[0])
+	require.False(t, b.failure(ups[0]))
+	require.True(t, b.failure(ups[0]))
+
+	for range 4 {
+		require.Equal(t, ups[1], b.pick(r, nil))
+	}
+
+	// all upstreams unavailable: fall back to all of them
+	ups[1].unhealthy.Store(true)
+	require.NotNil(t, b.pick(r, nil))
+
+	b = newBalancer(ups, RoundRobin, DefaultHashKeyFunc, 0, time.Minute)
+	require.False(t, b.failure(ups[0]))
+}
@@ -0,0 +1,61 @@
+package httpreverseproxy
+
+import (
+	"net/http"
+)
+
+// canaryBuckets is the number of buckets used to split the traffic by percentage.
+const canaryBuckets = 10000
+
+// CanaryRule defines which requests are routed to the canary upstream.
+// A request is routed to the canary if it matches the header or the cookie,
+// or if it falls in the percentage of traffic.
+type CanaryRule struct {
+	// Percent is the percentage of requests (0 to 100) routed to the canary.
+	// The split is sticky: it is based on the hash of the HashKeyFunc key (default the client IP),
+	// so the requests with the same key are always routed to the same side.
+	Percent float64 `mapstructure:"percent" validate:"min=0,max=100"`
+
+	// Header is the name of the request header that routes the request to the canary.
+	Header string `mapstructure:"header"`
+
+	// HeaderValue is the value of the Header that routes the request to the canary.
+	// If empty, any non-empty value matches.
+	HeaderValue string `mapstructure:"header_value"`
+
+	// Cookie is the name of the request cookie that routes the request to the canary.
+	Cookie string `mapstructure:"cookie"`
+
+	// CookieValue is the value of the Cookie that routes the request to the canary.
+	// If empty, any non-empty value matches.
+	CookieValue string `mapstructure:"cookie_value"`
+}
+
+// match returns true if the request should be routed to the canary.
+func (cr *CanaryRule) match(r *http.Request, hashKeyFn HashKeyFunc) bool {
+	if cr.Header != "" && matchValue(r.Header.Get(cr.Header), cr.HeaderValue) {
+		return true
+	}
+
+	if cr.Cookie != "" {
+		if c, err := r.Cookie(cr.Cookie); err == nil && matchValue(c.Value, cr.CookieValue) {
+			return true
+		}
+	}
+
+	if cr.Percent <= 0 {
+		return false
+	}
+
+	return hashKey("canary#"+hashKeyFn(r))%canaryBuckets < uint64(cr.Percent*canaryBuckets/100)
+}
+
+// matchValue returns true if the value is equal to the expected one,
+// or if the value is not empty and no specific value is expected.
+func matchValue(value, expected string) bool {
+	if expected == "" {
+		return value != ""
+	}
+
+	return value == expected
+}
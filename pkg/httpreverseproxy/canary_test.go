@@ -0,0 +1,104 @@
+package httpreverseproxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanaryRule_match(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		rule   CanaryRule
+		header http.Header
+		want   bool
+	}{
+		{
+			name: "empty rule",
+			want: false,
+		},
+		{
+			name:   "header with any value",
+			rule:   CanaryRule{Header: "X-Canary"},
+			header: http.Header{"X-Canary": {"yes"}},
+			want:   true,
+		},
+		{
+			name:   "header with specific value",
+			rule:   CanaryRule{Header: "X-Canary", HeaderValue: "v2"},
+			header: http.Header{"X-Canary": {"v2"}},
+			want:   true,
+		},
+		{
+			name:   "header with different value",
+			rule:   CanaryRule{Header: "X-Canary", HeaderValue: "v2"},
+			header: http.Header{"X-Canary": {"v1"}},
+			want:   false,
+		},
+		{
+			name:   "cookie with any value",
+			rule:   CanaryRule{Cookie: "canary"},
+			header: http.Header{"Cookie": {"session=1; canary=1"}},
+			want:   true,
+		},
+		{
+			name:   "cookie with specific value",
+			rule:   CanaryRule{Cookie: "canary", CookieValue: "always"},
+			header: http.Header{"Cookie": {"canary=always"}},
+			want:   true,
+		},
+		{
+			name:   "missing cookie",
+			rule:   CanaryRule{Cookie: "canary", CookieValue: "always"},
+			header: http.Header{"Cookie": {"session=1"}},
+			want:   false,
+		},
+		{
+			name: "all traffic",
+			rule: CanaryRule{Percent: 100},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != nil {
+				r.Header = tt.header
+			}
+
+			require.Equal(t, tt.want, tt.rule.match(r, DefaultHashKeyFunc))
+		})
+	}
+}
+
+func TestCanaryRule_match_percent(t *testing.T) {
+	t.Parallel()
+
+	rule := CanaryRule{Percent: 20}
+	keyFn := func(r *http.Request) string { return r.Header.Get("X-User") }
+
+	n := 0
+
+	for i := range 10000 {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("X-User", strconv.Itoa(i))
+
+		match := rule.match(r, keyFn)
+		if match {
+			n++
+		}
+
+		// the split is sticky
+		require.Equal(t, match, rule.match(r, keyFn))
+	}
+
+	require.InDelta(t, 2000, n, 200)
+}
@@ -1,15 +1,16 @@
 package httpreverseproxy
 
 import (
-	"fmt"
+	"errors"
 	"net/http"
 	"net/http/httputil"
-	"net/url"
-	"strings"
+	"sync"
 	"time"
 
 	libhttputil "github.com/Vonage/gosrvlib/pkg/httputil"
 	"github.com/Vonage/gosrvlib/pkg/logging"
+	"github.com/Vonage/gosrvlib/pkg/metrics"
+	"github.com/Vonage/gosrvlib/pkg/periodic"
 	"github.com/Vonage/gosrvlib/pkg/traceid"
 	"go.uber.org/zap"
 )
@@ -21,59 +22,94 @@ type HTTPClient interface {
 
 // Client implements the Reverse Proxy.
 type Client struct {
-	proxy      *httputil.ReverseProxy
-	httpClient HTTPClient
-	logger     *zap.Logger
+	proxy          *httputil.ReverseProxy
+	httpClient     HTTPClient
+	logger         *zap.Logger
+	metrics        metrics.Client
+	upstreamAddrs  []string
+	lb             LoadBalancing
+	hashKeyFn      HashKeyFunc
+	maxFailures    int64
+	ejectDuration  time.Duration
+	retries        uint
+	healthPath     string
+	healthInterval time.Duration
+	healthTimeout  time.Duration
+	balancer       *balancer
+	mux            sync.Mutex
+	periodic       *periodic.Periodic
+
+	rewrite          *Rewrite
+	respTransformFns []ResponseBodyTransformFn
+	transformFilter  TransformFilter
+
+	canaryAddr    string
+	canaryRule    CanaryRule
+	canary        *upstream
+	shadowAddr    string
+	shadowPercent float64
+	shadowTimeout time.Duration
+	shadowMethods []string
+	shadowMaxConc int
+	shadow        *shadow
 }
 
 type errHandler = func(w http.ResponseWriter, r *http.Request, err error)
 
+// errCustomProxy is returned when the options conflict with the Director or Transport set with WithReverseProxy.
+var errCustomProxy = errors.New("the load balancing, health check, retries, canary and shadow options can't be used with a custom ReverseProxy Director or Transport")
+
 // New returns a new instance of the Client.
-//
-//nolint:gocognit
+// The addr argument is the address of the proxied service,
+// additional upstreams for load balancing can be added with WithUpstreams.
 func New(addr string, opts ...Option) (*Client, error) {
-	c := &Client{}
+	c := &Client{
+		metrics:       &metrics.Default{},
+		lb:            RoundRobin,
+		hashKeyFn:     DefaultHashKeyFunc,
+		shadowMethods: defaultShadowMethods(),
+		shadowMaxConc: defaultShadowMaxConcurrency,
+	}
 
 	for _, applyOpt := range opts {
-		applyOpt(c)
+		if err := applyOpt(c); err != nil {
+			return nil, err
+		}
 	}
 
 	if c.proxy == nil {
 		c.proxy = &httputil.ReverseProxy{}
 	}
 
+	if c.httpClient == nil {
+		c.httpClient = &http.Client{}
+	}
+
+	if c.logger == nil {
+		c.logger, _ = logging.NewLogger(
+			logging.WithFormatStr("json"),
+			logging.WithLevelStr("error"),
+		)
+	}
+
 	//nolint:staticcheck
-	if c.proxy.Director == nil {
-		addr = strings.TrimRight(addr, "/")
+	if (c.proxy.Director != nil || c.proxy.Transport != nil) && c.balancingEnabled() {
+		return nil, errCustomProxy
+	}
 
-		proxyURL, err := url.Parse(addr)
+	//nolint:staticcheck
+	if c.proxy.Director == nil {
+		err := c.setBalancedDirector(addr)
 		if err != nil {
-			return nil, fmt.Errorf("invalid service address: %s", addr)
-		}
-
-		c.proxy.Director = func(r *http.Request) {
-			r.URL.Scheme = proxyURL.Scheme
-			r.URL.Host = proxyURL.Host
-			r.URL.Path = "/" + libhttputil.PathParam(r, "path")
-			r.Host = proxyURL.Host
-			r.Header.Set("X-Forwarded-Host", r.Header.Get("Host"))
+			return nil, err
 		}
 	}
 
 	if c.proxy.Transport == nil {
-		if c.httpClient == nil {
-			c.httpClient = &http.Client{}
-		}
-
 		c.proxy.Transport = &httpWrapper{client: c.httpClient}
 	}
 
-	if c.logger == nil {
-		c.logger, _ = logging.NewLogger(
-			logging.WithFormatStr("json"),
-			logging.WithLevelStr("error"),
-		)
-	}
+	c.setTransformations()
 
 	// Override the default logger to write to the zap one.
 	el, err := zap.NewStdLogAt(c.logger, zap.ErrorLevel)
@@ -88,6 +124,135 @@ func New(addr string, opts ...Option) (*Client, error) {
 	return c, nil
 }
 
+// balancingEnabled returns true if any option requiring the load balancing Director and Transport is set.
+func (c *Client) balancingEnabled() bool {
+	return len(c.upstreamAddrs) > 0 ||
+		c.lb != RoundRobin ||
+		c.maxFailures > 0 ||
+		c.retries > 0 ||
+		c.healthPath != "" ||
+		c.canaryAddr != "" ||
+		c.shadowAddr != ""
+}
+
+// setBalancedDirector sets the default Director and,
+// if the Transport is not specified, the load balancing Transport.
+func (c *Client) setBalancedDirector(addr string) error {
+	addrs := append([]string{addr}, c.upstreamAddrs...)
+	upstreams := make([]*upstream, 0, len(addrs))
+
+	for _, a := range addrs {
+		u, err := newUpstream(a)
+		if err != nil {
+			return err
+		}
+
+		upstreams = append(upstreams, u)
+	}
+
+	proxyURL := upstreams[0].url
+
+	//nolint:staticcheck
+	c.proxy.Director = func(r *http.Request) {
+		r.URL.Scheme = proxyURL.Scheme
+		r.URL.Host = proxyURL.Host
+		r.URL.Path = "/" + libhttputil.PathParam(r, "path")
+		r.Host = proxyURL.Host
+		r.Header.Set("X-Forwarded-Host", r.Header.Get("Host"))
+	}
+
+	if c.proxy.Transport != nil {
+		return nil
+	}
+
+	c.balancer = newBalancer(upstreams, c.lb, c.hashKeyFn, c.maxFailures, c.ejectDuration)
+
+	err := c.setTrafficSplit()
+	if err != nil {
+		return err
+	}
+
+	c.proxy.Transport = &balancedTransport{
+		client:     c.httpClient,
+		balancer:   c.balancer,
+		retries:    c.retries,
+		logger:     c.logger,
+		metrics:    c.metrics,
+		canary:     c.canary,
+		canaryRule: c.canaryRule,
+		shadow:     c.shadow,
+	}
+
+	return nil
+}
+
+// setTrafficSplit sets the canary and shadow upstreams, if configured.
+func (c *Client) setTrafficSplit() error {
+	if c.canaryAddr != "" {
+		u, err := newUpstream(c.canaryAddr)
+		if err != nil {
+			return err
+		}
+
+		c.canary = u
+	}
+
+	if c.shadowAddr != "" {
+		u, err := newUpstream(c.shadowAddr)
+		if err != nil {
+			return err
+		}
+
+		if c.shadowTimeout <= 0 {
+			c.shadowTimeout = defaultShadowTimeout
+		}
+
+		c.shadow = &shadow{
+			upstream: u,
+			percent:  c.shadowPercent,
+			methods:  c.shadowMethods,
+			timeout:  c.shadowTimeout,
+			client:   c.httpClient,
+			logger:   c.logger,
+			metrics:  c.metrics,
+			sem:      make(chan struct{}, c.shadowMaxConc),
+		}
+	}
+
+	return nil
+}
+
+// setTransformations wraps the Director and ModifyResponse functions
+// to apply the request rewrite rules and the response body transformations.
+func (c *Client) setTransformations() {
+	if c.rewrite == nil && len(c.respTransformFns) == 0 {
+		return
+	}
+
+	//nolint:staticcheck
+	director := c.proxy.Director
+	rw := c.rewrite
+	transformBody := len(c.respTransformFns) > 0
+	filter := &c.transformFilter
+
+	//nolint:staticcheck
+	c.proxy.Director = func(r *http.Request) {
+		director(r)
+
+		if rw != nil {
+			rw.apply(r)
+		}
+
+		if transformBody && filter.matchPath(r.URL.Path) {
+			r.Header.Del("Accept-Encoding")
+		}
+	}
+
+	if transformBody {
+		c.proxy.ModifyResponse = modifyResponse(c.proxy.ModifyResponse, c.respTransformFns, filter)
+	}
+}
+
 // ForwardRequest forwards a request to the proxied service.
 func (c *Client) ForwardRequest(w http.ResponseWriter, r *http.Request) {
 	c.proxy.ServeHTTP(w, r)
@@ -29,6 +29,24 @@ func TestNew(t *testing.T) {
 			serviceAddr: "http://invalid-url.domain.invalid\u007F",
 			wantErr:     true,
 		},
+		{
+			name:        "fails with invalid canary URL",
+			serviceAddr: "http://service.domain.invalid:1234/",
+			opts:        []Option{WithCanary("http://invalid-url.domain.invalid\u007F", CanaryRule{})},
+			wantErr:     true,
+		},
+		{
+			name:        "fails with invalid shadow URL",
+			serviceAddr: "http://service.domain.invalid:1234/",
+			opts:        []Option{WithShadow("http://invalid-url.domain.invalid\u007F", 1, 0)},
+			wantErr:     true,
+		},
+		{
+			name:        "fails with invalid shadow percentage",
+			serviceAddr: "http://service.domain.invalid:1234/",
+			opts:        []Option{WithShadow("http://service.domain.invalid:1235/", 101, 0)},
+			wantErr:     true,
+		},
 		{
 			name:        "succeeds with defaults",
 			serviceAddr: "http://service.domain.invalid:1234/",
@@ -46,6 +64,18 @@ func TestNew(t *testing.T) {
 			opts:        []Option{WithReverseProxy(&httputil.ReverseProxy{})},
 			wantErr:     false,
 		},
+		{
+			name:        "fails with custom transport and upstreams",
+			serviceAddr: "http://service.domain.invalid:1236/",
+			opts:        []Option{WithReverseProxy(&httputil.ReverseProxy{Transport: http.DefaultTransport}), WithUpstreams("http://service.domain.invalid:1237/")},
+			wantErr:     true,
+		},
+		{
+			name:        "fails with custom director and retries",
+			serviceAddr: "http://service.domain.invalid:1236/",
+			opts:        []Option{WithReverseProxy(&httputil.ReverseProxy{Director: func(_ *http.Request) {}}), WithRetries(1)},
+			wantErr:     true,
+		},
 		{
 			name:        "succeeds with custom logger",
 			serviceAddr: "http://service.domain.invalid:1237/",
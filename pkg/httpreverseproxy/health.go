@@ -0,0 +1,86 @@
+package httpreverseproxy
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/healthcheck"
+	"github.com/Vonage/gosrvlib/pkg/metrics"
+	"github.com/Vonage/gosrvlib/pkg/periodic"
+	"go.uber.org/zap"
+)
+
+// Start starts the active health probes of the upstreams, if enabled with WithHealthCheck.
+func (c *Client) Start(ctx context.Context) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if c.healthInterval <= 0 || c.balancer == nil || c.periodic != nil {
+		return nil
+	}
+
+	p, err := periodic.New(c.healthInterval, 1*time.Millisecond, c.healthInterval, c.probe)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	c.periodic = p
+	c.periodic.Start(ctx)
+
+	return nil
+}
+
+// Stop stops the active health probes and waits for the pending shadow requests.
+func (c *Client) Stop() {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if c.periodic != nil {
+		c.periodic.Stop()
+		c.periodic = nil
+	}
+
+	if c.shadow != nil {
+		c.shadow.wg.Wait()
+	}
+}
+
+// probe checks the health status of all the upstreams.
+func (c *Client) probe(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	upstreams := c.balancer.upstreams
+	if c.canary != nil {
+		upstreams = append(slices.Clip(upstreams), c.canary)
+	}
+
+	for _, u := range upstreams {
+		wg.Go(func() {
+			err := healthcheck.CheckHTTPStatus(ctx, c.httpClient, http.MethodGet, u.url.String()+c.healthPath, http.StatusOK, c.healthTimeout)
+			c.setHealth(u, err)
+		})
+	}
+
+	wg.Wait()
+}
+
+// setHealth updates the upstream health status.
+func (c *Client) setHealth(u *upstream, err error) {
+	healthy := err == nil
+
+	metrics.SetHealthCheckStatus(c.metrics, metricsTask+"_"+u.name, healthy)
+
+	if u.unhealthy.Swap(!healthy) == !healthy {
+		return
+	}
+
+	if healthy {
+		c.logger.Info("upstream healthy", zap.String("upstream", u.name))
+		return
+	}
+
+	c.logger.Warn("upstream unhealthy", zap.String("upstream", u.name), zap.Error(err))
+}
@@ -0,0 +1,66 @@
+package httpreverseproxy
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/metrics"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type testHealthMetrics struct {
+	metrics.Default
+
+	unhealthy atomic.Int32
+}
+
+func (m *testHealthMetrics) SetHealthCheckStatus(_ string, healthy bool) {
+	if !healthy {
+		m.unhealthy.Add(1)
+	}
+}
+
+func TestClient_Start(t *testing.T) {
+	t.Parallel()
+
+	calls := &atomic.Int32{}
+	okServer := newTestUpstream(t, http.StatusOK, calls)
+	failServer := newTestUpstream(t, http.StatusInternalServerError, calls)
+
+	m := &testHealthMetrics{}
+
+	c, err := New(
+		okServer.URL,
+		WithUpstreams(failServer.URL),
+		WithHealthCheck("/status", 10*time.Millisecond, time.Second),
+		WithMetrics(m),
+		WithLogger(zap.NewNop()),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, c.Start(t.Context()))
+	require.NoError(t, c.Start(t.Context()))
+
+	ups := c.balancer.upstreams
+
+	require.Eventually(t, func() bool {
+		return ups[1].unhealthy.Load() && m.unhealthy.Load() > 0
+	}, time.Second, 10*time.Millisecond)
+	require.False(t, ups[0].unhealthy.Load())
+
+	c.Stop()
+	c.Stop()
+
+	// recovery
+	c.setHealth(ups[1], nil)
+	require.False(t, ups[1].unhealthy.Load())
+
+	// disabled health checks
+	c, err = New(okServer.URL)
+	require.NoError(t, err)
+	require.NoError(t, c.Start(t.Context()))
+	c.Stop()
+}
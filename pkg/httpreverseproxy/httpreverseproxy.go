@@ -3,5 +3,29 @@ Package httpreverseproxy provides an HTTP Reverse Proxy that takes an incoming
 request and sends it to another server, proxying the response back to the
 client. It wraps the standard net/http/httputil ReverseProxy (or equivalent)
 with common functionalities, including logging and error handling.
+
+The requests can be load balanced across multiple upstreams (WithUpstreams)
+using the RoundRobin, LeastConnections or ConsistentHash algorithms. The
+upstreams can be excluded from the selection by the active health probes
+(WithHealthCheck and Client.Start) and by the passive ejection after consecutive
+failures (WithPassiveEjection). Idempotent requests can be retried on another
+upstream (WithRetries). The selected upstream of each attempt is logged at debug
+level with the request-scoped logger and counted with the metrics
+IncEventCounter (task "httpreverseproxy", operation set to the upstream host and
+outcome set to the status code).
+
+The proxy can also act as a thin API gateway in front of legacy services:
+the WithRewrite option applies declarative request rewrite rules (path prefix
+strip/add, header add/remove/rename, query parameters and upstream Basic or
+Bearer authentication), and the WithResponseBodyTransform option sets hooks to
+transform the upstream response bodies.
+
+To test new service versions, the WithCanary option routes a sticky percentage
+of the requests, or the requests matching a header or cookie, to a canary
+upstream. The WithShadow option asynchronously mirrors a sample of the requests
+(by default only the safe methods, see WithShadowMethods) to a shadow upstream: the shadow responses are discarded after being compared
+with the primary ones, and the differences are logged and counted in metrics.
+The requests exceeding the maximum number of concurrent shadow requests (see
+WithShadowMaxConcurrency) are not mirrored.
 */
 package httpreverseproxy
@@ -1,35 +1,226 @@
 package httpreverseproxy
 
 import (
+	"errors"
+	"net/http"
 	"net/http/httputil"
+	"time"
 
+	"github.com/Vonage/gosrvlib/pkg/metrics"
 	"go.uber.org/zap"
 )
 
 // Option is the interface that allows to set client options.
-type Option func(c *Client)
+type Option func(c *Client) error
 
 // WithReverseProxy overrides the default httputil.ReverseProxy.
 // Leave the Director and Transport entries nil to be automatically set.
 // If the Director entry is specified, then the addr argument of the New function is ignored.
 // If the Transport entry is specified, then the HTTP client specified with WithHTTPClient is ignored.
+// The Director and Transport entries can't be combined with the WithUpstreams, WithLoadBalancing,
+// WithPassiveEjection, WithRetries, WithHealthCheck, WithCanary and WithShadow options:
+// New returns an error in this case.
 func WithReverseProxy(p *httputil.ReverseProxy) Option {
-	return func(c *Client) {
+	return func(c *Client) error {
 		c.proxy = p
+
+		return nil
 	}
 }
 
 // WithHTTPClient overrides the default HTTP client used to forward the requests.
 // The HTTP client can contain extra logic for logging.
 func WithHTTPClient(h HTTPClient) Option {
-	return func(c *Client) {
+	return func(c *Client) error {
 		c.httpClient = h
+
+		return nil
 	}
 }
 
 // WithLogger overrides the default logger.
 func WithLogger(l *zap.Logger) Option {
-	return func(c *Client) {
+	return func(c *Client) error {
 		c.logger = l
+
+		return nil
+	}
+}
+
+// WithMetrics sets the metrics client used to count the requests to each upstream
+// and to report the upstreams health status.
+func WithMetrics(m metrics.Client) Option {
+	return func(c *Client) error {
+		c.metrics = m
+
+		return nil
+	}
+}
+
+// WithUpstreams adds more upstream addresses to the one specified in the New function.
+// The requests are load balanced across all the upstreams,
+// and the request path is appended to the base path of the selected upstream address.
+func WithUpstreams(addrs ...string) Option {
+	return func(c *Client) error {
+		c.upstreamAddrs = append(c.upstreamAddrs, addrs...)
+
+		return nil
+	}
+}
+
+// WithLoadBalancing sets the load balancing algorithm (default RoundRobin).
+func WithLoadBalancing(lb LoadBalancing) Option {
+	return func(c *Client) error {
+		c.lb = lb
+
+		return nil
+	}
+}
+
+// WithHashKeyFunc sets the function returning the request key for the ConsistentHash load balancing.
+// The default key is the client IP address.
+func WithHashKeyFunc(fn HashKeyFunc) Option {
+	return func(c *Client) error {
+		c.hashKeyFn = fn
+
+		return nil
+	}
+}
+
+// WithPassiveEjection ejects an upstream from the load balancing for the specified duration
+// after the specified number of consecutive failed requests (connection errors or 5xx responses).
+// If all the upstreams are ejected or unhealthy, the requests are distributed among all of them.
+func WithPassiveEjection(maxFailures int64, duration time.Duration) Option {
+	return func(c *Client) error {
+		c.maxFailures = maxFailures
+		c.ejectDuration = duration
+
+		return nil
+	}
+}
+
+// WithRetries sets the maximum number of times an idempotent request is retried on another upstream
+// after a connection error or a 502, 503 or 504 response.
+// The request bodies up to 1 MiB are buffered in memory to be replayed,
+// the requests with larger bodies are not retried.
+func WithRetries(retries uint) Option {
+	return func(c *Client) error {
+		c.retries = retries
+
+		return nil
+	}
+}
+
+// WithHealthCheck enables the active health probes of the upstreams, started with the Client.Start method.
+// Each upstream is checked with a GET request to the specified path, expecting a 200 response,
+// and it is excluded from the load balancing while failing.
+func WithHealthCheck(path string, interval, timeout time.Duration) Option {
+	return func(c *Client) error {
+		c.healthPath = path
+		c.healthInterval = interval
+		c.healthTimeout = timeout
+
+		return nil
+	}
+}
+
+// WithRewrite sets the declarative rules to transform the requests before forwarding them.
+// The rules are applied after the Director, including the one specified with WithReverseProxy.
+func WithRewrite(rw Rewrite) Option {
+	return func(c *Client) error {
+		c.rewrite = &rw
+
+		return nil
+	}
+}
+
+// WithResponseBodyTransform adds functions to transform the body of the upstream responses.
+// The functions are applied in order after the ModifyResponse function of the ReverseProxy, if any.
+// The Accept-Encoding header is removed from the forwarded requests matching the filter
+// (see WithResponseBodyTransformFilter), so the functions receive the uncompressed body.
+// Compressed responses and bodies larger than the filter MaxBodySize are passed through unchanged.
+func WithResponseBodyTransform(fns ...ResponseBodyTransformFn) Option {
+	return func(c *Client) error {
+		c.respTransformFns = append(c.respTransformFns, fns...)
+
+		return nil
+	}
+}
+
+// WithResponseBodyTransformFilter restricts the response body transformations
+// to the request paths and response content types specified in the filter.
+// By default all the responses up to DefaultTransformMaxBodySize are transformed.
+func WithResponseBodyTransformFilter(f TransformFilter) Option {
+	return func(c *Client) error {
+		c.transformFilter = f
+
+		return nil
+	}
+}
+
+// WithCanary routes the requests matching the rule to the canary upstream.
+// If the canary upstream is unhealthy or ejected, the requests are routed to the primary upstreams,
+// and the failed requests to the canary are retried on the primary upstreams (see WithRetries).
+func WithCanary(addr string, rule CanaryRule) Option {
+	return func(c *Client) error {
+		if rule.Percent < 0 || rule.Percent > 100 {
+			return errors.New("the canary percentage must be between 0 and 100")
+		}
+
+		c.canaryAddr = addr
+		c.canaryRule = rule
+
+		return nil
 	}
 }
+
+// WithShadow asynchronously mirrors the specified percentage (0 to 100) of requests to the shadow upstream.
+// Only the safe methods (GET, HEAD and OPTIONS) are mirrored by default, see WithShadowMethods.
+// The requests with a body larger than 1 MiB are not mirrored.
+// The shadow responses are discarded after being compared with the primary ones:
+// the differences in status code or body are logged and counted with the metrics
+// IncEventCounter (outcome "shadow_match", "shadow_diff" or "shadow_error").
+// The timeout applies to each shadow request, independently of the original request (default 30s).
+// At most 100 shadow requests run concurrently by default (see WithShadowMaxConcurrency).
+func WithShadow(addr string, percent float64, timeout time.Duration) Option {
+	return func(c *Client) error {
+		if percent < 0 || percent > 100 {
+			return errors.New("the shadow percentage must be between 0 and 100")
+		}
+
+		c.shadowAddr = addr
+		c.shadowPercent = percent
+		c.shadowTimeout = timeout
+
+		return nil
+	}
+}
+
+// WithShadowMethods sets the HTTP methods of the requests mirrored to the shadow upstream.
+// WARNING: mirroring the non-idempotent methods (e.g. POST) duplicates their side effects.
+func WithShadowMethods(methods ...string) Option {
+	return func(c *Client) error {
+		c.shadowMethods = methods
+
+		return nil
+	}
+}
+
+// WithShadowMaxConcurrency sets the maximum number of concurrent shadow requests (default 100).
+// The requests exceeding the limit are not mirrored and are counted with the "shadow_error" outcome.
+func WithShadowMaxConcurrency(n int) Option {
+	return func(c *Client) error {
+		if n < 1 {
+			return errors.New("the shadow max concurrency must be at least 1")
+		}
+
+		c.shadowMaxConc = n
+
+		return nil
+	}
+}
+
+// defaultShadowMethods returns the default HTTP methods of the requests mirrored to the shadow upstream.
+func defaultShadowMethods() []string {
+	return []string{http.MethodGet, http.MethodHead, http.MethodOptions}
+}
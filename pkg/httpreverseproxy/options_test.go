@@ -5,7 +5,9 @@ import (
 	"net/http/httputil"
 	"reflect"
 	"testing"
+	"time"
 
+	"github.com/Vonage/gosrvlib/pkg/metrics"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
@@ -21,7 +23,7 @@ func TestWithHTTPClient(t *testing.T) {
 
 	v := &testHTTPClient{}
 	c := &Client{}
-	WithHTTPClient(v)(c)
+	require.NoError(t, WithHTTPClient(v)(c))
 	require.Equal(t, reflect.ValueOf(v).Pointer(), reflect.ValueOf(c.httpClient).Pointer())
 }
 
@@ -30,7 +32,7 @@ func TestWithReverseProxy(t *testing.T) {
 
 	v := &httputil.ReverseProxy{}
 	c := &Client{}
-	WithReverseProxy(v)(c)
+	require.NoError(t, WithReverseProxy(v)(c))
 	require.Equal(t, reflect.ValueOf(v).Pointer(), reflect.ValueOf(c.proxy).Pointer())
 }
 
@@ -39,6 +41,139 @@ func TestWithLogger(t *testing.T) {
 
 	l := zap.NewNop()
 	c := &Client{}
-	WithLogger(l)(c)
+	require.NoError(t, WithLogger(l)(c))
 	require.Equal(t, reflect.ValueOf(l).Pointer(), reflect.ValueOf(c.logger).Pointer())
 }
+
+func TestWithMetrics(t *testing.T) {
+	t.Parallel()
+
+	m := &metrics.Default{}
+	c := &Client{}
+	require.NoError(t, WithMetrics(m)(c))
+	require.Equal(t, m, c.metrics)
+}
+
+func TestWithUpstreams(t *testing.T) {
+	t.Parallel()
+
+	c := &Client{}
+	require.NoError(t, WithUpstreams("http://a.invalid", "http://b.invalid")(c))
+	require.NoError(t, WithUpstreams("http://c.invalid")(c))
+	require.Equal(t, []string{"http://a.invalid", "http://b.invalid", "http://c.invalid"}, c.upstreamAddrs)
+}
+
+func TestWithLoadBalancing(t *testing.T) {
+	t.Parallel()
+
+	c := &Client{}
+	require.NoError(t, WithLoadBalancing(LeastConnections)(c))
+	require.Equal(t, LeastConnections, c.lb)
+}
+
+func TestWithHashKeyFunc(t *testing.T) {
+	t.Parallel()
+
+	c := &Client{}
+	require.NoError(t, WithHashKeyFunc(func(r *http.Request) string { return r.Header.Get("X-User") })(c))
+	require.NotNil(t, c.hashKeyFn)
+}
+
+func TestWithPassiveEjection(t *testing.T) {
+	t.Parallel()
+
+	c := &Client{}
+	require.NoError(t, WithPassiveEjection(3, time.Minute)(c))
+	require.Equal(t, int64(3), c.maxFailures)
+	require.Equal(t, time.Minute, c.ejectDuration)
+}
+
+func TestWithRetries(t *testing.T) {
+	t.Parallel()
+
+	c := &Client{}
+	require.NoError(t, WithRetries(2)(c))
+	require.Equal(t, uint(2), c.retries)
+}
+
+func TestWithHealthCheck(t *testing.T) {
+	t.Parallel()
+
+	c := &Client{}
+	require.NoError(t, WithHealthCheck("/status", time.Second, 100*time.Millisecond)(c))
+	require.Equal(t, "/status", c.healthPath)
+	require.Equal(t, time.Second, c.healthInterval)
+	require.Equal(t, 100*time.Millisecond, c.healthTimeout)
+}
+
+func TestWithRewrite(t *testing.T) {
+	t.Parallel()
+
+	c := &Client{}
+	require.NoError(t, WithRewrite(Rewrite{StripPathPrefix: "/api"})(c))
+	require.Equal(t, &Rewrite{StripPathPrefix: "/api"}, c.rewrite)
+}
+
+func TestWithResponseBodyTransform(t *testing.T) {
+	t.Parallel()
+
+	fn := func(_ *http.Response, body []byte) ([]byte, error) { return body, nil }
+
+	c := &Client{}
+	require.NoError(t, WithResponseBodyTransform(fn)(c))
+	require.NoError(t, WithResponseBodyTransform(fn, fn)(c))
+	require.Len(t, c.respTransformFns, 3)
+}
+
+func TestWithResponseBodyTransformFilter(t *testing.T) {
+	t.Parallel()
+
+	f := TransformFilter{PathPrefixes: []string{"/api"}, ContentTypes: []string{"application/json"}, MaxBodySize: 10}
+
+	c := &Client{}
+	require.NoError(t, WithResponseBodyTransformFilter(f)(c))
+	require.Equal(t, f, c.transformFilter)
+}
+
+func TestWithCanary(t *testing.T) {
+	t.Parallel()
+
+	c := &Client{}
+	require.NoError(t, WithCanary("http://canary.invalid", CanaryRule{Percent: 5})(c))
+	require.Equal(t, "http://canary.invalid", c.canaryAddr)
+	require.Equal(t, CanaryRule{Percent: 5}, c.canaryRule)
+
+	require.Error(t, WithCanary("http://canary.invalid", CanaryRule{Percent: 101})(c))
+	require.Error(t, WithCanary("http://canary.invalid", CanaryRule{Percent: -1})(c))
+}
+
+func TestWithShadow(t *testing.T) {
+	t.Parallel()
+
+	c := &Client{}
+	require.NoError(t, WithShadow("http://shadow.invalid", 10, time.Second)(c))
+	require.Equal(t, "http://shadow.invalid", c.shadowAddr)
+	require.InDelta(t, 10.0, c.shadowPercent, 0)
+	require.Equal(t, time.Second, c.shadowTimeout)
+
+	require.Error(t, WithShadow("http://shadow.invalid", 100.1, time.Second)(c))
+	require.Error(t, WithShadow("http://shadow.invalid", -1, time.Second)(c))
+}
+
+func TestWithShadowMethods(t *testing.T) {
+	t.Parallel()
+
+	c := &Client{}
+	require.NoError(t, WithShadowMethods(http.MethodGet, http.MethodPost)(c))
+	require.Equal(t, []string{http.MethodGet, http.MethodPost}, c.shadowMethods)
+}
+
+func TestWithShadowMaxConcurrency(t *testing.T) {
+	t.Parallel()
+
+	c := &Client{}
+	require.NoError(t, WithShadowMaxConcurrency(3)(c))
+	require.Equal(t, 3, c.shadowMaxConc)
+
+	require.Error(t, WithShadowMaxConcurrency(0)(c))
+}
@@ -0,0 +1,120 @@
+package httpreverseproxy
+
+import (
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	libhttputil "github.com/Vonage/gosrvlib/pkg/httputil"
+)
+
+// Rewrite contains the declarative rules applied to each request
+// before it is forwarded to the upstream service.
+// The rules are applied in the following order:
+// path prefix strip, path prefix add, header rename, header remove, header add,
+// query parameters injection and upstream authentication injection.
+type Rewrite struct {
+	// StripPathPrefix is removed from the beginning of the request path.
+	StripPathPrefix string `mapstructure:"strip_path_prefix"`
+
+	// AddPathPrefix is prepended to the request path.
+	AddPathPrefix string `mapstructure:"add_path_prefix"`
+
+	// RenameHeaders maps the names of the request headers to rename to their new names.
+	RenameHeaders map[string]string `mapstructure:"rename_headers"`
+
+	// RemoveHeaders is the list of the request headers to remove.
+	RemoveHeaders []string `mapstructure:"remove_headers"`
+
+	// AddHeaders contains the request headers to set, replacing any existing value.
+	AddHeaders map[string]string `mapstructure:"add_headers"`
+
+	// AddQuery contains the query parameters to set, replacing any existing value.
+	AddQuery map[string]string `mapstructure:"add_query"`
+
+	// BasicAuthKey and BasicAuthSecret, if set, replace the Authorization header
+	// with the Basic Authorization for the upstream service.
+	BasicAuthKey    string `mapstructure:"basic_auth_key"`
+	BasicAuthSecret string `mapstructure:"basic_auth_secret"`
+
+	// BearerToken, if set, replaces the Authorization header
+	// with the Bearer Authorization for the upstream service.
+	// It is ignored if BasicAuthKey is set.
+	BearerToken string `mapstructure:"bearer_token"`
+}
+
+// apply transforms the request according to the rewrite rules.
+func (rw *Rewrite) apply(r *http.Request) {
+	rw.applyPath(r)
+	rw.applyHeaders(r)
+	rw.applyQuery(r)
+	rw.applyAuth(r)
+}
+
+func (rw *Rewrite) applyPath(r *http.Request) {
+	if rw.StripPathPrefix == "" && rw.AddPathPrefix == "" {
+		return
+	}
+
+	p := strings.TrimPrefix(r.URL.Path, rw.StripPathPrefix)
+
+	if rw.AddPathPrefix != "" {
+		p = strings.TrimSuffix(rw.AddPathPrefix, "/") + "/" + strings.TrimPrefix(p, "/")
+	}
+
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+
+	r.URL.Path = p
+	r.URL.RawPath = ""
+}
+
+func (rw *Rewrite) applyHeaders(r *http.Request) {
+	// the map keys are sorted to apply the rules in a deterministic order
+	for _, from := range slices.Sorted(maps.Keys(rw.RenameHeaders)) {
+		to := rw.RenameHeaders[from]
+
+		v := r.Header.Values(from)
+		if len(v) == 0 {
+			continue
+		}
+
+		r.Header.Del(from)
+		r.Header[http.CanonicalHeaderKey(to)] = v
+	}
+
+	for _, k := range rw.RemoveHeaders {
+		r.Header.Del(k)
+	}
+
+	for _, k := range slices.Sorted(maps.Keys(rw.AddHeaders)) {
+		r.Header.Set(k, rw.AddHeaders[k])
+	}
+}
+
+func (rw *Rewrite) applyQuery(r *http.Request) {
+	if len(rw.AddQuery) == 0 {
+		return
+	}
+
+	q := r.URL.Query()
+
+	for k, v := range rw.AddQuery {
+		q.Set(k, v)
+	}
+
+	r.URL.RawQuery = q.Encode()
+}
+
+func (rw *Rewrite) applyAuth(r *http.Request) {
+	switch {
+	case rw.BasicAuthKey != "":
+		r.Header.Del(libhttputil.HeaderAuthorization)
+		libhttputil.AddBasicAuth(rw.BasicAuthKey, rw.BasicAuthSecret, r)
+	case rw.BearerToken != "":
+		r.Header.Del(libhttputil.HeaderAuthorization)
+		libhttputil.AddBearerToken(rw.BearerToken, r)
+	}
+}
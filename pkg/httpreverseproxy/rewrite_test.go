@@ -0,0 +1,127 @@
+package httpreverseproxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewrite_apply(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		rw         Rewrite
+		target     string
+		header     http.Header
+		wantURI    string
+		wantHeader http.Header
+	}{
+		{
+			name:       "empty rules",
+			target:     "/api/users?id=1",
+			header:     http.Header{"X-Test": {"a"}},
+			wantURI:    "/api/users?id=1",
+			wantHeader: http.Header{"X-Test": {"a"}},
+		},
+		{
+			name:       "strip path prefix",
+			rw:         Rewrite{StripPathPrefix: "/api"},
+			target:     "/api/users",
+			wantURI:    "/users",
+			wantHeader: http.Header{},
+		},
+		{
+			name:       "strip whole path",
+			rw:         Rewrite{StripPathPrefix: "/api"},
+			target:     "/api",
+			wantURI:    "/",
+			wantHeader: http.Header{},
+		},
+		{
+			name:       "strip and add path prefix",
+			rw:         Rewrite{StripPathPrefix: "/api", AddPathPrefix: "/legacy/v1/"},
+			target:     "/api/users",
+			wantURI:    "/legacy/v1/users",
+			wantHeader: http.Header{},
+		},
+		{
+			name: "headers",
+			rw: Rewrite{
+				RenameHeaders: map[string]string{"x-user": "X-Legacy-User", "X-Missing": "X-Other"},
+				RemoveHeaders: []string{"Cookie"},
+				AddHeaders:    map[string]string{"X-Gateway": "gosrvlib", "X-Test": "b"},
+			},
+			target: "/",
+			header: http.Header{
+				"X-User": {"alice", "bob"},
+				"Cookie": {"a=b"},
+				"X-Test": {"a"},
+			},
+			wantURI: "/",
+			wantHeader: http.Header{
+				"X-Legacy-User": {"alice", "bob"},
+				"X-Gateway":     {"gosrvlib"},
+				"X-Test":        {"b"},
+			},
+		},
+		{
+			name: "conflicting headers in key order",
+			rw: Rewrite{
+				RenameHeaders: map[string]string{"X-A": "X-C", "X-B": "X-C"},
+				AddHeaders:    map[string]string{"x-d": "1", "X-D": "2"},
+			},
+			target: "/",
+			header: http.Header{
+				"X-A": {"a"},
+				"X-B": {"b"},
+			},
+			wantURI: "/",
+			wantHeader: http.Header{
+				"X-C": {"b"},
+				"X-D": {"1"},
+			},
+		},
+		{
+			name:       "query",
+			rw:         Rewrite{AddQuery: map[string]string{"api_key": "secret", "id": "2"}},
+			target:     "/users?id=1&name=x",
+			wantURI:    "/users?api_key=secret&id=2&name=x",
+			wantHeader: http.Header{},
+		},
+		{
+			name:       "basic auth",
+			rw:         Rewrite{BasicAuthKey: "key", BasicAuthSecret: "secret", BearerToken: "ignored"},
+			target:     "/",
+			header:     http.Header{"Authorization": {"Bearer client"}},
+			wantURI:    "/",
+			wantHeader: http.Header{"Authorization": {"Basic a2V5OnNlY3JldA=="}},
+		},
+		{
+			name:       "bearer token",
+			rw:         Rewrite{BearerToken: "token"},
+			target:     "/",
+			header:     http.Header{"Authorization": {"Basic Y2xpZW50"}},
+			wantURI:    "/",
+			wantHeader: http.Header{"Authorization": {"Bearer token"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.header != nil {
+				r.Header = tt.header
+			}
+
+			tt.rw.apply(r)
+
+			require.Equal(t, tt.wantURI, r.URL.RequestURI())
+			require.Equal(t, tt.wantHeader, r.Header)
+		})
+	}
+}
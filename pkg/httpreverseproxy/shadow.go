@@ -0,0 +1,197 @@
+package httpreverseproxy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/metrics"
+	"go.uber.org/zap"
+)
+
+// shadowMaxBodySize is the maximum size of the request bodies buffered for the shadow traffic,
+// and of the response bodies compared.
+// Larger requests are not mirrored, larger responses are not compared (only the status codes are).
+const shadowMaxBodySize = 1 << 20
+
+// defaultShadowTimeout is the default timeout of the shadow requests.
+const defaultShadowTimeout = 30 * time.Second
+
+// defaultShadowMaxConcurrency is the default maximum number of concurrent shadow requests.
+const defaultShadowMaxConcurrency = 100
+
+// Shadow metrics outcomes.
+const (
+	shadowMatch = "shadow_match"
+	shadowDiff  = "shadow_diff"
+	shadowError = "shadow_error"
+)
+
+// errShadowBusy is recorded when a request is not mirrored because of the concurrency limit.
+var errShadowBusy = errors.New("too many concurrent shadow requests")
+
+// shadow mirrors a sample of the requests to a shadow upstream
+// and compares the responses with the primary ones.
+type shadow struct {
+	upstream *upstream
+	percent  float64
+	methods  []string
+	timeout  time.Duration
+	client   HTTPClient
+	logger   *zap.Logger
+	metrics  metrics.Client
+	sem      chan struct{}
+	wg       sync.WaitGroup
+}
+
+// sample returns true if the request with the specified method should be mirrored.
+func (s *shadow) sample(method string) bool {
+	if !slices.Contains(s.methods, method) {
+		return false
+	}
+
+	return s.percent >= 100 || rand.Float64()*100 < s.percent //nolint:gosec
+}
+
+// prepare makes the request body replayable and returns a copy of the request for the shadow upstream,
+// detached from the cancellation of the original request.
+// It returns nil if the request body is too large to be mirrored.
+func (s *shadow) prepare(r *http.Request) (*http.Request, error) {
+	ok, err := bufferBody(r, shadowMaxBodySize)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	req := r.Clone(context.WithoutCancel(r.Context()))
+	req.URL.Scheme = s.upstream.url.Scheme
+	req.URL.Host = s.upstream.url.Host
+	req.URL.Path, req.URL.RawPath = joinURLPath(s.upstream.url, r.URL)
+	req.Host = s.upstream.url.Host
+
+	return req, nil
+}
+
+// capture wraps the primary response body to compare it with the shadow response when it is closed.
+// The request is not mirrored if the maximum number of concurrent shadow requests is reached.
+func (s *shadow) capture(req *http.Request, resp *http.Response) {
+	resp.Body = &shadowBody{
+		ReadCloser: resp.Body,
+		onClose: func(body []byte, complete bool) {
+			select {
+			case s.sem <- struct{}{}:
+			default:
+				s.fail(req, errShadowBusy)
+				return
+			}
+
+			s.wg.Go(func() {
+				defer func() { <-s.sem }()
+
+				s.mirror(req, resp.StatusCode, body, complete)
+			})
+		},
+	}
+}
+
+// mirror sends the request to the shadow upstream and compares the response with the primary one.
+func (s *shadow) mirror(req *http.Request, status int, body []byte, complete bool) {
+	ctx, cancel := context.WithTimeout(req.Context(), s.timeout)
+	defer cancel()
+
+	req = req.WithContext(ctx)
+
+	if req.GetBody != nil {
+		b, err := req.GetBody()
+		if err != nil {
+			s.fail(req, err)
+			return
+		}
+
+		req.Body = b
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		s.fail(req, err)
+		return
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	sbody, err := io.ReadAll(io.LimitReader(resp.Body, shadowMaxBodySize+1))
+	if err != nil {
+		s.fail(req, err)
+		return
+	}
+
+	compareBody := complete && len(sbody) <= shadowMaxBodySize
+
+	if resp.StatusCode == status && (!compareBody || bytes.Equal(body, sbody)) {
+		metrics.IncEventCounter(s.metrics, metricsTask, s.upstream.name, shadowMatch)
+		return
+	}
+
+	metrics.IncEventCounter(s.metrics, metricsTask, s.upstream.name, shadowDiff)
+
+	s.logger.Info(
+		"shadow response differs",
+		zap.String("upstream", s.upstream.name),
+		zap.String("request_method", req.Method),
+		zap.String("request_path", req.URL.Path),
+		zap.Int("primary_status", status),
+		zap.Int("shadow_status", resp.StatusCode),
+		zap.Int("primary_body_size", len(body)),
+		zap.Int("shadow_body_size", len(sbody)),
+		zap.Bool("body_compared", compareBody),
+	)
+}
+
+// fail records a failed shadow request.
+func (s *shadow) fail(req *http.Request, err error) {
+	metrics.IncEventCounter(s.metrics, metricsTask, s.upstream.name, shadowError)
+
+	s.logger.Info(
+		"shadow request failed",
+		zap.String("upstream", s.upstream.name),
+		zap.String("request_method", req.Method),
+		zap.String("request_path", req.URL.Path),
+		zap.Error(err),
+	)
+}
+
+// shadowBody captures the primary response body up to shadowMaxBodySize bytes.
+type shadowBody struct {
+	io.ReadCloser
+
+	buf      bytes.Buffer
+	complete bool
+	once     sync.Once
+	onClose  func(body []byte, complete bool)
+}
+
+// Read reads and captures the body.
+func (b *shadowBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+
+	if room := shadowMaxBodySize + 1 - b.buf.Len(); room > 0 {
+		b.buf.Write(p[:min(n, room)])
+	}
+
+	if errors.Is(err, io.EOF) {
+		b.complete = b.buf.Len() <= shadowMaxBodySize
+	}
+
+	return n, err //nolint:wrapcheck
+}
+
+// Close closes the body and triggers the comparison with the shadow response.
+func (b *shadowBody) Close() error {
+	b.once.Do(func() { b.onClose(b.buf.Bytes(), b.complete) })
+	return b.ReadCloser.Close() //nolint:wrapcheck
+}
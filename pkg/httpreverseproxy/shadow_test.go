@@ -0,0 +1,279 @@
+package httpreverseproxy
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/metrics"
+	"github.com/Vonage/gosrvlib/pkg/testutil"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type testOutcomeMetrics struct {
+	metrics.Default
+
+	mux      sync.Mutex
+	outcomes map[string]int
+}
+
+func (m *testOutcomeMetrics) IncEventCounter(_, _, outcome string) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if m.outcomes == nil {
+		m.outcomes = make(map[string]int)
+	}
+
+	m.outcomes[outcome]++
+}
+
+func (m *testOutcomeMetrics) count(outcome string) int {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	return m.outcomes[outcome]
+}
+
+func newEchoUpstream(t *testing.T, prefix string) *httptest.Server {
+	t.Helper()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+
+		_, _ = w.Write([]byte(prefix + r.URL.Path + ":" + string(body)))
+	}))
+
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func TestClient_ForwardRequest_shadow(t *testing.T) {
+	t.Parallel()
+
+	primary := newEchoUpstream(t, "")
+	same := newEchoUpstream(t, "")
+	different := newEchoUpstream(t, "v2")
+
+	tests := []struct {
+		name      string
+		shadow    string
+		methods   []string
+		method    string
+		path      string
+		body      string
+		wantMatch int
+		wantDiff  int
+		wantError int
+	}{
+		{
+			name:      "same response",
+			shadow:    same.URL,
+			method:    http.MethodGet,
+			path:      "/test",
+			wantMatch: 1,
+		},
+		{
+			name:      "same response with body",
+			shadow:    same.URL,
+			methods:   []string{http.MethodPost},
+			method:    http.MethodPost,
+			path:      "/test",
+			body:      "data",
+			wantMatch: 1,
+		},
+		{
+			name:     "different body",
+			shadow:   different.URL,
+			methods:  []string{http.MethodPost},
+			method:   http.MethodPost,
+			path:     "/test",
+			body:     "data",
+			wantDiff: 1,
+		},
+		{
+			name:     "different status",
+			shadow:   different.URL,
+			method:   http.MethodGet,
+			path:     "/missing",
+			wantDiff: 1,
+		},
+		{
+			name:   "unsafe method not mirrored",
+			shadow: same.URL,
+			method: http.MethodPost,
+			path:   "/test",
+			body:   "data",
+		},
+		{
+			name:    "large body not mirrored",
+			shadow:  same.URL,
+			methods: []string{http.MethodPost},
+			method:  http.MethodPost,
+			path:    "/test",
+			body:    strings.Repeat("a", shadowMaxBodySize+1),
+		},
+		{
+			name:      "shadow error",
+			shadow:    "http://127.0.0.1:1",
+			method:    http.MethodGet,
+			path:      "/test",
+			wantError: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := &testOutcomeMetrics{}
+
+			opts := []Option{
+				WithShadow(tt.shadow, 100, time.Second),
+				WithMetrics(m),
+				WithLogger(zap.NewNop()),
+			}
+
+			if tt.methods != nil {
+				opts = append(opts, WithShadowMethods(tt.methods...))
+			}
+
+			c, err := New(primary.URL, opts...)
+			require.NoError(t, err)
+
+			proxyServer := httptest.NewServer(testutil.RouterWithHandler(tt.method, "/proxy/*path", c.ForwardRequest))
+			t.Cleanup(proxyServer.Close)
+
+			req, err := http.NewRequestWithContext(t.Context(), tt.method, proxyServer.URL+"/proxy"+tt.path, strings.NewReader(tt.body))
+			require.NoError(t, err)
+
+			// make the body not replayable and its size unknown
+			req.GetBody = nil
+			req.ContentLength = -1
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+			require.Equal(t, tt.path+":"+tt.body, string(body))
+
+			c.Stop()
+
+			require.Equal(t, tt.wantMatch, m.count(shadowMatch))
+			require.Equal(t, tt.wantDiff, m.count(shadowDiff))
+			require.Equal(t, tt.wantError, m.count(shadowError))
+		})
+	}
+}
+
+func TestBalancedTransport_RoundTrip_shadowBodyError(t *testing.T) {
+	t.Parallel()
+
+	u, err := newUpstream("http://shadow.invalid")
+	require.NoError(t, err)
+
+	bt := &balancedTransport{
+		shadow: &shadow{upstream: u, percent: 100, methods: []string{http.MethodPost}},
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/", io.NopCloser(errReader{}))
+
+	resp, err := bt.RoundTrip(req) //nolint:bodyclose
+	require.Error(t, err)
+	require.Nil(t, resp)
+}
+
+func Test_shadowBody(t *testing.T) {
+	t.Parallel()
+
+	var (
+		got      []byte
+		complete bool
+	)
+
+	b := &shadowBody{
+		ReadCloser: io.NopCloser(strings.NewReader(strings.Repeat("a", shadowMaxBodySize+10))),
+		onClose: func(body []byte, c bool) {
+			got = body
+			complete = c
+		},
+	}
+
+	n, err := io.Copy(io.Discard, b)
+	require.NoError(t, err)
+	require.Equal(t, int64(shadowMaxBodySize+10), n)
+	require.NoError(t, b.Close())
+	require.NoError(t, b.Close())
+	require.Len(t, got, shadowMaxBodySize+1)
+	require.False(t, complete)
+}
+
+func Test_shadow_sample(t *testing.T) {
+	t.Parallel()
+
+	s := &shadow{percent: 100, methods: defaultShadowMethods()}
+	require.True(t, s.sample(http.MethodGet))
+	require.False(t, s.sample(http.MethodPost))
+
+	s.percent = 0
+	require.False(t, s.sample(http.MethodGet))
+}
+
+func Test_shadow_capture_maxConcurrency(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		<-release
+
+		_, _ = w.Write([]byte("ok"))
+	}))
+	t.Cleanup(up.Close)
+
+	u, err := newUpstream(up.URL)
+	require.NoError(t, err)
+
+	m := &testOutcomeMetrics{}
+	s := &shadow{
+		upstream: u,
+		timeout:  time.Second,
+		client:   &http.Client{},
+		logger:   zap.NewNop(),
+		metrics:  m,
+		sem:      make(chan struct{}, 1),
+	}
+
+	r := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/", nil)
+	r.RequestURI = ""
+
+	req, err := s.prepare(r)
+	require.NoError(t, err)
+
+	for range 2 {
+		resp := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("ok"))}
+		s.capture(req, resp)
+		require.NoError(t, resp.Body.Close())
+	}
+
+	// the second request is dropped while the first one is in flight
+	require.Equal(t, 1, m.count(shadowError))
+
+	close(release)
+	s.wg.Wait()
+
+	require.Equal(t, 1, m.count(shadowMatch))
+	require.Empty(t, s.sem)
+}
@@ -0,0 +1,116 @@
+package httpreverseproxy
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DefaultTransformMaxBodySize is the default maximum size of the upstream response bodies to transform.
+const DefaultTransformMaxBodySize = 1 << 20
+
+// ResponseBodyTransformFn is the type of function used to transform the body of the upstream responses.
+// The original request is available as resp.Request.
+// The response headers can be modified, but the Content-Length is automatically updated.
+// Returning an error causes a 502 Bad Gateway response to be sent to the client.
+type ResponseBodyTransformFn func(resp *http.Response, body []byte) ([]byte, error)
+
+// TransformFilter selects the upstream responses processed by the ResponseBodyTransformFn functions.
+// The other responses are passed through unchanged.
+type TransformFilter struct {
+	// PathPrefixes are the prefixes of the forwarded request paths (after the rewrite rules).
+	// The Accept-Encoding header is only removed from the matching requests.
+	// All the paths match if empty.
+	PathPrefixes []string `mapstructure:"path_prefixes"`
+
+	// ContentTypes are the media types of the responses to transform (e.g. "application/json").
+	// All the content types match if empty.
+	ContentTypes []string `mapstructure:"content_types"`
+
+	// MaxBodySize is the maximum size in bytes of the response bodies to transform
+	// (default DefaultTransformMaxBodySize).
+	// Larger bodies are passed through unchanged.
+	MaxBodySize int64 `mapstructure:"max_body_size"`
+}
+
+// matchPath returns true if the forwarded request path matches the filter.
+func (f *TransformFilter) matchPath(path string) bool {
+	if len(f.PathPrefixes) == 0 {
+		return true
+	}
+
+	return slices.ContainsFunc(f.PathPrefixes, func(p string) bool { return strings.HasPrefix(path, p) })
+}
+
+// matchResponse returns true if the uncompressed upstream response matches the filter.
+func (f *TransformFilter) matchResponse(resp *http.Response) bool {
+	if resp.Request != nil && !f.matchPath(resp.Request.URL.Path) {
+		return false
+	}
+
+	if ce := resp.Header.Get("Content-Encoding"); ce != "" && !strings.EqualFold(ce, "identity") {
+		return false
+	}
+
+	if len(f.ContentTypes) == 0 {
+		return true
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+
+	return slices.ContainsFunc(f.ContentTypes, func(ct string) bool { return strings.EqualFold(ct, mediaType) })
+}
+
+// modifyResponse returns the ReverseProxy.ModifyResponse function
+// that calls the existing one (if any) and then applies the body transformations in order
+// to the responses matching the filter.
+func modifyResponse(next func(*http.Response) error, fns []ResponseBodyTransformFn, filter *TransformFilter) func(*http.Response) error {
+	maxSize := filter.MaxBodySize
+	if maxSize <= 0 {
+		maxSize = DefaultTransformMaxBodySize
+	}
+
+	return func(resp *http.Response) error {
+		if next != nil {
+			if err := next(resp); err != nil {
+				return err
+			}
+		}
+
+		if !filter.matchResponse(resp) || resp.ContentLength > maxSize {
+			return nil
+		}
+
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+		if err != nil {
+			_ = resp.Body.Close()
+			return fmt.Errorf("failed reading the upstream response body: %w", err)
+		}
+
+		if int64(len(body)) > maxSize {
+			// pass through the oversized body unchanged
+			resp.Body = &prefixedBody{Reader: io.MultiReader(bytes.NewReader(body), resp.Body), Closer: resp.Body}
+			return nil
+		}
+
+		_ = resp.Body.Close()
+
+		for _, fn := range fns {
+			body, err = fn(resp, body)
+			if err != nil {
+				return fmt.Errorf("failed transforming the upstream response body: %w", err)
+			}
+		}
+
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		resp.ContentLength = int64(len(body))
+		resp.Header.Set("Content-Length", strconv.Itoa(len(body)))
+
+		return nil
+	}
+}
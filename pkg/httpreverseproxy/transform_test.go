@@ -0,0 +1,252 @@
+package httpreverseproxy
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Vonage/gosrvlib/pkg/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+type errReader struct{}
+
+func (errReader) Read(_ []byte) (int, error) {
+	return 0, errors.New("read error")
+}
+
+func Test_modifyResponse(t *testing.T) {
+	t.Parallel()
+
+	upper := func(_ *http.Response, body []byte) ([]byte, error) {
+		return bytes.ToUpper(body), nil
+	}
+
+	wrap := func(resp *http.Response, body []byte) ([]byte, error) {
+		resp.Header.Set("Content-Type", "application/json")
+		return []byte(`{"data":"` + string(body) + `"}`), nil
+	}
+
+	tests := []struct {
+		name     string
+		next     func(*http.Response) error
+		fns      []ResponseBodyTransformFn
+		filter   TransformFilter
+		header   http.Header
+		body     io.Reader
+		wantBody string
+		wantErr  bool
+	}{
+		{
+			name:     "no transformations",
+			body:     strings.NewReader("hello"),
+			wantBody: "hello",
+		},
+		{
+			name:     "chained transformations",
+			fns:      []ResponseBodyTransformFn{upper, wrap},
+			body:     strings.NewReader("hello"),
+			wantBody: `{"data":"HELLO"}`,
+		},
+		{
+			name: "with next function",
+			next: func(resp *http.Response) error {
+				resp.Header.Set("X-Next", "1")
+				return nil
+			},
+			fns:      []ResponseBodyTransformFn{upper},
+			body:     strings.NewReader("hello"),
+			wantBody: "HELLO",
+		},
+		{
+			name:     "matching filter",
+			fns:      []ResponseBodyTransformFn{upper},
+			filter:   TransformFilter{PathPrefixes: []string{"/api"}, ContentTypes: []string{"text/plain"}},
+			header:   http.Header{"Content-Type": {"text/plain; charset=utf-8"}},
+			body:     strings.NewReader("hello"),
+			wantBody: "HELLO",
+		},
+		{
+			name:     "content type not matching",
+			fns:      []ResponseBodyTransformFn{upper},
+			filter:   TransformFilter{ContentTypes: []string{"application/json"}},
+			header:   http.Header{"Content-Type": {"text/plain"}},
+			body:     strings.NewReader("hello"),
+			wantBody: "hello",
+		},
+		{
+			name:     "path not matching",
+			fns:      []ResponseBodyTransformFn{upper},
+			filter:   TransformFilter{PathPrefixes: []string{"/other"}},
+			body:     strings.NewReader("hello"),
+			wantBody: "hello",
+		},
+		{
+			name:     "compressed",
+			fns:      []ResponseBodyTransformFn{upper},
+			header:   http.Header{"Content-Encoding": {"gzip"}},
+			body:     strings.NewReader("hello"),
+			wantBody: "hello",
+		},
+		{
+			name:     "too large",
+			fns:      []ResponseBodyTransformFn{upper},
+			filter:   TransformFilter{MaxBodySize: 3},
+			body:     strings.NewReader("hello"),
+			wantBody: "hello",
+		},
+		{
+			name:    "next function error",
+			next:    func(_ *http.Response) error { return errors.New("next error") },
+			body:    strings.NewReader("hello"),
+			wantErr: true,
+		},
+		{
+			name:    "read error",
+			fns:     []ResponseBodyTransformFn{upper},
+			body:    errReader{},
+			wantErr: true,
+		},
+		{
+			name: "transformation error",
+			fns: []ResponseBodyTransformFn{
+				func(_ *http.Response, _ []byte) ([]byte, error) { return nil, errors.New("transform error") },
+			},
+			body:    strings.NewReader("hello"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			header := http.Header{"Content-Length": {"5"}}
+			for k, v := range tt.header {
+				header[k] = v
+			}
+
+			resp := &http.Response{
+				Header:        header,
+				Body:          io.NopCloser(tt.body),
+				ContentLength: -1,
+				Request:       httptest.NewRequest(http.MethodGet, "/api/test", nil),
+			}
+
+			err := modifyResponse(tt.next, tt.fns, &tt.filter)(resp)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantBody, string(body))
+			require.Equal(t, strconv.Itoa(len(body)), resp.Header.Get("Content-Length"))
+			require.NoError(t, resp.Body.Close())
+		})
+	}
+}
+
+func TestClient_ForwardRequest_transformations(t *testing.T) {
+	t.Parallel()
+
+	targetServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-URI", r.URL.RequestURI())
+		w.Header().Set("X-Request-Auth", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte("hello"))
+	}))
+
+	t.Cleanup(func() { targetServer.Close() })
+
+	c, err := New(
+		targetServer.URL,
+		WithRewrite(Rewrite{
+			StripPathPrefix: "/v2",
+			AddPathPrefix:   "/legacy",
+			AddQuery:        map[string]string{"format": "json"},
+			BearerToken:     "upstream-token",
+		}),
+		WithResponseBodyTransform(func(_ *http.Response, body []byte) ([]byte, error) {
+			return bytes.ToUpper(body), nil
+		}),
+	)
+	require.NoError(t, err)
+
+	proxyServer := httptest.NewServer(testutil.RouterWithHandler(http.MethodGet, "/proxy/*path", c.ForwardRequest))
+
+	t.Cleanup(func() { proxyServer.Close() })
+
+	req, err := http.NewRequestWithContext(testutil.Context(), http.MethodGet, proxyServer.URL+"/proxy/v2/users", nil)
+	require.NoError(t, err)
+
+	req.Header.Set("Authorization", "Bearer client-token")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = resp.Body.Close() })
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "HELLO", string(body))
+	require.Equal(t, "/legacy/users?format=json", resp.Header.Get("X-Request-URI"))
+	require.Equal(t, "Bearer upstream-token", resp.Header.Get("X-Request-Auth"))
+}
+
+func TestClient_ForwardRequest_transformFilter(t *testing.T) {
+	t.Parallel()
+
+	targetServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(r.Header.Get("Accept-Encoding")))
+	}))
+
+	t.Cleanup(func() { targetServer.Close() })
+
+	c, err := New(
+		targetServer.URL,
+		WithResponseBodyTransform(func(_ *http.Response, body []byte) ([]byte, error) {
+			return append([]byte("transformed:"), body...), nil
+		}),
+		WithResponseBodyTransformFilter(TransformFilter{PathPrefixes: []string{"/api"}}),
+	)
+	require.NoError(t, err)
+
+	proxyServer := httptest.NewServer(testutil.RouterWithHandler(http.MethodGet, "/proxy/*path", c.ForwardRequest))
+
+	t.Cleanup(func() { proxyServer.Close() })
+
+	tests := []struct {
+		path     string
+		wantBody string
+	}{
+		// the Go transport requests and decodes gzip when the client Accept-Encoding is removed
+		{path: "/proxy/api/users", wantBody: "transformed:gzip"},
+		{path: "/proxy/static/app.js", wantBody: "br"},
+	}
+
+	for _, tt := range tests {
+		req, err := http.NewRequestWithContext(testutil.Context(), http.MethodGet, proxyServer.URL+tt.path, nil)
+		require.NoError(t, err)
+
+		req.Header.Set("Accept-Encoding", "br")
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, tt.wantBody, string(body))
+	}
+}
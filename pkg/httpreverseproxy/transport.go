@@ -0,0 +1,232 @@
+package httpreverseproxy
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/logging"
+	"github.com/Vonage/gosrvlib/pkg/metrics"
+	"go.uber.org/zap"
+)
+
+// metricsTask is the task name used for the metrics events.
+const metricsTask = "httpreverseproxy"
+
+// retryMaxBodySize is the maximum size of the request bodies buffered to be replayed on retries.
+// The requests with larger bodies are not retried.
+const retryMaxBodySize = 1 << 20
+
+// errNoUpstream is returned when no upstream can be selected.
+var errNoUpstream = errors.New("no upstream available")
+
+// balancedTransport forwards each request to an upstream selected by the balancer,
+// retrying the idempotent requests on another upstream.
+type balancedTransport struct {
+	client     HTTPClient
+	balancer   *balancer
+	retries    uint
+	logger     *zap.Logger
+	metrics    metrics.Client
+	canary     *upstream
+	canaryRule CanaryRule
+	shadow     *shadow
+}
+
+// RoundTrip implements the RoundTripper interface.
+func (t *balancedTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	// Request.RequestURI can't be set in client requests.
+	r.RequestURI = ""
+
+	var shadowReq *http.Request
+
+	if t.shadow != nil && t.shadow.sample(r.Method) {
+		var err error
+
+		shadowReq, err = t.shadow.prepare(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if t.retries > 0 && isIdempotentMethod(r.Method) {
+		_, err := bufferBody(r, retryMaxBodySize)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := t.roundTrip(r)
+
+	if shadowReq != nil && err == nil {
+		t.shadow.capture(shadowReq, resp)
+	}
+
+	return resp, err
+}
+
+// roundTrip forwards the request to the selected upstreams until success or no more retries.
+func (t *balancedTransport) roundTrip(r *http.Request) (*http.Response, error) {
+	tried := make([]*upstream, 0, t.retries+1)
+
+	for attempt := uint(0); ; attempt++ {
+		var u *upstream
+
+		if attempt == 0 {
+			u = t.pickCanary(r)
+		}
+
+		if u == nil {
+			u = t.balancer.pick(r, tried)
+		}
+
+		if u == nil {
+			return nil, errNoUpstream
+		}
+
+		tried = append(tried, u)
+
+		resp, err := t.forward(r, u, attempt)
+		if !t.isRetryable(r, resp, err, attempt) {
+			return resp, err
+		}
+
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+	}
+}
+
+// pickCanary returns the canary upstream if it is available and the request matches the canary rule.
+// Failed requests to the canary are retried on the primary upstreams.
+func (t *balancedTransport) pickCanary(r *http.Request) *upstream {
+	if t.canary == nil || !t.canary.available(time.Now().UnixNano()) || !t.canaryRule.match(r, t.balancer.hashKeyFn) {
+		return nil
+	}
+
+	return t.canary
+}
+
+// forward sends the request to the specified upstream.
+func (t *balancedTransport) forward(r *http.Request, u *upstream, attempt uint) (*http.Response, error) {
+	req := r.Clone(r.Context())
+	req.URL.Scheme = u.url.Scheme
+	req.URL.Host = u.url.Host
+	req.URL.Path, req.URL.RawPath = joinURLPath(u.url, r.URL)
+	req.Host = u.url.Host
+
+	if attempt > 0 && r.GetBody != nil {
+		body, err := r.GetBody()
+		if err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+
+		req.Body = body
+	}
+
+	u.inFlight.Add(1)
+
+	resp, err := t.client.Do(req)
+
+	outcome := "error"
+
+	if err == nil {
+		outcome = strconv.Itoa(resp.StatusCode)
+		resp.Body = &inFlightBody{ReadCloser: resp.Body, upstream: u}
+	} else {
+		u.inFlight.Add(-1)
+	}
+
+	logging.FromContext(r.Context()).Debug(
+		"proxy_upstream",
+		zap.String("upstream", u.name),
+		zap.Uint("attempt", attempt),
+		zap.String("outcome", outcome),
+	)
+
+	metrics.IncEventCounter(t.metrics, metricsTask, u.name, outcome)
+
+	if err != nil || resp.StatusCode >= http.StatusInternalServerError {
+		if t.balancer.failure(u) {
+			t.logger.Warn("upstream ejected", zap.String("upstream", u.name), zap.Duration("duration", t.balancer.ejectDuration))
+			metrics.IncEventCounter(t.metrics, metricsTask, u.name, "ejected")
+		}
+
+		return resp, err //nolint:wrapcheck
+	}
+
+	t.balancer.success(u)
+
+	return resp, nil
+}
+
+// isRetryable returns true if the request can be retried on another upstream.
+func (t *balancedTransport) isRetryable(r *http.Request, resp *http.Response, err error, attempt uint) bool {
+	if attempt >= t.retries || !isIdempotent(r) || r.Context().Err() != nil {
+		return false
+	}
+
+	if err != nil {
+		return true
+	}
+
+	return slices.Contains([]int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout}, resp.StatusCode)
+}
+
+// isIdempotent returns true for the idempotent requests with a replayable body.
+func isIdempotent(r *http.Request) bool {
+	return isIdempotentMethod(r.Method) && (r.Body == nil || r.Body == http.NoBody || r.GetBody != nil)
+}
+
+// isIdempotentMethod returns true for the idempotent HTTP methods.
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// bufferBody reads the request body up to the specified size and makes it replayable (Request.GetBody).
+// It returns false if the body is larger: the body is still fully readable once, but not replayable.
+func bufferBody(r *http.Request, maxSize int64) (bool, error) {
+	if r.Body == nil || r.Body == http.NoBody || r.GetBody != nil {
+		return true, nil
+	}
+
+	if r.ContentLength > maxSize {
+		return false, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxSize+1))
+	if err != nil {
+		_ = r.Body.Close()
+		return false, err //nolint:wrapcheck
+	}
+
+	if int64(len(body)) > maxSize {
+		r.Body = &prefixedBody{Reader: io.MultiReader(bytes.NewReader(body), r.Body), Closer: r.Body}
+		return false, nil
+	}
+
+	_ = r.Body.Close()
+
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	r.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+
+	return true, nil
+}
+
+// prefixedBody is a request body whose beginning has already been read in memory.
+type prefixedBody struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,335 @@
+package httpreverseproxy
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/metrics"
+	"github.com/Vonage/gosrvlib/pkg/testutil"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type testEventMetrics struct {
+	metrics.Default
+
+	events atomic.Int32
+}
+
+func (m *testEventMetrics) IncEventCounter(_, _, _ string) {
+	m.events.Add(1)
+}
+
+func newTestUpstream(t *testing.T, status int, calls *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(status)
+	}))
+
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func TestClient_ForwardRequest_balanced(t *testing.T) {
+	t.Parallel()
+
+	okCalls := &atomic.Int32{}
+	failCalls := &atomic.Int32{}
+
+	okServer := newTestUpstream(t, http.StatusOK, okCalls)
+	failServer := newTestUpstream(t, http.StatusServiceUnavailable, failCalls)
+
+	m := &testEventMetrics{}
+
+	c, err := New(
+		failServer.URL,
+		WithUpstreams(okServer.URL),
+		WithRetries(1),
+		WithPassiveEjection(1, time.Minute),
+		WithMetrics(m),
+		WithLogger(zap.NewNop()),
+	)
+	require.NoError(t, err)
+
+	proxyServer := httptest.NewServer(testutil.RouterWithHandler(http.MethodGet, "/proxy/*path", c.ForwardRequest))
+	t.Cleanup(proxyServer.Close)
+
+	hc := &http.Client{Timeout: time.Second}
+
+	for range 4 {
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, proxyServer.URL+"/proxy/test", nil)
+		require.NoError(t, err)
+
+		resp, err := hc.Do(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.NoError(t, resp.Body.Close())
+	}
+
+	// the failing upstream is ejected after the first failure
+	require.Equal(t, int32(1), failCalls.Load())
+	require.Equal(t, int32(4), okCalls.Load())
+
+	// 1 failure + 1 ejection + 4 success
+	require.Equal(t, int32(6), m.events.Load())
+}
+
+func TestBalancedTransport_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	calls := &atomic.Int32{}
+
+	s1 := newTestUpstream(t, http.StatusBadGateway, calls)
+	s2 := newTestUpstream(t, http.StatusBadGateway, calls)
+
+	u1, err := newUpstream(s1.URL)
+	require.NoError(t, err)
+
+	u2, err := newUpstream(s2.URL)
+	require.NoError(t, err)
+
+	closed, err := newUpstream("http://127.0.0.1:1")
+	require.NoError(t, err)
+
+	newTransport := func(ups ...*upstream) *balancedTransport {
+		return &balancedTransport{
+			client:   &http.Client{},
+			balancer: newBalancer(ups, RoundRobin, DefaultHashKeyFunc, 0, 0),
+			retries:  3,
+			logger:   zap.NewNop(),
+			metrics:  &metrics.Default{},
+		}
+	}
+
+	// retries stop when all upstreams have been tried
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/test", nil)
+	_, err = newTransport(u1, u2).RoundTrip(req)
+	require.ErrorIs(t, err, errNoUpstream)
+	require.Equal(t, int32(2), calls.Load())
+
+	// non-idempotent requests are not retried
+	req = httptest.NewRequestWithContext(t.Context(), http.MethodPost, "/test", strings.NewReader("data"))
+	resp, err := newTransport(u1, u2).RoundTrip(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, int32(3), calls.Load())
+
+	// connection errors are retried, replaying the body
+	req = httptest.NewRequestWithContext(t.Context(), http.MethodPut, "/test", strings.NewReader("data"))
+	req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(strings.NewReader("data")), nil }
+
+	tr := newTransport(closed, u1)
+	tr.retries = 1
+	resp, err = tr.RoundTrip(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, int64(0), u1.inFlight.Load())
+	require.Equal(t, int64(0), closed.inFlight.Load())
+
+	// bodies without GetBody are buffered to be replayed
+	req = httptest.NewRequestWithContext(t.Context(), http.MethodPut, "/test", strings.NewReader("data"))
+
+	tr = newTransport(closed, u1)
+	tr.retries = 1
+	resp, err = tr.RoundTrip(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, int32(5), calls.Load())
+
+	// larger bodies are not replayable
+	req = httptest.NewRequestWithContext(t.Context(), http.MethodPut, "/test", strings.NewReader(strings.Repeat("a", retryMaxBodySize+1)))
+	req.ContentLength = -1
+
+	_, err = newTransport(closed, u1).RoundTrip(req)
+	require.Error(t, err)
+	require.NotErrorIs(t, err, errNoUpstream)
+}
+
+func TestBalancedTransport_RoundTrip_basePath(t *testing.T) {
+	t.Parallel()
+
+	paths := make(chan string, 1)
+
+	s := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		paths <- r.URL.Path
+	}))
+
+	t.Cleanup(s.Close)
+
+	u, err := newUpstream(s.URL + "/base/")
+	require.NoError(t, err)
+
+	tr := &balancedTransport{
+		client:   &http.Client{},
+		balancer: newBalancer([]*upstream{u}, RoundRobin, DefaultHashKeyFunc, 0, 0),
+		logger:   zap.NewNop(),
+		metrics:  &metrics.Default{},
+	}
+
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/test", nil)
+
+	resp, err := tr.RoundTrip(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, "/base/test", <-paths)
+}
+
+func Test_joinURLPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		base        string
+		path        string
+		wantPath    string
+		wantRawPath string
+	}{
+		{
+			name:     "empty base",
+			base:     "http://a",
+			path:     "/test",
+			wantPath: "/test",
+		},
+		{
+			name:     "base with slash",
+			base:     "http://a/base/",
+			path:     "/test",
+			wantPath: "/base/test",
+		},
+		{
+			name:     "relative path",
+			base:     "http://a/base",
+			path:     "test",
+			wantPath: "/base/test",
+		},
+		{
+			name:        "escaped",
+			base:        "http://a/b%2Fc",
+			path:        "/d%2Fe",
+			wantPath:    "/b/c/d/e",
+			wantRawPath: "/b%2Fc/d%2Fe",
+		},
+		{
+			name:        "escaped with slashes",
+			base:        "http://a/b%2Fc/",
+			path:        "/d",
+			wantPath:    "/b/c/d",
+			wantRawPath: "/b%2Fc/d",
+		},
+		{
+			name:        "escaped relative",
+			base:        "http://a/b%2Fc",
+			path:        "d",
+			wantPath:    "/b/c/d",
+			wantRawPath: "/b%2Fc/d",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			base, err := url.Parse(tt.base)
+			require.NoError(t, err)
+
+			r, err := url.Parse(tt.path)
+			require.NoError(t, err)
+
+			path, rawPath := joinURLPath(base, r)
+			require.Equal(t, tt.wantPath, path)
+			require.Equal(t, tt.wantRawPath, rawPath)
+		})
+	}
+}
+
+func TestClient_ForwardRequest_canary(t *testing.T) {
+	t.Parallel()
+
+	primaryCalls := &atomic.Int32{}
+	canaryCalls := &atomic.Int32{}
+
+	primaryServer := newTestUpstream(t, http.StatusOK, primaryCalls)
+	canaryServer := newTestUpstream(t, http.StatusOK, canaryCalls)
+
+	c, err := New(
+		primaryServer.URL,
+		WithCanary(canaryServer.URL, CanaryRule{Header: "X-Canary", Cookie: "canary", CookieValue: "1"}),
+		WithLogger(zap.NewNop()),
+	)
+	require.NoError(t, err)
+
+	proxyServer := httptest.NewServer(testutil.RouterWithHandler(http.MethodGet, "/proxy/*path", c.ForwardRequest))
+	t.Cleanup(proxyServer.Close)
+
+	hc := &http.Client{Timeout: time.Second}
+
+	for _, h := range []http.Header{
+		{},
+		{"X-Canary": {"true"}},
+		{"Cookie": {"canary=1"}},
+		{"Cookie": {"canary=0"}},
+	} {
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, proxyServer.URL+"/proxy/test", nil)
+		require.NoError(t, err)
+
+		req.Header = h
+
+		resp, err := hc.Do(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.NoError(t, resp.Body.Close())
+	}
+
+	require.Equal(t, int32(2), primaryCalls.Load())
+	require.Equal(t, int32(2), canaryCalls.Load())
+}
+
+func TestClient_ForwardRequest_canaryFailover(t *testing.T) {
+	t.Parallel()
+
+	primaryCalls := &atomic.Int32{}
+	canaryCalls := &atomic.Int32{}
+
+	primaryServer := newTestUpstream(t, http.StatusOK, primaryCalls)
+	canaryServer := newTestUpstream(t, http.StatusBadGateway, canaryCalls)
+
+	c, err := New(
+		primaryServer.URL,
+		WithCanary(canaryServer.URL, CanaryRule{Percent: 100}),
+		WithRetries(1),
+		WithPassiveEjection(1, time.Minute),
+		WithLogger(zap.NewNop()),
+	)
+	require.NoError(t, err)
+
+	proxyServer := httptest.NewServer(testutil.RouterWithHandler(http.MethodGet, "/proxy/*path", c.ForwardRequest))
+	t.Cleanup(proxyServer.Close)
+
+	hc := &http.Client{Timeout: time.Second}
+
+	for range 3 {
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, proxyServer.URL+"/proxy/test", nil)
+		require.NoError(t, err)
+
+		resp, err := hc.Do(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.NoError(t, resp.Body.Close())
+	}
+
+	// the failing canary is ejected after the first failure
+	require.Equal(t, int32(1), canaryCalls.Load())
+	require.Equal(t, int32(3), primaryCalls.Load())
+}
@@ -0,0 +1,89 @@
+package httpreverseproxy
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// upstream is a proxied service instance.
+type upstream struct {
+	url          *url.URL
+	name         string
+	inFlight     atomic.Int64
+	unhealthy    atomic.Bool
+	failures     atomic.Int64
+	ejectedUntil atomic.Int64
+}
+
+// newUpstream parses the upstream address.
+func newUpstream(addr string) (*upstream, error) {
+	addr = strings.TrimRight(addr, "/")
+
+	u, err := url.Parse(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid service address: %s", addr)
+	}
+
+	return &upstream{url: u, name: u.Host}, nil
+}
+
+// available returns true if the upstream is healthy and not ejected.
+func (u *upstream) available(now int64) bool {
+	return !u.unhealthy.Load() && u.ejectedUntil.Load() <= now
+}
+
+// inFlightBody decrements the upstream in-flight requests when the response body is closed.
+type inFlightBody struct {
+	io.ReadCloser
+
+	once     sync.Once
+	upstream *upstream
+}
+
+// Close closes the body and decrements the in-flight requests.
+func (b *inFlightBody) Close() error {
+	b.once.Do(func() { b.upstream.inFlight.Add(-1) })
+	return b.ReadCloser.Close() //nolint:wrapcheck
+}
+
+// joinURLPath joins the upstream base path with the request path,
+// as done by httputil.NewSingleHostReverseProxy.
+func joinURLPath(base, r *url.URL) (string, string) {
+	if base.RawPath == "" && r.RawPath == "" {
+		return singleJoiningSlash(base.Path, r.Path), ""
+	}
+
+	bpath := base.EscapedPath()
+	rpath := r.EscapedPath()
+
+	bslash := strings.HasSuffix(bpath, "/")
+	rslash := strings.HasPrefix(rpath, "/")
+
+	switch {
+	case bslash && rslash:
+		return base.Path + r.Path[1:], bpath + rpath[1:]
+	case !bslash && !rslash:
+		return base.Path + "/" + r.Path, bpath + "/" + rpath
+	}
+
+	return base.Path + r.Path, bpath + rpath
+}
+
+// singleJoiningSlash joins two paths with a single slash.
+func singleJoiningSlash(a, b string) string {
+	aslash := strings.HasSuffix(a, "/")
+	bslash := strings.HasPrefix(b, "/")
+
+	switch {
+	case aslash && bslash:
+		return a + b[1:]
+	case !aslash && !bslash:
+		return a + "/" + b
+	}
+
+	return a + b
+}
@@ -3,6 +3,8 @@ package httpserver
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	"math"
 	"net/http"
@@ -40,28 +42,44 @@ func GetPublicIPDefaultFunc() GetPublicIPFunc {
 }
 
 type config struct {
-	router                      *httprouter.Router
+	router                      httpRouter
+	newRouterFn                 func() httpRouter
 	serverAddr                  string
 	traceIDHeaderName           string
 	requestTimeout              time.Duration
 	serverReadHeaderTimeout     time.Duration
 	serverReadTimeout           time.Duration
 	serverWriteTimeout          time.Duration
+	serverIdleTimeout           time.Duration
 	shutdownTimeout             time.Duration
 	tlsConfig                   *tls.Config
+	tlsCertProvider             *TLSCertProvider
+	tlsClientCAs                *x509.CertPool
+	tlsClientAuth               tls.ClientAuthType
+	http2Config                 *http.HTTP2Config
+	disableHTTP2                bool
+	enableH2C                   bool
+	enableHTTP3                 bool
 	defaultEnabledRoutes        []DefaultRoute
 	indexHandlerFunc            IndexHandlerFunc
+	openAPIInfo                 OpenAPIInfo
+	openAPIUI                   OpenAPIUI
+	openAPIUIAssetsURL          string
 	ipHandlerFunc               http.HandlerFunc
 	metricsHandlerFunc          http.HandlerFunc
 	pingHandlerFunc             http.HandlerFunc
 	pprofHandlerFunc            http.HandlerFunc
 	statusHandlerFunc           http.HandlerFunc
+	logLevelController          *logging.LevelController
 	notFoundHandlerFunc         http.HandlerFunc
 	methodNotAllowedHandlerFunc http.HandlerFunc
 	panicHandlerFunc            http.HandlerFunc
 	redactFn                    RedactFn
 	middleware                  []MiddlewareFn
 	disableDefaultRouteLogger   map[DefaultRoute]bool
+	defaultRoutesListener       map[DefaultRoute]string
+	listeners                   []Listener
+	listenerRouters             map[string]httpRouter
 	disableRouteLogger          bool
 	shutdownWaitGroup           *sync.WaitGroup
 	shutdownSignalChan          chan struct{}
@@ -69,7 +87,8 @@ type config struct {
 
 func defaultConfig() *config {
 	return &config{
-		router:                      httprouter.New(),
+		router:                      newDefaultRouter(),
+		newRouterFn:                 newDefaultRouter,
 		serverAddr:                  ":8017",
 		traceIDHeaderName:           traceid.DefaultHeader,
 		serverReadHeaderTimeout:     1 * time.Minute,
@@ -78,6 +97,7 @@ func defaultConfig() *config {
 		shutdownTimeout:             30 * time.Second,
 		defaultEnabledRoutes:        nil,
 		indexHandlerFunc:            defaultIndexHandler,
+		openAPIInfo:                 OpenAPIInfo{Title: "API", Version: "1.0.0"},
 		ipHandlerFunc:               defaultIPHandler(GetPublicIPDefaultFunc()),
 		metricsHandlerFunc:          notImplementedHandler,
 		pingHandlerFunc:             defaultPingHandler,
@@ -89,15 +109,110 @@ func defaultConfig() *config {
 		redactFn:                    redact.HTTPData,
 		middleware:                  []MiddlewareFn{},
 		disableDefaultRouteLogger:   make(map[DefaultRoute]bool, len(allDefaultRoutes())),
+		defaultRoutesListener:       make(map[DefaultRoute]string, len(allDefaultRoutes())),
+		listenerRouters:             make(map[string]httpRouter),
 		shutdownWaitGroup:           &sync.WaitGroup{},
 		shutdownSignalChan:          make(chan struct{}),
 	}
 }
 
+// http2Conf returns the HTTP/2 configuration, allocating it if required.
+func (c *config) http2Conf() *http.HTTP2Config {
+	if c.http2Config == nil {
+		c.http2Config = &http.HTTP2Config{}
+	}
+
+	return c.http2Config
+}
+
+// protocols returns the set of protocols accepted by the TCP server.
+func (c *config) protocols() *http.Protocols {
+	p := &http.Protocols{}
+	p.SetHTTP1(true)
+	p.SetHTTP2(!c.disableHTTP2)
+	p.SetUnencryptedHTTP2(!c.disableHTTP2 && c.enableH2C)
+
+	return p
+}
+
+// setTLSNextProtos sets the ALPN protocols to negotiate over TLS, if not already set.
+func (c *config) setTLSNextProtos() {
+	if c.tlsConfig == nil || len(c.tlsConfig.NextProtos) > 0 {
+		return
+	}
+
+	if c.disableHTTP2 {
+		c.tlsConfig.NextProtos = []string{"http/1.1"}
+		return
+	}
+
+	c.tlsConfig.NextProtos = []string{"h2", "http/1.1"}
+}
+
+// setTLSClientAuth configures the TLS client authentication (mTLS).
+func (c *config) setTLSClientAuth() {
+	if c.tlsConfig == nil {
+		return
+	}
+
+	if c.tlsClientCAs != nil && c.tlsClientAuth == tls.NoClientCert {
+		c.tlsClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	c.tlsConfig.ClientCAs = c.tlsClientCAs
+	c.tlsConfig.ClientAuth = c.tlsClientAuth
+}
+
+func (c *config) isTLSClientAuthEnabled() bool {
+	return c.tlsConfig != nil && c.tlsConfig.ClientAuth != tls.NoClientCert
+}
+
+func (c *config) validate() error {
+	if c.enableHTTP3 && c.tlsConfig == nil {
+		return errors.New("HTTP/3 requires TLS to be enabled")
+	}
+
+	if (c.tlsClientCAs != nil || c.tlsClientAuth != tls.NoClientCert) && c.tlsConfig == nil {
+		return errors.New("TLS client authentication requires TLS to be enabled")
+	}
+
+	for _, l := range c.listeners {
+		if l.TLS && c.tlsConfig == nil {
+			return fmt.Errorf("the listener %q requires TLS to be enabled", l.Name)
+		}
+
+		if l.TLS && c.enableHTTP3 {
+			return fmt.Errorf("HTTP/3 is only supported on the default listener, not on %q", l.Name)
+		}
+	}
+
+	for id, name := range c.defaultRoutesListener {
+		if err := c.validateListenerNames(name); err != nil {
+			return fmt.Errorf("invalid listener for the %q default route: %w", id, err)
+		}
+	}
+
+	return nil
+}
+
+// defaultRouteListeners returns the listeners the specified default route is assigned to.
+func (c *config) defaultRouteListeners(id DefaultRoute) []string {
+	name, ok := c.defaultRoutesListener[id]
+	if !ok {
+		return nil
+	}
+
+	return []string{name}
+}
+
 func (c *config) isIndexRouteEnabled() bool {
 	return slices.Contains(c.defaultEnabledRoutes, IndexRoute)
 }
 
+func (c *config) isOpenAPIRouteEnabled() bool {
+	return slices.Contains(c.defaultEnabledRoutes, OpenAPIRoute)
+}
+
 // validateAddr checks if a http server bind address is valid.
 func validateAddr(addr string) error {
 	addrErr := fmt.Errorf("invalid http server address: %s", addr)
@@ -128,7 +243,7 @@ func validateAddr(addr string) error {
 	return nil
 }
 
-func (c *config) commonMiddleware(noRouteLogger bool, rTimeout time.Duration) []MiddlewareFn {
+func (c *config) commonMiddleware(noRouteLogger, noTimeout bool, rTimeout time.Duration) []MiddlewareFn {
 	middleware := []MiddlewareFn{}
 
 	if !c.disableRouteLogger && !noRouteLogger {
@@ -140,7 +255,7 @@ func (c *config) commonMiddleware(noRouteLogger bool, rTimeout time.Duration) []
 		timeout = rTimeout
 	}
 
-	if timeout > 0 {
+	if timeout > 0 && !noTimeout {
 		timeoutMiddlewareFn := func(_ MiddlewareArgs, next http.Handler) http.Handler {
 			return http.TimeoutHandler(next, timeout, timeoutMessage)
 		}
@@ -152,11 +267,32 @@ func (c *config) commonMiddleware(noRouteLogger bool, rTimeout time.Duration) []
 }
 
 func (c *config) setRouter(ctx context.Context) {
+	c.setRouterHandlers(ctx, c.router)
+
+	for _, l := range c.listeners {
+		r := c.newRouterFn()
+		c.setRouterHandlers(ctx, r)
+		c.listenerRouters[l.Name] = r
+	}
+}
+
+// setRouterHandlers sets the default router handlers if not already set.
+func (c *config) setRouterHandlers(ctx context.Context, router httpRouter) {
+	switch r := router.(type) {
+	case *httprouter.Router:
+		c.setDefaultHandlers(ctx, &r.NotFound, &r.MethodNotAllowed, &r.PanicHandler)
+	case *ServeMuxRouter:
+		c.setDefaultHandlers(ctx, &r.NotFound, &r.MethodNotAllowed, &r.PanicHandler)
+	}
+}
+
+// setDefaultHandlers sets the not found, method not allowed and panic handlers if not already set.
+func (c *config) setDefaultHandlers(ctx context.Context, notFound, methodNotAllowed *http.Handler, panicHandler *func(http.ResponseWriter, *http.Request, any)) {
 	l := logging.FromContext(ctx)
-	middleware := c.commonMiddleware(false, 0)
+	middleware := c.commonMiddleware(false, false, 0)
 
-	if c.router.NotFound == nil {
-		c.router.NotFound = ApplyMiddleware(
+	if *notFound == nil {
+		*notFound = ApplyMiddleware(
 			MiddlewareArgs{
 				Path:              "404",
 				Description:       http.StatusText(http.StatusNotFound),
@@ -169,8 +305,8 @@ func (c *config) setRouter(ctx context.Context) {
 		)
 	}
 
-	if c.router.MethodNotAllowed == nil {
-		c.router.MethodNotAllowed = ApplyMiddleware(
+	if *methodNotAllowed == nil {
+		*methodNotAllowed = ApplyMiddleware(
 			MiddlewareArgs{
 				Path:              "405",
 				Description:       http.StatusText(http.StatusMethodNotAllowed),
@@ -183,8 +319,8 @@ func (c *config) setRouter(ctx context.Context) {
 		)
 	}
 
-	if c.router.PanicHandler == nil {
-		c.router.PanicHandler = func(w http.ResponseWriter, r *http.Request, p any) {
+	if *panicHandler == nil {
+		*panicHandler = func(w http.ResponseWriter, r *http.Request, p any) {
 			logging.FromContext(r.Context()).Error(
 				"panic",
 				zap.Any("err", p),
@@ -1,6 +1,8 @@
 package httpserver
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -89,6 +91,117 @@ func Test_validateAddr(t *testing.T) {
 	}
 }
 
+func Test_config_protocols(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultConfig()
+	p := cfg.protocols()
+	require.True(t, p.HTTP1())
+	require.True(t, p.HTTP2())
+	require.False(t, p.UnencryptedHTTP2())
+
+	cfg.enableH2C = true
+	p = cfg.protocols()
+	require.True(t, p.UnencryptedHTTP2())
+
+	cfg.disableHTTP2 = true
+	p = cfg.protocols()
+	require.True(t, p.HTTP1())
+	require.False(t, p.HTTP2())
+	require.False(t, p.UnencryptedHTTP2())
+}
+
+func Test_config_setTLSNextProtos(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultConfig()
+	cfg.setTLSNextProtos()
+	require.Nil(t, cfg.tlsConfig)
+
+	cfg.tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	cfg.setTLSNextProtos()
+	require.Equal(t, []string{"h2", "http/1.1"}, cfg.tlsConfig.NextProtos)
+
+	cfg.tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	cfg.disableHTTP2 = true
+	cfg.setTLSNextProtos()
+	require.Equal(t, []string{"http/1.1"}, cfg.tlsConfig.NextProtos)
+
+	cfg.tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12, NextProtos: []string{"custom"}}
+	cfg.setTLSNextProtos()
+	require.Equal(t, []string{"custom"}, cfg.tlsConfig.NextProtos)
+}
+
+func Test_config_setTLSClientAuth(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultConfig()
+	cfg.tlsClientCAs = x509.NewCertPool()
+	cfg.setTLSClientAuth()
+	require.False(t, cfg.isTLSClientAuthEnabled())
+
+	cfg.tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	cfg.setTLSClientAuth()
+	require.True(t, cfg.isTLSClientAuthEnabled())
+	require.Equal(t, tls.RequireAndVerifyClientCert, cfg.tlsConfig.ClientAuth)
+	require.Equal(t, cfg.tlsClientCAs, cfg.tlsConfig.ClientCAs)
+
+	cfg = defaultConfig()
+	cfg.tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	cfg.tlsClientCAs = x509.NewCertPool()
+	cfg.tlsClientAuth = tls.VerifyClientCertIfGiven
+	cfg.setTLSClientAuth()
+	require.Equal(t, tls.VerifyClientCertIfGiven, cfg.tlsConfig.ClientAuth)
+}
+
+func Test_config_validate(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultConfig()
+	require.NoError(t, cfg.validate())
+
+	cfg.enableHTTP3 = true
+	require.Error(t, cfg.validate())
+
+	cfg.tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	require.NoError(t, cfg.validate())
+
+	cfg = defaultConfig()
+	cfg.tlsClientCAs = x509.NewCertPool()
+	require.Error(t, cfg.validate())
+
+	cfg = defaultConfig()
+	cfg.tlsClientAuth = tls.RequireAnyClientCert
+	require.Error(t, cfg.validate())
+
+	cfg = defaultConfig()
+	cfg.listeners = []Listener{{Name: "admin", Addr: ":8081", TLS: true}}
+	require.Error(t, cfg.validate())
+
+	cfg.tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	require.NoError(t, cfg.validate())
+
+	cfg.enableHTTP3 = true
+	require.ErrorContains(t, cfg.validate(), "HTTP/3")
+
+	cfg = defaultConfig()
+	cfg.defaultRoutesListener[PingRoute] = "admin"
+	require.ErrorContains(t, cfg.validate(), "unknown listener")
+
+	cfg.listeners = []Listener{{Name: "admin", Addr: ":8081"}}
+	require.NoError(t, cfg.validate())
+}
+
+func Test_config_defaultRouteListeners(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultConfig()
+	require.Nil(t, cfg.defaultRouteListeners(PingRoute))
+
+	cfg.defaultRoutesListener[PingRoute] = "admin"
+	require.Equal(t, []string{"admin"}, cfg.defaultRouteListeners(PingRoute))
+}
+
 func Test_config_isIndexRouteEnabled(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,42 @@
+package httpserver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/http3"
+)
+
+// newHTTP3Server returns a new HTTP/3 server and the UDP listener to serve on.
+func newHTTP3Server(ctx context.Context, cfg *config, handler http.Handler, streams *streamTracker) (*http3.Server, net.PacketConn, error) {
+	var lc net.ListenConfig
+
+	pc, err := lc.ListenPacket(ctx, "udp", cfg.serverAddr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed creating the http3 server address listener: %w", err)
+	}
+
+	srv := &http3.Server{
+		Addr:        cfg.serverAddr,
+		Handler:     handler,
+		TLSConfig:   cfg.tlsConfig,
+		IdleTimeout: cfg.serverIdleTimeout,
+		ConnContext: func(ctx context.Context, _ *quic.Conn) context.Context {
+			return withStreamTracker(ctx, streams)
+		},
+	}
+
+	return srv, pc, nil
+}
+
+// altSvcHandler advertises the HTTP/3 endpoint on the HTTP/1.1 and HTTP/2 responses.
+func altSvcHandler(srv *http3.Server, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = srv.SetQUICHeaders(w.Header())
+
+		next.ServeHTTP(w, r)
+	})
+}
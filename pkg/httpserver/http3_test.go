@@ -0,0 +1,133 @@
+package httpserver
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+type protoBinder struct{}
+
+func (b *protoBinder) BindHTTP(_ context.Context) []Route {
+	return []Route{
+		{
+			Method: http.MethodGet,
+			Path:   "/proto",
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(r.Proto))
+			},
+		},
+	}
+}
+
+func getProto(t *testing.T, proto testutil.HTTPProtocol, url string) (string, http.Header) {
+	t.Helper()
+
+	client := testutil.NewHTTPClient(proto, 5*time.Second)
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, url, nil)
+	require.NoError(t, err)
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return string(body), resp.Header
+}
+
+func TestHTTPServer_protocolsTLS(t *testing.T) {
+	t.Parallel()
+
+	certPEM, keyPEM, err := testutil.TLSCertData()
+	require.NoError(t, err)
+
+	shutdownWG := &sync.WaitGroup{}
+	shutdownSG := make(chan struct{})
+
+	h, err := New(t.Context(), &protoBinder{},
+		WithServerAddr(":33119"),
+		WithTLSCertData(certPEM, keyPEM),
+		WithHTTP3(),
+		WithHTTP2MaxConcurrentStreams(10),
+		WithServerIdleTimeout(time.Second),
+		WithShutdownWaitGroup(shutdownWG),
+		WithShutdownSignalChan(shutdownSG),
+	)
+	require.NoError(t, err)
+
+	h.StartServerCtx(t.Context())
+
+	url := "https://127.0.0.1:33119/proto"
+
+	got, hdr := getProto(t, testutil.HTTP1, url)
+	require.Equal(t, "HTTP/1.1", got)
+	require.Equal(t, `h3=":33119"; ma=2592000`, hdr.Get("Alt-Svc"))
+
+	got, _ = getProto(t, testutil.HTTP2, url)
+	require.Equal(t, "HTTP/2.0", got)
+
+	got, _ = getProto(t, testutil.HTTP3, url)
+	require.Equal(t, "HTTP/3.0", got)
+
+	close(shutdownSG)
+	shutdownWG.Wait()
+}
+
+func TestHTTPServer_protocolsH2C(t *testing.T) {
+	t.Parallel()
+
+	shutdownWG := &sync.WaitGroup{}
+	shutdownSG := make(chan struct{})
+
+	h, err := New(t.Context(), &protoBinder{},
+		WithServerAddr(":33120"),
+		WithH2C(),
+		WithShutdownWaitGroup(shutdownWG),
+		WithShutdownSignalChan(shutdownSG),
+	)
+	require.NoError(t, err)
+
+	h.StartServerCtx(t.Context())
+
+	url := "http://127.0.0.1:33120/proto"
+
+	got, _ := getProto(t, testutil.HTTP1, url)
+	require.Equal(t, "HTTP/1.1", got)
+
+	got, _ = getProto(t, testutil.H2C, url)
+	require.Equal(t, "HTTP/2.0", got)
+
+	close(shutdownSG)
+	shutdownWG.Wait()
+}
+
+func TestNew_http3Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(t.Context(), NopBinder(), WithServerAddr(":33121"), WithHTTP3())
+	require.Error(t, err, "HTTP/3 without TLS")
+
+	var lc net.ListenConfig
+
+	pc, err := lc.ListenPacket(t.Context(), "udp", ":33122")
+	require.NoError(t, err)
+
+	defer func() { _ = pc.Close() }()
+
+	certPEM, keyPEM, err := testutil.TLSCertData()
+	require.NoError(t, err)
+
+	_, err = New(t.Context(), NopBinder(), WithServerAddr(":33122"), WithTLSCertData(certPEM, keyPEM), WithHTTP3())
+	require.Error(t, err, "UDP port already bound")
+}
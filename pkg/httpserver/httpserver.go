@@ -4,11 +4,45 @@ and options.
 
 Optional common routes are defined in the routes.go file. The routes include:
   - /ip: Returns the public IP address of the service instance.
+  - /openapi.json: Returns the OpenAPI 3.1 document generated from the routes.
   - /metrics: Returns Prometheus metrics (default and custom).
   - /ping: Pings the service to check if it is alive.
   - /pprof: Returns pprof profiling data for the selected profile.
   - /status: Checks and returns the health status of the service, including
     external services or components.
+  - /loglevel: Returns (GET) and changes (PUT) the global and per-component
+    log levels at runtime, optionally restoring them after a timeout. This
+    route must be enabled explicitly with WithEnableDefaultRoutes.
+
+The server supports HTTP/1.1 and HTTP/2 over TLS by default. Unencrypted
+HTTP/2 (h2c) and an additional HTTP/3 (QUIC) listener can be enabled with the
+WithH2C and WithHTTP3 options.
+
+TLS certificates can be rotated without restarting the server using a
+TLSCertProvider, and clients can be authenticated with their TLS certificates
+(mTLS) via WithTLSClientCAs. The verified client identity is available to the
+handlers via TLSClientIdentityFromContext.
+
+Long-lived streaming connections are supported via the SSEWriter (Server-Sent
+Events) and the WebSocketHandler. The routes using them should set
+DisableTimeout, and the open streams are closed on Shutdown.
+
+Additional named listeners (TCP addresses or unix domain sockets) can be served
+by the same server via WithListeners. Each route can be assigned to one or more
+listeners with Route.Listeners, and the default routes can be moved to a
+separate admin port with WithDefaultRoutesListener.
+
+Routes sharing a path prefix, middleware and settings can be defined with a
+RouteGroup, including path-based API versions (PathVersionGroup). Header-based
+API versioning is supported by HeaderVersionHandler. The default router is
+based on github.com/julienschmidt/httprouter, and WithServeMuxRouter switches
+to the standard http.ServeMux pattern routing (see ServeMuxRouter).
+
+The OpenAPI document is generated from the Route Request and Response types,
+using the "json" tags for the property names and the "validate" tags for the
+constraints. The OpenAPIRoute must be enabled explicitly with
+WithEnableDefaultRoutes. WithOpenAPIUI additionally serves a Swagger UI or
+Redoc page at /openapi.
 
 For a usage example, refer to the examples/service/internal/cli/bind.go file.
 */
@@ -17,12 +51,14 @@ package httpserver
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
 
 	"github.com/Vonage/gosrvlib/pkg/httputil"
 	"github.com/Vonage/gosrvlib/pkg/logging"
+	"github.com/quic-go/quic-go/http3"
 	"go.uber.org/zap"
 )
 
@@ -48,6 +84,14 @@ type HTTPServer struct {
 	httpServer *http.Server
 	listener   net.Listener
 	logger     *zap.Logger
+	streams    *streamTracker
+
+	// additional named listeners
+	listenerServers []*listenerServer
+
+	// optional HTTP/3 server
+	http3Server *http3.Server
+	packetConn  net.PacketConn
 }
 
 // Start configures and start a new HTTP server.
@@ -75,33 +119,90 @@ func New(ctx context.Context, binder Binder, opts ...Option) (*HTTPServer, error
 		}
 	}
 
+	err := cfg.validate()
+	if err != nil {
+		return nil, err
+	}
+
 	logger := logging.WithComponent(ctx, "httpserver").With(
 		zap.String("addr", cfg.serverAddr),
 	)
 
 	cfg.setRouter(ctx)
-	loadRoutes(ctx, logger, binder, cfg)
+	cfg.setTLSNextProtos()
+	cfg.setTLSClientAuth()
+
+	err = loadRoutes(ctx, logger, binder, cfg)
+	if err != nil {
+		return nil, err
+	}
 
 	listener, err := netListener(ctx, cfg.serverAddr, cfg.tlsConfig)
 	if err != nil {
 		return nil, err
 	}
 
-	return &HTTPServer{
-			cfg: cfg,
-			ctx: ctx,
-			httpServer: &http.Server{
-				Addr:              cfg.serverAddr,
-				Handler:           cfg.router,
-				ReadHeaderTimeout: cfg.serverReadHeaderTimeout,
-				ReadTimeout:       cfg.serverReadTimeout,
-				TLSConfig:         cfg.tlsConfig,
-				WriteTimeout:      cfg.serverWriteTimeout,
-			},
-			listener: listener,
-			logger:   logger,
+	streams := newStreamTracker()
+	handler := cfg.rootHandler(cfg.router)
+
+	h := &HTTPServer{
+		cfg:        cfg,
+		ctx:        ctx,
+		httpServer: cfg.newHTTPServer(cfg.serverAddr, handler, cfg.tlsConfig, streams),
+		listener:   listener,
+		logger:     logger,
+		streams:    streams,
+	}
+
+	if cfg.enableHTTP3 {
+		h.http3Server, h.packetConn, err = newHTTP3Server(ctx, cfg, handler, streams)
+		if err != nil {
+			_ = listener.Close()
+			return nil, err
+		}
+
+		h.httpServer.Handler = altSvcHandler(h.http3Server, handler)
+	}
+
+	h.listenerServers, err = newListenerServers(ctx, cfg, logger, streams)
+	if err != nil {
+		_ = listener.Close()
+
+		if h.packetConn != nil {
+			_ = h.packetConn.Close()
+		}
+
+		return nil, err
+	}
+
+	return h, nil
+}
+
+// newHTTPServer returns a new http.Server configured with the common settings.
+func (c *config) newHTTPServer(addr string, handler http.Handler, tlsConfig *tls.Config, streams *streamTracker) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: c.serverReadHeaderTimeout,
+		ReadTimeout:       c.serverReadTimeout,
+		TLSConfig:         tlsConfig,
+		WriteTimeout:      c.serverWriteTimeout,
+		IdleTimeout:       c.serverIdleTimeout,
+		Protocols:         c.protocols(),
+		HTTP2:             c.http2Config,
+		BaseContext: func(_ net.Listener) context.Context {
+			return withStreamTracker(context.Background(), streams)
 		},
-		nil
+	}
+}
+
+// rootHandler wraps the router with the handlers common to all listeners.
+func (c *config) rootHandler(router http.Handler) http.Handler {
+	if c.isTLSClientAuthEnabled() {
+		return tlsClientIdentityHandler(router)
+	}
+
+	return router
 }
 
 // StartServerCtx starts the current server and return without blocking.
@@ -124,11 +225,27 @@ func (h *HTTPServer) StartServerCtx(ctx context.Context) {
 		_ = h.Shutdown(shutdownCtx) //nolint:contextcheck
 	}()
 
+	if h.cfg.tlsCertProvider != nil {
+		h.cfg.tlsCertProvider.Start(ctx)
+	}
+
 	// start server
 	go func() {
 		h.serve()
 	}()
 
+	if h.http3Server != nil {
+		go func() {
+			h.serveHTTP3()
+		}()
+	}
+
+	for _, ls := range h.listenerServers {
+		go func() {
+			ls.serve()
+		}()
+	}
+
 	h.cfg.shutdownWaitGroup.Add(1)
 
 	h.logger.Info("listening for http requests")
@@ -140,11 +257,24 @@ func (h *HTTPServer) StartServer() {
 }
 
 // Shutdown gracefully shuts down the server without interrupting any active connections.
+// The long-lived streams (SSE and WebSocket) are notified to close.
 // Wraps the standard net/http/Server_Shutdown method.
 func (h *HTTPServer) Shutdown(ctx context.Context) error {
 	h.logger.Debug("shutting down http server")
 
-	err := h.httpServer.Shutdown(ctx)
+	h.streams.shutdown()
+
+	if h.cfg.tlsCertProvider != nil {
+		h.cfg.tlsCertProvider.Stop()
+	}
+
+	err := errors.Join(
+		h.httpServer.Shutdown(ctx),
+		h.shutdownHTTP3(ctx),
+		h.shutdownListeners(ctx),
+		h.streams.wait(ctx),
+	)
+
 	h.cfg.shutdownWaitGroup.Add(-1)
 
 	h.logger.Debug("http server shutdown complete", zap.Error(err))
@@ -162,28 +292,38 @@ func (h *HTTPServer) serve() {
 	h.logger.Error("unexpected http server failure", zap.Error(err))
 }
 
-func netListener(ctx context.Context, serverAddr string, tlsConfig *tls.Config) (net.Listener, error) {
-	var (
-		ls  net.Listener
-		err error
-	)
+func (h *HTTPServer) serveHTTP3() {
+	err := h.http3Server.Serve(h.packetConn)
+	if err == http.ErrServerClosed {
+		h.logger.Debug("closed http3 server")
+		return
+	}
 
-	if tlsConfig == nil {
-		var lc net.ListenConfig
+	h.logger.Error("unexpected http3 server failure", zap.Error(err))
+}
 
-		ls, err = lc.Listen(ctx, "tcp", serverAddr)
-	} else {
-		ls, err = tls.Listen("tcp", serverAddr, tlsConfig)
+func (h *HTTPServer) shutdownHTTP3(ctx context.Context) error {
+	if h.http3Server == nil {
+		return nil
 	}
 
-	if err != nil {
-		return nil, fmt.Errorf("failed creating the http server address listener: %w", err)
+	return errors.Join(
+		h.http3Server.Shutdown(ctx),
+		h.packetConn.Close(),
+	)
+}
+
+func (h *HTTPServer) shutdownListeners(ctx context.Context) error {
+	errs := make([]error, 0, len(h.listenerServers))
+
+	for _, ls := range h.listenerServers {
+		errs = append(errs, ls.httpServer.Shutdown(ctx))
 	}
 
-	return ls, nil
+	return errors.Join(errs...)
 }
 
-func loadRoutes(ctx context.Context, l *zap.Logger, binder Binder, cfg *config) {
+func loadRoutes(ctx context.Context, l *zap.Logger, binder Binder, cfg *config) error {
 	l.Debug("loading default routes")
 
 	routes := newDefaultRoutes(cfg)
@@ -194,13 +334,19 @@ func loadRoutes(ctx context.Context, l *zap.Logger, binder Binder, cfg *config)
 
 	routes = append(routes, customRoutes...)
 
+	for _, r := range routes {
+		if err := cfg.validateListenerNames(r.Listeners...); err != nil {
+			return fmt.Errorf("invalid listener for the route %s %s: %w", r.Method, r.Path, err)
+		}
+	}
+
 	l.Debug("applying routes")
 
 	for _, r := range routes {
-		l.Debug("binding route", zap.String("path", r.Path))
+		l.Debug("binding route", zap.String("path", r.Path), zap.Strings("listeners", routeListeners(r)))
 
 		// Add default and custom middleware functions
-		middleware := cfg.commonMiddleware(r.DisableLogger, r.Timeout)
+		middleware := cfg.commonMiddleware(r.DisableLogger, r.DisableTimeout, r.Timeout)
 		middleware = append(middleware, r.Middleware...)
 
 		args := MiddlewareArgs{
@@ -214,29 +360,75 @@ func loadRoutes(ctx context.Context, l *zap.Logger, binder Binder, cfg *config)
 
 		handler := ApplyMiddleware(args, r.Handler, middleware...)
 
-		cfg.router.Handler(r.Method, r.Path, handler)
+		err := bindRoute(cfg, routeListeners(r), r.Method, r.Path, handler)
+		if err != nil {
+			return err
+		}
 	}
 
 	// attach route index if enabled
 	if cfg.isIndexRouteEnabled() {
 		l.Debug("enabling route index handler")
 
-		_, disableLogger := cfg.disableDefaultRouteLogger[IndexRoute]
-		middleware := cfg.commonMiddleware(disableLogger, 0)
-
-		args := MiddlewareArgs{
-			Method:            http.MethodGet,
-			Path:              indexPath,
-			Description:       "Index",
-			TraceIDHeaderName: cfg.traceIDHeaderName,
-			RedactFunc:        cfg.redactFn,
-			Logger:            l,
+		err := bindDefaultRoute(l, cfg, IndexRoute, indexPath, "Index", cfg.indexHandlerFunc(routes))
+		if err != nil {
+			return err
 		}
+	}
+
+	// attach OpenAPI document if enabled
+	if cfg.isOpenAPIRouteEnabled() {
+		l.Debug("enabling OpenAPI handler")
+
+		return bindOpenAPIRoutes(l, cfg, routes)
+	}
+
+	return nil
+}
+
+// bindOpenAPIRoutes attaches the OpenAPI document and the optional web page.
+func bindOpenAPIRoutes(l *zap.Logger, cfg *config, routes []Route) error {
+	doc := newOpenAPIDocument(cfg.openAPIInfo, routes)
+
+	err := bindDefaultRoute(l, cfg, OpenAPIRoute, openAPIDocPath, "Returns the OpenAPI document.", openAPIHandler(doc))
+	if err != nil || cfg.openAPIUI == OpenAPIUINone {
+		return err
+	}
 
-		handler := ApplyMiddleware(args, cfg.indexHandlerFunc(routes), middleware...)
+	return bindDefaultRoute(l, cfg, OpenAPIRoute, openAPIUIPath, "OpenAPI documentation.", openAPIUIHandler(cfg.openAPIUI, cfg.openAPIInfo.Title, openAPIDocPath, cfg.openAPIUIAssetsURL))
+}
 
-		cfg.router.Handler(args.Method, args.Path, handler)
+// bindDefaultRoute attaches a default GET route that is not listed in the routes.
+func bindDefaultRoute(l *zap.Logger, cfg *config, id DefaultRoute, path, description string, handler http.Handler) error {
+	_, disableLogger := cfg.disableDefaultRouteLogger[id]
+	middleware := cfg.commonMiddleware(disableLogger, false, 0)
+
+	args := MiddlewareArgs{
+		Method:            http.MethodGet,
+		Path:              path,
+		Description:       description,
+		TraceIDHeaderName: cfg.traceIDHeaderName,
+		RedactFunc:        cfg.redactFn,
+		Logger:            l,
 	}
+
+	listeners := routeListeners(Route{Listeners: cfg.defaultRouteListeners(id)})
+
+	return bindRoute(cfg, listeners, args.Method, args.Path, ApplyMiddleware(args, handler, middleware...))
+}
+
+// bindRoute attaches the handler to the routers of the specified listeners.
+func bindRoute(cfg *config, listeners []string, method, path string, handler http.Handler) error {
+	for _, name := range listeners {
+		router, err := cfg.routerFor(name)
+		if err != nil {
+			return fmt.Errorf("failed binding the route %s %s: %w", method, path, err)
+		}
+
+		router.Handler(method, path, handler)
+	}
+
+	return nil
 }
 
 func defaultIndexHandler(routes []Route) http.HandlerFunc {
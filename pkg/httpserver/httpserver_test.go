@@ -248,7 +248,8 @@ func Test_customMiddlewares(t *testing.T) {
 	l := zap.NewNop()
 	cfg := defaultConfig()
 	cfg.setRouter(ctx)
-	loadRoutes(ctx, l, binder, cfg)
+	err := loadRoutes(ctx, l, binder, cfg)
+	require.NoError(t, err)
 
 	go func() {
 		select {
@@ -0,0 +1,198 @@
+package httpserver
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"syscall"
+
+	"go.uber.org/zap"
+)
+
+// DefaultListener is the name of the main server listener bound to the address set with WithServerAddr.
+const DefaultListener = "default"
+
+// unixAddrPrefix is the address prefix used to listen on a unix domain socket.
+const unixAddrPrefix = "unix:"
+
+// Listener defines an additional listener served by the same HTTPServer.
+type Listener struct {
+	// Name is the unique listener name used to assign the routes (see Route.Listeners).
+	Name string
+
+	// Addr is the TCP address in the "host:port" format, or the path of a unix
+	// domain socket prefixed by "unix:" (e.g. "unix:/run/service.sock").
+	Addr string
+
+	// TLS enables TLS on this listener with the server TLS configuration (see WithTLSCertData).
+	TLS bool
+}
+
+func (l Listener) validate() error {
+	if l.Name == "" || l.Name == DefaultListener {
+		return fmt.Errorf("invalid listener name: %q", l.Name)
+	}
+
+	if path, ok := strings.CutPrefix(l.Addr, unixAddrPrefix); ok {
+		if path == "" {
+			return fmt.Errorf("invalid unix socket listener address: %s", l.Addr)
+		}
+
+		return nil
+	}
+
+	return validateAddr(l.Addr)
+}
+
+// listenerServer is an additional HTTP server bound to a named listener.
+type listenerServer struct {
+	httpServer *http.Server
+	listener   net.Listener
+	logger     *zap.Logger
+}
+
+func (s *listenerServer) serve() {
+	err := s.httpServer.Serve(s.listener)
+	if err == http.ErrServerClosed {
+		s.logger.Debug("closed http server listener")
+		return
+	}
+
+	s.logger.Error("unexpected http server listener failure", zap.Error(err))
+}
+
+// newListenerServers creates the servers for the additional named listeners.
+// On error, the listeners already created are closed.
+func newListenerServers(ctx context.Context, cfg *config, logger *zap.Logger, streams *streamTracker) ([]*listenerServer, error) {
+	servers := make([]*listenerServer, 0, len(cfg.listeners))
+
+	for _, l := range cfg.listeners {
+		var tlsConfig *tls.Config
+
+		if l.TLS {
+			tlsConfig = cfg.tlsConfig
+		}
+
+		ls, err := netListener(ctx, l.Addr, tlsConfig)
+		if err != nil {
+			for _, s := range servers {
+				_ = s.listener.Close()
+			}
+
+			return nil, fmt.Errorf("failed creating the %q listener: %w", l.Name, err)
+		}
+
+		servers = append(servers, &listenerServer{
+			httpServer: cfg.newHTTPServer(l.Addr, cfg.rootHandler(cfg.listenerRouters[l.Name]), tlsConfig, streams),
+			listener:   ls,
+			logger:     logger.With(zap.String("listener", l.Name), zap.String("listener_addr", l.Addr)),
+		})
+	}
+
+	return servers, nil
+}
+
+// routeListeners returns the names of the listeners the route is assigned to.
+func routeListeners(r Route) []string {
+	if len(r.Listeners) == 0 {
+		return []string{DefaultListener}
+	}
+
+	return r.Listeners
+}
+
+// routerFor returns the router associated with the named listener.
+func (c *config) routerFor(name string) (httpRouter, error) {
+	if name == DefaultListener {
+		return c.router, nil
+	}
+
+	r, ok := c.listenerRouters[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown listener: %q", name)
+	}
+
+	return r, nil
+}
+
+// validateListenerNames checks that the named listeners are defined (see WithListeners).
+func (c *config) validateListenerNames(names ...string) error {
+	for _, name := range names {
+		if name != DefaultListener && !slices.ContainsFunc(c.listeners, func(l Listener) bool { return l.Name == name }) {
+			return fmt.Errorf("unknown listener: %q", name)
+		}
+	}
+
+	return nil
+}
+
+// netListener creates a TCP or unix domain socket listener, optionally with TLS.
+func netListener(ctx context.Context, addr string, tlsConfig *tls.Config) (net.Listener, error) {
+	network := "tcp"
+
+	if path, ok := strings.CutPrefix(addr, unixAddrPrefix); ok {
+		network = "unix"
+		addr = path
+
+		err := removeStaleSocket(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var lc net.ListenConfig
+
+	ls, err := lc.Listen(ctx, network, addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating the http server address listener: %w", err)
+	}
+
+	if tlsConfig != nil {
+		ls = tls.NewListener(ls, tlsConfig)
+	}
+
+	return ls, nil
+}
+
+// removeStaleSocket removes a unix socket file left by a previous process.
+// The socket file is removed only if no process is accepting connections on it.
+func removeStaleSocket(ctx context.Context, path string) error {
+	fi, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed checking the unix socket file: %w", err)
+	}
+
+	if fi.Mode().Type() != fs.ModeSocket {
+		return fmt.Errorf("the unix socket address is not a socket file: %s", path)
+	}
+
+	var d net.Dialer
+
+	conn, err := d.DialContext(ctx, "unix", path)
+	if err == nil {
+		_ = conn.Close()
+		return fmt.Errorf("the unix socket address is already in use: %s", path)
+	}
+
+	if !errors.Is(err, syscall.ECONNREFUSED) {
+		return fmt.Errorf("failed checking the unix socket: %w", err)
+	}
+
+	err = os.Remove(path)
+	if err != nil {
+		return fmt.Errorf("failed removing the stale unix socket file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,244 @@
+package httpserver
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/httputil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListener_validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		l       Listener
+		wantErr bool
+	}{
+		{
+			name: "tcp",
+			l:    Listener{Name: "admin", Addr: ":8081"},
+		},
+		{
+			name: "unix",
+			l:    Listener{Name: "local", Addr: "unix:/run/test.sock"},
+		},
+		{
+			name:    "empty name",
+			l:       Listener{Addr: ":8081"},
+			wantErr: true,
+		},
+		{
+			name:    "default name",
+			l:       Listener{Name: DefaultListener, Addr: ":8081"},
+			wantErr: true,
+		},
+		{
+			name:    "empty unix path",
+			l:       Listener{Name: "local", Addr: "unix:"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid address",
+			l:       Listener{Name: "admin", Addr: "invalid"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.l.validate()
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func Test_routeListeners(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []string{DefaultListener}, routeListeners(Route{}))
+	require.Equal(t, []string{"a", "b"}, routeListeners(Route{Listeners: []string{"a", "b"}}))
+}
+
+func Test_config_routerFor(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultConfig()
+
+	err := WithListeners(Listener{Name: "admin", Addr: ":8081"})(cfg)
+	require.NoError(t, err)
+
+	cfg.setRouter(t.Context())
+
+	r, err := cfg.routerFor(DefaultListener)
+	require.NoError(t, err)
+	require.Equal(t, cfg.router, r)
+
+	r, err = cfg.routerFor("admin")
+	require.NoError(t, err)
+	require.Equal(t, cfg.listenerRouters["admin"], r)
+
+	r, err = cfg.routerFor("unknown")
+	require.Error(t, err)
+	require.Nil(t, r)
+}
+
+func Test_netListener_unix(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.sock")
+
+	ls, err := netListener(t.Context(), unixAddrPrefix+path, nil)
+	require.NoError(t, err)
+	require.Equal(t, "unix", ls.Addr().Network())
+
+	// leave a stale socket file behind
+	ls.(*net.UnixListener).SetUnlinkOnClose(false)
+	require.NoError(t, ls.Close())
+
+	ls, err = netListener(t.Context(), unixAddrPrefix+path, nil)
+	require.NoError(t, err)
+
+	// a socket with a live listener is never removed
+	_, err = netListener(t.Context(), unixAddrPrefix+path, nil)
+	require.ErrorContains(t, err, "already in use")
+
+	require.NoError(t, ls.Close())
+
+	// regular files are never removed
+	file := filepath.Join(dir, "file")
+	require.NoError(t, os.WriteFile(file, []byte("data"), 0o600))
+
+	_, err = netListener(t.Context(), unixAddrPrefix+file, nil)
+	require.Error(t, err)
+}
+
+type listenersBinder struct{}
+
+func (b *listenersBinder) BindHTTP(_ context.Context) []Route {
+	return []Route{
+		{
+			Method: http.MethodGet,
+			Path:   "/public",
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				httputil.SendStatus(r.Context(), w, http.StatusOK)
+			},
+		},
+		{
+			Method:    http.MethodGet,
+			Path:      "/internal",
+			Listeners: []string{"admin", "local"},
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				httputil.SendStatus(r.Context(), w, http.StatusOK)
+			},
+		},
+	}
+}
+
+func TestNew_listeners(t *testing.T) {
+	t.Parallel()
+
+	sock := filepath.Join(t.TempDir(), "test.sock")
+
+	shutdownWG := &sync.WaitGroup{}
+	shutdownSG := make(chan struct{})
+
+	h, err := New(t.Context(), &listenersBinder{},
+		WithServerAddr(":33124"),
+		WithListeners(
+			Listener{Name: "admin", Addr: ":33125"},
+			Listener{Name: "local", Addr: unixAddrPrefix + sock},
+		),
+		WithEnableAllDefaultRoutes(),
+		WithDefaultRoutesListener("admin"),
+		WithShutdownWaitGroup(shutdownWG),
+		WithShutdownSignalChan(shutdownSG),
+	)
+	require.NoError(t, err)
+
+	h.StartServerCtx(t.Context())
+
+	unixClient := &http.Client{
+		Timeout: 1 * time.Second,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", sock)
+			},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		client *http.Client
+		url    string
+		want   int
+	}{
+		{name: "public on default", url: "http://127.0.0.1:33124/public", want: http.StatusOK},
+		{name: "internal not on default", url: "http://127.0.0.1:33124/internal", want: http.StatusNotFound},
+		{name: "ping not on default", url: "http://127.0.0.1:33124/ping", want: http.StatusNotFound},
+		{name: "index not on default", url: "http://127.0.0.1:33124/", want: http.StatusNotFound},
+		{name: "public not on admin", url: "http://127.0.0.1:33125/public", want: http.StatusNotFound},
+		{name: "internal on admin", url: "http://127.0.0.1:33125/internal", want: http.StatusOK},
+		{name: "ping on admin", url: "http://127.0.0.1:33125/ping", want: http.StatusOK},
+		{name: "index on admin", url: "http://127.0.0.1:33125/", want: http.StatusOK},
+		{name: "internal on unix", client: unixClient, url: "http://unix/internal", want: http.StatusOK},
+		{name: "ping not on unix", client: unixClient, url: "http://unix/ping", want: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		client := tt.client
+		if client == nil {
+			client = &http.Client{Timeout: 1 * time.Second}
+		}
+
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, tt.url, nil)
+		require.NoError(t, err, tt.name)
+
+		resp, err := client.Do(req)
+		require.NoError(t, err, tt.name)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, tt.want, resp.StatusCode, tt.name)
+	}
+
+	close(shutdownSG)
+	shutdownWG.Wait()
+
+	_, err = os.Stat(sock)
+	require.Error(t, err, "the unix socket file should be removed on shutdown")
+}
+
+func TestNew_listenersErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(t.Context(), &listenersBinder{}, WithServerAddr(":33126"))
+	require.ErrorContains(t, err, "unknown listener")
+
+	_, err = New(t.Context(), NopBinder(),
+		WithServerAddr(":33126"),
+		WithEnableDefaultRoutes(PingRoute),
+		WithDefaultRoutesListener("admin"),
+	)
+	require.ErrorContains(t, err, "unknown listener")
+
+	_, err = New(t.Context(), NopBinder(),
+		WithServerAddr(":33126"),
+		WithListeners(Listener{Name: "admin", Addr: unixAddrPrefix + t.TempDir()}),
+	)
+	require.Error(t, err, "invalid unix socket")
+}
@@ -0,0 +1,100 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/httputil"
+	"github.com/Vonage/gosrvlib/pkg/logging"
+	"go.uber.org/zap"
+)
+
+// maxLogLevelRequestSize is the maximum size of the log level request body.
+const maxLogLevelRequestSize = 1 << 10
+
+// LogLevelRequest is the request body to change the log level (see LogLevelRoute).
+type LogLevelRequest struct {
+	// Level is the new log level (e.g. "debug", "info", "warning", "error").
+	// An empty level removes the override of the specified component.
+	Level string `json:"level"`
+
+	// Component is the optional name of the component to override (see logging.WithComponent).
+	// If empty, the global log level is changed.
+	Component string `json:"component,omitempty"`
+
+	// Timeout is the optional duration (e.g. "15m") after which the previous level is restored.
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// logLevelGetHandler returns the handler returning the current log levels.
+func logLevelGetHandler(c *logging.LevelController) http.HandlerFunc {
+	if c == nil {
+		return notImplementedHandler
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		httputil.SendJSON(r.Context(), w, http.StatusOK, c.State())
+	}
+}
+
+// logLevelSetHandler returns the handler changing the log level and returning the new log levels.
+func logLevelSetHandler(c *logging.LevelController) http.HandlerFunc {
+	if c == nil {
+		return notImplementedHandler
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := &LogLevelRequest{}
+
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxLogLevelRequestSize)).Decode(req); err != nil {
+			httputil.SendStatus(r.Context(), w, http.StatusBadRequest)
+			return
+		}
+
+		if err := setLogLevel(c, req); err != nil {
+			httputil.SendStatus(r.Context(), w, http.StatusBadRequest)
+			return
+		}
+
+		logging.FromContext(r.Context()).Info(
+			"log level changed",
+			zap.String("level", req.Level),
+			zap.String("target_component", req.Component),
+			zap.String("timeout", req.Timeout),
+		)
+
+		httputil.SendJSON(r.Context(), w, http.StatusOK, c.State())
+	}
+}
+
+func setLogLevel(c *logging.LevelController, req *LogLevelRequest) error {
+	var timeout time.Duration
+
+	if req.Timeout != "" {
+		d, err := time.ParseDuration(req.Timeout)
+		if err != nil || d < 0 {
+			return fmt.Errorf("invalid timeout %q", req.Timeout)
+		}
+
+		timeout = d
+	}
+
+	if req.Level == "" && req.Component != "" {
+		c.RemoveComponentLevel(req.Component)
+		return nil
+	}
+
+	level, err := logging.ParseLevel(req.Level)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	if req.Component == "" {
+		c.SetLevel(level, timeout)
+		return nil
+	}
+
+	return c.SetComponentLevel(req.Component, level, timeout) //nolint:wrapcheck
+}
@@ -0,0 +1,137 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/logging"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestLogLevelHandlers(t *testing.T) {
+	t.Parallel()
+
+	c := logging.NewLevelController()
+	c.SetLevel(zap.InfoLevel, 0)
+
+	getHandler := logLevelGetHandler(c)
+	setHandler := logLevelSetHandler(c)
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantLevel  string
+		wantComps  map[string]string
+		wantRevert bool
+	}{
+		{
+			name:       "invalid body",
+			body:       `{`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid level",
+			body:       `{"level":"invalid"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid timeout",
+			body:       `{"level":"debug","timeout":"invalid"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "negative timeout",
+			body:       `{"level":"debug","timeout":"-1m"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "global level",
+			body:       `{"level":"warning"}`,
+			wantStatus: http.StatusOK,
+			wantLevel:  "warn",
+			wantComps:  map[string]string{},
+		},
+		{
+			name:       "component level with timeout",
+			body:       `{"level":"debug","component":"db","timeout":"1h"}`,
+			wantStatus: http.StatusOK,
+			wantLevel:  "warn",
+			wantComps:  map[string]string{"db": "debug"},
+		},
+		{
+			name:       "global level with timeout",
+			body:       `{"level":"error","timeout":"1h"}`,
+			wantStatus: http.StatusOK,
+			wantLevel:  "error",
+			wantComps:  map[string]string{"db": "debug"},
+			wantRevert: true,
+		},
+		{
+			name:       "remove component level",
+			body:       `{"component":"db"}`,
+			wantStatus: http.StatusOK,
+			wantLevel:  "error",
+			wantComps:  map[string]string{},
+			wantRevert: true,
+		},
+	}
+
+	for _, tt := range tests {
+		// the test cases share the same controller and are executed in order
+		req := httptest.NewRequestWithContext(t.Context(), http.MethodPut, logLevelHandlerPath, strings.NewReader(tt.body))
+		rr := httptest.NewRecorder()
+
+		setHandler(rr, req)
+
+		require.Equal(t, tt.wantStatus, rr.Code, tt.name)
+
+		if tt.wantStatus != http.StatusOK {
+			continue
+		}
+
+		req = httptest.NewRequestWithContext(t.Context(), http.MethodGet, logLevelHandlerPath, nil)
+		rr = httptest.NewRecorder()
+
+		getHandler(rr, req)
+
+		require.Equal(t, http.StatusOK, rr.Code, tt.name)
+
+		state := &logging.LevelState{}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), state), tt.name)
+		require.Equal(t, tt.wantLevel, state.Level, tt.name)
+		require.Equal(t, tt.wantRevert, state.RevertAt != nil, tt.name)
+
+		comps := map[string]string{}
+		for k, v := range state.Components {
+			comps[k] = v.Level
+		}
+
+		require.Equal(t, tt.wantComps, comps, tt.name)
+	}
+
+	// the level before the first temporary change is restored
+	c.SetLevel(zap.DebugLevel, time.Millisecond)
+
+	require.Eventually(t, func() bool {
+		return c.AtomicLevel().Level() == zap.WarnLevel
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestLogLevelHandlers_notImplemented(t *testing.T) {
+	t.Parallel()
+
+	for _, h := range []http.HandlerFunc{logLevelGetHandler(nil), logLevelSetHandler(nil)} {
+		req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, logLevelHandlerPath, nil)
+		rr := httptest.NewRecorder()
+
+		h(rr, req)
+
+		require.Equal(t, http.StatusNotImplemented, rr.Code)
+	}
+}
@@ -0,0 +1,254 @@
+package httpserver
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+
+	"github.com/Vonage/gosrvlib/pkg/httputil"
+)
+
+const openAPIVersion = "3.1.0"
+
+// OpenAPIUI is the type of the web page used to render the OpenAPI document.
+type OpenAPIUI string
+
+const (
+	// OpenAPIUINone disables the OpenAPI web page.
+	OpenAPIUINone OpenAPIUI = ""
+
+	// OpenAPIUISwagger enables the Swagger UI page.
+	OpenAPIUISwagger OpenAPIUI = "swagger"
+
+	// OpenAPIUIRedoc enables the Redoc page.
+	OpenAPIUIRedoc OpenAPIUI = "redoc"
+)
+
+// OpenAPIInfo contains the general information of the OpenAPI document.
+type OpenAPIInfo struct {
+	// Title is the title of the API.
+	Title string `json:"title"`
+
+	// Version is the version of the API.
+	Version string `json:"version"`
+
+	// Description is the description of the API.
+	Description string `json:"description,omitempty"`
+}
+
+type openAPIDocument struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       OpenAPIInfo                             `json:"info"`
+	Paths      map[string]map[string]*openAPIOperation `json:"paths"`
+	Components *openAPIComponents                      `json:"components,omitempty"`
+}
+
+type openAPIComponents struct {
+	Schemas map[string]*jsonSchema `json:"schemas,omitempty"`
+}
+
+type openAPIOperation struct {
+	Summary     string                      `json:"summary,omitempty"`
+	OperationID string                      `json:"operationId"`
+	Parameters  []*openAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *openAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*openAPIResponse `json:"responses"`
+}
+
+type openAPIParameter struct {
+	Name     string      `json:"name"`
+	In       string      `json:"in"`
+	Required bool        `json:"required"`
+	Schema   *jsonSchema `json:"schema"`
+}
+
+type openAPIRequestBody struct {
+	Required bool                         `json:"required"`
+	Content  map[string]*openAPIMediaType `json:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                       `json:"description"`
+	Content     map[string]*openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPIMediaType struct {
+	Schema *jsonSchema `json:"schema"`
+}
+
+// newOpenAPIDocument returns the OpenAPI document describing the routes.
+func newOpenAPIDocument(info OpenAPIInfo, routes []Route) *openAPIDocument {
+	g := newSchemaGenerator()
+
+	doc := &openAPIDocument{
+		OpenAPI: openAPIVersion,
+		Info:    info,
+		Paths:   make(map[string]map[string]*openAPIOperation),
+	}
+
+	for _, r := range routes {
+		path, params := openAPIPath(r.Path)
+
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(map[string]*openAPIOperation)
+		}
+
+		doc.Paths[path][strings.ToLower(r.Method)] = newOpenAPIOperation(g, r, path, params)
+	}
+
+	if len(g.components) > 0 {
+		doc.Components = &openAPIComponents{Schemas: g.components}
+	}
+
+	return doc
+}
+
+func newOpenAPIOperation(g *schemaGenerator, r Route, path string, params []string) *openAPIOperation {
+	op := &openAPIOperation{
+		Summary:     r.Description,
+		OperationID: openAPIOperationID(r.Method, path),
+		Responses:   make(map[string]*openAPIResponse, 1),
+	}
+
+	for _, p := range params {
+		op.Parameters = append(op.Parameters, &openAPIParameter{
+			Name:     p,
+			In:       "path",
+			Required: true,
+			Schema:   &jsonSchema{Type: "string"},
+		})
+	}
+
+	if r.Request != nil {
+		op.RequestBody = &openAPIRequestBody{
+			Required: true,
+			Content:  jsonContent(g.schemaOf(r.Request)),
+		}
+	}
+
+	res := &openAPIResponse{Description: http.StatusText(http.StatusOK)}
+
+	if r.Response != nil {
+		res.Content = jsonContent(g.schemaOf(r.Response))
+	}
+
+	op.Responses[fmt.Sprint(http.StatusOK)] = res
+
+	return op
+}
+
+func jsonContent(s *jsonSchema) map[string]*openAPIMediaType {
+	return map[string]*openAPIMediaType{httputil.MimeTypeJSON: {Schema: s}}
+}
+
+// openAPIPath converts the router path syntax to the OpenAPI one and returns the path parameters.
+func openAPIPath(path string) (string, []string) {
+	segments := strings.Split(path, "/")
+	params := make([]string, 0, len(segments))
+
+	for i, s := range segments {
+		var name string
+
+		switch {
+		case strings.HasPrefix(s, ":"):
+			name = s[1:]
+		case strings.HasPrefix(s, "*"):
+			name = s[1:]
+		case strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") && s != "{$}":
+			name = strings.TrimSuffix(s[1:len(s)-1], "...")
+		case s == "{$}":
+			segments[i] = ""
+			continue
+		default:
+			continue
+		}
+
+		segments[i] = "{" + name + "}"
+		params = append(params, name)
+	}
+
+	return strings.Join(segments, "/"), params
+}
+
+// openAPIOperationID returns an operation identifier derived from the method and path (e.g. "getUsersId").
+func openAPIOperationID(method, path string) string {
+	var b strings.Builder
+
+	b.WriteString(strings.ToLower(method))
+
+	for w := range strings.FieldsFuncSeq(path, func(r rune) bool {
+		return !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9')
+	}) {
+		b.WriteString(strings.ToUpper(w[:1]) + w[1:])
+	}
+
+	return b.String()
+}
+
+// openAPIHandler returns the handler serving the OpenAPI document.
+func openAPIHandler(doc *openAPIDocument) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		httputil.SendJSON(r.Context(), w, http.StatusOK, doc)
+	}
+}
+
+const (
+	// DefaultSwaggerUIAssetsURL is the default base URL of the Swagger UI assets (pinned version).
+	DefaultSwaggerUIAssetsURL = "https://unpkg.com/swagger-ui-dist@5.17.14"
+
+	// DefaultRedocAssetsURL is the default base URL of the Redoc assets (pinned version).
+	DefaultRedocAssetsURL = "https://cdn.redoc.ly/redoc/v2.1.5/bundles"
+)
+
+const swaggerUITemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>%[1]s</title>
+<link rel="stylesheet" href="%[3]s/swagger-ui.css">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="%[3]s/swagger-ui-bundle.js"></script>
+<script>window.ui = SwaggerUIBundle({url: "%[2]s", dom_id: "#swagger-ui"});</script>
+</body>
+</html>
+`
+
+const redocTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>%[1]s</title>
+</head>
+<body>
+<redoc spec-url="%[2]s"></redoc>
+<script src="%[3]s/redoc.standalone.js"></script>
+</body>
+</html>
+`
+
+// openAPIUIHandler returns the handler serving the web page that renders the OpenAPI document.
+// The assets are loaded from the assetsURL, or from the default pinned CDN URL if empty.
+func openAPIUIHandler(ui OpenAPIUI, title, specURL, assetsURL string) http.HandlerFunc {
+	tpl := swaggerUITemplate
+	defaultAssetsURL := DefaultSwaggerUIAssetsURL
+
+	if ui == OpenAPIUIRedoc {
+		tpl = redocTemplate
+		defaultAssetsURL = DefaultRedocAssetsURL
+	}
+
+	if assetsURL == "" {
+		assetsURL = defaultAssetsURL
+	}
+
+	page := fmt.Sprintf(tpl, html.EscapeString(title), html.EscapeString(specURL), html.EscapeString(assetsURL))
+
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set(httputil.HeaderContentType, "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(page))
+	}
+}
@@ -0,0 +1,251 @@
+package httpserver
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_openAPIPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path       string
+		wantPath   string
+		wantParams []string
+	}{
+		{path: "/", wantPath: "/", wantParams: []string{}},
+		{path: "/users/:id", wantPath: "/users/{id}", wantParams: []string{"id"}},
+		{path: "/pprof/*option", wantPath: "/pprof/{option}", wantParams: []string{"option"}},
+		{path: "/users/{id}/files/{path...}", wantPath: "/users/{id}/files/{path}", wantParams: []string{"id", "path"}},
+		{path: "/items/{$}", wantPath: "/items/", wantParams: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+
+			path, params := openAPIPath(tt.path)
+			require.Equal(t, tt.wantPath, path)
+			require.Equal(t, tt.wantParams, params)
+		})
+	}
+}
+
+func Test_openAPIOperationID(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "get", openAPIOperationID(http.MethodGet, "/"))
+	require.Equal(t, "postUsersIdItems", openAPIOperationID(http.MethodPost, "/users/{id}/items"))
+	require.Equal(t, "getOpenapiJson", openAPIOperationID(http.MethodGet, "/openapi.json"))
+}
+
+type testCreateUserRequest struct {
+	Name  string `json:"name" validate:"required,max=50"`
+	Email string `json:"email" validate:"required,email"`
+}
+
+type testUser struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func Test_newOpenAPIDocument(t *testing.T) {
+	t.Parallel()
+
+	routes := []Route{
+		{
+			Method:      http.MethodPost,
+			Path:        "/users",
+			Description: "Create a user.",
+			Request:     &testCreateUserRequest{},
+			Response:    &testUser{},
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/users/:id",
+			Description: "Get a user.",
+			Response:    &testUser{},
+		},
+		{
+			Method: http.MethodDelete,
+			Path:   "/users/:id",
+		},
+	}
+
+	doc := newOpenAPIDocument(OpenAPIInfo{Title: "Test", Version: "2.0.0"}, routes)
+
+	got, err := json.Marshal(doc)
+	require.NoError(t, err)
+
+	want := `{
+		"openapi": "3.1.0",
+		"info": {"title": "Test", "version": "2.0.0"},
+		"paths": {
+			"/users": {
+				"post": {
+					"summary": "Create a user.",
+					"operationId": "postUsers",
+					"requestBody": {
+						"required": true,
+						"content": {"application/json": {"schema": {"$ref": "#/components/schemas/testCreateUserRequest"}}}
+					},
+					"responses": {
+						"200": {
+							"description": "OK",
+							"content": {"application/json": {"schema": {"$ref": "#/components/schemas/testUser"}}}
+						}
+					}
+				}
+			},
+			"/users/{id}": {
+				"get": {
+					"summary": "Get a user.",
+					"operationId": "getUsersId",
+					"parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}],
+					"responses": {
+						"200": {
+							"description": "OK",
+							"content": {"application/json": {"schema": {"$ref": "#/components/schemas/testUser"}}}
+						}
+					}
+				},
+				"delete": {
+					"operationId": "deleteUsersId",
+					"parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}],
+					"responses": {"200": {"description": "OK"}}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"testCreateUserRequest": {
+					"type": "object",
+					"properties": {
+						"name": {"type": "string", "maxLength": 50},
+						"email": {"type": "string", "format": "email"}
+					},
+					"required": ["name", "email"]
+				},
+				"testUser": {
+					"type": "object",
+					"properties": {
+						"id": {"type": "string"},
+						"name": {"type": "string"}
+					}
+				}
+			}
+		}
+	}`
+
+	require.JSONEq(t, want, string(got))
+
+	doc = newOpenAPIDocument(OpenAPIInfo{Title: "Empty", Version: "1"}, nil)
+	require.Nil(t, doc.Components)
+	require.Empty(t, doc.Paths)
+}
+
+func Test_openAPIUIHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		ui        OpenAPIUI
+		assetsURL string
+		want      string
+	}{
+		{name: "swagger", ui: OpenAPIUISwagger, want: DefaultSwaggerUIAssetsURL + "/swagger-ui-bundle.js"},
+		{name: "redoc", ui: OpenAPIUIRedoc, want: DefaultRedocAssetsURL + "/redoc.standalone.js"},
+		{name: "custom assets", ui: OpenAPIUIRedoc, assetsURL: "/static/redoc", want: `src="/static/redoc/redoc.standalone.js"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			openAPIUIHandler(tt.ui, "<Test>", "/openapi.json", tt.assetsURL)(rr, httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/openapi", nil))
+
+			require.Equal(t, http.StatusOK, rr.Code)
+			require.Equal(t, "text/html; charset=utf-8", rr.Header().Get("Content-Type"))
+			require.Contains(t, rr.Body.String(), tt.want)
+			require.Contains(t, rr.Body.String(), "&lt;Test&gt;")
+			require.Contains(t, rr.Body.String(), "/openapi.json")
+		})
+	}
+}
+
+type openAPIBinder struct{}
+
+func (b *openAPIBinder) BindHTTP(_ context.Context) []Route {
+	return []Route{
+		{
+			Method:      http.MethodPost,
+			Path:        "/users",
+			Description: "Create a user.",
+			Request:     &testCreateUserRequest{},
+			Response:    &testUser{},
+			Handler:     func(_ http.ResponseWriter, _ *http.Request) {},
+		},
+	}
+}
+
+func TestNew_openAPI(t *testing.T) {
+	t.Parallel()
+
+	shutdownWG := &sync.WaitGroup{}
+	shutdownSG := make(chan struct{})
+
+	h, err := New(t.Context(), &openAPIBinder{},
+		WithServerAddr(":33128"),
+		WithEnableDefaultRoutes(OpenAPIRoute, PingRoute),
+		WithOpenAPIInfo(OpenAPIInfo{Title: "Users", Version: "3.0.0"}),
+		WithOpenAPIUI(OpenAPIUIRedoc),
+		WithShutdownWaitGroup(shutdownWG),
+		WithShutdownSignalChan(shutdownSG),
+	)
+	require.NoError(t, err)
+
+	h.StartServerCtx(t.Context())
+
+	client := &http.Client{Timeout: 2 * time.Second}
+
+	get := func(path string) (int, []byte) {
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://127.0.0.1:33128"+path, nil)
+		require.NoError(t, err)
+
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+
+		defer func() { _ = resp.Body.Close() }()
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		return resp.StatusCode, body
+	}
+
+	code, body := get("/openapi.json")
+	require.Equal(t, http.StatusOK, code)
+
+	doc := &openAPIDocument{}
+	require.NoError(t, json.Unmarshal(body, doc))
+	require.Equal(t, "Users", doc.Info.Title)
+	require.Contains(t, doc.Paths, "/users")
+	require.Contains(t, doc.Paths, "/ping")
+	require.Contains(t, doc.Components.Schemas, "testCreateUserRequest")
+
+	code, body = get("/openapi")
+	require.Equal(t, http.StatusOK, code)
+	require.Contains(t, string(body), "redoc")
+
+	close(shutdownSG)
+	shutdownWG.Wait()
+}
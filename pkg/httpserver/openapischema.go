@@ -0,0 +1,207 @@
+package httpserver
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	openAPIComponentsRef = "#/components/schemas/"
+	jsonTagName          = "json"
+	validateTagName      = "validate"
+)
+
+var (
+	timeType            = reflect.TypeFor[time.Time]()
+	invalidSchemaNameRx = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+)
+
+// jsonSchema is the subset of JSON Schema (draft 2020-12) used by the OpenAPI 3.1 documents.
+type jsonSchema struct {
+	Ref                  string                 `json:"$ref,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	Enum                 []any                  `json:"enum,omitempty"`
+	Minimum              *float64               `json:"minimum,omitempty"`
+	Maximum              *float64               `json:"maximum,omitempty"`
+	ExclusiveMinimum     *float64               `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     *float64               `json:"exclusiveMaximum,omitempty"`
+	MinLength            *int                   `json:"minLength,omitempty"`
+	MaxLength            *int                   `json:"maxLength,omitempty"`
+	MinItems             *int                   `json:"minItems,omitempty"`
+	MaxItems             *int                   `json:"maxItems,omitempty"`
+	Items                *jsonSchema            `json:"items,omitempty"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties *jsonSchema            `json:"additionalProperties,omitempty"`
+}
+
+// schemaGenerator generates the JSON schemas of Go types via reflection.
+// The named struct types are stored as reusable components.
+type schemaGenerator struct {
+	components map[string]*jsonSchema
+	names      map[reflect.Type]string
+}
+
+func newSchemaGenerator() *schemaGenerator {
+	return &schemaGenerator{
+		components: make(map[string]*jsonSchema),
+		names:      make(map[reflect.Type]string),
+	}
+}
+
+// schemaOf returns the JSON schema of the type of the value v.
+func (g *schemaGenerator) schemaOf(v any) *jsonSchema {
+	if v == nil {
+		return nil
+	}
+
+	if t, ok := v.(reflect.Type); ok {
+		return g.schema(t)
+	}
+
+	return g.schema(reflect.TypeOf(v))
+}
+
+//nolint:cyclop
+func (g *schemaGenerator) schema(t reflect.Type) *jsonSchema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return &jsonSchema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &jsonSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &jsonSchema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &jsonSchema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &jsonSchema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &jsonSchema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &jsonSchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &jsonSchema{Type: "string", Format: "byte"}
+		}
+
+		return &jsonSchema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &jsonSchema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		return g.structRef(t)
+	default:
+		// interfaces and other types accept any value
+		return &jsonSchema{}
+	}
+}
+
+// structRef returns a reference to the component schema of a named struct, or the inline schema of an anonymous one.
+func (g *schemaGenerator) structRef(t reflect.Type) *jsonSchema {
+	if t.Name() == "" {
+		return g.structSchema(t)
+	}
+
+	if name, ok := g.names[t]; ok {
+		return &jsonSchema{Ref: openAPIComponentsRef + name}
+	}
+
+	name := g.componentName(t)
+
+	// register the name before generating the schema to support recursive types
+	g.names[t] = name
+	g.components[name] = g.structSchema(t)
+
+	return &jsonSchema{Ref: openAPIComponentsRef + name}
+}
+
+// componentName returns a unique component name for the type.
+func (g *schemaGenerator) componentName(t reflect.Type) string {
+	name := invalidSchemaNameRx.ReplaceAllString(t.Name(), "_")
+
+	if _, ok := g.components[name]; !ok {
+		return name
+	}
+
+	pkg := t.PkgPath()
+	pkg = pkg[strings.LastIndex(pkg, "/")+1:]
+
+	name = invalidSchemaNameRx.ReplaceAllString(pkg, "_") + "." + name
+
+	for i := 2; ; i++ {
+		n := name + strconv.Itoa(i)
+		if _, ok := g.components[n]; !ok {
+			return n
+		}
+	}
+}
+
+// structSchema returns the object schema of a struct type.
+func (g *schemaGenerator) structSchema(t reflect.Type) *jsonSchema {
+	s := &jsonSchema{
+		Type:       "object",
+		Properties: make(map[string]*jsonSchema),
+	}
+
+	g.addFields(s, t)
+
+	return s
+}
+
+// addFields adds the struct fields to the object schema, flattening the embedded structs.
+func (g *schemaGenerator) addFields(s *jsonSchema, t reflect.Type) {
+	for f := range t.Fields() {
+		name, ok := jsonFieldName(f)
+		if !ok {
+			continue
+		}
+
+		ft := f.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			g.addFields(s, ft)
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		fs := g.schema(f.Type)
+
+		if applyValidateTag(fs, f.Type, f.Tag.Get(validateTagName)) {
+			s.Required = append(s.Required, name)
+		}
+
+		s.Properties[name] = fs
+	}
+}
+
+// jsonFieldName returns the JSON name of the field (empty if not specified) and false if the field is not serialized.
+func jsonFieldName(f reflect.StructField) (string, bool) {
+	if !f.IsExported() && !f.Anonymous {
+		return "", false
+	}
+
+	tag := f.Tag.Get(jsonTagName)
+	if tag == "-" {
+		return "", false
+	}
+
+	name, _, _ := strings.Cut(tag, ",")
+
+	return name, true
+}
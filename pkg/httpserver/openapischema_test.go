@@ -0,0 +1,90 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testSchemaBase struct {
+	ID string `json:"id" validate:"required,uuid"`
+}
+
+type testSchemaNode struct {
+	testSchemaBase
+
+	Name       string              `json:"name" validate:"required,min=1,max=20"`
+	Age        *int                `json:"age,omitempty" validate:"omitempty,gte=0,lt=150"`
+	Score      float64             `json:"score"`
+	Tags       []string            `json:"tags" validate:"max=5,dive,alpha"`
+	Labels     map[string]int64    `json:"labels"`
+	Data       []byte              `json:"data"`
+	Created    time.Time           `json:"created"`
+	Children   []*testSchemaNode   `json:"children"`
+	Any        any                 `json:"any"`
+	Inline     struct{ Flag bool } `json:"inline"`
+	Ignored    string              `json:"-"`
+	NoTag      bool                //nolint:tagliatelle
+	unexported string
+}
+
+func TestSchemaGenerator(t *testing.T) {
+	t.Parallel()
+
+	g := newSchemaGenerator()
+
+	require.Nil(t, g.schemaOf(nil))
+
+	s := g.schemaOf(&testSchemaNode{})
+	require.Equal(t, &jsonSchema{Ref: "#/components/schemas/testSchemaNode"}, s)
+
+	// the same type is not generated twice
+	require.Equal(t, s, g.schemaOf(reflect.TypeFor[testSchemaNode]()))
+	require.Len(t, g.components, 1)
+
+	c := g.components["testSchemaNode"]
+	require.Equal(t, "object", c.Type)
+	require.ElementsMatch(t, []string{"id", "name"}, c.Required)
+	require.NotContains(t, c.Properties, "Ignored")
+	require.NotContains(t, c.Properties, "unexported")
+	require.Contains(t, c.Properties, "NoTag")
+
+	got, err := json.Marshal(c.Properties)
+	require.NoError(t, err)
+
+	want := `{
+		"NoTag": {"type": "boolean"},
+		"age": {"type": "integer", "format": "int32", "minimum": 0, "exclusiveMaximum": 150},
+		"any": {},
+		"children": {"type": "array", "items": {"$ref": "#/components/schemas/testSchemaNode"}},
+		"created": {"type": "string", "format": "date-time"},
+		"data": {"type": "string", "format": "byte"},
+		"id": {"type": "string", "format": "uuid"},
+		"inline": {"type": "object", "properties": {"Flag": {"type": "boolean"}}},
+		"labels": {"type": "object", "additionalProperties": {"type": "integer", "format": "int64"}},
+		"name": {"type": "string", "minLength": 1, "maxLength": 20},
+		"score": {"type": "number", "format": "double"},
+		"tags": {"type": "array", "maxItems": 5, "items": {"type": "string"}}
+	}`
+
+	require.JSONEq(t, want, string(got))
+}
+
+func TestSchemaGenerator_nameCollision(t *testing.T) {
+	t.Parallel()
+
+	type testSchemaBase struct {
+		Value int `json:"value"`
+	}
+
+	g := newSchemaGenerator()
+
+	s1 := g.schemaOf(testSchemaNode{}.testSchemaBase)
+	s2 := g.schemaOf(testSchemaBase{})
+
+	require.Equal(t, "#/components/schemas/testSchemaBase", s1.Ref)
+	require.Equal(t, "#/components/schemas/httpserver.testSchemaBase2", s2.Ref)
+}
@@ -0,0 +1,192 @@
+package httpserver
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// validateFormats maps the validate tags to the JSON Schema string formats.
+var validateFormats = map[string]string{ //nolint:gochecknoglobals
+	"email":    "email",
+	"url":      "uri",
+	"uri":      "uri",
+	"http_url": "uri",
+	"uuid":     "uuid",
+	"uuid4":    "uuid",
+	"ipv4":     "ipv4",
+	"ipv6":     "ipv6",
+	"hostname": "hostname",
+	"fqdn":     "hostname",
+	"datetime": "date-time",
+}
+
+// validatePatterns maps the validate tags to the JSON Schema string patterns.
+var validatePatterns = map[string]string{ //nolint:gochecknoglobals
+	"alpha":       "^[a-zA-Z]+$",
+	"alphanum":    "^[a-zA-Z0-9]+$",
+	"numeric":     "^[-+]?[0-9]+(?:\\.[0-9]+)?$",
+	"number":      "^[0-9]+$",
+	"hexadecimal": "^(0[xX])?[0-9a-fA-F]+$",
+	"lowercase":   "^[^A-Z]*$",
+	"uppercase":   "^[^a-z]*$",
+	"e164":        "^\\+[1-9]?[0-9]{7,14}$",
+}
+
+var oneofValuesRx = regexp.MustCompile(`'[^']*'|\S+`)
+
+// applyValidateTag maps the rules of a validate tag (github.com/go-playground/validator) to the JSON Schema constraints.
+// It returns true if the field is required.
+// The alternative rules (OR) and the rules after "dive" are ignored.
+func applyValidateTag(s *jsonSchema, t reflect.Type, tag string) bool {
+	var required bool
+
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	for rule := range strings.SplitSeq(tag, ",") {
+		if rule == "dive" {
+			break
+		}
+
+		if strings.Contains(rule, "|") {
+			continue
+		}
+
+		key, param, _ := strings.Cut(rule, "=")
+
+		if key == "required" {
+			required = true
+			continue
+		}
+
+		applyValidateRule(s, t.Kind(), key, param)
+	}
+
+	return required
+}
+
+func applyValidateRule(s *jsonSchema, kind reflect.Kind, key, param string) {
+	if f, ok := validateFormats[key]; ok {
+		s.Format = f
+		return
+	}
+
+	if p, ok := validatePatterns[key]; ok {
+		s.Pattern = p
+		return
+	}
+
+	switch key {
+	case "oneof":
+		s.Enum = validateEnum(kind, param)
+	case "len":
+		applyValidateBound(s, kind, param, true, true)
+	case "min", "gte":
+		applyValidateBound(s, kind, param, true, false)
+	case "max", "lte":
+		applyValidateBound(s, kind, param, false, true)
+	case "gt":
+		applyValidateExclusiveBound(s, kind, param, true)
+	case "lt":
+		applyValidateExclusiveBound(s, kind, param, false)
+	}
+}
+
+// applyValidateBound sets the inclusive lower and/or upper bounds depending on the type.
+func applyValidateBound(s *jsonSchema, kind reflect.Kind, param string, lower, upper bool) {
+	v, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+
+	n := int(v)
+
+	switch {
+	case isNumberKind(kind):
+		if lower {
+			s.Minimum = &v
+		}
+
+		if upper {
+			s.Maximum = &v
+		}
+	case kind == reflect.String:
+		if lower {
+			s.MinLength = &n
+		}
+
+		if upper {
+			s.MaxLength = &n
+		}
+	case kind == reflect.Slice || kind == reflect.Array:
+		if lower {
+			s.MinItems = &n
+		}
+
+		if upper {
+			s.MaxItems = &n
+		}
+	}
+}
+
+// applyValidateExclusiveBound sets the exclusive lower or upper bound depending on the type.
+func applyValidateExclusiveBound(s *jsonSchema, kind reflect.Kind, param string, lower bool) {
+	v, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+
+	if isNumberKind(kind) {
+		if lower {
+			s.ExclusiveMinimum = &v
+		} else {
+			s.ExclusiveMaximum = &v
+		}
+
+		return
+	}
+
+	// lengths are integers
+	if lower {
+		applyValidateBound(s, kind, strconv.Itoa(int(v)+1), true, false)
+	} else {
+		applyValidateBound(s, kind, strconv.Itoa(int(v)-1), false, true)
+	}
+}
+
+// validateEnum returns the enumeration values of a "oneof" rule.
+// The values containing spaces can be enclosed in single quotes.
+func validateEnum(kind reflect.Kind, param string) []any {
+	fields := oneofValuesRx.FindAllString(param, -1)
+	enum := make([]any, 0, len(fields))
+
+	for _, f := range fields {
+		if !isNumberKind(kind) {
+			enum = append(enum, strings.Trim(f, "'"))
+			continue
+		}
+
+		v, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			continue
+		}
+
+		enum = append(enum, v)
+	}
+
+	return enum
+}
+
+func isNumberKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,52 @@
+package httpserver
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_applyValidateTag(t *testing.T) {
+	t.Parallel()
+
+	f := func(v float64) *float64 { return &v }
+	n := func(v int) *int { return &v }
+
+	tests := []struct {
+		name         string
+		typ          reflect.Type
+		tag          string
+		want         *jsonSchema
+		wantRequired bool
+	}{
+		{name: "empty", typ: reflect.TypeFor[string](), want: &jsonSchema{}},
+		{name: "required", typ: reflect.TypeFor[string](), tag: "required", want: &jsonSchema{}, wantRequired: true},
+		{name: "string length", typ: reflect.TypeFor[string](), tag: "min=2,max=8", want: &jsonSchema{MinLength: n(2), MaxLength: n(8)}},
+		{name: "string len", typ: reflect.TypeFor[string](), tag: "len=3", want: &jsonSchema{MinLength: n(3), MaxLength: n(3)}},
+		{name: "string gt lt", typ: reflect.TypeFor[string](), tag: "gt=2,lt=8", want: &jsonSchema{MinLength: n(3), MaxLength: n(7)}},
+		{name: "number range", typ: reflect.TypeFor[*int](), tag: "gte=1,lte=10", want: &jsonSchema{Minimum: f(1), Maximum: f(10)}},
+		{name: "number exclusive", typ: reflect.TypeFor[float64](), tag: "gt=0,lt=1", want: &jsonSchema{ExclusiveMinimum: f(0), ExclusiveMaximum: f(1)}},
+		{name: "slice items", typ: reflect.TypeFor[[]int](), tag: "min=1,max=3", want: &jsonSchema{MinItems: n(1), MaxItems: n(3)}},
+		{name: "string enum", typ: reflect.TypeFor[string](), tag: "oneof=red green 'light blue'", want: &jsonSchema{Enum: []any{"red", "green", "light blue"}}},
+		{name: "number enum", typ: reflect.TypeFor[int](), tag: "oneof=1 2 x", want: &jsonSchema{Enum: []any{1.0, 2.0}}},
+		{name: "format", typ: reflect.TypeFor[string](), tag: "required,email", want: &jsonSchema{Format: "email"}, wantRequired: true},
+		{name: "pattern", typ: reflect.TypeFor[string](), tag: "alphanum", want: &jsonSchema{Pattern: "^[a-zA-Z0-9]+$"}},
+		{name: "alternatives ignored", typ: reflect.TypeFor[string](), tag: "email|url", want: &jsonSchema{}},
+		{name: "dive stops", typ: reflect.TypeFor[[]string](), tag: "max=2,dive,min=5", want: &jsonSchema{MaxItems: n(2)}},
+		{name: "invalid param", typ: reflect.TypeFor[int](), tag: "min=x,gt=y", want: &jsonSchema{}},
+		{name: "unknown rule", typ: reflect.TypeFor[int](), tag: "custom=1", want: &jsonSchema{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := &jsonSchema{}
+			required := applyValidateTag(s, tt.typ, tt.tag)
+
+			require.Equal(t, tt.wantRequired, required)
+			require.Equal(t, tt.want, s)
+		})
+	}
+}
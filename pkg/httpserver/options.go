@@ -2,12 +2,17 @@ package httpserver
 
 import (
 	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/Vonage/gosrvlib/pkg/logging"
 	"github.com/julienschmidt/httprouter"
 )
 
@@ -15,6 +20,7 @@ import (
 type Option func(*config) error
 
 // WithRouter replaces the default router used by the httpServer (mostly used for test purposes with a mock router).
+// The routers of the additional listeners are not affected (see WithServeMuxRouter).
 func WithRouter(r *httprouter.Router) Option {
 	return func(cfg *config) error {
 		if r == nil {
@@ -94,6 +100,20 @@ func WithServerWriteTimeout(timeout time.Duration) Option {
 	}
 }
 
+// WithServerIdleTimeout sets the maximum amount of time to wait for the next request when keep-alives are enabled.
+// If not set, the read timeout is used.
+func WithServerIdleTimeout(timeout time.Duration) Option {
+	return func(cfg *config) error {
+		if timeout <= 0 {
+			return errors.New("invalid serverIdleTimeout")
+		}
+
+		cfg.serverIdleTimeout = timeout
+
+		return nil
+	}
+}
+
 // WithShutdownTimeout sets the shutdown timeout.
 func WithShutdownTimeout(timeout time.Duration) Option {
 	return func(cfg *config) error {
@@ -151,6 +171,163 @@ func WithTLSCertData(pemCert, pemKey []byte) Option {
 	}
 }
 
+// WithHTTP2MaxConcurrentStreams sets the maximum number of concurrent streams per HTTP/2 connection.
+func WithHTTP2MaxConcurrentStreams(n int) Option {
+	return func(cfg *config) error {
+		if n <= 0 {
+			return errors.New("invalid http2MaxConcurrentStreams")
+		}
+
+		cfg.http2Conf().MaxConcurrentStreams = n
+
+		return nil
+	}
+}
+
+// WithHTTP2ReadIdleTimeout sets the time after which a ping frame is sent
+// to check the health of an HTTP/2 connection if no frame has been received.
+// The connection is closed if the ping is not answered within the ping timeout.
+func WithHTTP2ReadIdleTimeout(readIdleTimeout, pingTimeout time.Duration) Option {
+	return func(cfg *config) error {
+		if readIdleTimeout <= 0 || pingTimeout <= 0 {
+			return errors.New("invalid http2ReadIdleTimeout")
+		}
+
+		cfg.http2Conf().SendPingTimeout = readIdleTimeout
+		cfg.http2Conf().PingTimeout = pingTimeout
+
+		return nil
+	}
+}
+
+// WithoutHTTP2 disables the HTTP/2 protocol, so only HTTP/1.1 is served over TCP.
+func WithoutHTTP2() Option {
+	return func(cfg *config) error {
+		cfg.disableHTTP2 = true
+		return nil
+	}
+}
+
+// WithH2C enables the unencrypted HTTP/2 protocol (h2c) with prior knowledge,
+// typically used behind a service mesh or a load balancer terminating TLS.
+// The HTTP/1.1 Upgrade mechanism is not supported.
+func WithH2C() Option {
+	return func(cfg *config) error {
+		cfg.enableH2C = true
+		return nil
+	}
+}
+
+// WithHTTP3 enables an additional HTTP/3 (QUIC) listener on the same UDP port.
+// The TLS configuration is required (see WithTLSCertData).
+// The HTTP/1.1 and HTTP/2 responses advertise the HTTP/3 endpoint via the Alt-Svc header.
+// HTTP/3 is only served on the default listener and can't be combined with TLS additional listeners.
+func WithHTTP3() Option {
+	return func(cfg *config) error {
+		cfg.enableHTTP3 = true
+		return nil
+	}
+}
+
+// WithTLSCertProvider enables TLS with the certificate served by the specified provider.
+// This allows certificate rotation without restarting the server.
+// The provider is started with the server and stopped on shutdown.
+func WithTLSCertProvider(p *TLSCertProvider) Option {
+	return func(cfg *config) error {
+		if p == nil {
+			return errors.New("tlsCertProvider is required")
+		}
+
+		cfg.tlsCertProvider = p
+		cfg.tlsConfig = &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: p.GetCertificate,
+		}
+
+		return nil
+	}
+}
+
+// WithTLSClientCAs enables the TLS client authentication (mTLS) using the specified PEM encoded CA certificates.
+// Unless otherwise specified with WithTLSClientAuth, the clients are required to present a valid certificate.
+// The verified client identity is available to the handlers via TLSClientIdentityFromContext.
+// TLS must be enabled (see WithTLSCertData and WithTLSCertProvider).
+func WithTLSClientCAs(pemCAs []byte) Option {
+	return func(cfg *config) error {
+		pool := x509.NewCertPool()
+
+		if !pool.AppendCertsFromPEM(pemCAs) {
+			return errors.New("failed parsing the TLS client CA certificates")
+		}
+
+		cfg.tlsClientCAs = pool
+
+		return nil
+	}
+}
+
+// WithTLSClientAuth sets the policy for the TLS client authentication (e.g. tls.RequireAndVerifyClientCert).
+// TLS must be enabled (see WithTLSCertData and WithTLSCertProvider).
+func WithTLSClientAuth(auth tls.ClientAuthType) Option {
+	return func(cfg *config) error {
+		cfg.tlsClientAuth = auth
+		return nil
+	}
+}
+
+// WithServeMuxRouter replaces the default httprouter-based routers with ones based on the standard http.ServeMux (see ServeMuxRouter).
+func WithServeMuxRouter() Option {
+	return func(cfg *config) error {
+		cfg.router = NewServeMuxRouter()
+		cfg.newRouterFn = func() httpRouter { return NewServeMuxRouter() }
+
+		return nil
+	}
+}
+
+// WithListeners adds one or more named listeners (e.g. an admin port or a unix socket) served together with the default one.
+// The routes are assigned to the listeners via Route.Listeners and WithDefaultRoutesListener.
+// All the listeners are started and shut down together.
+func WithListeners(listeners ...Listener) Option {
+	return func(cfg *config) error {
+		for _, l := range listeners {
+			err := l.validate()
+			if err != nil {
+				return err
+			}
+
+			if slices.ContainsFunc(cfg.listeners, func(v Listener) bool { return v.Name == l.Name }) {
+				return fmt.Errorf("duplicate listener name: %q", l.Name)
+			}
+
+			cfg.listeners = append(cfg.listeners, l)
+		}
+
+		return nil
+	}
+}
+
+// WithDefaultRoutesListener assigns the specified default routes to the named listener.
+// If no routes are specified, all the default routes are assigned, including the opt-in ones (e.g. OpenAPIRoute).
+// For example, this allows serving the metrics, pprof and status routes on a separate admin port.
+func WithDefaultRoutesListener(name string, routes ...DefaultRoute) Option {
+	return func(cfg *config) error {
+		if name == "" {
+			return errors.New("listener name is required")
+		}
+
+		if len(routes) == 0 {
+			routes = append(allDefaultRoutes(), optInDefaultRoutes()...)
+		}
+
+		for _, route := range routes {
+			cfg.defaultRoutesListener[route] = name
+		}
+
+		return nil
+	}
+}
+
 // WithEnableDefaultRoutes sets the default routes to be enabled on the server.
 func WithEnableDefaultRoutes(ids ...DefaultRoute) Option {
 	return func(cfg *config) error {
@@ -159,7 +336,8 @@ func WithEnableDefaultRoutes(ids ...DefaultRoute) Option {
 	}
 }
 
-// WithEnableAllDefaultRoutes enables all default routes on the server.
+// WithEnableAllDefaultRoutes enables all default routes on the server,
+// except the ones that must be enabled explicitly with WithEnableDefaultRoutes (LogLevelRoute and OpenAPIRoute).
 func WithEnableAllDefaultRoutes() Option {
 	return func(cfg *config) error {
 		cfg.defaultEnabledRoutes = allDefaultRoutes()
@@ -180,6 +358,55 @@ func WithIndexHandlerFunc(handler IndexHandlerFunc) Option {
 	}
 }
 
+// WithOpenAPIInfo sets the general information of the OpenAPI document (see OpenAPIRoute).
+func WithOpenAPIInfo(info OpenAPIInfo) Option {
+	return func(cfg *config) error {
+		if info.Title == "" || info.Version == "" {
+			return errors.New("OpenAPI title and version are required")
+		}
+
+		cfg.openAPIInfo = info
+
+		return nil
+	}
+}
+
+// WithOpenAPIUI enables the web page rendering the OpenAPI document (Swagger UI or Redoc)
+// on the "/openapi" path when the OpenAPIRoute is enabled.
+// By default the page loads pinned versions of the assets from public CDNs
+// (see DefaultSwaggerUIAssetsURL and DefaultRedocAssetsURL, and WithOpenAPIUIAssetsURL).
+func WithOpenAPIUI(ui OpenAPIUI) Option {
+	return func(cfg *config) error {
+		switch ui {
+		case OpenAPIUINone, OpenAPIUISwagger, OpenAPIUIRedoc:
+			cfg.openAPIUI = ui
+			return nil
+		default:
+			return fmt.Errorf("invalid OpenAPI UI: %q", ui)
+		}
+	}
+}
+
+// WithOpenAPIUIAssetsURL sets the base URL of the assets of the OpenAPI web page (see WithOpenAPIUI),
+// e.g. to serve them from a self-hosted location.
+// The Swagger UI page loads "swagger-ui.css" and "swagger-ui-bundle.js",
+// and the Redoc page loads "redoc.standalone.js" from this URL.
+func WithOpenAPIUIAssetsURL(baseURL string) Option {
+	return func(cfg *config) error {
+		if baseURL == "" {
+			return errors.New("OpenAPI UI assets URL is required")
+		}
+
+		if _, err := url.Parse(baseURL); err != nil {
+			return fmt.Errorf("invalid OpenAPI UI assets URL: %w", err)
+		}
+
+		cfg.openAPIUIAssetsURL = strings.TrimSuffix(baseURL, "/")
+
+		return nil
+	}
+}
+
 // WithIPHandlerFunc replaces the default ip handler function.
 func WithIPHandlerFunc(handler http.HandlerFunc) Option {
 	return func(cfg *config) error {
@@ -245,6 +472,20 @@ func WithStatusHandlerFunc(handler http.HandlerFunc) Option {
 	}
 }
 
+// WithLogLevelController sets the controller used by the LogLevelRoute to read and change the log levels at runtime.
+// The same controller must be attached to the logger with logging.WithLevelController.
+func WithLogLevelController(c *logging.LevelController) Option {
+	return func(cfg *config) error {
+		if c == nil {
+			return errors.New("logLevelController is required")
+		}
+
+		cfg.logLevelController = c
+
+		return nil
+	}
+}
+
 // WithTraceIDHeaderName overrides the default trace id header name.
 func WithTraceIDHeaderName(name string) Option {
 	return func(cfg *config) error {
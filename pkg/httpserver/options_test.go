@@ -1,12 +1,16 @@
 package httpserver
 
 import (
+	"context"
+	"crypto/tls"
 	"net/http"
 	"reflect"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/Vonage/gosrvlib/pkg/logging"
+	"github.com/Vonage/gosrvlib/pkg/testutil"
 	"github.com/julienschmidt/httprouter"
 	"github.com/stretchr/testify/require"
 )
@@ -95,6 +99,80 @@ func TestWithServerWriteTimeout(t *testing.T) {
 	require.Equal(t, v, cfg.serverWriteTimeout)
 }
 
+func TestWithServerIdleTimeout(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultConfig()
+
+	err := WithServerIdleTimeout(-1)(cfg)
+	require.Error(t, err)
+
+	v := 23 * time.Second
+	err = WithServerIdleTimeout(v)(cfg)
+	require.NoError(t, err)
+	require.Equal(t, v, cfg.serverIdleTimeout)
+}
+
+func TestWithHTTP2MaxConcurrentStreams(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultConfig()
+
+	err := WithHTTP2MaxConcurrentStreams(0)(cfg)
+	require.Error(t, err)
+
+	err = WithHTTP2MaxConcurrentStreams(31)(cfg)
+	require.NoError(t, err)
+	require.Equal(t, 31, cfg.http2Config.MaxConcurrentStreams)
+}
+
+func TestWithHTTP2ReadIdleTimeout(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultConfig()
+
+	err := WithHTTP2ReadIdleTimeout(0, time.Second)(cfg)
+	require.Error(t, err)
+
+	err = WithHTTP2ReadIdleTimeout(time.Second, 0)(cfg)
+	require.Error(t, err)
+
+	err = WithHTTP2ReadIdleTimeout(29*time.Second, 3*time.Second)(cfg)
+	require.NoError(t, err)
+	require.Equal(t, 29*time.Second, cfg.http2Config.SendPingTimeout)
+	require.Equal(t, 3*time.Second, cfg.http2Config.PingTimeout)
+}
+
+func TestWithoutHTTP2(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultConfig()
+
+	err := WithoutHTTP2()(cfg)
+	require.NoError(t, err)
+	require.True(t, cfg.disableHTTP2)
+}
+
+func TestWithH2C(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultConfig()
+
+	err := WithH2C()(cfg)
+	require.NoError(t, err)
+	require.True(t, cfg.enableH2C)
+}
+
+func TestWithHTTP3(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultConfig()
+
+	err := WithHTTP3()(cfg)
+	require.NoError(t, err)
+	require.True(t, cfg.enableHTTP3)
+}
+
 func TestWithShutdownTimeout(t *testing.T) {
 	t.Parallel()
 
@@ -204,6 +282,109 @@ YlAqGKDZ+A+l
 	}
 }
 
+func TestWithTLSCertProvider(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultConfig()
+
+	err := WithTLSCertProvider(nil)(cfg)
+	require.Error(t, err)
+
+	certPEM, keyPEM, err := testutil.TLSCertData()
+	require.NoError(t, err)
+
+	loader := func(_ context.Context) (*tls.Certificate, error) {
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		return &cert, err
+	}
+
+	p, err := NewTLSCertProvider(t.Context(), loader, time.Minute)
+	require.NoError(t, err)
+
+	err = WithTLSCertProvider(p)(cfg)
+	require.NoError(t, err)
+	require.Equal(t, p, cfg.tlsCertProvider)
+	require.NotNil(t, cfg.tlsConfig)
+	require.NotNil(t, cfg.tlsConfig.GetCertificate)
+}
+
+func TestWithTLSClientCAs(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultConfig()
+
+	err := WithTLSClientCAs([]byte("invalid"))(cfg)
+	require.Error(t, err)
+
+	certPEM, _, err := testutil.TLSCertData()
+	require.NoError(t, err)
+
+	err = WithTLSClientCAs(certPEM)(cfg)
+	require.NoError(t, err)
+	require.NotNil(t, cfg.tlsClientCAs)
+}
+
+func TestWithTLSClientAuth(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultConfig()
+
+	err := WithTLSClientAuth(tls.VerifyClientCertIfGiven)(cfg)
+	require.NoError(t, err)
+	require.Equal(t, tls.VerifyClientCertIfGiven, cfg.tlsClientAuth)
+}
+
+func TestWithServeMuxRouter(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultConfig()
+
+	err := WithServeMuxRouter()(cfg)
+	require.NoError(t, err)
+	require.IsType(t, &ServeMuxRouter{}, cfg.router)
+	require.IsType(t, &ServeMuxRouter{}, cfg.newRouterFn())
+}
+
+func TestWithListeners(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultConfig()
+
+	err := WithListeners(
+		Listener{Name: "admin", Addr: ":8081"},
+		Listener{Name: "local", Addr: "unix:/tmp/test.sock"},
+	)(cfg)
+	require.NoError(t, err)
+	require.Len(t, cfg.listeners, 2)
+
+	err = WithListeners(Listener{Name: "admin", Addr: ":8082"})(cfg)
+	require.Error(t, err)
+
+	err = WithListeners(Listener{Name: "", Addr: ":8082"})(cfg)
+	require.Error(t, err)
+
+	err = WithListeners(Listener{Name: "other", Addr: "invalid"})(cfg)
+	require.Error(t, err)
+}
+
+func TestWithDefaultRoutesListener(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultConfig()
+
+	err := WithDefaultRoutesListener("admin", MetricsRoute)(cfg)
+	require.NoError(t, err)
+	require.Equal(t, map[DefaultRoute]string{MetricsRoute: "admin"}, cfg.defaultRoutesListener)
+
+	err = WithDefaultRoutesListener("admin")(cfg)
+	require.NoError(t, err)
+	require.Len(t, cfg.defaultRoutesListener, len(allDefaultRoutes())+len(optInDefaultRoutes()))
+	require.Equal(t, "admin", cfg.defaultRoutesListener[OpenAPIRoute])
+
+	err = WithDefaultRoutesListener("")(cfg)
+	require.Error(t, err)
+}
+
 func TestWithEnableDefaultRoutes(t *testing.T) {
 	t.Parallel()
 
@@ -220,6 +401,8 @@ func TestWithEnableAllDefaultRoutes(t *testing.T) {
 	err := WithEnableAllDefaultRoutes()(cfg)
 	require.NoError(t, err)
 	require.Equal(t, allDefaultRoutes(), cfg.defaultEnabledRoutes)
+	require.NotContains(t, cfg.defaultEnabledRoutes, OpenAPIRoute)
+	require.NotContains(t, cfg.defaultEnabledRoutes, LogLevelRoute)
 }
 
 func TestWithIndexHandlerFunc(t *testing.T) {
@@ -240,6 +423,49 @@ func TestWithIndexHandlerFunc(t *testing.T) {
 	require.Equal(t, reflect.ValueOf(v).Pointer(), reflect.ValueOf(cfg.indexHandlerFunc).Pointer())
 }
 
+func TestWithOpenAPIInfo(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultConfig()
+
+	err := WithOpenAPIInfo(OpenAPIInfo{Title: "Test"})(cfg)
+	require.Error(t, err)
+
+	v := OpenAPIInfo{Title: "Test", Version: "1.2.3", Description: "Test API"}
+	err = WithOpenAPIInfo(v)(cfg)
+	require.NoError(t, err)
+	require.Equal(t, v, cfg.openAPIInfo)
+}
+
+func TestWithOpenAPIUI(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultConfig()
+
+	err := WithOpenAPIUI(OpenAPIUISwagger)(cfg)
+	require.NoError(t, err)
+	require.Equal(t, OpenAPIUISwagger, cfg.openAPIUI)
+
+	err = WithOpenAPIUI("invalid")(cfg)
+	require.Error(t, err)
+}
+
+func TestWithOpenAPIUIAssetsURL(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config{}
+
+	err := WithOpenAPIUIAssetsURL("")(cfg)
+	require.Error(t, err)
+
+	err = WithOpenAPIUIAssetsURL("https://example.com/\x00")(cfg)
+	require.Error(t, err)
+
+	err = WithOpenAPIUIAssetsURL("https://example.com/assets/")(cfg)
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/assets", cfg.openAPIUIAssetsURL)
+}
+
 func TestWithIPHandlerFunc(t *testing.T) {
 	t.Parallel()
 
@@ -320,6 +546,20 @@ func TestWithStatusHandlerFunc(t *testing.T) {
 	require.Equal(t, reflect.ValueOf(v).Pointer(), reflect.ValueOf(cfg.statusHandlerFunc).Pointer())
 }
 
+func TestWithLogLevelController(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultConfig()
+
+	err := WithLogLevelController(nil)(cfg)
+	require.Error(t, err)
+
+	c := logging.NewLevelController()
+	err = WithLogLevelController(c)(cfg)
+	require.NoError(t, err)
+	require.Same(t, c, cfg.logLevelController)
+}
+
 func TestWithTraceIDHeaderName(t *testing.T) {
 	t.Parallel()
 
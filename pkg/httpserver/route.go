@@ -16,6 +16,18 @@ type Route struct {
 	// Description is the description of this route that is displayed by the /index endpoint.
 	Description string `json:"description"`
 
+	// Listeners is the list of listener names this route is served on (see WithListeners).
+	// If empty, the route is served on the DefaultListener.
+	Listeners []string `json:"listeners,omitempty"`
+
+	// Request is an optional value of the request body type (e.g. &CreateUserRequest{}),
+	// used to generate the OpenAPI schema (see OpenAPIRoute).
+	Request any `json:"-"`
+
+	// Response is an optional value of the successful response body type,
+	// used to generate the OpenAPI schema (see OpenAPIRoute).
+	Response any `json:"-"`
+
 	// Handler is the handler function.
 	Handler http.HandlerFunc `json:"-"`
 
@@ -28,6 +40,10 @@ type Route struct {
 	// Timeout time limit after which a request receives a 503 Service Unavailable.
 	// If set, overrides the common value set with WithRequestTimeout.
 	Timeout time.Duration `json:"-"`
+
+	// DisableTimeout disables the request timeout when set to true.
+	// This is required for long-lived connections, like Server-Sent Events (SSE) or WebSocket.
+	DisableTimeout bool `json:"-"`
 }
 
 // Index contains the list of routes attached to the current service.
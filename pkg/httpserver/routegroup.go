@@ -0,0 +1,87 @@
+package httpserver
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// RouteGroup defines a set of routes sharing a path prefix, middleware and metadata.
+// Groups can be nested, and the Flatten method returns the resulting list of routes
+// to be returned by Binder.BindHTTP.
+type RouteGroup struct {
+	// Prefix is the path prefix added to all the routes in the group (e.g. "/api/v1").
+	Prefix string
+
+	// Listeners is the default list of listeners for the routes that do not specify any.
+	Listeners []string
+
+	// Middleware is a set of middleware applied to all the routes in the group,
+	// before the middleware of each route.
+	Middleware []MiddlewareFn
+
+	// DisableLogger disables the default logger for all the routes in the group.
+	DisableLogger bool
+
+	// Timeout is the default request timeout for the routes that do not specify any.
+	Timeout time.Duration
+
+	// DisableTimeout disables the request timeout for all the routes in the group.
+	DisableTimeout bool
+
+	// Routes is the list of routes in the group.
+	Routes []Route
+
+	// Groups is the list of nested groups.
+	Groups []RouteGroup
+}
+
+// Flatten returns the list of routes in the group and nested groups,
+// with the group prefix, middleware and metadata applied.
+func (g RouteGroup) Flatten() []Route {
+	routes := make([]Route, 0, len(g.Routes))
+	routes = append(routes, g.Routes...)
+
+	for _, sub := range g.Groups {
+		routes = append(routes, sub.Flatten()...)
+	}
+
+	for i, r := range routes {
+		routes[i] = g.apply(r)
+	}
+
+	return routes
+}
+
+// apply returns a copy of the route with the group settings applied.
+func (g RouteGroup) apply(r Route) Route {
+	r.Path = joinRoutePath(g.Prefix, r.Path)
+	r.Middleware = slices.Concat(g.Middleware, r.Middleware)
+	r.DisableLogger = r.DisableLogger || g.DisableLogger
+	r.DisableTimeout = r.DisableTimeout || g.DisableTimeout
+
+	if len(r.Listeners) == 0 {
+		r.Listeners = g.Listeners
+	}
+
+	if r.Timeout == 0 {
+		r.Timeout = g.Timeout
+	}
+
+	return r
+}
+
+// joinRoutePath joins the prefix and the route path.
+func joinRoutePath(prefix, path string) string {
+	prefix = strings.TrimRight(prefix, "/")
+
+	if path == "" {
+		return prefix
+	}
+
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	return prefix + path
+}
@@ -0,0 +1,87 @@
+package httpserver
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteGroup_Flatten(t *testing.T) {
+	t.Parallel()
+
+	var calls []string
+
+	mw := func(name string) MiddlewareFn {
+		return func(_ MiddlewareArgs, next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls = append(calls, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+
+	group := RouteGroup{
+		Prefix:     "/api/",
+		Listeners:  []string{"public"},
+		Middleware: []MiddlewareFn{mw("group")},
+		Timeout:    5 * time.Second,
+		Routes: []Route{
+			{
+				Method:     http.MethodGet,
+				Path:       "/users",
+				Middleware: []MiddlewareFn{mw("route")},
+			},
+			{
+				Method:    http.MethodGet,
+				Path:      "",
+				Listeners: []string{"admin"},
+				Timeout:   1 * time.Second,
+			},
+		},
+		Groups: []RouteGroup{
+			{
+				Prefix:         "/v1",
+				DisableLogger:  true,
+				DisableTimeout: true,
+				Middleware:     []MiddlewareFn{mw("subgroup")},
+				Routes: []Route{
+					{
+						Method: http.MethodPost,
+						Path:   "items",
+					},
+				},
+			},
+		},
+	}
+
+	routes := group.Flatten()
+	require.Len(t, routes, 3)
+
+	require.Equal(t, "/api/users", routes[0].Path)
+	require.Equal(t, []string{"public"}, routes[0].Listeners)
+	require.Equal(t, 5*time.Second, routes[0].Timeout)
+	require.False(t, routes[0].DisableLogger)
+	require.Len(t, routes[0].Middleware, 2)
+
+	require.Equal(t, "/api", routes[1].Path)
+	require.Equal(t, []string{"admin"}, routes[1].Listeners)
+	require.Equal(t, 1*time.Second, routes[1].Timeout)
+
+	require.Equal(t, "/api/v1/items", routes[2].Path)
+	require.Equal(t, http.MethodPost, routes[2].Method)
+	require.True(t, routes[2].DisableLogger)
+	require.True(t, routes[2].DisableTimeout)
+	require.Len(t, routes[2].Middleware, 2)
+
+	handler := http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {})
+	ApplyMiddleware(MiddlewareArgs{}, handler, routes[2].Middleware...).ServeHTTP(nil, nil)
+	ApplyMiddleware(MiddlewareArgs{}, handler, routes[0].Middleware...).ServeHTTP(nil, nil)
+
+	require.Equal(t, []string{"group", "subgroup", "group", "route"}, calls)
+
+	// the original group must not be modified
+	require.Equal(t, "/users", group.Routes[0].Path)
+	require.Len(t, group.Routes[0].Middleware, 1)
+}
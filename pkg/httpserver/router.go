@@ -0,0 +1,140 @@
+package httpserver
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// httpRouter is the interface of the HTTP request routers supported by the HTTPServer:
+// *httprouter.Router (default) and *ServeMuxRouter.
+type httpRouter interface {
+	http.Handler
+
+	// Handler registers the handler for the given method and path.
+	Handler(method, path string, handler http.Handler)
+}
+
+// ServeMuxRouter is an HTTP request router based on the standard http.ServeMux pattern routing.
+// It accepts both the httprouter path syntax (e.g. "/users/:id", "/files/*path")
+// and the http.ServeMux one (e.g. "/users/{id}", "/files/{path...}").
+// The path parameters can be read with r.PathValue or httputil.PathParam.
+type ServeMuxRouter struct {
+	// NotFound is the handler called when no route matches.
+	NotFound http.Handler
+
+	// MethodNotAllowed is the handler called when the path matches but the method does not.
+	MethodNotAllowed http.Handler
+
+	// PanicHandler is the function called to handle the panics recovered from the HTTP handlers.
+	PanicHandler func(http.ResponseWriter, *http.Request, any)
+
+	mux *http.ServeMux
+
+	// paths contains the patterns without method, used to detect the method not allowed requests.
+	paths *http.ServeMux
+
+	// allowed contains the allowed methods of each pattern.
+	allowed map[string][]string
+}
+
+// NewServeMuxRouter returns a new router based on http.ServeMux.
+func NewServeMuxRouter() *ServeMuxRouter {
+	m := &ServeMuxRouter{
+		mux:     http.NewServeMux(),
+		paths:   http.NewServeMux(),
+		allowed: make(map[string][]string),
+	}
+
+	// the requests not matching any route are handled by the lowest precedence pattern
+	m.mux.HandleFunc("/", m.serveNoMatch)
+
+	return m
+}
+
+// Handler registers the handler for the given method and path.
+func (m *ServeMuxRouter) Handler(method, path string, handler http.Handler) {
+	pattern := serveMuxPattern(path)
+
+	m.mux.Handle(method+" "+pattern, handler)
+
+	if _, ok := m.allowed[pattern]; !ok {
+		m.addPath(pattern)
+	}
+
+	m.allowed[pattern] = append(m.allowed[pattern], method)
+}
+
+// addPath registers the pattern without method.
+// The patterns conflicting only when the method is ignored are skipped, so the requests
+// with a different method are handled as not found.
+func (m *ServeMuxRouter) addPath(pattern string) {
+	defer func() {
+		_ = recover()
+	}()
+
+	m.paths.Handle(pattern, http.NotFoundHandler())
+}
+
+// ServeHTTP dispatches the request to the handler whose pattern matches the request.
+func (m *ServeMuxRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if m.PanicHandler != nil {
+		defer func() {
+			if p := recover(); p != nil {
+				m.PanicHandler(w, r, p)
+			}
+		}()
+	}
+
+	m.mux.ServeHTTP(w, r)
+}
+
+// serveNoMatch handles the requests that do not match any route.
+func (m *ServeMuxRouter) serveNoMatch(w http.ResponseWriter, r *http.Request) {
+	if _, pattern := m.paths.Handler(r); pattern != "" {
+		w.Header().Set("Allow", strings.Join(m.allowed[pattern], ", "))
+
+		if m.MethodNotAllowed != nil {
+			m.MethodNotAllowed.ServeHTTP(w, r)
+			return
+		}
+
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	if m.NotFound != nil {
+		m.NotFound.ServeHTTP(w, r)
+		return
+	}
+
+	http.NotFound(w, r)
+}
+
+// serveMuxPattern converts the httprouter path syntax to the http.ServeMux one.
+// As in httprouter, a path ending with a slash only matches itself.
+func serveMuxPattern(path string) string {
+	segments := strings.Split(path, "/")
+
+	for i, s := range segments {
+		switch {
+		case strings.HasPrefix(s, ":"):
+			segments[i] = "{" + s[1:] + "}"
+		case strings.HasPrefix(s, "*"):
+			segments[i] = "{" + s[1:] + "...}"
+		}
+	}
+
+	if segments[len(segments)-1] == "" {
+		segments[len(segments)-1] = "{$}"
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// newDefaultRouter returns the default router.
+func newDefaultRouter() httpRouter {
+	return httprouter.New()
+}
@@ -0,0 +1,221 @@
+package httpserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/httputil"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_serveMuxPattern(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "/", want: "/{$}"},
+		{path: "/ping", want: "/ping"},
+		{path: "/users/", want: "/users/{$}"},
+		{path: "/users/:id", want: "/users/{id}"},
+		{path: "/users/:id/items/:item", want: "/users/{id}/items/{item}"},
+		{path: "/pprof/*option", want: "/pprof/{option...}"},
+		{path: "/users/{id}", want: "/users/{id}"},
+		{path: "/files/{path...}", want: "/files/{path...}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, serveMuxPattern(tt.path))
+		})
+	}
+}
+
+func TestServeMuxRouter(t *testing.T) {
+	t.Parallel()
+
+	okHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		httputil.SendText(r.Context(), w, http.StatusOK, httputil.PathParam(r, "id"))
+	})
+
+	r := NewServeMuxRouter()
+	r.Handler(http.MethodGet, "/", okHandler)
+	r.Handler(http.MethodGet, "/users/:id", okHandler)
+	r.Handler(http.MethodGet, "/panic", http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		panic("panicking!")
+	}))
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		wantCode int
+		wantBody string
+	}{
+		{name: "index", method: http.MethodGet, path: "/", wantCode: http.StatusOK},
+		{name: "path param", method: http.MethodGet, path: "/users/123", wantCode: http.StatusOK, wantBody: "123"},
+		{name: "not found", method: http.MethodGet, path: "/missing", wantCode: http.StatusNotFound},
+		{name: "method not allowed", method: http.MethodPost, path: "/users/123", wantCode: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, httptest.NewRequestWithContext(t.Context(), tt.method, tt.path, nil))
+
+			require.Equal(t, tt.wantCode, rr.Code)
+
+			if tt.wantBody != "" {
+				require.Equal(t, tt.wantBody, rr.Body.String())
+			}
+		})
+	}
+
+	require.Panics(t, func() {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/panic", nil))
+	})
+}
+
+func TestServeMuxRouter_allowedMethods(t *testing.T) {
+	t.Parallel()
+
+	okHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		httputil.SendStatus(r.Context(), w, http.StatusOK)
+	})
+
+	r := NewServeMuxRouter()
+	r.Handler(http.MethodGet, "/a/:x", okHandler)
+	r.Handler(http.MethodPut, "/a/:x", okHandler)
+
+	// conflicting with "/a/{x}" only when the method is ignored
+	r.Handler(http.MethodPost, "/{y}/b", okHandler)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequestWithContext(t.Context(), http.MethodDelete, "/a/c", nil))
+	require.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+	require.Equal(t, "GET, PUT", rr.Header().Get("Allow"))
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequestWithContext(t.Context(), http.MethodPost, "/c/b", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequestWithContext(t.Context(), http.MethodDelete, "/c/b", nil))
+	require.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestServeMuxRouter_customHandlers(t *testing.T) {
+	t.Parallel()
+
+	r := NewServeMuxRouter()
+	r.NotFound = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusTeapot) })
+	r.MethodNotAllowed = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusConflict) })
+	r.PanicHandler = func(w http.ResponseWriter, _ *http.Request, _ any) { w.WriteHeader(http.StatusBadGateway) }
+
+	r.Handler(http.MethodGet, "/panic", http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		panic("panicking!")
+	}))
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/missing", nil))
+	require.Equal(t, http.StatusTeapot, rr.Code)
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequestWithContext(t.Context(), http.MethodDelete, "/panic", nil))
+	require.Equal(t, http.StatusConflict, rr.Code)
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/panic", nil))
+	require.Equal(t, http.StatusBadGateway, rr.Code)
+}
+
+type serveMuxBinder struct{}
+
+func (b *serveMuxBinder) BindHTTP(_ context.Context) []Route {
+	group := RouteGroup{
+		Prefix: "/api",
+		Groups: []RouteGroup{
+			PathVersionGroup("v1", Route{
+				Method: http.MethodGet,
+				Path:   "/users/:id",
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					httputil.SendText(r.Context(), w, http.StatusOK, "v1:"+httputil.PathParam(r, "id"))
+				},
+			}),
+			PathVersionGroup("v2", Route{
+				Method: http.MethodGet,
+				Path:   "/users/{id}",
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					httputil.SendText(r.Context(), w, http.StatusOK, "v2:"+r.PathValue("id"))
+				},
+			}),
+		},
+	}
+
+	return group.Flatten()
+}
+
+func TestNew_serveMuxRouter(t *testing.T) {
+	t.Parallel()
+
+	shutdownWG := &sync.WaitGroup{}
+	shutdownSG := make(chan struct{})
+
+	h, err := New(t.Context(), &serveMuxBinder{},
+		WithServerAddr(":33127"),
+		WithServeMuxRouter(),
+		WithEnableAllDefaultRoutes(),
+		WithShutdownWaitGroup(shutdownWG),
+		WithShutdownSignalChan(shutdownSG),
+	)
+	require.NoError(t, err)
+
+	h.StartServerCtx(t.Context())
+
+	tests := []struct {
+		method   string
+		path     string
+		wantCode int
+		wantBody string
+	}{
+		{method: http.MethodGet, path: "/", wantCode: http.StatusOK},
+		{method: http.MethodGet, path: "/ping", wantCode: http.StatusOK},
+		{method: http.MethodGet, path: "/pprof/cmdline", wantCode: http.StatusOK},
+		{method: http.MethodGet, path: "/api/v1/users/7", wantCode: http.StatusOK, wantBody: "v1:7"},
+		{method: http.MethodGet, path: "/api/v2/users/8", wantCode: http.StatusOK, wantBody: "v2:8"},
+		{method: http.MethodGet, path: "/api/v3/users/9", wantCode: http.StatusNotFound},
+		{method: http.MethodPost, path: "/ping", wantCode: http.StatusMethodNotAllowed},
+	}
+
+	client := &http.Client{Timeout: 2 * time.Second}
+
+	for _, tt := range tests {
+		req, err := http.NewRequestWithContext(t.Context(), tt.method, "http://127.0.0.1:33127"+tt.path, nil)
+		require.NoError(t, err)
+
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+
+		body := make([]byte, 64)
+		n, _ := resp.Body.Read(body)
+
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, tt.wantCode, resp.StatusCode, tt.path)
+
+		if tt.wantBody != "" {
+			require.Equal(t, tt.wantBody, string(body[:n]), tt.path)
+		}
+	}
+
+	close(shutdownSG)
+	shutdownWG.Wait()
+}
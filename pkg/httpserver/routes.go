@@ -2,6 +2,8 @@ package httpserver
 
 import (
 	"net/http"
+
+	"github.com/Vonage/gosrvlib/pkg/logging"
 )
 
 // DefaultRoute is the type for the default route names.
@@ -31,6 +33,20 @@ const (
 	// StatusRoute is the identifier to enable the status handler.
 	StatusRoute       DefaultRoute = "status"
 	statusHandlerPath string       = "/status"
+
+	// LogLevelRoute is the identifier to enable the handlers to read (GET) and change (PUT) the log levels at runtime.
+	// The log levels are managed by the logging.LevelController set with WithLogLevelController.
+	// This route is not included in WithEnableAllDefaultRoutes and must be enabled explicitly.
+	LogLevelRoute       DefaultRoute = "loglevel"
+	logLevelHandlerPath string       = "/loglevel"
+
+	// OpenAPIRoute is the identifier to enable the OpenAPI document handler.
+	// The document is generated from the routes (see Route.Request and Route.Response).
+	// The optional web page (see WithOpenAPIUI) is served on the openAPIUIPath.
+	// This route is not included in WithEnableAllDefaultRoutes and must be enabled explicitly.
+	OpenAPIRoute   DefaultRoute = "openapi"
+	openAPIDocPath string       = "/openapi.json"
+	openAPIUIPath  string       = "/openapi"
 )
 
 func allDefaultRoutes() []DefaultRoute {
@@ -44,21 +60,32 @@ func allDefaultRoutes() []DefaultRoute {
 	}
 }
 
+// optInDefaultRoutes returns the default routes not included in allDefaultRoutes,
+// that must be enabled explicitly with WithEnableDefaultRoutes.
+func optInDefaultRoutes() []DefaultRoute {
+	return []DefaultRoute{
+		LogLevelRoute,
+		OpenAPIRoute,
+	}
+}
+
 func newDefaultRoutes(cfg *config) []Route {
 	routes := make([]Route, 0, len(cfg.defaultEnabledRoutes)+1)
 
 	for _, id := range cfg.defaultEnabledRoutes {
 		_, disableLogger := cfg.disableDefaultRouteLogger[id]
+		listeners := cfg.defaultRouteListeners(id)
 
 		switch id {
-		case IndexRoute:
-			// The index route needs to access all the routes bound to the handler.
+		case IndexRoute, OpenAPIRoute:
+			// The index and OpenAPI routes need to access all the routes bound to the handler.
 		case IPRoute:
 			routes = append(routes, Route{
 				Method:        http.MethodGet,
 				Path:          ipHandlerPath,
 				Handler:       cfg.ipHandlerFunc,
 				DisableLogger: disableLogger,
+				Listeners:     listeners,
 				Description:   "Returns the public IP address of this service instance.",
 			})
 		case MetricsRoute:
@@ -67,6 +94,7 @@ func newDefaultRoutes(cfg *config) []Route {
 				Path:          metricsHandlerPath,
 				Handler:       cfg.metricsHandlerFunc,
 				DisableLogger: disableLogger,
+				Listeners:     listeners,
 				Description:   "Returns Prometheus metrics.",
 			})
 		case PingRoute:
@@ -75,6 +103,7 @@ func newDefaultRoutes(cfg *config) []Route {
 				Path:          pingHandlerPath,
 				Handler:       cfg.pingHandlerFunc,
 				DisableLogger: disableLogger,
+				Listeners:     listeners,
 				Description:   "Ping this service.",
 			})
 		case PprofRoute:
@@ -83,6 +112,7 @@ func newDefaultRoutes(cfg *config) []Route {
 				Path:          pprofHandlerPath,
 				Handler:       cfg.pprofHandlerFunc,
 				DisableLogger: disableLogger,
+				Listeners:     listeners,
 				Description:   "Returns pprof data for the selected profile.",
 			})
 		case StatusRoute:
@@ -91,8 +121,31 @@ func newDefaultRoutes(cfg *config) []Route {
 				Path:          statusHandlerPath,
 				Handler:       cfg.statusHandlerFunc,
 				DisableLogger: disableLogger,
+				Listeners:     listeners,
 				Description:   "Check this service health status.",
 			})
+		case LogLevelRoute:
+			routes = append(routes,
+				Route{
+					Method:        http.MethodGet,
+					Path:          logLevelHandlerPath,
+					Handler:       logLevelGetHandler(cfg.logLevelController),
+					DisableLogger: disableLogger,
+					Listeners:     listeners,
+					Description:   "Returns the log levels.",
+					Response:      &logging.LevelState{},
+				},
+				Route{
+					Method:        http.MethodPut,
+					Path:          logLevelHandlerPath,
+					Handler:       logLevelSetHandler(cfg.logLevelController),
+					DisableLogger: disableLogger,
+					Listeners:     listeners,
+					Description:   "Changes the global or component log level, optionally for a limited time.",
+					Request:       &LogLevelRequest{},
+					Response:      &logging.LevelState{},
+				},
+			)
 		}
 	}
 
@@ -13,7 +13,7 @@ func Test_newDefaultRoutes(t *testing.T) {
 
 	cfg := defaultConfig()
 
-	cfg.defaultEnabledRoutes = allDefaultRoutes()
+	cfg.defaultEnabledRoutes = append(allDefaultRoutes(), LogLevelRoute)
 	cfg.metricsHandlerFunc = func(_ http.ResponseWriter, _ *http.Request) {}
 	cfg.pingHandlerFunc = func(_ http.ResponseWriter, _ *http.Request) {}
 	cfg.pprofHandlerFunc = func(_ http.ResponseWriter, _ *http.Request) {}
@@ -26,6 +26,7 @@ func Test_newDefaultRoutes(t *testing.T) {
 	cfg.disableDefaultRouteLogger[PingRoute] = true
 	cfg.disableDefaultRouteLogger[PprofRoute] = true
 	cfg.disableDefaultRouteLogger[StatusRoute] = true
+	cfg.disableDefaultRouteLogger[LogLevelRoute] = true
 
 	routes := newDefaultRoutes(cfg)
 	expFuncs := []http.HandlerFunc{
@@ -49,4 +50,16 @@ func Test_newDefaultRoutes(t *testing.T) {
 	}
 
 	require.Equal(t, 5, boundCount)
+
+	logLevelMethods := []string{}
+
+	for _, r := range routes {
+		if r.Path == logLevelHandlerPath {
+			logLevelMethods = append(logLevelMethods, r.Method)
+
+			require.True(t, r.DisableLogger, r.Path)
+		}
+	}
+
+	require.Equal(t, []string{http.MethodGet, http.MethodPut}, logLevelMethods)
 }
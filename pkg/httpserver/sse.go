@@ -0,0 +1,273 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LastEventIDHeader is the header sent by the SSE clients when reconnecting.
+const LastEventIDHeader = "Last-Event-ID"
+
+// SSEEvent contains the fields of a Server-Sent Event.
+// See: https://html.spec.whatwg.org/multipage/server-sent-events.html
+type SSEEvent struct {
+	// ID is the optional event ID used by the clients to resume the stream via the Last-Event-ID header.
+	ID string
+
+	// Event is the optional event type.
+	Event string
+
+	// Data is the event payload. Multiple lines are sent as separate data fields.
+	Data string
+
+	// Retry is the optional reconnection time to be used by the client.
+	Retry time.Duration
+}
+
+// SSEReplayFn is the type of function used to return the events to be sent
+// to a reconnecting client after the specified last event ID.
+type SSEReplayFn func(ctx context.Context, lastEventID string) []*SSEEvent
+
+// SSEWriter writes Server-Sent Events (SSE) to an HTTP response.
+// The route using it should disable the request timeout (see Route.DisableTimeout).
+type SSEWriter struct {
+	mux         sync.Mutex
+	w           http.ResponseWriter
+	rc          *http.ResponseController
+	ctx         context.Context //nolint:containedctx
+	cancel      context.CancelFunc
+	tracker     *streamTracker
+	lastEventID string
+	closeOnce   sync.Once
+	wg          sync.WaitGroup
+}
+
+// NewSSEWriter initializes the response for Server-Sent Events and returns a new SSEWriter.
+// The Close method must be called when done.
+// The stream context (see Context) is canceled when the client disconnects or the server shuts down.
+// The server write timeout is disabled for this response, when supported.
+func NewSSEWriter(w http.ResponseWriter, r *http.Request, opts ...SSEOption) (*SSEWriter, error) {
+	cfg := defaultSSEConfig()
+
+	for _, applyOpt := range opts {
+		applyOpt(cfg)
+	}
+
+	rc := http.NewResponseController(w)
+
+	err := rc.SetWriteDeadline(time.Time{})
+	if err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return nil, fmt.Errorf("unable to disable the write deadline: %w", err)
+	}
+
+	tracker := streamTrackerFromContext(r.Context())
+	if tracker != nil && !tracker.add() {
+		return nil, errors.New("the server is shutting down")
+	}
+
+	ctx, cancel := streamContext(r.Context(), tracker)
+
+	s := &SSEWriter{
+		w:           w,
+		rc:          rc,
+		ctx:         ctx,
+		cancel:      cancel,
+		tracker:     tracker,
+		lastEventID: r.Header.Get(LastEventIDHeader),
+	}
+
+	hdr := w.Header()
+	hdr.Set("Content-Type", "text/event-stream")
+	hdr.Set("Cache-Control", "no-cache")
+	hdr.Set("Connection", "keep-alive")
+	hdr.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	err = s.init(cfg)
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+
+	if cfg.heartbeat > 0 {
+		s.wg.Add(1)
+
+		go s.heartbeat(cfg.heartbeat)
+	}
+
+	return s, nil
+}
+
+func (s *SSEWriter) init(cfg *sseConfig) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if cfg.retry > 0 {
+		_, err := fmt.Fprintf(s.w, "retry: %d\n\n", cfg.retry.Milliseconds())
+		if err != nil {
+			return fmt.Errorf("unable to write the SSE retry: %w", err)
+		}
+	}
+
+	err := s.rc.Flush()
+	if err != nil {
+		return fmt.Errorf("the ResponseWriter does not support streaming: %w", err)
+	}
+
+	if s.lastEventID == "" || cfg.replayFn == nil {
+		return nil
+	}
+
+	for _, ev := range cfg.replayFn(s.ctx, s.lastEventID) {
+		err = s.write(ev)
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.flush()
+}
+
+// Context returns the stream context.
+// It is canceled when the client disconnects, the server shuts down or Close is called.
+func (s *SSEWriter) Context() context.Context {
+	return s.ctx
+}
+
+// LastEventID returns the value of the Last-Event-ID header sent by a reconnecting client.
+func (s *SSEWriter) LastEventID() string {
+	return s.lastEventID
+}
+
+// Send writes and flushes a single event.
+func (s *SSEWriter) Send(ev *SSEEvent) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	err := s.write(ev)
+	if err != nil {
+		return err
+	}
+
+	return s.flush()
+}
+
+// SendComment writes and flushes a comment line, ignored by the clients.
+func (s *SSEWriter) SendComment(comment string) error {
+	if strings.ContainsAny(comment, "\r\n") {
+		return errors.New("the SSE comment cannot contain new lines")
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if s.ctx.Err() != nil {
+		return fmt.Errorf("SSE stream closed: %w", s.ctx.Err())
+	}
+
+	_, err := fmt.Fprintf(s.w, ": %s\n\n", comment)
+	if err != nil {
+		return fmt.Errorf("unable to write the SSE comment: %w", err)
+	}
+
+	return s.flush()
+}
+
+// Close stops the heartbeat, cancels the stream context and releases the resources.
+func (s *SSEWriter) Close() {
+	s.closeOnce.Do(func() {
+		s.cancel()
+		s.wg.Wait()
+
+		if s.tracker != nil {
+			s.tracker.remove()
+		}
+	})
+}
+
+func (s *SSEWriter) write(ev *SSEEvent) error {
+	if s.ctx.Err() != nil {
+		return fmt.Errorf("SSE stream closed: %w", s.ctx.Err())
+	}
+
+	data, err := formatSSEEvent(ev)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.w.Write(data)
+	if err != nil {
+		return fmt.Errorf("unable to write the SSE event: %w", err)
+	}
+
+	return nil
+}
+
+func (s *SSEWriter) flush() error {
+	err := s.rc.Flush()
+	if err != nil {
+		return fmt.Errorf("unable to flush the SSE event: %w", err)
+	}
+
+	return nil
+}
+
+func (s *SSEWriter) heartbeat(interval time.Duration) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			if s.SendComment("heartbeat") != nil {
+				s.cancel()
+				return
+			}
+		}
+	}
+}
+
+func formatSSEEvent(ev *SSEEvent) ([]byte, error) {
+	if ev == nil {
+		return nil, errors.New("the SSE event is required")
+	}
+
+	if strings.ContainsAny(ev.ID, "\r\n\x00") || strings.ContainsAny(ev.Event, "\r\n") {
+		return nil, errors.New("the SSE event ID and type cannot contain new lines")
+	}
+
+	var b strings.Builder
+
+	if ev.ID != "" {
+		b.WriteString("id: " + ev.ID + "\n")
+	}
+
+	if ev.Event != "" {
+		b.WriteString("event: " + ev.Event + "\n")
+	}
+
+	if ev.Retry > 0 {
+		b.WriteString("retry: " + strconv.FormatInt(ev.Retry.Milliseconds(), 10) + "\n")
+	}
+
+	data := strings.ReplaceAll(ev.Data, "\r\n", "\n")
+	data = strings.ReplaceAll(data, "\r", "\n")
+
+	for line := range strings.SplitSeq(data, "\n") {
+		b.WriteString("data: " + line + "\n")
+	}
+
+	b.WriteString("\n")
+
+	return []byte(b.String()), nil
+}
@@ -0,0 +1,252 @@
+package httpserver
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type noFlushResponseWriter struct {
+	header http.Header
+}
+
+func (w *noFlushResponseWriter) Header() http.Header         { return w.header }
+func (w *noFlushResponseWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (w *noFlushResponseWriter) WriteHeader(_ int)           {}
+
+// syncRecorder is a thread-safe httptest.ResponseRecorder.
+type syncRecorder struct {
+	mux sync.Mutex
+	rec *httptest.ResponseRecorder
+}
+
+func (w *syncRecorder) Header() http.Header { return w.rec.Header() }
+
+func (w *syncRecorder) Write(b []byte) (int, error) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+
+	return w.rec.Write(b) //nolint:wrapcheck
+}
+
+func (w *syncRecorder) WriteHeader(code int) { w.rec.WriteHeader(code) }
+
+func (w *syncRecorder) Flush() { w.rec.Flush() }
+
+func (w *syncRecorder) body() string {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+
+	return w.rec.Body.String()
+}
+
+func Test_formatSSEEvent(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		ev      *SSEEvent
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "data only",
+			ev:   &SSEEvent{Data: "hello"},
+			want: "data: hello\n\n",
+		},
+		{
+			name: "all fields",
+			ev: &SSEEvent{
+				ID:    "7",
+				Event: "update",
+				Data:  "line1\nline2\r\nline3\rline4",
+				Retry: 3 * time.Second,
+			},
+			want: "id: 7\nevent: update\nretry: 3000\ndata: line1\ndata: line2\ndata: line3\ndata: line4\n\n",
+		},
+		{
+			name:    "invalid id",
+			ev:      &SSEEvent{ID: "1\n2"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid event",
+			ev:      &SSEEvent{Event: "a\rb"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := formatSSEEvent(tt.ev)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestNewSSEWriter(t *testing.T) {
+	t.Parallel()
+
+	replayFn := func(_ context.Context, lastEventID string) []*SSEEvent {
+		return []*SSEEvent{{ID: lastEventID + "1", Data: "missed"}}
+	}
+
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/events", nil)
+	req.Header.Set(LastEventIDHeader, "4")
+
+	rr := httptest.NewRecorder()
+
+	s, err := NewSSEWriter(rr, req, WithSSERetry(2*time.Second), WithSSEReplayFn(replayFn))
+	require.NoError(t, err)
+	require.NotNil(t, s)
+	require.Equal(t, "4", s.LastEventID())
+
+	require.NoError(t, s.Send(&SSEEvent{ID: "42", Data: "hello"}))
+	require.Error(t, s.Send(&SSEEvent{ID: "4\n2"}))
+	require.Error(t, s.Send(nil))
+	require.NoError(t, s.SendComment("ping"))
+	require.Error(t, s.SendComment("a\nb"))
+
+	s.Close()
+	s.Close() // no-op
+
+	require.Error(t, s.Context().Err())
+	require.Error(t, s.Send(&SSEEvent{Data: "closed"}))
+	require.Error(t, s.SendComment("closed"))
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, "text/event-stream", rr.Header().Get("Content-Type"))
+	require.Equal(t, "no-cache", rr.Header().Get("Cache-Control"))
+	require.Equal(t, "retry: 2000\n\nid: 41\ndata: missed\n\nid: 42\ndata: hello\n\n: ping\n\n", rr.Body.String())
+}
+
+func TestNewSSEWriter_notSupported(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/events", nil)
+	w := &noFlushResponseWriter{header: http.Header{}}
+
+	s, err := NewSSEWriter(w, req)
+	require.Error(t, err)
+	require.Nil(t, s)
+}
+
+func TestNewSSEWriter_shutdown(t *testing.T) {
+	t.Parallel()
+
+	st := newStreamTracker()
+	st.shutdown()
+
+	ctx := withStreamTracker(t.Context(), st)
+	req := httptest.NewRequestWithContext(ctx, http.MethodGet, "/events", nil)
+
+	s, err := NewSSEWriter(httptest.NewRecorder(), req)
+	require.Error(t, err)
+	require.Nil(t, s)
+}
+
+func TestSSEWriter_heartbeat(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/events", nil)
+	rr := &syncRecorder{rec: httptest.NewRecorder()}
+
+	s, err := NewSSEWriter(rr, req, WithSSEHeartbeat(time.Millisecond))
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return strings.Contains(rr.body(), ": heartbeat\n\n")
+	}, 1*time.Second, 5*time.Millisecond)
+
+	s.Close()
+}
+
+type sseBinder struct {
+	started chan struct{}
+}
+
+func (b *sseBinder) BindHTTP(_ context.Context) []Route {
+	return []Route{
+		{
+			Method:         http.MethodGet,
+			Path:           "/events",
+			Handler:        b.handler,
+			DisableTimeout: true,
+		},
+	}
+}
+
+func (b *sseBinder) handler(w http.ResponseWriter, r *http.Request) {
+	s, err := NewSSEWriter(w, r)
+	if err != nil {
+		return
+	}
+
+	defer s.Close()
+
+	_ = s.Send(&SSEEvent{ID: "1", Data: "first"})
+
+	close(b.started)
+
+	<-s.Context().Done()
+}
+
+func TestSSEWriter_serverShutdown(t *testing.T) {
+	t.Parallel()
+
+	binder := &sseBinder{started: make(chan struct{})}
+	shutdownWG := &sync.WaitGroup{}
+	shutdownSG := make(chan struct{})
+
+	h, err := New(t.Context(), binder,
+		WithServerAddr(":33117"),
+		WithRequestTimeout(10*time.Millisecond),
+		WithServerWriteTimeout(10*time.Millisecond),
+		WithShutdownWaitGroup(shutdownWG),
+		WithShutdownSignalChan(shutdownSG),
+	)
+	require.NoError(t, err)
+
+	h.StartServerCtx(t.Context())
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://127.0.0.1:33117/events", nil)
+	require.NoError(t, err)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	defer func() { _ = resp.Body.Close() }()
+
+	<-binder.started
+
+	// the stream must survive beyond the request and write timeouts
+	time.Sleep(50 * time.Millisecond)
+
+	rd := bufio.NewReader(resp.Body)
+
+	line, err := rd.ReadString('\n')
+	require.NoError(t, err)
+	require.Equal(t, "id: 1\n", line)
+
+	close(shutdownSG)
+	shutdownWG.Wait()
+
+	_, err = io.ReadAll(rd)
+	require.NoError(t, err)
+}
@@ -0,0 +1,41 @@
+package httpserver
+
+import (
+	"time"
+)
+
+// SSEOption is a type alias for a function that configures the SSEWriter.
+type SSEOption func(*sseConfig)
+
+type sseConfig struct {
+	heartbeat time.Duration
+	retry     time.Duration
+	replayFn  SSEReplayFn
+}
+
+func defaultSSEConfig() *sseConfig {
+	return &sseConfig{}
+}
+
+// WithSSEHeartbeat sets the interval for sending comment lines to keep the connection alive.
+// This prevents proxies and load balancers from closing idle connections.
+func WithSSEHeartbeat(interval time.Duration) SSEOption {
+	return func(cfg *sseConfig) {
+		cfg.heartbeat = interval
+	}
+}
+
+// WithSSERetry sets the reconnection time sent to the client when the stream starts.
+func WithSSERetry(retry time.Duration) SSEOption {
+	return func(cfg *sseConfig) {
+		cfg.retry = retry
+	}
+}
+
+// WithSSEReplayFn sets the function used to resend the missed events
+// when a client reconnects with a Last-Event-ID header.
+func WithSSEReplayFn(fn SSEReplayFn) SSEOption {
+	return func(cfg *sseConfig) {
+		cfg.replayFn = fn
+	}
+}
@@ -0,0 +1,36 @@
+package httpserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithSSEHeartbeat(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultSSEConfig()
+	v := 3 * time.Second
+	WithSSEHeartbeat(v)(cfg)
+	require.Equal(t, v, cfg.heartbeat)
+}
+
+func TestWithSSERetry(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultSSEConfig()
+	v := 5 * time.Second
+	WithSSERetry(v)(cfg)
+	require.Equal(t, v, cfg.retry)
+}
+
+func TestWithSSEReplayFn(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultSSEConfig()
+	v := func(_ context.Context, _ string) []*SSEEvent { return nil }
+	WithSSEReplayFn(v)(cfg)
+	require.NotNil(t, cfg.replayFn)
+}
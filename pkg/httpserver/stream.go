@@ -0,0 +1,107 @@
+package httpserver
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// streamTracker keeps track of the long-lived connections (SSE and WebSocket)
+// so they can be notified and awaited during the server shutdown.
+type streamTracker struct {
+	mux    sync.Mutex
+	wg     sync.WaitGroup
+	done   chan struct{}
+	closed bool
+}
+
+type streamTrackerKey struct{}
+
+func newStreamTracker() *streamTracker {
+	return &streamTracker{
+		done: make(chan struct{}),
+	}
+}
+
+// withStreamTracker returns a copy of the parent context containing the stream tracker.
+func withStreamTracker(ctx context.Context, t *streamTracker) context.Context {
+	return context.WithValue(ctx, streamTrackerKey{}, t)
+}
+
+// streamTrackerFromContext returns the stream tracker from the context, if any.
+func streamTrackerFromContext(ctx context.Context) *streamTracker {
+	t, _ := ctx.Value(streamTrackerKey{}).(*streamTracker)
+	return t
+}
+
+// add registers a new stream.
+// It returns false if the shutdown has already been initiated.
+func (t *streamTracker) add() bool {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	if t.closed {
+		return false
+	}
+
+	t.wg.Add(1)
+
+	return true
+}
+
+// remove de-registers a stream.
+func (t *streamTracker) remove() {
+	t.wg.Done()
+}
+
+// shutdown notifies all the registered streams to close.
+func (t *streamTracker) shutdown() {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	if t.closed {
+		return
+	}
+
+	t.closed = true
+
+	close(t.done)
+}
+
+// wait blocks until all the registered streams are closed or the context is canceled.
+func (t *streamTracker) wait(ctx context.Context) error {
+	ch := make(chan struct{})
+
+	go func() {
+		t.wg.Wait()
+		close(ch)
+	}()
+
+	select {
+	case <-ch:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("streams still open: %w", ctx.Err())
+	}
+}
+
+// streamContext returns a context that is canceled when the parent context is
+// canceled or when the stream tracker (if any) initiates the shutdown.
+// The returned function must be called to release the resources.
+func streamContext(ctx context.Context, t *streamTracker) (context.Context, context.CancelFunc) {
+	sctx, cancel := context.WithCancel(ctx)
+
+	if t == nil {
+		return sctx, cancel
+	}
+
+	go func() {
+		select {
+		case <-t.done:
+			cancel()
+		case <-sctx.Done():
+		}
+	}()
+
+	return sctx, cancel
+}
@@ -0,0 +1,50 @@
+package httpserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_streamTracker(t *testing.T) {
+	t.Parallel()
+
+	st := newStreamTracker()
+
+	ctx := withStreamTracker(t.Context(), st)
+	require.Equal(t, st, streamTrackerFromContext(ctx))
+	require.Nil(t, streamTrackerFromContext(t.Context()))
+
+	require.True(t, st.add())
+
+	sctx, cancel := streamContext(t.Context(), st)
+	defer cancel()
+
+	wctx, wcancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer wcancel()
+
+	require.Error(t, st.wait(wctx))
+
+	st.shutdown()
+	st.shutdown() // no-op
+
+	<-sctx.Done()
+
+	require.False(t, st.add())
+
+	st.remove()
+
+	require.NoError(t, st.wait(t.Context()))
+}
+
+func Test_streamContext_noTracker(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := streamContext(t.Context(), nil)
+	require.NoError(t, ctx.Err())
+
+	cancel()
+	require.Error(t, ctx.Err())
+}
@@ -0,0 +1,149 @@
+package httpserver
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/logging"
+	"github.com/Vonage/gosrvlib/pkg/periodic"
+	"go.uber.org/zap"
+)
+
+// TLSCertLoaderFn is the type of function used to load a TLS certificate.
+type TLSCertLoaderFn func(ctx context.Context) (*tls.Certificate, error)
+
+// TLSCertFileLoader returns a TLSCertLoaderFn that reads the PEM encoded certificate and key from the specified files.
+func TLSCertFileLoader(certFile, keyFile string) TLSCertLoaderFn {
+	return func(_ context.Context) (*tls.Certificate, error) {
+		pemCert, err := os.ReadFile(certFile) //nolint:gosec
+		if err != nil {
+			return nil, fmt.Errorf("failed reading the TLS certificate file: %w", err)
+		}
+
+		pemKey, err := os.ReadFile(keyFile) //nolint:gosec
+		if err != nil {
+			return nil, fmt.Errorf("failed reading the TLS key file: %w", err)
+		}
+
+		cert, err := tls.X509KeyPair(pemCert, pemKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed parsing the TLS certificate: %w", err)
+		}
+
+		return &cert, nil
+	}
+}
+
+// TLSCertProvider holds a TLS certificate that is periodically reloaded,
+// allowing certificate rotation without restarting the server.
+// The certificate is swapped atomically and served via GetCertificate.
+type TLSCertProvider struct {
+	mux      sync.Mutex
+	cert     atomic.Pointer[tls.Certificate]
+	loader   TLSCertLoaderFn
+	periodic *periodic.Periodic
+	running  bool
+}
+
+// NewTLSCertProvider loads the certificate using the specified function and returns a new provider.
+// The certificate is reloaded at the specified interval after calling Start.
+// When used with WithTLSCertProvider, the server starts and stops the provider automatically.
+func NewTLSCertProvider(ctx context.Context, loader TLSCertLoaderFn, interval time.Duration) (*TLSCertProvider, error) {
+	if loader == nil {
+		return nil, errors.New("the TLS certificate loader is required")
+	}
+
+	p := &TLSCertProvider{
+		loader: loader,
+	}
+
+	timeout := min(interval, 1*time.Minute)
+
+	task, err := periodic.New(interval, 1*time.Millisecond, timeout, p.reloadTask)
+	if err != nil {
+		return nil, fmt.Errorf("failed configuring the TLS certificate reload: %w", err)
+	}
+
+	p.periodic = task
+
+	err = p.Reload(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Start periodically reloads the certificate until Stop is called or the context is canceled.
+func (p *TLSCertProvider) Start(ctx context.Context) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	if p.running {
+		return
+	}
+
+	p.running = true
+
+	p.periodic.Start(ctx)
+}
+
+// Stop stops the periodic certificate reload.
+func (p *TLSCertProvider) Stop() {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	if !p.running {
+		return
+	}
+
+	p.running = false
+
+	p.periodic.Stop()
+}
+
+// Reload loads the certificate and swaps it if it has changed.
+func (p *TLSCertProvider) Reload(ctx context.Context) error {
+	cert, err := p.loader(ctx)
+	if err != nil {
+		return err
+	}
+
+	if cert == nil || len(cert.Certificate) == 0 {
+		return errors.New("empty TLS certificate")
+	}
+
+	old := p.cert.Load()
+	if old != nil && bytes.Equal(old.Certificate[0], cert.Certificate[0]) {
+		return nil
+	}
+
+	p.cert.Store(cert)
+
+	if old != nil {
+		logging.FromContext(ctx).Info("TLS certificate reloaded")
+	}
+
+	return nil
+}
+
+// GetCertificate returns the current certificate.
+// It can be used as tls.Config.GetCertificate function.
+func (p *TLSCertProvider) GetCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return p.cert.Load(), nil
+}
+
+func (p *TLSCertProvider) reloadTask(ctx context.Context) {
+	err := p.Reload(ctx)
+	if err != nil {
+		// keep serving the current certificate
+		logging.FromContext(ctx).Error("failed reloading the TLS certificate", zap.Error(err))
+	}
+}
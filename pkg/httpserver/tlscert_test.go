@@ -0,0 +1,110 @@
+package httpserver
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTLSCertFiles(t *testing.T, dir string, hosts ...string) (string, string) {
+	t.Helper()
+
+	certPEM, keyPEM, err := testutil.TLSCertData(hosts...)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+
+	require.NoError(t, os.WriteFile(certFile, certPEM, 0o600))
+	require.NoError(t, os.WriteFile(keyFile, keyPEM, 0o600))
+
+	return certFile, keyFile
+}
+
+func TestTLSCertFileLoader(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	certFile, keyFile := writeTLSCertFiles(t, dir)
+
+	cert, err := TLSCertFileLoader(certFile, keyFile)(t.Context())
+	require.NoError(t, err)
+	require.NotNil(t, cert)
+
+	_, err = TLSCertFileLoader(filepath.Join(dir, "missing.pem"), keyFile)(t.Context())
+	require.Error(t, err)
+
+	_, err = TLSCertFileLoader(certFile, filepath.Join(dir, "missing.pem"))(t.Context())
+	require.Error(t, err)
+
+	_, err = TLSCertFileLoader(keyFile, certFile)(t.Context())
+	require.Error(t, err)
+}
+
+func TestNewTLSCertProvider(t *testing.T) {
+	t.Parallel()
+
+	p, err := NewTLSCertProvider(t.Context(), nil, time.Second)
+	require.Error(t, err)
+	require.Nil(t, p)
+
+	loader := func(_ context.Context) (*tls.Certificate, error) { return &tls.Certificate{}, nil }
+
+	p, err = NewTLSCertProvider(t.Context(), loader, 0)
+	require.Error(t, err)
+	require.Nil(t, p)
+
+	p, err = NewTLSCertProvider(t.Context(), loader, time.Second)
+	require.Error(t, err, "empty certificate")
+	require.Nil(t, p)
+
+	loader = func(_ context.Context) (*tls.Certificate, error) { return nil, errors.New("ERROR") }
+
+	p, err = NewTLSCertProvider(t.Context(), loader, time.Second)
+	require.Error(t, err)
+	require.Nil(t, p)
+}
+
+func TestTLSCertProvider_reload(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	certFile, keyFile := writeTLSCertFiles(t, dir, "first.example.com")
+
+	p, err := NewTLSCertProvider(t.Context(), TLSCertFileLoader(certFile, keyFile), 10*time.Millisecond)
+	require.NoError(t, err)
+
+	first, err := p.GetCertificate(nil)
+	require.NoError(t, err)
+	require.NoError(t, first.Leaf.VerifyHostname("first.example.com"))
+
+	p.Start(t.Context())
+	p.Start(t.Context()) // no-op
+
+	defer p.Stop()
+
+	// an invalid certificate is ignored
+	require.NoError(t, os.WriteFile(certFile, []byte("invalid"), 0o600))
+	time.Sleep(50 * time.Millisecond)
+
+	cert, err := p.GetCertificate(nil)
+	require.NoError(t, err)
+	require.Equal(t, first, cert)
+
+	writeTLSCertFiles(t, dir, "second.example.com")
+
+	require.Eventually(t, func() bool {
+		cert, err := p.GetCertificate(nil)
+		return err == nil && cert.Leaf.VerifyHostname("second.example.com") == nil
+	}, 2*time.Second, 10*time.Millisecond)
+
+	p.Stop()
+	p.Stop() // no-op
+}
@@ -0,0 +1,59 @@
+package httpserver
+
+import (
+	"context"
+	"crypto/x509"
+	"net/http"
+	"net/url"
+)
+
+// TLSClientIdentity contains the identity of a client authenticated via a verified TLS certificate (mTLS).
+type TLSClientIdentity struct {
+	// Certificate is the verified client leaf certificate.
+	Certificate *x509.Certificate
+
+	// CommonName is the subject common name.
+	CommonName string
+
+	// SerialNumber is the certificate serial number as decimal string.
+	SerialNumber string
+
+	// DNSNames contains the DNS Subject Alternative Names.
+	DNSNames []string
+
+	// EmailAddresses contains the email Subject Alternative Names.
+	EmailAddresses []string
+
+	// URIs contains the URI Subject Alternative Names (e.g. SPIFFE IDs).
+	URIs []*url.URL
+}
+
+type tlsClientIdentityKey struct{}
+
+// TLSClientIdentityFromContext returns the verified TLS client identity stored in the request context, if any.
+func TLSClientIdentityFromContext(ctx context.Context) (*TLSClientIdentity, bool) {
+	id, ok := ctx.Value(tlsClientIdentityKey{}).(*TLSClientIdentity)
+	return id, ok
+}
+
+// tlsClientIdentityHandler stores the verified TLS client identity in the request context.
+func tlsClientIdentityHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 && len(r.TLS.VerifiedChains[0]) > 0 {
+			cert := r.TLS.VerifiedChains[0][0]
+
+			id := &TLSClientIdentity{
+				Certificate:    cert,
+				CommonName:     cert.Subject.CommonName,
+				SerialNumber:   cert.SerialNumber.String(),
+				DNSNames:       cert.DNSNames,
+				EmailAddresses: cert.EmailAddresses,
+				URIs:           cert.URIs,
+			}
+
+			r = r.WithContext(context.WithValue(r.Context(), tlsClientIdentityKey{}, id))
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
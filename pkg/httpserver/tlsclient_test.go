@@ -0,0 +1,133 @@
+package httpserver
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_tlsClientIdentityHandler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		got   *TLSClientIdentity
+		found bool
+	)
+
+	handler := tlsClientIdentityHandler(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		got, found = TLSClientIdentityFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	require.False(t, found)
+	require.Nil(t, got)
+
+	cert := &x509.Certificate{
+		Subject:      pkix.Name{CommonName: "client"},
+		SerialNumber: big.NewInt(123),
+		DNSNames:     []string{"client.example.com"},
+	}
+
+	req = httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/", nil)
+	req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	require.True(t, found)
+	require.Equal(t, "client", got.CommonName)
+	require.Equal(t, "123", got.SerialNumber)
+	require.Equal(t, []string{"client.example.com"}, got.DNSNames)
+	require.Equal(t, cert, got.Certificate)
+}
+
+func TestHTTPServer_mTLS(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	certFile, keyFile := writeTLSCertFiles(t, dir)
+
+	provider, err := NewTLSCertProvider(t.Context(), TLSCertFileLoader(certFile, keyFile), time.Minute)
+	require.NoError(t, err)
+
+	clientCertPEM, clientKeyPEM, err := testutil.TLSCertData("client.example.com")
+	require.NoError(t, err)
+
+	clientCert, err := tls.X509KeyPair(clientCertPEM, clientKeyPEM)
+	require.NoError(t, err)
+
+	binder := &identityBinder{}
+	shutdownWG := &sync.WaitGroup{}
+	shutdownSG := make(chan struct{})
+
+	h, err := New(t.Context(), binder,
+		WithServerAddr(":33123"),
+		WithTLSCertProvider(provider),
+		WithTLSClientCAs(clientCertPEM),
+		WithShutdownWaitGroup(shutdownWG),
+		WithShutdownSignalChan(shutdownSG),
+	)
+	require.NoError(t, err)
+
+	h.StartServerCtx(t.Context())
+
+	//nolint:gosec // self-signed test certificate
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: true,
+	}
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "https://127.0.0.1:33123/identity", nil)
+	require.NoError(t, err)
+
+	resp, err := client.Do(req) //nolint:bodyclose
+	require.Error(t, err, "client certificate required")
+	require.Nil(t, resp)
+
+	mtlsConfig := tlsConfig.Clone()
+	mtlsConfig.Certificates = []tls.Certificate{clientCert}
+	client = &http.Client{Transport: &http.Transport{TLSClientConfig: mtlsConfig}}
+
+	resp, err = client.Do(req)
+	require.NoError(t, err)
+
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "client.example.com", string(body))
+
+	close(shutdownSG)
+	shutdownWG.Wait()
+}
+
+type identityBinder struct{}
+
+func (b *identityBinder) BindHTTP(_ context.Context) []Route {
+	return []Route{
+		{
+			Method: http.MethodGet,
+			Path:   "/identity",
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				id, ok := TLSClientIdentityFromContext(r.Context())
+				if !ok {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+
+				_, _ = w.Write([]byte(id.CommonName))
+			},
+		},
+	}
+}
@@ -0,0 +1,44 @@
+package httpserver
+
+import (
+	"net/http"
+
+	"github.com/Vonage/gosrvlib/pkg/httputil"
+)
+
+// DefaultVersionHeader is the default HTTP header used to select the API version.
+const DefaultVersionHeader = "API-Version"
+
+// VersionHandlers maps the API versions to their handlers.
+type VersionHandlers map[string]http.HandlerFunc
+
+// PathVersionGroup returns a RouteGroup serving the routes under the version path prefix (e.g. "/v1/users").
+func PathVersionGroup(version string, routes ...Route) RouteGroup {
+	return RouteGroup{
+		Prefix: "/" + version,
+		Routes: routes,
+	}
+}
+
+// HeaderVersionHandler returns a handler that dispatches the requests to the handler of the API version
+// specified in the request header (e.g. DefaultVersionHeader).
+// The defaultVersion handler is used when the header is missing.
+// The requests for unknown versions receive a 400 Bad Request response.
+// The served version is returned in the same response header.
+func HeaderVersionHandler(header, defaultVersion string, handlers VersionHandlers) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		version := httputil.HeaderOrDefault(r, header, defaultVersion)
+
+		w.Header().Add("Vary", header)
+
+		handler, ok := handlers[version]
+		if !ok {
+			httputil.SendStatus(r.Context(), w, http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set(header, version)
+
+		handler(w, r)
+	}
+}
@@ -0,0 +1,66 @@
+package httpserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Vonage/gosrvlib/pkg/httputil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPathVersionGroup(t *testing.T) {
+	t.Parallel()
+
+	routes := PathVersionGroup("v2", Route{Method: http.MethodGet, Path: "/users"}).Flatten()
+
+	require.Len(t, routes, 1)
+	require.Equal(t, "/v2/users", routes[0].Path)
+}
+
+func TestHeaderVersionHandler(t *testing.T) {
+	t.Parallel()
+
+	handler := HeaderVersionHandler(DefaultVersionHeader, "1", VersionHandlers{
+		"1": func(w http.ResponseWriter, r *http.Request) {
+			httputil.SendText(r.Context(), w, http.StatusOK, "one")
+		},
+		"2": func(w http.ResponseWriter, r *http.Request) {
+			httputil.SendText(r.Context(), w, http.StatusOK, "two")
+		},
+	})
+
+	tests := []struct {
+		name        string
+		version     string
+		wantCode    int
+		wantBody    string
+		wantVersion string
+	}{
+		{name: "default", wantCode: http.StatusOK, wantBody: "one", wantVersion: "1"},
+		{name: "explicit", version: "2", wantCode: http.StatusOK, wantBody: "two", wantVersion: "2"},
+		{name: "unknown", version: "3", wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/users", nil)
+			if tt.version != "" {
+				req.Header.Set(DefaultVersionHeader, tt.version)
+			}
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			require.Equal(t, tt.wantCode, rr.Code)
+			require.Equal(t, DefaultVersionHeader, rr.Header().Get("Vary"))
+			require.Equal(t, tt.wantVersion, rr.Header().Get(DefaultVersionHeader))
+
+			if tt.wantBody != "" {
+				require.Equal(t, tt.wantBody, rr.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package httpserver
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/httputil"
+	"github.com/Vonage/gosrvlib/pkg/logging"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+// WebSocketHandlerFn is the type of function used to handle an upgraded WebSocket connection.
+// The context is canceled when the client disconnects or the server shuts down.
+// The connection is automatically closed when the function returns.
+type WebSocketHandlerFn func(ctx context.Context, conn *websocket.Conn)
+
+// WebSocketHandler returns an HTTP handler that upgrades the connection to the WebSocket protocol and calls the specified function.
+// The route using it should disable the request timeout (see Route.DisableTimeout).
+// When the function returns a "normal closure" close message is sent to the client,
+// while on server shutdown or client disconnection a "going away" close message is sent.
+// The connection is closed after the close timeout.
+func WebSocketHandler(fn WebSocketHandlerFn, opts ...WebSocketOption) http.HandlerFunc {
+	cfg := defaultWebSocketConfig()
+
+	for _, applyOpt := range opts {
+		applyOpt(cfg)
+	}
+
+	upgrader := &websocket.Upgrader{
+		HandshakeTimeout: cfg.handshakeTimeout,
+		ReadBufferSize:   cfg.readBufferSize,
+		WriteBufferSize:  cfg.writeBufferSize,
+		Subprotocols:     cfg.subprotocols,
+		CheckOrigin:      cfg.checkOrigin,
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		tracker := streamTrackerFromContext(r.Context())
+		if tracker != nil {
+			if !tracker.add() {
+				httputil.SendStatus(r.Context(), w, http.StatusServiceUnavailable)
+				return
+			}
+
+			defer tracker.remove()
+		}
+
+		// On error the upgrader replies with an HTTP error.
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			logging.FromContext(r.Context()).Debug("unable to upgrade to websocket", zap.Error(err))
+			return
+		}
+
+		ctx, cancel := streamContext(r.Context(), tracker)
+
+		var (
+			wg       sync.WaitGroup
+			returned atomic.Bool
+		)
+
+		wg.Go(func() {
+			<-ctx.Done()
+
+			code := websocket.CloseGoingAway
+			if returned.Load() {
+				code = websocket.CloseNormalClosure
+			}
+
+			closeWebSocket(conn, code, cfg.closeTimeout)
+		})
+
+		if cfg.pingInterval > 0 {
+			wg.Go(func() {
+				pingWebSocket(ctx, conn, cfg.pingInterval, cfg.closeTimeout)
+			})
+		}
+
+		fn(ctx, conn)
+
+		returned.Store(true)
+		cancel()
+		wg.Wait()
+
+		_ = conn.Close()
+	}
+}
+
+// closeWebSocket sends a close message with the specified code and sets a deadline
+// to let the handler receive the close reply from the client.
+func closeWebSocket(conn *websocket.Conn, code int, timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+	msg := websocket.FormatCloseMessage(code, "")
+
+	_ = conn.WriteControl(websocket.CloseMessage, msg, deadline)
+	_ = conn.SetReadDeadline(deadline)
+}
+
+// pingWebSocket periodically sends ping messages to keep the connection alive.
+func pingWebSocket(ctx context.Context, conn *websocket.Conn, interval, timeout time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(timeout))
+			if err != nil {
+				return
+			}
+		}
+	}
+}
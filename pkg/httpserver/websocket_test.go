@@ -0,0 +1,144 @@
+package httpserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+func echoWebSocket(_ context.Context, conn *websocket.Conn) {
+	for {
+		mt, msg, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		err = conn.WriteMessage(mt, msg)
+		if err != nil {
+			return
+		}
+	}
+}
+
+func TestWebSocketHandler_upgradeError(t *testing.T) {
+	t.Parallel()
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/ws", nil)
+
+	WebSocketHandler(echoWebSocket).ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestWebSocketHandler_shuttingDown(t *testing.T) {
+	t.Parallel()
+
+	st := newStreamTracker()
+	st.shutdown()
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequestWithContext(withStreamTracker(t.Context(), st), http.MethodGet, "/ws", nil)
+
+	WebSocketHandler(echoWebSocket).ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusServiceUnavailable, rr.Code)
+}
+
+type webSocketBinder struct{}
+
+func (b *webSocketBinder) BindHTTP(_ context.Context) []Route {
+	return []Route{
+		{
+			Method: http.MethodGet,
+			Path:   "/ws",
+			Handler: WebSocketHandler(
+				echoWebSocket,
+				WithWebSocketPingInterval(5*time.Millisecond),
+				WithWebSocketCloseTimeout(1*time.Second),
+			),
+			DisableTimeout: true,
+		},
+		{
+			Method: http.MethodGet,
+			Path:   "/ws/bye",
+			Handler: WebSocketHandler(func(_ context.Context, conn *websocket.Conn) {
+				_ = conn.WriteMessage(websocket.TextMessage, []byte("bye"))
+			}),
+			DisableTimeout: true,
+		},
+	}
+}
+
+func TestWebSocketHandler(t *testing.T) {
+	t.Parallel()
+
+	shutdownWG := &sync.WaitGroup{}
+	shutdownSG := make(chan struct{})
+
+	h, err := New(t.Context(), &webSocketBinder{},
+		WithServerAddr(":33118"),
+		WithRequestTimeout(10*time.Millisecond),
+		WithServerWriteTimeout(10*time.Millisecond),
+		WithShutdownWaitGroup(shutdownWG),
+		WithShutdownSignalChan(shutdownSG),
+	)
+	require.NoError(t, err)
+
+	h.StartServerCtx(t.Context())
+
+	pings := make(chan struct{}, 100)
+
+	conn, resp, err := websocket.DefaultDialer.DialContext(t.Context(), "ws://127.0.0.1:33118/ws", nil)
+	require.NoError(t, err)
+
+	defer func() {
+		_ = resp.Body.Close()
+		_ = conn.Close()
+	}()
+
+	conn.SetPingHandler(func(_ string) error {
+		pings <- struct{}{}
+		return nil
+	})
+
+	// the connection must survive beyond the request and write timeouts
+	time.Sleep(50 * time.Millisecond)
+
+	err = conn.WriteMessage(websocket.TextMessage, []byte("hello"))
+	require.NoError(t, err)
+
+	_, msg, err := conn.ReadMessage()
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(msg))
+	require.NotEmpty(t, pings)
+
+	// the handler returning closes the connection normally
+	byeConn, byeResp, err := websocket.DefaultDialer.DialContext(t.Context(), "ws://127.0.0.1:33118/ws/bye", nil)
+	require.NoError(t, err)
+
+	defer func() {
+		_ = byeResp.Body.Close()
+		_ = byeConn.Close()
+	}()
+
+	_, msg, err = byeConn.ReadMessage()
+	require.NoError(t, err)
+	require.Equal(t, "bye", string(msg))
+
+	_, _, err = byeConn.ReadMessage()
+	require.True(t, websocket.IsCloseError(err, websocket.CloseNormalClosure))
+
+	close(shutdownSG)
+
+	_, _, err = conn.ReadMessage()
+	require.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway))
+
+	shutdownWG.Wait()
+}
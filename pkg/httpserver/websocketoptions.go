@@ -0,0 +1,71 @@
+package httpserver
+
+import (
+	"net/http"
+	"time"
+)
+
+// WebSocketOption is a type alias for a function that configures the WebSocket handler.
+type WebSocketOption func(*webSocketConfig)
+
+type webSocketConfig struct {
+	handshakeTimeout time.Duration
+	closeTimeout     time.Duration
+	pingInterval     time.Duration
+	readBufferSize   int
+	writeBufferSize  int
+	subprotocols     []string
+	checkOrigin      func(r *http.Request) bool
+}
+
+func defaultWebSocketConfig() *webSocketConfig {
+	return &webSocketConfig{
+		handshakeTimeout: 10 * time.Second,
+		closeTimeout:     5 * time.Second,
+	}
+}
+
+// WithWebSocketHandshakeTimeout sets the time limit for the upgrade handshake.
+func WithWebSocketHandshakeTimeout(timeout time.Duration) WebSocketOption {
+	return func(cfg *webSocketConfig) {
+		cfg.handshakeTimeout = timeout
+	}
+}
+
+// WithWebSocketCloseTimeout sets the time limit to wait for the client close reply on shutdown.
+func WithWebSocketCloseTimeout(timeout time.Duration) WebSocketOption {
+	return func(cfg *webSocketConfig) {
+		cfg.closeTimeout = timeout
+	}
+}
+
+// WithWebSocketPingInterval enables sending ping messages at the specified interval to keep the connection alive.
+func WithWebSocketPingInterval(interval time.Duration) WebSocketOption {
+	return func(cfg *webSocketConfig) {
+		cfg.pingInterval = interval
+	}
+}
+
+// WithWebSocketBufferSize sets the I/O buffer sizes in bytes.
+// If a buffer size is zero, then the buffers allocated by the HTTP server are used.
+func WithWebSocketBufferSize(read, write int) WebSocketOption {
+	return func(cfg *webSocketConfig) {
+		cfg.readBufferSize = read
+		cfg.writeBufferSize = write
+	}
+}
+
+// WithWebSocketSubprotocols sets the server supported protocols in order of preference.
+func WithWebSocketSubprotocols(protocols ...string) WebSocketOption {
+	return func(cfg *webSocketConfig) {
+		cfg.subprotocols = protocols
+	}
+}
+
+// WithWebSocketCheckOrigin sets the function used to validate the request Origin header.
+// By default the requests with an Origin host different from the Host header are rejected.
+func WithWebSocketCheckOrigin(fn func(r *http.Request) bool) WebSocketOption {
+	return func(cfg *webSocketConfig) {
+		cfg.checkOrigin = fn
+	}
+}
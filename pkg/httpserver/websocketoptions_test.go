@@ -0,0 +1,62 @@
+package httpserver
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithWebSocketHandshakeTimeout(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultWebSocketConfig()
+	v := 3 * time.Second
+	WithWebSocketHandshakeTimeout(v)(cfg)
+	require.Equal(t, v, cfg.handshakeTimeout)
+}
+
+func TestWithWebSocketCloseTimeout(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultWebSocketConfig()
+	v := 7 * time.Second
+	WithWebSocketCloseTimeout(v)(cfg)
+	require.Equal(t, v, cfg.closeTimeout)
+}
+
+func TestWithWebSocketPingInterval(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultWebSocketConfig()
+	v := 11 * time.Second
+	WithWebSocketPingInterval(v)(cfg)
+	require.Equal(t, v, cfg.pingInterval)
+}
+
+func TestWithWebSocketBufferSize(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultWebSocketConfig()
+	WithWebSocketBufferSize(1024, 2048)(cfg)
+	require.Equal(t, 1024, cfg.readBufferSize)
+	require.Equal(t, 2048, cfg.writeBufferSize)
+}
+
+func TestWithWebSocketSubprotocols(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultWebSocketConfig()
+	WithWebSocketSubprotocols("alpha", "beta")(cfg)
+	require.Equal(t, []string{"alpha", "beta"}, cfg.subprotocols)
+}
+
+func TestWithWebSocketCheckOrigin(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultWebSocketConfig()
+	WithWebSocketCheckOrigin(func(_ *http.Request) bool { return true })(cfg)
+	require.NotNil(t, cfg.checkOrigin)
+	require.True(t, cfg.checkOrigin(nil))
+}
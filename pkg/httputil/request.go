@@ -38,8 +38,13 @@ func AddBearerToken(token string, r *http.Request) {
 }
 
 // PathParam returns the value from the named path segment.
+// It supports both httprouter and http.ServeMux path parameters.
 func PathParam(r *http.Request, name string) string {
 	v := httprouter.ParamsFromContext(r.Context()).ByName(name)
+	if v == "" {
+		v = r.PathValue(name)
+	}
+
 	return strings.TrimLeft(v, "/")
 }
 
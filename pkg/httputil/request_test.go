@@ -90,6 +90,24 @@ func TestPathParam(t *testing.T) {
 	}
 }
 
+func TestPathParam_serveMux(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /resource/{id}", func(w http.ResponseWriter, r *http.Request) {
+		SendText(r.Context(), w, http.StatusOK, PathParam(r, "id"))
+	})
+
+	rr := httptest.NewRecorder()
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "/resource/test-12345", nil)
+	require.NoError(t, err)
+
+	mux.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, "test-12345", rr.Body.String())
+}
+
 func TestHeaderOrDefault(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,335 @@
+/*
+Package idempotency provides an HTTP middleware that honours the
+Idempotency-Key request header, protecting the write endpoints from duplicated
+requests (e.g. retried by github.com/Vonage/gosrvlib/pkg/httpretrier).
+
+The first request with a given key is processed and its response (status,
+headers and body) is stored in a pluggable Store. The duplicate requests with
+the same key receive:
+  - the stored response, with the Idempotent-Replayed header set to "true";
+  - 409 Conflict while the first request is still in flight;
+  - 422 Unprocessable Entity if the method, path, query or body differ from
+    the first request (key reuse with a different payload).
+
+Server errors (5xx) are not stored, so the request can be retried.
+
+The keys are scoped by the required ScopeFn (e.g. the authenticated principal),
+so the same key sent by different clients never collides. The scope is hashed
+before being included in the store key. Each in-flight request holds the key
+with a unique owner token: if the lock expires and the key is reserved again,
+the late response is not stored (ErrLockLost).
+
+Available stores are the in-memory MemoryStore, the RedisStore backed by a
+github.com/redis/go-redis/v9 client, the ValkeyStore backed by a
+github.com/Vonage/gosrvlib/pkg/valkey client, and the SQLStore.
+
+The MiddlewareFn method can be used as a github.com/Vonage/gosrvlib/pkg/httpserver
+middleware, for example in the Route.Middleware list of the write routes.
+*/
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/httpserver"
+	"github.com/Vonage/gosrvlib/pkg/httputil"
+	"github.com/Vonage/gosrvlib/pkg/logging"
+	"github.com/Vonage/gosrvlib/pkg/uidc"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultHeader is the default name of the request header containing the idempotency key.
+	DefaultHeader = "Idempotency-Key"
+
+	// HeaderReplayed is the response header set on the replayed responses.
+	HeaderReplayed = "Idempotent-Replayed"
+
+	// DefaultTTL is the default retention time of the completed responses.
+	DefaultTTL = 24 * time.Hour
+
+	// DefaultLockTTL is the default maximum time a key is reserved for an in-flight request.
+	DefaultLockTTL = 1 * time.Minute
+
+	// DefaultKeyPrefix is the default prefix of the keys in the store.
+	DefaultKeyPrefix = "idempotency:"
+
+	// DefaultMaxBodySize is the default maximum size in bytes of the request body.
+	DefaultMaxBodySize = 1 << 20
+)
+
+// errBodyTooLarge is returned when the request body exceeds the maximum size.
+var errBodyTooLarge = errors.New("request body too large")
+
+// ScopeFn returns the scope of a request (e.g. the authenticated principal or tenant).
+// The idempotency keys are unique within each scope.
+type ScopeFn func(r *http.Request) string
+
+// AuthorizationScope is a ScopeFn that uses the Authorization request header as scope.
+func AuthorizationScope(r *http.Request) string {
+	return r.Header.Get("Authorization")
+}
+
+// Middleware is the idempotency middleware.
+type Middleware struct {
+	store       Store
+	scopeFn     ScopeFn
+	header      string
+	ttl         time.Duration
+	lockTTL     time.Duration
+	methods     []string
+	required    bool
+	keyPrefix   string
+	maxBodySize int64
+}
+
+// New creates a new idempotency middleware with the specified store and scope function.
+// If scopeFn is nil, AuthorizationScope is used.
+func New(store Store, scopeFn ScopeFn, opts ...Option) *Middleware {
+	if scopeFn == nil {
+		scopeFn = AuthorizationScope
+	}
+
+	m := &Middleware{
+		store:       store,
+		scopeFn:     scopeFn,
+		header:      DefaultHeader,
+		ttl:         DefaultTTL,
+		lockTTL:     DefaultLockTTL,
+		methods:     defaultMethods(),
+		keyPrefix:   DefaultKeyPrefix,
+		maxBodySize: DefaultMaxBodySize,
+	}
+
+	for _, applyOpt := range opts {
+		applyOpt(m)
+	}
+
+	return m
+}
+
+// MiddlewareFn is the httpserver.MiddlewareFn implementation.
+func (m *Middleware) MiddlewareFn(_ httpserver.MiddlewareArgs, next http.Handler) http.Handler {
+	return m.Handler(next)
+}
+
+// Handler returns the idempotency middleware for the next handler.
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !slices.Contains(m.methods, r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		key := r.Header.Get(m.header)
+		if key == "" {
+			if m.required {
+				httputil.SendStatus(r.Context(), w, http.StatusBadRequest)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		m.serve(w, r, next, m.storeKey(r, key))
+	})
+}
+
+// storeKey returns the key in the store, including the hash of the request scope.
+func (m *Middleware) storeKey(r *http.Request, key string) string {
+	scope := sha256.Sum256([]byte(m.scopeFn(r)))
+
+	return m.keyPrefix + hex.EncodeToString(scope[:]) + ":" + key
+}
+
+// serve processes a request with an idempotency key.
+func (m *Middleware) serve(w http.ResponseWriter, r *http.Request, next http.Handler, key string) {
+	ctx := r.Context()
+
+	fingerprint, err := m.fingerprint(r)
+	if errors.Is(err, errBodyTooLarge) {
+		httputil.SendStatus(ctx, w, http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	if err != nil {
+		httputil.SendStatus(ctx, w, http.StatusBadRequest)
+		return
+	}
+
+	lock := &Record{
+		Fingerprint: fingerprint,
+		Token:       uidc.NewID128(),
+	}
+
+	rec, ok, err := m.store.Begin(ctx, key, lock, m.lockTTL)
+	if err != nil {
+		logging.FromContext(ctx).Error("idempotency store failed", zap.Error(err))
+		httputil.SendStatus(ctx, w, http.StatusInternalServerError)
+
+		return
+	}
+
+	if !ok {
+		replay(w, r, rec, fingerprint)
+		return
+	}
+
+	rw := &recordWriter{ResponseWriter: w}
+	completed := false
+
+	defer func() {
+		if !completed {
+			// the handler panicked: release the key so the request can be retried
+			m.release(context.WithoutCancel(ctx), key, lock)
+		}
+	}()
+
+	next.ServeHTTP(rw, r)
+
+	completed = true
+
+	m.complete(context.WithoutCancel(ctx), key, lock, rw.record(fingerprint))
+}
+
+// complete stores the response, or releases the key for server errors.
+func (m *Middleware) complete(ctx context.Context, key string, lock, rec *Record) {
+	if rec.Response.StatusCode >= http.StatusInternalServerError {
+		m.release(ctx, key, lock)
+		return
+	}
+
+	logStoreError(ctx, m.store.Complete(ctx, key, lock, rec, m.ttl))
+}
+
+// release removes the key from the store.
+func (m *Middleware) release(ctx context.Context, key string, lock *Record) {
+	logStoreError(ctx, m.store.Delete(ctx, key, lock))
+}
+
+// logStoreError logs the errors of the store operations after the request has been processed.
+func logStoreError(ctx context.Context, err error) {
+	if errors.Is(err, ErrLockLost) {
+		logging.FromContext(ctx).Warn("idempotency key lock lost, the lock TTL should be greater than the request timeout")
+		return
+	}
+
+	if err != nil {
+		logging.FromContext(ctx).Error("idempotency store failed", zap.Error(err))
+	}
+}
+
+// fingerprint returns the hash of the request method, path, query and body, and restores the body.
+func (m *Middleware) fingerprint(r *http.Request) (string, error) {
+	h := sha256.New()
+	h.Write([]byte(r.Method + " " + r.URL.Path + "?" + r.URL.RawQuery + "\n"))
+
+	if r.Body != nil && r.Body != http.NoBody {
+		body, err := io.ReadAll(io.LimitReader(r.Body, m.maxBodySize+1))
+		if err != nil {
+			return "", err //nolint:wrapcheck
+		}
+
+		if int64(len(body)) > m.maxBodySize {
+			return "", errBodyTooLarge
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		h.Write(body)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// replay answers a duplicate request.
+func replay(w http.ResponseWriter, r *http.Request, rec *Record, fingerprint string) {
+	ctx := r.Context()
+
+	if rec.Fingerprint != fingerprint {
+		httputil.SendStatus(ctx, w, http.StatusUnprocessableEntity)
+		return
+	}
+
+	if rec.Response == nil {
+		httputil.SendStatus(ctx, w, http.StatusConflict)
+		return
+	}
+
+	h := w.Header()
+	maps.Copy(h, rec.Response.Header)
+	h.Set(HeaderReplayed, "true")
+	h.Set("Content-Length", strconv.Itoa(len(rec.Response.Body)))
+	w.WriteHeader(rec.Response.StatusCode)
+
+	_, err := w.Write(rec.Response.Body)
+	if err != nil {
+		logging.FromContext(ctx).Error("idempotency replay failed", zap.Error(err))
+	}
+}
+
+// recordWriter writes the response and records a copy of it.
+type recordWriter struct {
+	http.ResponseWriter
+
+	status int
+	header http.Header
+	body   bytes.Buffer
+}
+
+// WriteHeader records the status code and the header snapshot.
+func (rw *recordWriter) WriteHeader(code int) {
+	if rw.status == 0 {
+		rw.status = code
+		rw.header = rw.Header().Clone()
+	}
+
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+// Write records and writes the response body.
+func (rw *recordWriter) Write(b []byte) (int, error) {
+	if rw.status == 0 {
+		rw.WriteHeader(http.StatusOK)
+	}
+
+	rw.body.Write(b)
+
+	return rw.ResponseWriter.Write(b) //nolint:wrapcheck
+}
+
+// Unwrap returns the underlying ResponseWriter.
+func (rw *recordWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// record returns the recorded response.
+func (rw *recordWriter) record(fingerprint string) *Record {
+	resp := &Response{
+		StatusCode: rw.status,
+		Header:     rw.header,
+		Body:       rw.body.Bytes(),
+	}
+
+	if resp.StatusCode == 0 {
+		resp.StatusCode = http.StatusOK
+		resp.Header = rw.Header().Clone()
+	}
+
+	return &Record{
+		Fingerprint: fingerprint,
+		Response:    resp,
+	}
+}
@@ -0,0 +1,274 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/httpserver"
+	"github.com/stretchr/testify/require"
+)
+
+func doRequest(t *testing.T, h http.Handler, method, body, key string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	r := httptest.NewRequestWithContext(t.Context(), method, "/orders", strings.NewReader(body))
+	if key != "" {
+		r.Header.Set(DefaultHeader, key)
+	}
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, r)
+
+	return rr
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	calls := &atomic.Int32{}
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+
+		body, _ := io.ReadAll(r.Body)
+
+		switch string(body) {
+		case "fail":
+			http.Error(w, "fail", http.StatusServiceUnavailable)
+		case "empty":
+			w.Header().Set("X-Test", "empty")
+		default:
+			w.Header().Set("X-Test", "created")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write(append([]byte("order "), body...))
+		}
+	})
+
+	m := New(NewMemoryStore(t.Context(), 0), nil)
+	h := m.MiddlewareFn(httpserver.MiddlewareArgs{}, next)
+
+	rr := doRequest(t, h, http.MethodPost, "1", "k1")
+	require.Equal(t, http.StatusCreated, rr.Code)
+	require.Equal(t, "order 1", rr.Body.String())
+	require.Empty(t, rr.Header().Get(HeaderReplayed))
+
+	rr = doRequest(t, h, http.MethodPost, "1", "k1")
+	require.Equal(t, http.StatusCreated, rr.Code)
+	require.Equal(t, "order 1", rr.Body.String())
+	require.Equal(t, "created", rr.Header().Get("X-Test"))
+	require.Equal(t, "true", rr.Header().Get(HeaderReplayed))
+	require.Equal(t, int32(1), calls.Load())
+
+	rr = doRequest(t, h, http.MethodPost, "2", "k1")
+	require.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+
+	rr = doRequest(t, h, http.MethodPatch, "1", "k1")
+	require.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+
+	rr = doRequest(t, h, http.MethodPost, "empty", "k2")
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	rr = doRequest(t, h, http.MethodPost, "empty", "k2")
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, "empty", rr.Header().Get("X-Test"))
+	require.Equal(t, "true", rr.Header().Get(HeaderReplayed))
+	require.Equal(t, int32(2), calls.Load())
+
+	// server errors are not stored
+
+	rr = doRequest(t, h, http.MethodPost, "fail", "k3")
+	require.Equal(t, http.StatusServiceUnavailable, rr.Code)
+
+	rr = doRequest(t, h, http.MethodPost, "fail", "k3")
+	require.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	require.Empty(t, rr.Header().Get(HeaderReplayed))
+	require.Equal(t, int32(4), calls.Load())
+
+	// no key or other methods
+
+	doRequest(t, h, http.MethodPost, "1", "")
+	doRequest(t, h, http.MethodPut, "1", "k1")
+	require.Equal(t, int32(6), calls.Load())
+
+	// key required
+
+	h = New(NewMemoryStore(t.Context(), 0), nil, WithRequired()).Handler(next)
+	rr = doRequest(t, h, http.MethodPost, "1", "")
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+
+	// body too large
+
+	h = New(NewMemoryStore(t.Context(), 0), nil, WithMaxBodySize(3)).Handler(next)
+	rr = doRequest(t, h, http.MethodPost, "1234", "k1")
+	require.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	require.Equal(t, int32(6), calls.Load())
+}
+
+func TestMiddleware_query(t *testing.T) {
+	t.Parallel()
+
+	calls := &atomic.Int32{}
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	h := New(NewMemoryStore(t.Context(), 0), nil).Handler(next)
+
+	do := func(target string) int {
+		r := httptest.NewRequestWithContext(t.Context(), http.MethodPost, target, strings.NewReader("1"))
+		r.Header.Set(DefaultHeader, "k1")
+
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, r)
+
+		return rr.Code
+	}
+
+	require.Equal(t, http.StatusCreated, do("/orders?amount=10"))
+	require.Equal(t, http.StatusCreated, do("/orders?amount=10"))
+	require.Equal(t, http.StatusUnprocessableEntity, do("/orders?amount=20"))
+	require.Equal(t, http.StatusUnprocessableEntity, do("/orders"))
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestMiddleware_inFlight(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	h := New(NewMemoryStore(t.Context(), 0), nil).Handler(next)
+
+	done := make(chan int)
+
+	go func() {
+		r := httptest.NewRequestWithContext(context.Background(), http.MethodPost, "/orders", strings.NewReader("1"))
+		r.Header.Set(DefaultHeader, "k1")
+
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, r)
+		done <- rr.Code
+	}()
+
+	<-started
+
+	rr := doRequest(t, h, http.MethodPost, "1", "k1")
+	require.Equal(t, http.StatusConflict, rr.Code)
+
+	close(release)
+	require.Equal(t, http.StatusAccepted, <-done)
+
+	rr = doRequest(t, h, http.MethodPost, "1", "k1")
+	require.Equal(t, http.StatusAccepted, rr.Code)
+	require.Equal(t, "true", rr.Header().Get(HeaderReplayed))
+}
+
+func TestMiddleware_panic(t *testing.T) {
+	t.Parallel()
+
+	store := NewMemoryStore(t.Context(), 0)
+
+	h := New(store, nil).Handler(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		panic("test")
+	}))
+
+	require.Panics(t, func() { doRequest(t, h, http.MethodPost, "1", "k1") })
+
+	// the key has been released
+	h = New(store, nil).Handler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	rr := doRequest(t, h, http.MethodPost, "1", "k1")
+	require.Equal(t, http.StatusCreated, rr.Code)
+	require.Empty(t, rr.Header().Get(HeaderReplayed))
+}
+
+func TestMiddleware_scope(t *testing.T) {
+	t.Parallel()
+
+	calls := &atomic.Int32{}
+
+	h := New(NewMemoryStore(t.Context(), 0), AuthorizationScope).Handler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	for _, auth := range []string{"Bearer alice", "Bearer bob", "Bearer alice"} {
+		r := httptest.NewRequestWithContext(t.Context(), http.MethodPost, "/orders", strings.NewReader("1"))
+		r.Header.Set(DefaultHeader, "k1")
+		r.Header.Set("Authorization", auth)
+
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, r)
+		require.Equal(t, http.StatusCreated, rr.Code)
+	}
+
+	require.Equal(t, int32(2), calls.Load())
+}
+
+type errStore struct {
+	beginErr error
+}
+
+func (s *errStore) Begin(_ context.Context, _ string, _ *Record, _ time.Duration) (*Record, bool, error) {
+	return nil, s.beginErr == nil, s.beginErr
+}
+
+func (s *errStore) Complete(_ context.Context, _ string, _, _ *Record, _ time.Duration) error {
+	return ErrLockLost
+}
+
+func (s *errStore) Delete(_ context.Context, _ string, _ *Record) error {
+	return errors.New("delete error")
+}
+
+type errReader struct{}
+
+func (errReader) Read(_ []byte) (int, error) {
+	return 0, errors.New("read error")
+}
+
+func TestMiddleware_errors(t *testing.T) {
+	t.Parallel()
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) == "fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+
+	h := New(&errStore{beginErr: errors.New("begin error")}, nil).Handler(next)
+	rr := doRequest(t, h, http.MethodPost, "1", "k1")
+	require.Equal(t, http.StatusInternalServerError, rr.Code)
+
+	h = New(&errStore{}, nil).Handler(next)
+	rr = doRequest(t, h, http.MethodPost, "1", "k1")
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	rr = doRequest(t, h, http.MethodPost, "fail", "k1")
+	require.Equal(t, http.StatusInternalServerError, rr.Code)
+
+	r := httptest.NewRequestWithContext(t.Context(), http.MethodPost, "/orders", errReader{})
+	r.Header.Set(DefaultHeader, "k1")
+
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, r)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+}
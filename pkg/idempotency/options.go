@@ -0,0 +1,65 @@
+package idempotency
+
+import (
+	"net/http"
+	"time"
+)
+
+// Option is the interface that allows to set the middleware options.
+type Option func(m *Middleware)
+
+// WithHeader sets the name of the request header containing the idempotency key.
+func WithHeader(name string) Option {
+	return func(m *Middleware) {
+		m.header = name
+	}
+}
+
+// WithTTL sets the retention time of the completed responses.
+func WithTTL(ttl time.Duration) Option {
+	return func(m *Middleware) {
+		m.ttl = ttl
+	}
+}
+
+// WithLockTTL sets the maximum time a key is reserved for an in-flight request.
+// It should be greater than the request timeout.
+func WithLockTTL(ttl time.Duration) Option {
+	return func(m *Middleware) {
+		m.lockTTL = ttl
+	}
+}
+
+// WithMethods sets the HTTP methods the idempotency keys are processed for.
+func WithMethods(methods ...string) Option {
+	return func(m *Middleware) {
+		m.methods = methods
+	}
+}
+
+// WithRequired rejects the requests without the idempotency key with 400 Bad Request.
+func WithRequired() Option {
+	return func(m *Middleware) {
+		m.required = true
+	}
+}
+
+// WithKeyPrefix sets the prefix of all the keys (namespace) in the store.
+func WithKeyPrefix(prefix string) Option {
+	return func(m *Middleware) {
+		m.keyPrefix = prefix
+	}
+}
+
+// WithMaxBodySize sets the maximum size in bytes of the request body used to compute the fingerprint.
+// Larger requests are rejected with 413 Request Entity Too Large.
+func WithMaxBodySize(size int64) Option {
+	return func(m *Middleware) {
+		m.maxBodySize = size
+	}
+}
+
+// defaultMethods returns the default methods the idempotency keys are processed for.
+func defaultMethods() []string {
+	return []string{http.MethodPost, http.MethodPatch}
+}
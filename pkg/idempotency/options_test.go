@@ -0,0 +1,65 @@
+package idempotency
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithHeader(t *testing.T) {
+	t.Parallel()
+
+	m := &Middleware{}
+	WithHeader("X-Request-Key")(m)
+	require.Equal(t, "X-Request-Key", m.header)
+}
+
+func TestWithTTL(t *testing.T) {
+	t.Parallel()
+
+	m := &Middleware{}
+	WithTTL(time.Hour)(m)
+	require.Equal(t, time.Hour, m.ttl)
+}
+
+func TestWithLockTTL(t *testing.T) {
+	t.Parallel()
+
+	m := &Middleware{}
+	WithLockTTL(time.Second)(m)
+	require.Equal(t, time.Second, m.lockTTL)
+}
+
+func TestWithMethods(t *testing.T) {
+	t.Parallel()
+
+	m := &Middleware{}
+	WithMethods(http.MethodPut)(m)
+	require.Equal(t, []string{http.MethodPut}, m.methods)
+}
+
+func TestWithRequired(t *testing.T) {
+	t.Parallel()
+
+	m := &Middleware{}
+	WithRequired()(m)
+	require.True(t, m.required)
+}
+
+func TestWithKeyPrefix(t *testing.T) {
+	t.Parallel()
+
+	m := &Middleware{}
+	WithKeyPrefix("test:")(m)
+	require.Equal(t, "test:", m.keyPrefix)
+}
+
+func TestWithMaxBodySize(t *testing.T) {
+	t.Parallel()
+
+	m := &Middleware{}
+	WithMaxBodySize(17)(m)
+	require.Equal(t, int64(17), m.maxBodySize)
+}
@@ -0,0 +1,121 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	libredis "github.com/redis/go-redis/v9"
+)
+
+// completeScript atomically replaces the lock record (with expiration in milliseconds) only if it is still held.
+const completeScript = `if redis.call('GET', KEYS[1]) == ARGV[1] then
+  redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+  return 1
+end
+return 0`
+
+// releaseScript atomically deletes the lock record only if it is still held.
+const releaseScript = `if redis.call('GET', KEYS[1]) == ARGV[1] then
+  return redis.call('DEL', KEYS[1])
+end
+return 0`
+
+// RedisClient contains the methods of the github.com/redis/go-redis/v9 client used by the RedisStore
+// (e.g. *redis.Client or *redis.ClusterClient).
+type RedisClient interface {
+	Eval(ctx context.Context, script string, keys []string, args ...any) *libredis.Cmd
+	Get(ctx context.Context, key string) *libredis.StringCmd
+	SetNX(ctx context.Context, key string, value any, expiration time.Duration) *libredis.BoolCmd
+}
+
+// RedisStore is a Store backed by Redis.
+type RedisStore struct {
+	client RedisClient
+}
+
+// NewRedisStore creates a new Store backed by Redis.
+func NewRedisStore(client RedisClient) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// Begin atomically reserves the key for an in-flight request with the specified lock record
+// (fingerprint and owner token).
+// If the key already exists it returns the existing record and false.
+func (s *RedisStore) Begin(ctx context.Context, key string, lock *Record, ttl time.Duration) (*Record, bool, error) {
+	data, _ := json.Marshal(lock) //nolint:errchkjson
+
+	ok, err := s.client.SetNX(ctx, key, data, ttl).Result()
+	if err != nil {
+		return nil, false, fmt.Errorf("redis store: %w", err)
+	}
+
+	if ok {
+		return nil, true, nil
+	}
+
+	value, err := s.client.Get(ctx, key).Bytes()
+	if errors.Is(err, libredis.Nil) {
+		return nil, false, fmt.Errorf("redis store: the key %q expired: %w", key, err)
+	}
+
+	if err != nil {
+		return nil, false, fmt.Errorf("redis store: %w", err)
+	}
+
+	return decodeRecord(value)
+}
+
+// Complete stores the final record for the key, only if it is still reserved by the specified lock.
+// It returns ErrLockLost otherwise.
+func (s *RedisStore) Complete(ctx context.Context, key string, lock, rec *Record, ttl time.Duration) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("redis store: %w", err)
+	}
+
+	lockData, _ := json.Marshal(lock) //nolint:errchkjson
+
+	n, err := s.client.Eval(ctx, completeScript, []string{key}, lockData, data, ttl.Milliseconds()).Int()
+	if err != nil {
+		return fmt.Errorf("redis store: %w", err)
+	}
+
+	if n == 0 {
+		return ErrLockLost
+	}
+
+	return nil
+}
+
+// Delete removes the key, only if it is still reserved by the specified lock,
+// so the request can be retried.
+// It returns ErrLockLost otherwise.
+func (s *RedisStore) Delete(ctx context.Context, key string, lock *Record) error {
+	lockData, _ := json.Marshal(lock) //nolint:errchkjson
+
+	n, err := s.client.Eval(ctx, releaseScript, []string{key}, lockData).Int()
+	if err != nil {
+		return fmt.Errorf("redis store: %w", err)
+	}
+
+	if n == 0 {
+		return ErrLockLost
+	}
+
+	return nil
+}
+
+// decodeRecord decodes an existing JSON record.
+func decodeRecord(data []byte) (*Record, bool, error) {
+	rec := &Record{}
+
+	err := json.Unmarshal(data, rec)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid idempotency record: %w", err)
+	}
+
+	return rec, false, nil
+}
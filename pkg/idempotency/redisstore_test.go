@@ -0,0 +1,130 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	libredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+type redisClientMock struct {
+	data map[string][]byte
+	err  error
+}
+
+func (m *redisClientMock) SetNX(_ context.Context, key string, value any, _ time.Duration) *libredis.BoolCmd {
+	if m.err != nil {
+		return libredis.NewBoolResult(false, m.err)
+	}
+
+	if _, ok := m.data[key]; ok {
+		return libredis.NewBoolResult(false, nil)
+	}
+
+	m.data[key], _ = value.([]byte)
+
+	return libredis.NewBoolResult(true, nil)
+}
+
+func (m *redisClientMock) Eval(_ context.Context, script string, keys []string, args ...any) *libredis.Cmd {
+	if m.err != nil {
+		return libredis.NewCmdResult(nil, m.err)
+	}
+
+	key := keys[0]
+	lock, _ := args[0].([]byte)
+
+	if string(m.data[key]) != string(lock) {
+		return libredis.NewCmdResult(int64(0), nil)
+	}
+
+	switch script {
+	case completeScript:
+		m.data[key], _ = args[1].([]byte)
+	case releaseScript:
+		delete(m.data, key)
+	}
+
+	return libredis.NewCmdResult(int64(1), nil)
+}
+
+func (m *redisClientMock) Get(_ context.Context, key string) *libredis.StringCmd {
+	if key == "error" {
+		return libredis.NewStringResult("", errors.New("get error"))
+	}
+
+	v, ok := m.data[key]
+	if !ok {
+		return libredis.NewStringResult("", libredis.Nil)
+	}
+
+	return libredis.NewStringResult(string(v), nil)
+}
+
+func TestRedisStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	m := &redisClientMock{data: map[string][]byte{}}
+	s := NewRedisStore(m)
+	lock := &Record{Fingerprint: "fp1", Token: "t1"}
+
+	_, ok, err := s.Begin(ctx, "k1", lock, time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	rec, ok, err := s.Begin(ctx, "k1", &Record{Fingerprint: "fp1", Token: "t2"}, time.Minute)
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Equal(t, lock, rec)
+
+	want := &Record{Fingerprint: "fp1", Response: &Response{StatusCode: http.StatusCreated, Header: http.Header{"A": {"b"}}, Body: []byte("ok")}}
+	require.ErrorIs(t, s.Complete(ctx, "k1", &Record{Fingerprint: "fp1", Token: "t2"}, want, time.Minute), ErrLockLost)
+	require.NoError(t, s.Complete(ctx, "k1", lock, want, time.Minute))
+	require.ErrorIs(t, s.Complete(ctx, "k1", lock, want, time.Minute), ErrLockLost)
+
+	rec, _, err = s.Begin(ctx, "k1", lock, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, want, rec)
+
+	require.ErrorIs(t, s.Delete(ctx, "k1", lock), ErrLockLost)
+
+	_, _, err = s.Begin(ctx, "k2", lock, time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, s.Delete(ctx, "k2", lock))
+
+	m.data["error"] = []byte("{}")
+	_, _, err = s.Begin(ctx, "error", lock, time.Minute)
+	require.Error(t, err)
+
+	m.data["invalid"] = []byte("{")
+	_, _, err = s.Begin(ctx, "invalid", lock, time.Minute)
+	require.Error(t, err)
+
+	m.err = errors.New("test error")
+	_, _, err = s.Begin(ctx, "k3", lock, time.Minute)
+	require.Error(t, err)
+	require.Error(t, s.Complete(ctx, "k3", lock, want, time.Minute))
+	require.Error(t, s.Delete(ctx, "k3", lock))
+}
+
+type redisClientExpiredMock struct {
+	redisClientMock
+}
+
+func (m *redisClientExpiredMock) SetNX(_ context.Context, _ string, _ any, _ time.Duration) *libredis.BoolCmd {
+	return libredis.NewBoolResult(false, nil)
+}
+
+func TestRedisStore_expired(t *testing.T) {
+	t.Parallel()
+
+	s := NewRedisStore(&redisClientExpiredMock{redisClientMock{data: map[string][]byte{}}})
+
+	_, _, err := s.Begin(t.Context(), "k1", &Record{Fingerprint: "fp1"}, time.Minute)
+	require.ErrorIs(t, err, libredis.Nil)
+}
@@ -0,0 +1,157 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// SQLQueries contains the SQL queries used by the SQLStore.
+// The default queries (DefaultSQLQueries) use the "?" placeholders (e.g. MySQL, SQLite)
+// and can be replaced for other dialects (e.g. "$1" for PostgreSQL).
+type SQLQueries struct {
+	// DeleteExpired deletes the key if expired. Arguments: key, now (unix milliseconds).
+	DeleteExpired string
+
+	// Insert reserves the key. Arguments: key, fingerprint, token, expires_at (unix milliseconds).
+	Insert string
+
+	// Select returns the fingerprint and response of the key. Arguments: key.
+	Select string
+
+	// Update sets the response of the key, only if it is still reserved by the token and not expired.
+	// Arguments: response, expires_at (unix milliseconds), key, token, now (unix milliseconds).
+	Update string
+
+	// Delete deletes the key, only if it is still reserved by the token. Arguments: key, token.
+	Delete string
+}
+
+// DefaultSQLQueries returns the default queries for the specified table.
+//
+// Example of a MySQL database table that can be used with the default queries:
+//
+//	CREATE TABLE IF NOT EXISTS `idempotency_keys` (
+//	  `idempotency_key` VARCHAR(255) NOT NULL,
+//	  `fingerprint` CHAR(64) NOT NULL,
+//	  `token` VARCHAR(64) NOT NULL,
+//	  `response` MEDIUMBLOB NULL,
+//	  `expires_at` BIGINT NOT NULL,
+//	  PRIMARY KEY (`idempotency_key`))
+//	ENGINE = InnoDB;
+func DefaultSQLQueries(table string) *SQLQueries {
+	return &SQLQueries{
+		DeleteExpired: "DELETE FROM " + table + " WHERE idempotency_key = ? AND expires_at < ?",
+		Insert:        "INSERT INTO " + table + " (idempotency_key, fingerprint, token, expires_at) VALUES (?, ?, ?, ?)",
+		Select:        "SELECT fingerprint, response FROM " + table + " WHERE idempotency_key = ?",
+		Update:        "UPDATE " + table + " SET response = ?, expires_at = ? WHERE idempotency_key = ? AND token = ? AND response IS NULL AND expires_at >= ?",
+		Delete:        "DELETE FROM " + table + " WHERE idempotency_key = ? AND token = ? AND response IS NULL",
+	}
+}
+
+// SQLStore is a Store backed by a SQL database table.
+// The table primary key on the idempotency key guarantees the atomic reservation.
+type SQLStore struct {
+	db      *sql.DB
+	queries *SQLQueries
+}
+
+// NewSQLStore creates a new Store backed by a SQL database.
+func NewSQLStore(db *sql.DB, queries *SQLQueries) *SQLStore {
+	return &SQLStore{
+		db:      db,
+		queries: queries,
+	}
+}
+
+// Begin atomically reserves the key for an in-flight request with the specified lock record
+// (fingerprint and owner token).
+// If the key already exists it returns the existing record and false.
+func (s *SQLStore) Begin(ctx context.Context, key string, lock *Record, ttl time.Duration) (*Record, bool, error) {
+	now := time.Now()
+
+	_, err := s.db.ExecContext(ctx, s.queries.DeleteExpired, key, now.UnixMilli())
+	if err != nil {
+		return nil, false, fmt.Errorf("sql store: unable to delete the expired key: %w", err)
+	}
+
+	_, errInsert := s.db.ExecContext(ctx, s.queries.Insert, key, lock.Fingerprint, lock.Token, now.Add(ttl).UnixMilli())
+	if errInsert == nil {
+		return nil, true, nil
+	}
+
+	// the insert failed, most likely because of a duplicate key
+	var (
+		fp   string
+		resp []byte
+	)
+
+	err = s.db.QueryRowContext(ctx, s.queries.Select, key).Scan(&fp, &resp)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, fmt.Errorf("sql store: unable to reserve the key: %w", errInsert)
+	}
+
+	if err != nil {
+		return nil, false, fmt.Errorf("sql store: unable to read the key: %w", err)
+	}
+
+	rec := &Record{Fingerprint: fp}
+
+	if resp != nil {
+		rec.Response = &Response{}
+
+		err = json.Unmarshal(resp, rec.Response)
+		if err != nil {
+			return nil, false, fmt.Errorf("sql store: invalid response: %w", err)
+		}
+	}
+
+	return rec, false, nil
+}
+
+// Complete stores the final record for the key, only if it is still reserved by the specified lock.
+// It returns ErrLockLost otherwise.
+func (s *SQLStore) Complete(ctx context.Context, key string, lock, rec *Record, ttl time.Duration) error {
+	resp, err := json.Marshal(rec.Response)
+	if err != nil {
+		return fmt.Errorf("sql store: %w", err)
+	}
+
+	now := time.Now()
+
+	res, err := s.db.ExecContext(ctx, s.queries.Update, resp, now.Add(ttl).UnixMilli(), key, lock.Token, now.UnixMilli())
+	if err != nil {
+		return fmt.Errorf("sql store: unable to update the key: %w", err)
+	}
+
+	return checkLocked(res)
+}
+
+// Delete removes the key, only if it is still reserved by the specified lock,
+// so the request can be retried.
+// It returns ErrLockLost otherwise.
+func (s *SQLStore) Delete(ctx context.Context, key string, lock *Record) error {
+	res, err := s.db.ExecContext(ctx, s.queries.Delete, key, lock.Token)
+	if err != nil {
+		return fmt.Errorf("sql store: unable to delete the key: %w", err)
+	}
+
+	return checkLocked(res)
+}
+
+// checkLocked returns ErrLockLost if no rows were affected by the fenced query.
+func checkLocked(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("sql store: unable to read the affected rows: %w", err)
+	}
+
+	if n == 0 {
+		return ErrLockLost
+	}
+
+	return nil
+}
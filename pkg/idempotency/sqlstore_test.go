@@ -0,0 +1,157 @@
+package idempotency
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLStore_Begin(t *testing.T) {
+	t.Parallel()
+
+	q := DefaultSQLQueries("idempotency_keys")
+
+	tests := []struct {
+		name       string
+		setupMocks func(mock sqlmock.Sqlmock)
+		want       *Record
+		wantOK     bool
+		wantErr    bool
+	}{
+		{
+			name: "reserved",
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(q.DeleteExpired)).WithArgs("k1", sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta(q.Insert)).WithArgs("k1", "fp1", "t1", sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantOK: true,
+		},
+		{
+			name: "in flight",
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(q.DeleteExpired)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta(q.Insert)).WillReturnError(errors.New("duplicate key"))
+				mock.ExpectQuery(regexp.QuoteMeta(q.Select)).WithArgs("k1").
+					WillReturnRows(sqlmock.NewRows([]string{"fingerprint", "response"}).AddRow("fp1", nil))
+			},
+			want: &Record{Fingerprint: "fp1"},
+		},
+		{
+			name: "completed",
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(q.DeleteExpired)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta(q.Insert)).WillReturnError(errors.New("duplicate key"))
+				mock.ExpectQuery(regexp.QuoteMeta(q.Select)).WithArgs("k1").
+					WillReturnRows(sqlmock.NewRows([]string{"fingerprint", "response"}).AddRow("fp1", []byte(`{"status":201,"header":null,"body":"b2s="}`)))
+			},
+			want: &Record{Fingerprint: "fp1", Response: &Response{StatusCode: http.StatusCreated, Body: []byte("ok")}},
+		},
+		{
+			name: "invalid response",
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(q.DeleteExpired)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta(q.Insert)).WillReturnError(errors.New("duplicate key"))
+				mock.ExpectQuery(regexp.QuoteMeta(q.Select)).
+					WillReturnRows(sqlmock.NewRows([]string{"fingerprint", "response"}).AddRow("fp1", []byte(`{`)))
+			},
+			wantErr: true,
+		},
+		{
+			name: "insert error",
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(q.DeleteExpired)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta(q.Insert)).WillReturnError(errors.New("insert error"))
+				mock.ExpectQuery(regexp.QuoteMeta(q.Select)).
+					WillReturnRows(sqlmock.NewRows([]string{"fingerprint", "response"}))
+			},
+			wantErr: true,
+		},
+		{
+			name: "select error",
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(q.DeleteExpired)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta(q.Insert)).WillReturnError(errors.New("duplicate key"))
+				mock.ExpectQuery(regexp.QuoteMeta(q.Select)).WillReturnError(errors.New("select error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "delete expired error",
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(q.DeleteExpired)).WillReturnError(errors.New("delete error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			t.Cleanup(func() { _ = db.Close() })
+
+			tt.setupMocks(mock)
+
+			rec, ok, err := NewSQLStore(db, q).Begin(t.Context(), "k1", &Record{Fingerprint: "fp1", Token: "t1"}, time.Minute)
+			require.NoError(t, mock.ExpectationsWereMet())
+			require.Equal(t, tt.wantOK, ok)
+			require.Equal(t, tt.want, rec)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestSQLStore_CompleteDelete(t *testing.T) {
+	t.Parallel()
+
+	q := DefaultSQLQueries("idempotency_keys")
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = db.Close() })
+
+	s := NewSQLStore(db, q)
+	ctx := t.Context()
+	lock := &Record{Fingerprint: "fp1", Token: "t1"}
+	rec := &Record{Fingerprint: "fp1", Response: &Response{StatusCode: http.StatusCreated, Body: []byte("ok")}}
+
+	mock.ExpectExec(regexp.QuoteMeta(q.Update)).
+		WithArgs([]byte(`{"status":201,"header":null,"body":"b2s="}`), sqlmock.AnyArg(), "k1", "t1", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, s.Complete(ctx, "k1", lock, rec, time.Minute))
+
+	mock.ExpectExec(regexp.QuoteMeta(q.Update)).WillReturnResult(sqlmock.NewResult(0, 0))
+	require.ErrorIs(t, s.Complete(ctx, "k1", lock, rec, time.Minute), ErrLockLost)
+
+	mock.ExpectExec(regexp.QuoteMeta(q.Update)).WillReturnResult(sqlmock.NewErrorResult(errors.New("result error")))
+	require.Error(t, s.Complete(ctx, "k1", lock, rec, time.Minute))
+
+	mock.ExpectExec(regexp.QuoteMeta(q.Update)).WillReturnError(errors.New("update error"))
+	require.Error(t, s.Complete(ctx, "k1", lock, rec, time.Minute))
+
+	mock.ExpectExec(regexp.QuoteMeta(q.Delete)).WithArgs("k1", "t1").WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, s.Delete(ctx, "k1", lock))
+
+	mock.ExpectExec(regexp.QuoteMeta(q.Delete)).WithArgs("k1", "t1").WillReturnResult(sqlmock.NewResult(0, 0))
+	require.ErrorIs(t, s.Delete(ctx, "k1", lock), ErrLockLost)
+
+	mock.ExpectExec(regexp.QuoteMeta(q.Delete)).WillReturnError(errors.New("delete error"))
+	require.Error(t, s.Delete(ctx, "k1", lock))
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,177 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Response is the stored response of a completed request.
+type Response struct {
+	StatusCode int         `json:"status"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+}
+
+// DefaultMemoryEvictInterval is the default interval between the evictions of the expired MemoryStore entries.
+const DefaultMemoryEvictInterval = 1 * time.Minute
+
+// ErrLockLost is returned when a key is no longer reserved by the in-flight request,
+// because the lock expired and the key was deleted or reserved by another request.
+var ErrLockLost = errors.New("the idempotency key lock was lost")
+
+// Record is the stored state of an idempotency key.
+type Record struct {
+	// Fingerprint is the hash of the request method, path, query and body.
+	Fingerprint string `json:"fingerprint"`
+
+	// Token is the unique owner token of the in-flight request lock.
+	Token string `json:"token,omitempty"`
+
+	// Response is the stored response, or nil while the request is in flight.
+	Response *Response `json:"response,omitempty"`
+}
+
+// Store is the interface of the backends used to store the idempotency records.
+type Store interface {
+	// Begin atomically reserves the key for an in-flight request with the specified lock record
+	// (fingerprint and owner token).
+	// If the key already exists it returns the existing record and false.
+	Begin(ctx context.Context, key string, lock *Record, ttl time.Duration) (*Record, bool, error)
+
+	// Complete stores the final record for the key, only if it is still reserved by the specified lock.
+	// It returns ErrLockLost otherwise.
+	Complete(ctx context.Context, key string, lock, rec *Record, ttl time.Duration) error
+
+	// Delete removes the key, only if it is still reserved by the specified lock,
+	// so the request can be retried.
+	// It returns ErrLockLost otherwise.
+	Delete(ctx context.Context, key string, lock *Record) error
+}
+
+// memoryItem is an entry of the MemoryStore.
+type memoryItem struct {
+	rec      *Record
+	expireAt time.Time
+}
+
+// MemoryStore is a local, thread-safe, in-memory Store.
+// It is only suitable for single-instance services.
+type MemoryStore struct {
+	mux   sync.Mutex
+	items map[string]*memoryItem
+}
+
+// NewMemoryStore creates a new in-memory store.
+// The expired entries are evicted in background at the specified interval
+// (DefaultMemoryEvictInterval if not positive) until the context is canceled.
+func NewMemoryStore(ctx context.Context, evictInterval time.Duration) *MemoryStore {
+	if evictInterval <= 0 {
+		evictInterval = DefaultMemoryEvictInterval
+	}
+
+	s := &MemoryStore{
+		items: make(map[string]*memoryItem),
+	}
+
+	go s.evictLoop(ctx, evictInterval)
+
+	return s
+}
+
+// Begin atomically reserves the key for an in-flight request with the specified lock record
+// (fingerprint and owner token).
+// If the key already exists it returns the existing record and false.
+func (s *MemoryStore) Begin(_ context.Context, key string, lock *Record, ttl time.Duration) (*Record, bool, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	now := time.Now()
+
+	if item, ok := s.items[key]; ok && now.Before(item.expireAt) {
+		return item.rec, false, nil
+	}
+
+	s.items[key] = &memoryItem{
+		rec:      lock,
+		expireAt: now.Add(ttl),
+	}
+
+	return nil, true, nil
+}
+
+// Complete stores the final record for the key, only if it is still reserved by the specified lock.
+// It returns ErrLockLost otherwise.
+func (s *MemoryStore) Complete(_ context.Context, key string, lock, rec *Record, ttl time.Duration) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	now := time.Now()
+
+	if !s.locked(key, lock, now) {
+		return ErrLockLost
+	}
+
+	s.items[key] = &memoryItem{
+		rec:      rec,
+		expireAt: now.Add(ttl),
+	}
+
+	return nil
+}
+
+// Delete removes the key, only if it is still reserved by the specified lock,
+// so the request can be retried.
+// It returns ErrLockLost otherwise.
+func (s *MemoryStore) Delete(_ context.Context, key string, lock *Record) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if !s.locked(key, lock, time.Now()) {
+		return ErrLockLost
+	}
+
+	delete(s.items, key)
+
+	return nil
+}
+
+// locked returns true if the key is reserved by the specified lock and not expired.
+// NOTE: this is not thread-safe, it should be called within a mutex lock.
+func (s *MemoryStore) locked(key string, lock *Record, now time.Time) bool {
+	item, ok := s.items[key]
+
+	return ok &&
+		now.Before(item.expireAt) &&
+		item.rec.Response == nil &&
+		item.rec.Token == lock.Token
+}
+
+// evictLoop periodically removes the expired entries until the context is canceled.
+func (s *MemoryStore) evictLoop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.evict(time.Now())
+		}
+	}
+}
+
+// evict removes the expired entries.
+func (s *MemoryStore) evict(now time.Time) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	for key, item := range s.items {
+		if !now.Before(item.expireAt) {
+			delete(s.items, key)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package idempotency
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	s := NewMemoryStore(ctx, 0)
+	lock := &Record{Fingerprint: "fp1", Token: "t1"}
+
+	rec, ok, err := s.Begin(ctx, "k1", lock, time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Nil(t, rec)
+
+	rec, ok, err = s.Begin(ctx, "k1", &Record{Fingerprint: "fp2", Token: "t2"}, time.Minute)
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Equal(t, lock, rec)
+
+	want := &Record{Fingerprint: "fp1", Response: &Response{StatusCode: http.StatusCreated, Body: []byte("ok")}}
+	require.ErrorIs(t, s.Complete(ctx, "k1", &Record{Token: "t2"}, want, time.Minute), ErrLockLost)
+	require.NoError(t, s.Complete(ctx, "k1", lock, want, time.Minute))
+	require.ErrorIs(t, s.Complete(ctx, "k1", lock, want, time.Minute), ErrLockLost)
+
+	rec, ok, err = s.Begin(ctx, "k1", lock, time.Minute)
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Equal(t, want, rec)
+
+	require.ErrorIs(t, s.Delete(ctx, "k1", lock), ErrLockLost)
+
+	// expired lock
+
+	_, ok, err = s.Begin(ctx, "k2", lock, -1)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	require.ErrorIs(t, s.Complete(ctx, "k2", lock, want, time.Minute), ErrLockLost)
+
+	lock2 := &Record{Fingerprint: "fp1", Token: "t2"}
+
+	_, ok, err = s.Begin(ctx, "k2", lock2, time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	require.ErrorIs(t, s.Delete(ctx, "k2", lock), ErrLockLost)
+	require.NoError(t, s.Delete(ctx, "k2", lock2))
+}
+
+func TestMemoryStore_evict(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	s := NewMemoryStore(ctx, time.Millisecond)
+
+	_, ok, err := s.Begin(ctx, "k1", &Record{Token: "t1"}, time.Millisecond)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	require.Eventually(t, func() bool {
+		s.mux.Lock()
+		defer s.mux.Unlock()
+
+		return len(s.items) == 0
+	}, time.Second, 5*time.Millisecond)
+}
@@ -0,0 +1,97 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	libvalkey "github.com/valkey-io/valkey-go"
+)
+
+// ValkeyClient contains the methods of the github.com/Vonage/gosrvlib/pkg/valkey Client used by the ValkeyStore.
+type ValkeyClient interface {
+	SetNX(ctx context.Context, key string, value string, exp time.Duration) (bool, error)
+	SetIfEqual(ctx context.Context, key string, oldValue string, value string, exp time.Duration) (bool, error)
+	Get(ctx context.Context, key string) (string, error)
+	DelIfEqual(ctx context.Context, key string, value string) (bool, error)
+}
+
+// ValkeyStore is a Store backed by Valkey.
+// NOTE: Valkey expiration times have a resolution of one second.
+type ValkeyStore struct {
+	client ValkeyClient
+}
+
+// NewValkeyStore creates a new Store backed by Valkey.
+func NewValkeyStore(client ValkeyClient) *ValkeyStore {
+	return &ValkeyStore{client: client}
+}
+
+// Begin atomically reserves the key for an in-flight request with the specified lock record
+// (fingerprint and owner token).
+// If the key already exists it returns the existing record and false.
+func (s *ValkeyStore) Begin(ctx context.Context, key string, lock *Record, ttl time.Duration) (*Record, bool, error) {
+	data, _ := json.Marshal(lock) //nolint:errchkjson
+
+	ok, err := s.client.SetNX(ctx, key, string(data), max(ttl, time.Second))
+	if err != nil {
+		return nil, false, fmt.Errorf("valkey store: %w", err)
+	}
+
+	if ok {
+		return nil, true, nil
+	}
+
+	value, err := s.client.Get(ctx, key)
+	if errors.Is(err, libvalkey.Nil) {
+		return nil, false, fmt.Errorf("valkey store: the key %q expired: %w", key, err)
+	}
+
+	if err != nil {
+		return nil, false, fmt.Errorf("valkey store: %w", err)
+	}
+
+	return decodeRecord([]byte(value))
+}
+
+// Complete stores the final record for the key, only if it is still reserved by the specified lock.
+// It returns ErrLockLost otherwise.
+func (s *ValkeyStore) Complete(ctx context.Context, key string, lock, rec *Record, ttl time.Duration) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("valkey store: %w", err)
+	}
+
+	lockData, _ := json.Marshal(lock) //nolint:errchkjson
+
+	ok, err := s.client.SetIfEqual(ctx, key, string(lockData), string(data), max(ttl, time.Second))
+	if err != nil {
+		return fmt.Errorf("valkey store: %w", err)
+	}
+
+	if !ok {
+		return ErrLockLost
+	}
+
+	return nil
+}
+
+// Delete removes the key, only if it is still reserved by the specified lock,
+// so the request can be retried.
+// It returns ErrLockLost otherwise.
+func (s *ValkeyStore) Delete(ctx context.Context, key string, lock *Record) error {
+	lockData, _ := json.Marshal(lock) //nolint:errchkjson
+
+	ok, err := s.client.DelIfEqual(ctx, key, string(lockData))
+	if err != nil {
+		return fmt.Errorf("valkey store: %w", err)
+	}
+
+	if !ok {
+		return ErrLockLost
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	libvalkey "github.com/valkey-io/valkey-go"
+)
+
+type valkeyClientMock struct {
+	data    map[string]string
+	err     error
+	expired bool
+}
+
+func (m *valkeyClientMock) SetNX(_ context.Context, key string, value string, exp time.Duration) (bool, error) {
+	if m.err != nil {
+		return false, m.err
+	}
+
+	if exp < time.Second {
+		return false, errors.New("invalid expiration")
+	}
+
+	if _, ok := m.data[key]; ok || m.expired {
+		return false, nil
+	}
+
+	m.data[key] = value
+
+	return true, nil
+}
+
+func (m *valkeyClientMock) SetIfEqual(_ context.Context, key string, oldValue string, value string, exp time.Duration) (bool, error) {
+	if m.err != nil {
+		return false, m.err
+	}
+
+	if exp < time.Second {
+		return false, errors.New("invalid expiration")
+	}
+
+	if v, ok := m.data[key]; !ok || v != oldValue {
+		return false, nil
+	}
+
+	m.data[key] = value
+
+	return true, nil
+}
+
+func (m *valkeyClientMock) Get(_ context.Context, key string) (string, error) {
+	if key == "error" {
+		return "", errors.New("get error")
+	}
+
+	v, ok := m.data[key]
+	if !ok {
+		return "", fmt.Errorf("cannot retrieve key %s: %w", key, libvalkey.Nil)
+	}
+
+	return v, nil
+}
+
+func (m *valkeyClientMock) DelIfEqual(_ context.Context, key string, value string) (bool, error) {
+	if m.err != nil {
+		return false, m.err
+	}
+
+	if v, ok := m.data[key]; !ok || v != value {
+		return false, nil
+	}
+
+	delete(m.data, key)
+
+	return true, nil
+}
+
+func TestValkeyStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	m := &valkeyClientMock{data: map[string]string{}}
+	s := NewValkeyStore(m)
+	lock := &Record{Fingerprint: "fp1", Token: "t1"}
+
+	_, ok, err := s.Begin(ctx, "k1", lock, time.Millisecond)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	rec, ok, err := s.Begin(ctx, "k1", &Record{Fingerprint: "fp1", Token: "t2"}, time.Minute)
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Equal(t, lock, rec)
+
+	want := &Record{Fingerprint: "fp1", Response: &Response{StatusCode: http.StatusCreated, Header: http.Header{"A": {"b"}}, Body: []byte("ok")}}
+	require.ErrorIs(t, s.Complete(ctx, "k1", &Record{Fingerprint: "fp1", Token: "t2"}, want, time.Minute), ErrLockLost)
+	require.NoError(t, s.Complete(ctx, "k1", lock, want, time.Minute))
+
+	rec, _, err = s.Begin(ctx, "k1", lock, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, want, rec)
+
+	require.ErrorIs(t, s.Delete(ctx, "k1", lock), ErrLockLost)
+
+	_, _, err = s.Begin(ctx, "k2", lock, time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, s.Delete(ctx, "k2", lock))
+
+	m.data["error"] = "{}"
+	_, _, err = s.Begin(ctx, "error", lock, time.Minute)
+	require.Error(t, err)
+
+	m.expired = true
+	_, _, err = s.Begin(ctx, "k3", lock, time.Minute)
+	require.ErrorIs(t, err, libvalkey.Nil)
+
+	m.err = errors.New("test error")
+	_, _, err = s.Begin(ctx, "k4", lock, time.Minute)
+	require.Error(t, err)
+	require.Error(t, s.Complete(ctx, "k4", lock, want, time.Minute))
+	require.Error(t, s.Delete(ctx, "k4", lock))
+}
@@ -0,0 +1,218 @@
+package jobqueue
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/httpserver"
+	"github.com/Vonage/gosrvlib/pkg/httputil"
+	"github.com/Vonage/gosrvlib/pkg/logging"
+	"go.uber.org/zap"
+)
+
+// Admin list limits.
+const (
+	DefaultListLimit = 100
+	MaxListLimit     = 1000
+)
+
+// Get returns the job with the specified ID, or ErrNotFound.
+func (q *Queue) Get(ctx context.Context, id int64) (*Job, error) {
+	j, err := scanJob(q.db.QueryRowContext(ctx, q.queries.Get, id, q.name))
+	if err != nil {
+		return nil, q.notFound(err)
+	}
+
+	return j, nil
+}
+
+// List returns the jobs in reverse creation order, optionally filtered by status (empty for all).
+func (q *Queue) List(ctx context.Context, status Status, limit, offset uint) ([]*Job, error) {
+	rows, err := q.db.QueryContext(ctx, q.queries.List, q.name, status, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("jobqueue: unable to list the jobs: %w", err)
+	}
+
+	defer logging.Close(ctx, rows, "error closing the jobqueue rows")
+
+	jobs := make([]*Job, 0, limit)
+
+	for rows.Next() {
+		j, err := scanJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("jobqueue: unable to read the job: %w", err)
+		}
+
+		jobs = append(jobs, j)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("jobqueue: unable to list the jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// Retry sets a dead or canceled job as pending, resetting the number of attempts.
+// The unique key of the job was cleared when it stopped, so it is not restored.
+func (q *Queue) Retry(ctx context.Context, id int64) error {
+	now := time.Now().UnixMilli()
+	return q.update(ctx, id, q.queries.Retry, now, now, id, q.name)
+}
+
+// Cancel marks a pending job as canceled.
+func (q *Queue) Cancel(ctx context.Context, id int64) error {
+	return q.update(ctx, id, q.queries.Cancel, time.Now().UnixMilli(), id, q.name)
+}
+
+// Purge deletes the done and canceled jobs last updated before the specified time,
+// and returns the number of deleted jobs.
+func (q *Queue) Purge(ctx context.Context, before time.Time) (int64, error) {
+	res, err := q.db.ExecContext(ctx, q.queries.Purge, q.name, before.UnixMilli())
+	if err != nil {
+		return 0, fmt.Errorf("jobqueue: unable to purge the jobs: %w", err)
+	}
+
+	return res.RowsAffected() //nolint:wrapcheck
+}
+
+// update executes a conditional status update.
+// It returns ErrNotFound or ErrInvalidStatus when no job is updated.
+func (q *Queue) update(ctx context.Context, id int64, query string, args ...any) error {
+	res, err := q.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("jobqueue: unable to update the job: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("jobqueue: unable to update the job: %w", err)
+	}
+
+	if n > 0 {
+		return nil
+	}
+
+	if _, err := q.Get(ctx, id); err != nil {
+		return err
+	}
+
+	return ErrInvalidStatus
+}
+
+func (q *Queue) notFound(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+
+	return fmt.Errorf("jobqueue: unable to read the job: %w", err)
+}
+
+// AdminRoutes returns the HTTP routes to manage the jobs:
+//
+//   - GET /jobs?status=&limit=&offset= lists the jobs;
+//   - GET /jobs/:id returns a job;
+//   - POST /jobs/:id/retry sets a dead or canceled job as pending;
+//   - POST /jobs/:id/cancel cancels a pending job.
+//
+// The routes should be mounted with an httpserver.RouteGroup prefix and protected by an authorization middleware.
+func (q *Queue) AdminRoutes() []httpserver.Route {
+	return []httpserver.Route{
+		{
+			Method:      http.MethodGet,
+			Path:        "/jobs",
+			Description: "Lists the " + q.name + " queue jobs.",
+			Response:    []*Job{},
+			Handler:     q.handleList,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/jobs/:id",
+			Description: "Returns a " + q.name + " queue job.",
+			Response:    &Job{},
+			Handler:     q.handleGet,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/jobs/:id/retry",
+			Description: "Retries a dead or canceled " + q.name + " queue job.",
+			Response:    &Job{},
+			Handler:     q.handleAction(q.Retry),
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        "/jobs/:id/cancel",
+			Description: "Cancels a pending " + q.name + " queue job.",
+			Response:    &Job{},
+			Handler:     q.handleAction(q.Cancel),
+		},
+	}
+}
+
+func (q *Queue) handleList(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+	limit := min(httputil.QueryUintOrDefault(qs, "limit", DefaultListLimit), MaxListLimit)
+	offset := httputil.QueryUintOrDefault(qs, "offset", 0)
+
+	jobs, err := q.List(r.Context(), Status(qs.Get("status")), limit, offset)
+	if err != nil {
+		q.sendError(w, r, err)
+		return
+	}
+
+	httputil.SendJSON(r.Context(), w, http.StatusOK, jobs)
+}
+
+func (q *Queue) handleGet(w http.ResponseWriter, r *http.Request) {
+	id, err := jobID(r)
+	if err != nil {
+		httputil.SendStatus(r.Context(), w, http.StatusBadRequest)
+		return
+	}
+
+	job, err := q.Get(r.Context(), id)
+	if err != nil {
+		q.sendError(w, r, err)
+		return
+	}
+
+	httputil.SendJSON(r.Context(), w, http.StatusOK, job)
+}
+
+func (q *Queue) handleAction(action func(ctx context.Context, id int64) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := jobID(r)
+		if err != nil {
+			httputil.SendStatus(r.Context(), w, http.StatusBadRequest)
+			return
+		}
+
+		if err := action(r.Context(), id); err != nil {
+			q.sendError(w, r, err)
+			return
+		}
+
+		q.handleGet(w, r)
+	}
+}
+
+func (q *Queue) sendError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		httputil.SendStatus(r.Context(), w, http.StatusNotFound)
+	case errors.Is(err, ErrInvalidStatus):
+		httputil.SendStatus(r.Context(), w, http.StatusConflict)
+	default:
+		q.logger.Error("jobqueue admin request failed", zap.Error(err))
+		httputil.SendStatus(r.Context(), w, http.StatusInternalServerError)
+	}
+}
+
+func jobID(r *http.Request) (int64, error) {
+	return strconv.ParseInt(httputil.PathParam(r, "id"), 10, 64) //nolint:wrapcheck
+}
@@ -0,0 +1,251 @@
+package jobqueue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueue_AdminRoutes(t *testing.T) {
+	t.Parallel()
+
+	qs := MySQLQueries("jobs")
+
+	tests := []struct {
+		name       string
+		method     string
+		route      string
+		path       string
+		id         string
+		setupMocks func(mock sqlmock.Sqlmock)
+		wantStatus int
+		wantJobs   int
+	}{
+		{
+			name:   "list",
+			method: http.MethodGet,
+			route:  "/jobs",
+			path:   "/jobs?status=dead&limit=5000&offset=10",
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(qs.List)).
+					WithArgs(DefaultQueue, "dead", "dead", MaxListLimit, 10).
+					WillReturnRows(testJobRows(testJob(2, StatusDead), testJob(1, StatusDead)))
+			},
+			wantStatus: http.StatusOK,
+			wantJobs:   2,
+		},
+		{
+			name:   "list all",
+			method: http.MethodGet,
+			route:  "/jobs",
+			path:   "/jobs",
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(qs.List)).
+					WithArgs(DefaultQueue, "", "", DefaultListLimit, 0).
+					WillReturnRows(testJobRows())
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "list error",
+			method: http.MethodGet,
+			route:  "/jobs",
+			path:   "/jobs",
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(qs.List)).WillReturnError(errors.New("ERROR"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:   "list scan error",
+			method: http.MethodGet,
+			route:  "/jobs",
+			path:   "/jobs",
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(qs.List)).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:   "get",
+			method: http.MethodGet,
+			route:  "/jobs/:id",
+			path:   "/jobs/1",
+			id:     "1",
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(qs.Get)).WithArgs(1, DefaultQueue).WillReturnRows(testJobRows(testJob(1, StatusDone)))
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "get not found",
+			method: http.MethodGet,
+			route:  "/jobs/:id",
+			path:   "/jobs/1",
+			id:     "1",
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(qs.Get)).WillReturnRows(testJobRows())
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "get invalid id",
+			method:     http.MethodGet,
+			route:      "/jobs/:id",
+			path:       "/jobs/x",
+			id:         "x",
+			setupMocks: func(_ sqlmock.Sqlmock) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "retry",
+			method: http.MethodPost,
+			route:  "/jobs/:id/retry",
+			path:   "/jobs/1/retry",
+			id:     "1",
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(qs.Retry)).
+					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 1, DefaultQueue).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(regexp.QuoteMeta(qs.Get)).WillReturnRows(testJobRows(testJob(1, StatusPending)))
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "retry invalid status",
+			method: http.MethodPost,
+			route:  "/jobs/:id/retry",
+			path:   "/jobs/1/retry",
+			id:     "1",
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(qs.Retry)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(regexp.QuoteMeta(qs.Get)).WillReturnRows(testJobRows(testJob(1, StatusRunning)))
+			},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:   "retry not found",
+			method: http.MethodPost,
+			route:  "/jobs/:id/retry",
+			path:   "/jobs/1/retry",
+			id:     "1",
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(qs.Retry)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(regexp.QuoteMeta(qs.Get)).WillReturnRows(testJobRows())
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "retry invalid id",
+			method:     http.MethodPost,
+			route:      "/jobs/:id/retry",
+			path:       "/jobs/x/retry",
+			id:         "x",
+			setupMocks: func(_ sqlmock.Sqlmock) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "cancel",
+			method: http.MethodPost,
+			route:  "/jobs/:id/cancel",
+			path:   "/jobs/1/cancel",
+			id:     "1",
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(qs.Cancel)).
+					WithArgs(sqlmock.AnyArg(), 1, DefaultQueue).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(regexp.QuoteMeta(qs.Get)).WillReturnRows(testJobRows(testJob(1, StatusCanceled)))
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "cancel error",
+			method: http.MethodPost,
+			route:  "/jobs/:id/cancel",
+			path:   "/jobs/1/cancel",
+			id:     "1",
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(qs.Cancel)).WillReturnError(errors.New("ERROR"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:   "cancel rows affected error",
+			method: http.MethodPost,
+			route:  "/jobs/:id/cancel",
+			path:   "/jobs/1/cancel",
+			id:     "1",
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(qs.Cancel)).WillReturnResult(sqlmock.NewErrorResult(errors.New("ERROR")))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			q, mock := newTestQueue(t, qs)
+			tt.setupMocks(mock)
+
+			var handler http.HandlerFunc
+
+			for _, route := range q.AdminRoutes() {
+				if route.Method == tt.method && route.Path == tt.route {
+					handler = route.Handler
+				}
+			}
+
+			require.NotNil(t, handler)
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.SetPathValue("id", tt.id)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			require.NoError(t, mock.ExpectationsWereMet())
+			require.Equal(t, tt.wantStatus, rr.Code)
+
+			if tt.wantJobs > 0 {
+				var jobs []*Job
+
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &jobs))
+				require.Len(t, jobs, tt.wantJobs)
+			}
+		})
+	}
+}
+
+func TestQueue_Purge(t *testing.T) {
+	t.Parallel()
+
+	qs := MySQLQueries("jobs")
+	before := time.Now()
+
+	q, mock := newTestQueue(t, qs)
+
+	mock.ExpectExec(regexp.QuoteMeta(qs.Purge)).
+		WithArgs(DefaultQueue, before.UnixMilli()).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	n, err := q.Purge(context.Background(), before)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), n)
+
+	mock.ExpectExec(regexp.QuoteMeta(qs.Purge)).WillReturnError(errors.New("ERROR"))
+
+	_, err = q.Purge(context.Background(), before)
+	require.Error(t, err)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,499 @@
+/*
+Package jobqueue provides a durable background job queue backed by a SQL
+database table (MySQL 8.0+ and PostgreSQL 9.5+, e.g. via the
+github.com/Vonage/gosrvlib/pkg/sqlconn package).
+
+The jobs are enqueued with a type and a payload, and executed by a pool of
+workers calling the handler registered for the job type. Each worker claims the
+next ready job with a "SELECT ... FOR UPDATE SKIP LOCKED" query, so multiple
+workers and service instances can consume the same queue concurrently.
+Only the jobs of the registered types are claimed.
+The jobs are ordered by priority and scheduled time, and can be delayed or
+scheduled at a specific time.
+
+A job is retried with the configured backoff strategy (see retrier.Backoff) when
+the handler returns an error, until the maximum number of attempts is reached
+and the job is marked as dead. A job claimed by a worker that stopped before
+completing it is claimed again after its lease expires, and the status updates
+of the previous worker are rejected (ErrLeaseLost).
+
+An optional unique key prevents enqueuing a job while another job with the same
+key is pending or running (ErrDuplicate). The unique key is cleared when the
+job is done, dead or canceled, and it is not restored when the job is retried,
+so a retried job no longer prevents the duplicates.
+
+The AdminRoutes method returns the HTTP routes to list, retry and cancel the
+jobs, which can be mounted with an httpserver.RouteGroup prefix.
+
+Each job execution is counted with the IncEventCounter metric ("jobqueue"
+task, job type operation) with the "success", "retry", "dead" and "error"
+outcomes.
+*/
+package jobqueue
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"runtime/debug"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/logging"
+	"github.com/Vonage/gosrvlib/pkg/metrics"
+	"github.com/Vonage/gosrvlib/pkg/retrier"
+	"github.com/Vonage/gosrvlib/pkg/sqltransaction"
+	"go.uber.org/zap"
+)
+
+// Default values.
+const (
+	DefaultQueue        = "default"
+	DefaultWorkers      = 4
+	DefaultPollInterval = time.Second
+	DefaultJobTimeout   = 5 * time.Minute
+	DefaultMaxAttempts  = 5
+	DefaultMaxDelay     = time.Hour
+)
+
+// leaseMargin is the time added to the job timeout to compute the lease of the claimed jobs.
+const leaseMargin = time.Minute
+
+// metricsTask is the task name used in the metrics.
+const metricsTask = "jobqueue"
+
+// Metrics outcomes.
+const (
+	outcomeSuccess = "success"
+	outcomeRetry   = "retry"
+	outcomeDead    = "dead"
+	outcomeError   = "error"
+)
+
+var (
+	// ErrDuplicate is returned when enqueuing a job with the unique key of an active job.
+	ErrDuplicate = errors.New("duplicate job")
+
+	// ErrNotFound is returned when the job does not exist.
+	ErrNotFound = errors.New("job not found")
+
+	// ErrInvalidStatus is returned when the job status does not allow the operation.
+	ErrInvalidStatus = errors.New("invalid job status")
+
+	// ErrLeaseLost is returned when the job status cannot be updated because its lease expired and
+	// the job was claimed again by another worker.
+	ErrLeaseLost = errors.New("job lease lost")
+)
+
+// Status is the status of a job.
+type Status string
+
+// Job statuses.
+const (
+	StatusPending  Status = "pending"
+	StatusRunning  Status = "running"
+	StatusDone     Status = "done"
+	StatusDead     Status = "dead"
+	StatusCanceled Status = "canceled"
+)
+
+// Job is a job stored in the queue.
+type Job struct {
+	ID          int64     `json:"id"`
+	Queue       string    `json:"queue"`
+	Type        string    `json:"type"`
+	Payload     []byte    `json:"payload"`
+	UniqueKey   string    `json:"unique_key,omitempty"`
+	Priority    int       `json:"priority"`
+	Status      Status    `json:"status"`
+	Attempts    int       `json:"attempts"`
+	MaxAttempts int       `json:"max_attempts"`
+	RunAt       time.Time `json:"run_at"`
+	LastError   string    `json:"last_error,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	// lockedUntil is the lease set when the job was claimed, used to fence the status updates.
+	lockedUntil int64
+
+	// prevDelay is the retry delay applied before the current attempt,
+	// used by the backoff strategies depending on it (e.g. retrier.DecorrelatedJitter).
+	prevDelay time.Duration
+}
+
+// HandlerFn is the type of function executing the jobs of a type.
+// The job is retried when an error is returned.
+type HandlerFn func(ctx context.Context, job *Job) error
+
+// Queue is a durable job queue backed by a SQL table.
+type Queue struct {
+	db           *sql.DB
+	queries      *SQLQueries
+	name         string
+	handlers     map[string]HandlerFn
+	claimQuery   string
+	workers      int
+	pollInterval time.Duration
+	jobTimeout   time.Duration
+	maxAttempts  int
+	backoff      retrier.Backoff
+	maxDelay     time.Duration
+	logger       *zap.Logger
+	metrics      metrics.Client
+	cancel       context.CancelFunc
+	wg           sync.WaitGroup
+}
+
+// New creates a new Queue using the specified database and queries (see MySQLQueries and PostgreSQLQueries).
+func New(db *sql.DB, queries *SQLQueries, opts ...Option) (*Queue, error) {
+	if db == nil {
+		return nil, errors.New("the database is required")
+	}
+
+	if queries == nil {
+		return nil, errors.New("the queries are required")
+	}
+
+	q := &Queue{
+		db:           db,
+		queries:      queries,
+		name:         DefaultQueue,
+		handlers:     make(map[string]HandlerFn),
+		workers:      DefaultWorkers,
+		pollInterval: DefaultPollInterval,
+		jobTimeout:   DefaultJobTimeout,
+		maxAttempts:  DefaultMaxAttempts,
+		backoff:      retrier.ExponentialBackoff(10*time.Second, 2, retrier.EqualJitter),
+		maxDelay:     DefaultMaxDelay,
+		logger:       zap.NewNop(),
+		metrics:      &metrics.Default{},
+	}
+
+	for _, applyOpt := range opts {
+		if err := applyOpt(q); err != nil {
+			return nil, err
+		}
+	}
+
+	return q, nil
+}
+
+// Register sets the handler function for the jobs of the specified type.
+// It must be called before Start.
+func (q *Queue) Register(jobType string, fn HandlerFn) error {
+	if jobType == "" {
+		return errors.New("the job type is required")
+	}
+
+	if fn == nil {
+		return errors.New("nil handler")
+	}
+
+	q.handlers[jobType] = fn
+	q.claimQuery = q.queries.claimQuery(len(q.handlers))
+
+	return nil
+}
+
+// Enqueue adds a new job of the specified type and returns its ID.
+// It returns ErrDuplicate if the job has the same unique key of a pending or running job.
+func (q *Queue) Enqueue(ctx context.Context, jobType string, payload []byte, opts ...EnqueueOption) (int64, error) {
+	if jobType == "" {
+		return 0, errors.New("the job type is required")
+	}
+
+	now := time.Now()
+
+	e := &enqueueConfig{
+		runAt:       now,
+		maxAttempts: q.maxAttempts,
+	}
+
+	for _, applyOpt := range opts {
+		if err := applyOpt(e); err != nil {
+			return 0, err
+		}
+	}
+
+	if payload == nil {
+		payload = []byte{}
+	}
+
+	args := []any{
+		q.name,
+		jobType,
+		payload,
+		sql.NullString{String: e.uniqueKey, Valid: e.uniqueKey != ""},
+		e.priority,
+		e.maxAttempts,
+		e.runAt.UnixMilli(),
+		now.UnixMilli(),
+		now.UnixMilli(),
+	}
+
+	if q.queries.InsertReturning {
+		var id int64
+
+		err := q.db.QueryRowContext(ctx, q.queries.Insert, args...).Scan(&id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrDuplicate
+		}
+
+		if err != nil {
+			return 0, fmt.Errorf("jobqueue: unable to insert the job: %w", err)
+		}
+
+		return id, nil
+	}
+
+	res, err := q.db.ExecContext(ctx, q.queries.Insert, args...)
+	if err != nil {
+		return 0, fmt.Errorf("jobqueue: unable to insert the job: %w", err)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return 0, ErrDuplicate
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("jobqueue: unable to read the job ID: %w", err)
+	}
+
+	return id, nil
+}
+
+// Start the workers.
+func (q *Queue) Start(ctx context.Context) {
+	ctx, q.cancel = context.WithCancel(ctx)
+
+	for range q.workers {
+		q.wg.Go(func() { q.work(ctx) })
+	}
+}
+
+// Stop the workers.
+// It blocks until the running jobs return.
+func (q *Queue) Stop() {
+	if q.cancel != nil {
+		q.cancel()
+	}
+
+	q.wg.Wait()
+}
+
+// work claims and executes the jobs until the context is canceled.
+func (q *Queue) work(ctx context.Context) {
+	for ctx.Err() == nil {
+		job, err := q.claim(ctx)
+		if err != nil && ctx.Err() == nil {
+			q.logger.Error("unable to claim a job", zap.String("queue", q.name), zap.Error(err))
+		}
+
+		if job != nil {
+			q.process(ctx, job)
+			continue
+		}
+
+		// random jitter to spread the polling of the workers
+		wait := q.pollInterval/2 + rand.N(q.pollInterval/2+1) //nolint:gosec
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// claim selects and locks the next job ready to run of the registered types,
+// or returns nil if there are no jobs.
+func (q *Queue) claim(ctx context.Context) (*Job, error) {
+	if len(q.handlers) == 0 {
+		return nil, nil //nolint:nilnil
+	}
+
+	types := slices.Sorted(maps.Keys(q.handlers))
+
+	var job *Job
+
+	err := sqltransaction.Exec(ctx, q.db, func(ctx context.Context, tx *sql.Tx) error {
+		now := time.Now()
+
+		args := make([]any, 0, 3+len(types))
+		args = append(args, q.name, now.UnixMilli(), now.UnixMilli())
+
+		for _, t := range types {
+			args = append(args, t)
+		}
+
+		j, err := scanJob(tx.QueryRowContext(ctx, q.claimQuery, args...))
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		lockedUntil := now.Add(q.jobTimeout + leaseMargin).UnixMilli()
+
+		_, err = tx.ExecContext(ctx, q.queries.Lock, lockedUntil, now.UnixMilli(), j.ID)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		if j.Status == StatusPending && j.Attempts > 0 {
+			// rescheduled after a failure: the run time is the update time plus the retry delay
+			j.prevDelay = j.RunAt.Sub(j.UpdatedAt)
+		}
+
+		j.Status = StatusRunning
+		j.Attempts++
+		j.lockedUntil = lockedUntil
+		job = j
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("jobqueue: %w", err)
+	}
+
+	return job, nil
+}
+
+// process executes the job and updates its status.
+// The status is updated even if the context is canceled, to not wait for the lease expiration.
+func (q *Queue) process(ctx context.Context, job *Job) {
+	logger := q.logger.With(
+		zap.String("queue", q.name),
+		zap.Int64("job_id", job.ID),
+		zap.String("job_type", job.Type),
+		zap.Int("attempt", job.Attempts),
+	)
+
+	errRun := q.exec(logging.WithLogger(ctx, logger), job)
+
+	ctx = context.WithoutCancel(ctx)
+	now := time.Now()
+
+	var (
+		outcome string
+		res     sql.Result
+		err     error
+	)
+
+	switch {
+	case errRun == nil:
+		outcome = outcomeSuccess
+		res, err = q.db.ExecContext(ctx, q.queries.Complete, now.UnixMilli(), job.ID, job.lockedUntil)
+	case job.Attempts >= job.MaxAttempts:
+		outcome = outcomeDead
+		res, err = q.db.ExecContext(ctx, q.queries.Kill, errRun.Error(), now.UnixMilli(), job.ID, job.lockedUntil)
+	default:
+		outcome = outcomeRetry
+		delay := min(q.backoff.Delay(uint(job.Attempts), job.prevDelay), q.maxDelay) //nolint:gosec
+		res, err = q.db.ExecContext(ctx, q.queries.Reschedule,
+			now.Add(delay).UnixMilli(), errRun.Error(), now.UnixMilli(), job.ID, job.lockedUntil)
+	}
+
+	if err == nil {
+		err = checkLease(res)
+	}
+
+	if err != nil {
+		outcome = outcomeError
+		logger.Error("unable to update the job status", zap.Error(err))
+	}
+
+	metrics.IncEventCounter(q.metrics, metricsTask, job.Type, outcome)
+
+	if errRun != nil {
+		logger.Warn("job failed", zap.String("outcome", outcome), zap.Error(errRun))
+		return
+	}
+
+	logger.Debug("job completed")
+}
+
+// checkLease returns ErrLeaseLost if the status update did not match the running job with the claimed lease.
+func checkLease(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("unable to read the affected rows: %w", err)
+	}
+
+	if n == 0 {
+		return ErrLeaseLost
+	}
+
+	return nil
+}
+
+// exec calls the job handler with the job timeout, recovering from panics.
+func (q *Queue) exec(ctx context.Context, job *Job) (err error) {
+	fn, ok := q.handlers[job.Type]
+	if !ok {
+		return fmt.Errorf("no handler registered for the job type %q", job.Type)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, q.jobTimeout)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panic: %v", r)
+
+			logging.FromContext(ctx).Error(
+				"job panic",
+				zap.Any("err", r),
+				zap.String("stacktrace", string(debug.Stack())),
+			)
+		}
+	}()
+
+	return fn(ctx, job)
+}
+
+// rowScanner is the interface shared by sql.Row and sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanJob(row rowScanner) (*Job, error) {
+	var (
+		j                           Job
+		uniqueKey                   sql.NullString
+		runAt, createdAt, updatedAt int64
+	)
+
+	err := row.Scan(
+		&j.ID,
+		&j.Queue,
+		&j.Type,
+		&j.Payload,
+		&uniqueKey,
+		&j.Priority,
+		&j.Status,
+		&j.Attempts,
+		&j.MaxAttempts,
+		&runAt,
+		&j.LastError,
+		&createdAt,
+		&updatedAt,
+	)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	j.UniqueKey = uniqueKey.String
+	j.RunAt = time.UnixMilli(runAt)
+	j.CreatedAt = time.UnixMilli(createdAt)
+	j.UpdatedAt = time.UnixMilli(updatedAt)
+
+	return &j, nil
+}
@@ -0,0 +1,621 @@
+package jobqueue
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Vonage/gosrvlib/pkg/logging"
+	"github.com/Vonage/gosrvlib/pkg/retrier"
+	"github.com/Vonage/gosrvlib/pkg/testutil"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+var testJobColumns = []string{ //nolint:gochecknoglobals
+	"id", "queue", "job_type", "payload", "unique_key", "priority", "status", "attempts", "max_attempts",
+	"run_at", "last_error", "created_at", "updated_at",
+}
+
+func testJobRows(jobs ...*Job) *sqlmock.Rows {
+	rows := sqlmock.NewRows(testJobColumns)
+
+	for _, j := range jobs {
+		var uniqueKey any
+
+		if j.UniqueKey != "" {
+			uniqueKey = j.UniqueKey
+		}
+
+		rows.AddRow(
+			j.ID, j.Queue, j.Type, j.Payload, uniqueKey, j.Priority, string(j.Status), j.Attempts, j.MaxAttempts,
+			j.RunAt.UnixMilli(), j.LastError, j.CreatedAt.UnixMilli(), j.UpdatedAt.UnixMilli(),
+		)
+	}
+
+	return rows
+}
+
+const testLockedUntil int64 = 1767225900000
+
+func testJob(id int64, status Status) *Job {
+	t := time.UnixMilli(1767225600000)
+
+	return &Job{
+		ID:          id,
+		Queue:       DefaultQueue,
+		Type:        "email",
+		Payload:     []byte(`{"to":"alice"}`),
+		UniqueKey:   "u1",
+		Priority:    1,
+		Status:      status,
+		Attempts:    1,
+		MaxAttempts: 3,
+		RunAt:       t,
+		CreatedAt:   t,
+		UpdatedAt:   t,
+	}
+}
+
+func newTestQueue(t *testing.T, queries *SQLQueries, opts ...Option) (*Queue, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = db.Close() })
+
+	q, err := New(db, queries, opts...)
+	require.NoError(t, err)
+
+	return q, mock
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+
+	defer func() { _ = db.Close() }()
+
+	q, err := New(db, MySQLQueries("jobs"), WithName("emails"))
+	require.NoError(t, err)
+	require.Equal(t, "emails", q.name)
+
+	q, err = New(nil, MySQLQueries("jobs"))
+	require.Error(t, err)
+	require.Nil(t, q)
+
+	q, err = New(db, nil)
+	require.Error(t, err)
+	require.Nil(t, q)
+
+	q, err = New(db, MySQLQueries("jobs"), WithName(""))
+	require.Error(t, err)
+	require.Nil(t, q)
+}
+
+func TestQueue_Register(t *testing.T) {
+	t.Parallel()
+
+	q, _ := newTestQueue(t, MySQLQueries("jobs"))
+
+	err := q.Register("email", func(_ context.Context, _ *Job) error { return nil })
+	require.NoError(t, err)
+	require.Contains(t, q.handlers, "email")
+
+	err = q.Register("", func(_ context.Context, _ *Job) error { return nil })
+	require.Error(t, err)
+
+	err = q.Register("email", nil)
+	require.Error(t, err)
+}
+
+func TestQueue_Enqueue(t *testing.T) {
+	t.Parallel()
+
+	my := MySQLQueries("jobs")
+	pg := PostgreSQLQueries("jobs")
+
+	tests := []struct {
+		name       string
+		queries    *SQLQueries
+		jobType    string
+		opts       []EnqueueOption
+		setupMocks func(mock sqlmock.Sqlmock)
+		want       int64
+		wantErr    error
+		wantAnyErr bool
+	}{
+		{
+			name:    "mysql",
+			queries: my,
+			jobType: "email",
+			opts:    []EnqueueOption{WithUniqueKey("u1"), WithPriority(5), WithDelay(time.Minute), WithJobMaxAttempts(2)},
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(my.Insert)).
+					WithArgs(DefaultQueue, "email", []byte("p"), "u1", 5, 2, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(7, 1))
+			},
+			want: 7,
+		},
+		{
+			name:    "mysql duplicate",
+			queries: my,
+			jobType: "email",
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(my.Insert)).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: ErrDuplicate,
+		},
+		{
+			name:    "mysql error",
+			queries: my,
+			jobType: "email",
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(my.Insert)).WillReturnError(errors.New("ERROR"))
+			},
+			wantAnyErr: true,
+		},
+		{
+			name:    "mysql last insert id error",
+			queries: my,
+			jobType: "email",
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(my.Insert)).WillReturnResult(sqlmock.NewErrorResult(errors.New("ERROR")))
+			},
+			wantAnyErr: true,
+		},
+		{
+			name:    "postgresql",
+			queries: pg,
+			jobType: "email",
+			opts:    []EnqueueOption{WithRunAt(time.Now().Add(time.Hour))},
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(pg.Insert)).
+					WithArgs(DefaultQueue, "email", []byte("p"), nil, 0, DefaultMaxAttempts, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(9))
+			},
+			want: 9,
+		},
+		{
+			name:    "postgresql duplicate",
+			queries: pg,
+			jobType: "email",
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(pg.Insert)).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+			wantErr: ErrDuplicate,
+		},
+		{
+			name:    "postgresql error",
+			queries: pg,
+			jobType: "email",
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(pg.Insert)).WillReturnError(errors.New("ERROR"))
+			},
+			wantAnyErr: true,
+		},
+		{
+			name:       "empty job type",
+			queries:    my,
+			setupMocks: func(_ sqlmock.Sqlmock) {},
+			wantAnyErr: true,
+		},
+		{
+			name:       "invalid option",
+			queries:    my,
+			jobType:    "email",
+			opts:       []EnqueueOption{WithUniqueKey("")},
+			setupMocks: func(_ sqlmock.Sqlmock) {},
+			wantAnyErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			q, mock := newTestQueue(t, tt.queries)
+			tt.setupMocks(mock)
+
+			id, err := q.Enqueue(context.Background(), tt.jobType, []byte("p"), tt.opts...)
+
+			require.NoError(t, mock.ExpectationsWereMet())
+
+			switch {
+			case tt.wantErr != nil:
+				require.ErrorIs(t, err, tt.wantErr)
+			case tt.wantAnyErr:
+				require.Error(t, err)
+			default:
+				require.NoError(t, err)
+				require.Equal(t, tt.want, id)
+			}
+		})
+	}
+}
+
+func TestQueue_claim(t *testing.T) {
+	t.Parallel()
+
+	qs := MySQLQueries("jobs")
+	claimQuery := qs.claimQuery(1)
+
+	tests := []struct {
+		name       string
+		jobTypes   []string
+		setupMocks func(mock sqlmock.Sqlmock)
+		want       *Job
+		wantErr    bool
+	}{
+		{
+			name:       "no handlers",
+			setupMocks: func(_ sqlmock.Sqlmock) {},
+		},
+		{
+			name:     "claimed",
+			jobTypes: []string{"email"},
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(claimQuery)).
+					WithArgs(DefaultQueue, sqlmock.AnyArg(), sqlmock.AnyArg(), "email").
+					WillReturnRows(testJobRows(testJob(1, StatusPending)))
+				mock.ExpectExec(regexp.QuoteMeta(qs.Lock)).
+					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			want: func() *Job {
+				j := testJob(1, StatusRunning)
+				j.Attempts = 2
+
+				return j
+			}(),
+		},
+		{
+			name:     "claimed after a retry",
+			jobTypes: []string{"email"},
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				j := testJob(1, StatusPending)
+				j.RunAt = j.UpdatedAt.Add(30 * time.Second)
+
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(claimQuery)).WillReturnRows(testJobRows(j))
+				mock.ExpectExec(regexp.QuoteMeta(qs.Lock)).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			want: func() *Job {
+				j := testJob(1, StatusRunning)
+				j.RunAt = j.UpdatedAt.Add(30 * time.Second)
+				j.Attempts = 2
+				j.prevDelay = 30 * time.Second
+
+				return j
+			}(),
+		},
+		{
+			name:     "claimed after the lease expiration",
+			jobTypes: []string{"email"},
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				j := testJob(1, StatusRunning)
+				j.RunAt = j.UpdatedAt.Add(30 * time.Second)
+
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(claimQuery)).WillReturnRows(testJobRows(j))
+				mock.ExpectExec(regexp.QuoteMeta(qs.Lock)).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			want: func() *Job {
+				j := testJob(1, StatusRunning)
+				j.RunAt = j.UpdatedAt.Add(30 * time.Second)
+				j.Attempts = 2
+
+				return j
+			}(),
+		},
+		{
+			name:     "no jobs",
+			jobTypes: []string{"email"},
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(claimQuery)).WillReturnRows(testJobRows())
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:     "begin error",
+			jobTypes: []string{"email"},
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin().WillReturnError(errors.New("ERROR"))
+			},
+			wantErr: true,
+		},
+		{
+			name:     "select error",
+			jobTypes: []string{"email"},
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(claimQuery)).WillReturnError(errors.New("ERROR"))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name:     "lock error",
+			jobTypes: []string{"email"},
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(claimQuery)).WillReturnRows(testJobRows(testJob(1, StatusPending)))
+				mock.ExpectExec(regexp.QuoteMeta(qs.Lock)).WillReturnError(errors.New("ERROR"))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			q, mock := newTestQueue(t, qs)
+			tt.setupMocks(mock)
+
+			for _, jobType := range tt.jobTypes {
+				require.NoError(t, q.Register(jobType, func(_ context.Context, _ *Job) error { return nil }))
+			}
+
+			job, err := q.claim(context.Background())
+
+			require.NoError(t, mock.ExpectationsWereMet())
+
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			if job != nil {
+				require.Positive(t, job.lockedUntil)
+				job.lockedUntil = 0
+			}
+
+			require.Equal(t, tt.want, job)
+		})
+	}
+}
+
+func TestQueue_process(t *testing.T) {
+	t.Parallel()
+
+	qs := MySQLQueries("jobs")
+
+	tests := []struct {
+		name        string
+		attempts    int
+		jobType     string
+		handler     HandlerFn
+		setupMocks  func(mock sqlmock.Sqlmock)
+		wantOutcome string
+	}{
+		{
+			name:     "success",
+			attempts: 1,
+			jobType:  "email",
+			handler:  func(_ context.Context, _ *Job) error { return nil },
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(qs.Complete)).WithArgs(sqlmock.AnyArg(), 1, testLockedUntil).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantOutcome: outcomeSuccess,
+		},
+		{
+			name:     "retry",
+			attempts: 1,
+			jobType:  "email",
+			handler:  func(_ context.Context, _ *Job) error { return errors.New("FAIL") },
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(qs.Reschedule)).
+					WithArgs(sqlmock.AnyArg(), "FAIL", sqlmock.AnyArg(), 1, testLockedUntil).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantOutcome: outcomeRetry,
+		},
+		{
+			name:     "dead",
+			attempts: 3,
+			jobType:  "email",
+			handler:  func(_ context.Context, _ *Job) error { return errors.New("FAIL") },
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(qs.Kill)).WithArgs("FAIL", sqlmock.AnyArg(), 1, testLockedUntil).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantOutcome: outcomeDead,
+		},
+		{
+			name:     "panic",
+			attempts: 1,
+			jobType:  "email",
+			handler:  func(_ context.Context, _ *Job) error { panic("PANIC") },
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(qs.Reschedule)).
+					WithArgs(sqlmock.AnyArg(), "job panic: PANIC", sqlmock.AnyArg(), 1, testLockedUntil).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantOutcome: outcomeRetry,
+		},
+		{
+			name:     "no handler",
+			attempts: 3,
+			jobType:  "unknown",
+			handler:  func(_ context.Context, _ *Job) error { return nil },
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(qs.Kill)).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantOutcome: outcomeDead,
+		},
+		{
+			name:     "lease lost",
+			attempts: 1,
+			jobType:  "email",
+			handler:  func(_ context.Context, _ *Job) error { return nil },
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(qs.Complete)).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantOutcome: outcomeError,
+		},
+		{
+			name:     "rows affected error",
+			attempts: 1,
+			jobType:  "email",
+			handler:  func(_ context.Context, _ *Job) error { return nil },
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(qs.Complete)).WillReturnResult(sqlmock.NewErrorResult(errors.New("ERROR")))
+			},
+			wantOutcome: outcomeError,
+		},
+		{
+			name:     "update error",
+			attempts: 1,
+			jobType:  "email",
+			handler:  func(_ context.Context, _ *Job) error { return nil },
+			setupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(qs.Complete)).WillReturnError(errors.New("ERROR"))
+			},
+			wantOutcome: outcomeError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := &testutil.EventMetrics{}
+			q, mock := newTestQueue(t, qs, WithMetrics(m), WithBackoff(retrier.ConstantBackoff(time.Second)))
+			tt.setupMocks(mock)
+
+			err := q.Register("email", tt.handler)
+			require.NoError(t, err)
+
+			job := testJob(1, StatusRunning)
+			job.Type = tt.jobType
+			job.Attempts = tt.attempts
+			job.lockedUntil = testLockedUntil
+
+			q.process(context.Background(), job)
+
+			require.NoError(t, mock.ExpectationsWereMet())
+			require.Equal(t, 1, m.Count(tt.wantOutcome))
+		})
+	}
+}
+
+type testBackoff struct {
+	prev time.Duration
+}
+
+func (b *testBackoff) Delay(_ uint, prev time.Duration) time.Duration {
+	b.prev = prev
+	return 2 * prev
+}
+
+func TestQueue_process_prevDelay(t *testing.T) {
+	t.Parallel()
+
+	qs := MySQLQueries("jobs")
+	b := &testBackoff{}
+	q, mock := newTestQueue(t, qs, WithBackoff(b))
+
+	require.NoError(t, q.Register("email", func(_ context.Context, _ *Job) error { return errors.New("FAIL") }))
+
+	mock.ExpectExec(regexp.QuoteMeta(qs.Reschedule)).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	job := testJob(1, StatusRunning)
+	job.prevDelay = 7 * time.Second
+
+	q.process(context.Background(), job)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.Equal(t, 7*time.Second, b.prev)
+}
+
+func TestQueue_exec_panic(t *testing.T) {
+	t.Parallel()
+
+	q, _ := newTestQueue(t, MySQLQueries("jobs"))
+
+	require.NoError(t, q.Register("email", func(_ context.Context, _ *Job) error { panic("PANIC") }))
+
+	core, logs := observer.New(zap.ErrorLevel)
+	ctx := logging.WithLogger(context.Background(), zap.New(core))
+
+	err := q.exec(ctx, testJob(1, StatusRunning))
+	require.EqualError(t, err, "job panic: PANIC")
+
+	entries := logs.FilterMessage("job panic").All()
+	require.Len(t, entries, 1)
+	require.Contains(t, entries[0].ContextMap()["stacktrace"], "jobqueue")
+}
+
+func TestQueue_Start(t *testing.T) {
+	t.Parallel()
+
+	qs := MySQLQueries("jobs")
+	q, mock := newTestQueue(t, qs, WithWorkers(1), WithPollInterval(time.Millisecond))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(qs.claimQuery(1))).WillReturnRows(testJobRows())
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(qs.claimQuery(1))).WillReturnRows(testJobRows(testJob(1, StatusPending)))
+	mock.ExpectExec(regexp.QuoteMeta(qs.Lock)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectExec(regexp.QuoteMeta(qs.Complete)).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	done := make(chan *Job, 1)
+
+	err := q.Register("email", func(_ context.Context, job *Job) error {
+		done <- job
+		return nil
+	})
+	require.NoError(t, err)
+
+	q.Start(context.Background())
+
+	job := <-done
+	require.Equal(t, int64(1), job.ID)
+	require.Equal(t, []byte(`{"to":"alice"}`), job.Payload)
+
+	require.Eventually(t, func() bool { return mock.ExpectationsWereMet() == nil }, time.Second, time.Millisecond)
+
+	q.Stop()
+}
+
+func TestScanJob(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	defer func() { _ = db.Close() }()
+
+	want := testJob(3, StatusDead)
+	want.UniqueKey = ""
+	want.LastError = "FAIL"
+
+	mock.ExpectQuery("SELECT").WillReturnRows(testJobRows(want))
+
+	got, err := scanJob(db.QueryRowContext(context.Background(), "SELECT"))
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+
+	mock.ExpectQuery("SELECT").WillReturnRows(testJobRows())
+
+	_, err = scanJob(db.QueryRowContext(context.Background(), "SELECT"))
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
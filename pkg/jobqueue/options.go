@@ -0,0 +1,204 @@
+package jobqueue
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/metrics"
+	"github.com/Vonage/gosrvlib/pkg/retrier"
+	"go.uber.org/zap"
+)
+
+// Option is the interface that allows to set the Queue options.
+type Option func(q *Queue) error
+
+// WithName sets the name of the queue, so multiple queues can share the same table.
+func WithName(name string) Option {
+	return func(q *Queue) error {
+		if name == "" {
+			return errors.New("the queue name is required")
+		}
+
+		q.name = name
+
+		return nil
+	}
+}
+
+// WithWorkers sets the number of workers executing the jobs concurrently.
+func WithWorkers(n int) Option {
+	return func(q *Queue) error {
+		if n < 1 {
+			return errors.New("the number of workers must be at least 1")
+		}
+
+		q.workers = n
+
+		return nil
+	}
+}
+
+// WithPollInterval sets the average time waited by an idle worker before checking again for ready jobs.
+func WithPollInterval(interval time.Duration) Option {
+	return func(q *Queue) error {
+		if int64(interval) < 1 {
+			return errors.New("the poll interval must be greater than zero")
+		}
+
+		q.pollInterval = interval
+
+		return nil
+	}
+}
+
+// WithJobTimeout sets the timeout applied to each job execution via context.
+// A claimed job that is not completed within the timeout plus one minute can be claimed again by another worker.
+func WithJobTimeout(timeout time.Duration) Option {
+	return func(q *Queue) error {
+		if int64(timeout) < 1 {
+			return errors.New("the job timeout must be greater than zero")
+		}
+
+		q.jobTimeout = timeout
+
+		return nil
+	}
+}
+
+// WithMaxAttempts sets the default maximum number of attempts before a job is marked as dead.
+func WithMaxAttempts(n int) Option {
+	return func(q *Queue) error {
+		if n < 1 {
+			return errors.New("the maximum number of attempts must be at least 1")
+		}
+
+		q.maxAttempts = n
+
+		return nil
+	}
+}
+
+// WithBackoff sets the strategy used to compute the delay before retrying a failed job.
+func WithBackoff(backoff retrier.Backoff) Option {
+	return func(q *Queue) error {
+		if backoff == nil {
+			return errors.New("the backoff is required")
+		}
+
+		q.backoff = backoff
+
+		return nil
+	}
+}
+
+// WithMaxDelay sets the maximum delay before retrying a failed job.
+func WithMaxDelay(maxDelay time.Duration) Option {
+	return func(q *Queue) error {
+		if int64(maxDelay) < 1 {
+			return errors.New("the maximum delay must be greater than zero")
+		}
+
+		q.maxDelay = maxDelay
+
+		return nil
+	}
+}
+
+// WithLogger sets the logger used to log the job executions.
+// The logger is also passed to the job handlers via context, with the job fields.
+func WithLogger(l *zap.Logger) Option {
+	return func(q *Queue) error {
+		if l == nil {
+			return errors.New("the logger is required")
+		}
+
+		q.logger = l
+
+		return nil
+	}
+}
+
+// WithMetrics sets the metrics client used to count the job executions.
+func WithMetrics(m metrics.Client) Option {
+	return func(q *Queue) error {
+		if m == nil {
+			return errors.New("the metrics client is required")
+		}
+
+		q.metrics = m
+
+		return nil
+	}
+}
+
+// enqueueConfig contains the options of a new job.
+type enqueueConfig struct {
+	runAt       time.Time
+	uniqueKey   string
+	priority    int
+	maxAttempts int
+}
+
+// EnqueueOption is the interface that allows to set the options of a new job.
+type EnqueueOption func(e *enqueueConfig) error
+
+// WithDelay delays the first execution of the job.
+func WithDelay(delay time.Duration) EnqueueOption {
+	return func(e *enqueueConfig) error {
+		if delay < 0 {
+			return errors.New("the delay must not be negative")
+		}
+
+		e.runAt = e.runAt.Add(delay)
+
+		return nil
+	}
+}
+
+// WithRunAt schedules the first execution of the job at the specified time.
+func WithRunAt(t time.Time) EnqueueOption {
+	return func(e *enqueueConfig) error {
+		if t.IsZero() {
+			return errors.New("the run time is required")
+		}
+
+		e.runAt = t
+
+		return nil
+	}
+}
+
+// WithUniqueKey sets a key that prevents enqueuing the job while another job
+// with the same key is pending or running in the same queue.
+func WithUniqueKey(key string) EnqueueOption {
+	return func(e *enqueueConfig) error {
+		if key == "" {
+			return errors.New("the unique key is required")
+		}
+
+		e.uniqueKey = key
+
+		return nil
+	}
+}
+
+// WithPriority sets the job priority. The jobs with higher priority are executed first.
+func WithPriority(priority int) EnqueueOption {
+	return func(e *enqueueConfig) error {
+		e.priority = priority
+		return nil
+	}
+}
+
+// WithJobMaxAttempts sets the maximum number of attempts of the job, overriding the queue default.
+func WithJobMaxAttempts(n int) EnqueueOption {
+	return func(e *enqueueConfig) error {
+		if n < 1 {
+			return errors.New("the maximum number of attempts must be at least 1")
+		}
+
+		e.maxAttempts = n
+
+		return nil
+	}
+}
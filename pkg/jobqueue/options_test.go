@@ -0,0 +1,195 @@
+package jobqueue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Vonage/gosrvlib/pkg/metrics"
+	"github.com/Vonage/gosrvlib/pkg/retrier"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestWithName(t *testing.T) {
+	t.Parallel()
+
+	q := &Queue{}
+
+	err := WithName("emails")(q)
+	require.NoError(t, err)
+	require.Equal(t, "emails", q.name)
+
+	err = WithName("")(q)
+	require.Error(t, err)
+}
+
+func TestWithWorkers(t *testing.T) {
+	t.Parallel()
+
+	q := &Queue{}
+
+	err := WithWorkers(8)(q)
+	require.NoError(t, err)
+	require.Equal(t, 8, q.workers)
+
+	err = WithWorkers(0)(q)
+	require.Error(t, err)
+}
+
+func TestWithPollInterval(t *testing.T) {
+	t.Parallel()
+
+	q := &Queue{}
+
+	err := WithPollInterval(3 * time.Second)(q)
+	require.NoError(t, err)
+	require.Equal(t, 3*time.Second, q.pollInterval)
+
+	err = WithPollInterval(0)(q)
+	require.Error(t, err)
+}
+
+func TestWithJobTimeout(t *testing.T) {
+	t.Parallel()
+
+	q := &Queue{}
+
+	err := WithJobTimeout(time.Minute)(q)
+	require.NoError(t, err)
+	require.Equal(t, time.Minute, q.jobTimeout)
+
+	err = WithJobTimeout(0)(q)
+	require.Error(t, err)
+}
+
+func TestWithMaxAttempts(t *testing.T) {
+	t.Parallel()
+
+	q := &Queue{}
+
+	err := WithMaxAttempts(10)(q)
+	require.NoError(t, err)
+	require.Equal(t, 10, q.maxAttempts)
+
+	err = WithMaxAttempts(0)(q)
+	require.Error(t, err)
+}
+
+func TestWithBackoff(t *testing.T) {
+	t.Parallel()
+
+	q := &Queue{}
+	b := retrier.ConstantBackoff(time.Second)
+
+	err := WithBackoff(b)(q)
+	require.NoError(t, err)
+	require.Equal(t, b, q.backoff)
+
+	err = WithBackoff(nil)(q)
+	require.Error(t, err)
+}
+
+func TestWithMaxDelay(t *testing.T) {
+	t.Parallel()
+
+	q := &Queue{}
+
+	err := WithMaxDelay(time.Minute)(q)
+	require.NoError(t, err)
+	require.Equal(t, time.Minute, q.maxDelay)
+
+	err = WithMaxDelay(0)(q)
+	require.Error(t, err)
+}
+
+func TestWithLogger(t *testing.T) {
+	t.Parallel()
+
+	q := &Queue{}
+	l := zap.NewNop()
+
+	err := WithLogger(l)(q)
+	require.NoError(t, err)
+	require.Equal(t, l, q.logger)
+
+	err = WithLogger(nil)(q)
+	require.Error(t, err)
+}
+
+func TestWithMetrics(t *testing.T) {
+	t.Parallel()
+
+	q := &Queue{}
+	m := &metrics.Default{}
+
+	err := WithMetrics(m)(q)
+	require.NoError(t, err)
+	require.Equal(t, m, q.metrics)
+
+	err = WithMetrics(nil)(q)
+	require.Error(t, err)
+}
+
+func TestWithDelay(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	e := &enqueueConfig{runAt: now}
+
+	err := WithDelay(time.Minute)(e)
+	require.NoError(t, err)
+	require.Equal(t, now.Add(time.Minute), e.runAt)
+
+	err = WithDelay(-time.Minute)(e)
+	require.Error(t, err)
+}
+
+func TestWithRunAt(t *testing.T) {
+	t.Parallel()
+
+	e := &enqueueConfig{}
+	v := time.Now().Add(time.Hour)
+
+	err := WithRunAt(v)(e)
+	require.NoError(t, err)
+	require.Equal(t, v, e.runAt)
+
+	err = WithRunAt(time.Time{})(e)
+	require.Error(t, err)
+}
+
+func TestWithUniqueKey(t *testing.T) {
+	t.Parallel()
+
+	e := &enqueueConfig{}
+
+	err := WithUniqueKey("u1")(e)
+	require.NoError(t, err)
+	require.Equal(t, "u1", e.uniqueKey)
+
+	err = WithUniqueKey("")(e)
+	require.Error(t, err)
+}
+
+func TestWithPriority(t *testing.T) {
+	t.Parallel()
+
+	e := &enqueueConfig{}
+
+	err := WithPriority(-3)(e)
+	require.NoError(t, err)
+	require.Equal(t, -3, e.priority)
+}
+
+func TestWithJobMaxAttempts(t *testing.T) {
+	t.Parallel()
+
+	e := &enqueueConfig{}
+
+	err := WithJobMaxAttempts(2)(e)
+	require.NoError(t, err)
+	require.Equal(t, 2, e.maxAttempts)
+
+	err = WithJobMaxAttempts(0)(e)
+	require.Error(t, err)
+}
@@ -0,0 +1,212 @@
+package jobqueue
+
+import (
+	"strconv"
+	"strings"
+)
+
+// JobTypesMarker is replaced in the Claim query with the placeholders of the registered job types.
+const JobTypesMarker = "{job_types}"
+
+// jobColumns are the columns returned by the queries selecting the jobs, in the Job fields order.
+const jobColumns = "id, queue, job_type, payload, unique_key, priority, status, attempts, max_attempts, " +
+	"run_at, last_error, created_at, updated_at"
+
+// SQLQueries contains the SQL queries used by the Queue.
+// All the times are stored as unix milliseconds.
+type SQLQueries struct {
+	// Insert adds a new pending job, ignoring the duplicates of an active unique key.
+	// Arguments: queue, job_type, payload, unique_key, priority, max_attempts, run_at, created_at, updated_at.
+	Insert string
+
+	// InsertReturning is true when the Insert query returns the job ID (e.g. PostgreSQL RETURNING clause),
+	// otherwise the ID is read with LastInsertId (e.g. MySQL).
+	InsertReturning bool
+
+	// DollarPlaceholders is true when the queries use the numbered "$n" placeholders (e.g. PostgreSQL),
+	// otherwise the "?" placeholders are used (e.g. MySQL).
+	DollarPlaceholders bool
+
+	// Claim selects and locks the next job ready to run, skipping the rows locked by other workers.
+	// The JobTypesMarker is replaced with one placeholder for each registered job type.
+	// Arguments: queue, now (pending jobs), now (expired leases), job_type...
+	Claim string
+
+	// Lock marks the claimed job as running. Arguments: locked_until, updated_at, id.
+	Lock string
+
+	// Complete marks the running job as done, only if still holding the lease.
+	// Arguments: updated_at, id, locked_until.
+	Complete string
+
+	// Reschedule sets the running job to be retried, only if still holding the lease.
+	// Arguments: run_at, last_error, updated_at, id, locked_until.
+	Reschedule string
+
+	// Kill marks the running job as dead, only if still holding the lease.
+	// Arguments: last_error, updated_at, id, locked_until.
+	Kill string
+
+	// Get returns the job. Arguments: id, queue.
+	Get string
+
+	// List returns the jobs, optionally filtered by status. Arguments: queue, status, status, limit, offset.
+	List string
+
+	// Retry sets a dead or canceled job as pending, without restoring the cleared unique key.
+	// Arguments: run_at, updated_at, id, queue.
+	Retry string
+
+	// Cancel marks a pending job as canceled. Arguments: updated_at, id, queue.
+	Cancel string
+
+	// Purge deletes the done and canceled jobs. Arguments: queue, updated_at.
+	Purge string
+}
+
+// MySQLQueries returns the queries for MySQL 8.0+ and the specified table.
+//
+// Example of a MySQL database table that can be used with these queries:
+//
+//	CREATE TABLE IF NOT EXISTS `jobs` (
+//	  `id` BIGINT NOT NULL AUTO_INCREMENT,
+//	  `queue` VARCHAR(64) NOT NULL,
+//	  `job_type` VARCHAR(64) NOT NULL,
+//	  `payload` MEDIUMBLOB NOT NULL,
+//	  `unique_key` VARCHAR(255) NULL,
+//	  `priority` INT NOT NULL DEFAULT 0,
+//	  `status` VARCHAR(16) NOT NULL,
+//	  `attempts` INT NOT NULL DEFAULT 0,
+//	  `max_attempts` INT NOT NULL,
+//	  `run_at` BIGINT NOT NULL,
+//	  `locked_until` BIGINT NOT NULL DEFAULT 0,
+//	  `last_error` TEXT NOT NULL,
+//	  `created_at` BIGINT NOT NULL,
+//	  `updated_at` BIGINT NOT NULL,
+//	  PRIMARY KEY (`id`),
+//	  UNIQUE INDEX `jobs_unique_key` (`queue`, `unique_key`),
+//	  INDEX `jobs_claim` (`queue`, `status`, `priority`, `run_at`))
+//	ENGINE = InnoDB;
+func MySQLQueries(table string) *SQLQueries {
+	q := commonQueries(table)
+	q.Insert = "INSERT INTO " + table +
+		" (queue, job_type, payload, unique_key, priority, status, attempts, max_attempts, run_at, last_error, created_at, updated_at)" +
+		" VALUES (?, ?, ?, ?, ?, 'pending', 0, ?, ?, '', ?, ?)" +
+		" ON DUPLICATE KEY UPDATE id = id"
+
+	return q
+}
+
+// PostgreSQLQueries returns the queries for PostgreSQL 9.5+ and the specified table.
+//
+// Example of a PostgreSQL database table that can be used with these queries:
+//
+//	CREATE TABLE IF NOT EXISTS jobs (
+//	  id BIGSERIAL PRIMARY KEY,
+//	  queue VARCHAR(64) NOT NULL,
+//	  job_type VARCHAR(64) NOT NULL,
+//	  payload BYTEA NOT NULL,
+//	  unique_key VARCHAR(255) NULL,
+//	  priority INT NOT NULL DEFAULT 0,
+//	  status VARCHAR(16) NOT NULL,
+//	  attempts INT NOT NULL DEFAULT 0,
+//	  max_attempts INT NOT NULL,
+//	  run_at BIGINT NOT NULL,
+//	  locked_until BIGINT NOT NULL DEFAULT 0,
+//	  last_error TEXT NOT NULL,
+//	  created_at BIGINT NOT NULL,
+//	  updated_at BIGINT NOT NULL,
+//	  UNIQUE (queue, unique_key));
+//	CREATE INDEX IF NOT EXISTS jobs_claim ON jobs (queue, status, priority, run_at);
+func PostgreSQLQueries(table string) *SQLQueries {
+	q := commonQueries(table)
+	q.Insert = "INSERT INTO " + table +
+		" (queue, job_type, payload, unique_key, priority, status, attempts, max_attempts, run_at, last_error, created_at, updated_at)" +
+		" VALUES (?, ?, ?, ?, ?, 'pending', 0, ?, ?, '', ?, ?)" +
+		" ON CONFLICT (queue, unique_key) DO NOTHING RETURNING id"
+	q.InsertReturning = true
+	q.DollarPlaceholders = true
+
+	q.Insert = dollarPlaceholders(q.Insert)
+	q.Claim = dollarPlaceholders(q.Claim)
+	q.Lock = dollarPlaceholders(q.Lock)
+	q.Complete = dollarPlaceholders(q.Complete)
+	q.Reschedule = dollarPlaceholders(q.Reschedule)
+	q.Kill = dollarPlaceholders(q.Kill)
+	q.Get = dollarPlaceholders(q.Get)
+	q.List = dollarPlaceholders(q.List)
+	q.Retry = dollarPlaceholders(q.Retry)
+	q.Cancel = dollarPlaceholders(q.Cancel)
+	q.Purge = dollarPlaceholders(q.Purge)
+
+	return q
+}
+
+// commonQueries returns the queries shared by MySQL and PostgreSQL, with the "?" placeholders.
+// The unique key is cleared when the job is no longer active, so the same key can be enqueued again.
+func commonQueries(table string) *SQLQueries {
+	return &SQLQueries{
+		Claim: "SELECT " + jobColumns + " FROM " + table +
+			" WHERE queue = ? AND ((status = 'pending' AND run_at <= ?) OR (status = 'running' AND locked_until < ?))" +
+			" AND job_type IN (" + JobTypesMarker + ")" +
+			" ORDER BY priority DESC, run_at ASC, id ASC LIMIT 1 FOR UPDATE SKIP LOCKED",
+		Lock: "UPDATE " + table +
+			" SET status = 'running', attempts = attempts + 1, locked_until = ?, updated_at = ? WHERE id = ?",
+		Complete: "UPDATE " + table +
+			" SET status = 'done', unique_key = NULL, locked_until = 0, updated_at = ? WHERE id = ?" +
+			" AND status = 'running' AND locked_until = ?",
+		Reschedule: "UPDATE " + table +
+			" SET status = 'pending', run_at = ?, last_error = ?, locked_until = 0, updated_at = ? WHERE id = ?" +
+			" AND status = 'running' AND locked_until = ?",
+		Kill: "UPDATE " + table +
+			" SET status = 'dead', unique_key = NULL, last_error = ?, locked_until = 0, updated_at = ? WHERE id = ?" +
+			" AND status = 'running' AND locked_until = ?",
+		Get: "SELECT " + jobColumns + " FROM " + table + " WHERE id = ? AND queue = ?",
+		List: "SELECT " + jobColumns + " FROM " + table +
+			" WHERE queue = ? AND (? = '' OR status = ?) ORDER BY id DESC LIMIT ? OFFSET ?",
+		Retry: "UPDATE " + table +
+			" SET status = 'pending', attempts = 0, run_at = ?, last_error = '', updated_at = ?" +
+			" WHERE id = ? AND queue = ? AND status IN ('dead', 'canceled')",
+		Cancel: "UPDATE " + table +
+			" SET status = 'canceled', unique_key = NULL, updated_at = ?" +
+			" WHERE id = ? AND queue = ? AND status = 'pending'",
+		Purge: "DELETE FROM " + table +
+			" WHERE queue = ? AND status IN ('done', 'canceled') AND updated_at < ?",
+	}
+}
+
+// claimQuery returns the Claim query with the placeholders of the specified number of job types.
+func (q *SQLQueries) claimQuery(types int) string {
+	placeholders := make([]string, types)
+
+	for i := range placeholders {
+		placeholders[i] = "?"
+
+		if q.DollarPlaceholders {
+			// the job types follow the queue and the two times arguments
+			placeholders[i] = "$" + strconv.Itoa(i+4)
+		}
+	}
+
+	return strings.ReplaceAll(q.Claim, JobTypesMarker, strings.Join(placeholders, ", "))
+}
+
+// dollarPlaceholders replaces the "?" placeholders with the numbered "$n" ones.
+func dollarPlaceholders(query string) string {
+	var b strings.Builder
+
+	n := 0
+
+	for _, c := range query {
+		if c != '?' {
+			b.WriteRune(c)
+			continue
+		}
+
+		n++
+
+		b.WriteString("$" + strconv.Itoa(n))
+	}
+
+	return b.String()
+}
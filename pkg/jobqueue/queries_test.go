@@ -0,0 +1,102 @@
+package jobqueue
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMySQLQueries(t *testing.T) {
+	t.Parallel()
+
+	q := MySQLQueries("jobs")
+
+	require.False(t, q.InsertReturning)
+	require.False(t, q.DollarPlaceholders)
+	require.Contains(t, q.Insert, "INSERT INTO jobs ")
+	require.Contains(t, q.Insert, "ON DUPLICATE KEY UPDATE")
+	require.Contains(t, q.Claim, "FOR UPDATE SKIP LOCKED")
+	require.NotContains(t, q.Claim, "$1")
+}
+
+func TestPostgreSQLQueries(t *testing.T) {
+	t.Parallel()
+
+	q := PostgreSQLQueries("jobs")
+
+	require.True(t, q.InsertReturning)
+	require.True(t, q.DollarPlaceholders)
+	require.Contains(t, q.Insert, "ON CONFLICT (queue, unique_key) DO NOTHING RETURNING id")
+	require.Contains(t, q.Insert, "$9")
+
+	for _, query := range []string{
+		q.Insert, q.Claim, q.Lock, q.Complete, q.Reschedule, q.Kill, q.Get, q.List, q.Retry, q.Cancel, q.Purge,
+	} {
+		require.NotContains(t, query, "?")
+	}
+
+	require.Contains(t, q.List, "($2 = '' OR status = $3)")
+}
+
+func TestSQLQueries_claimQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		queries *SQLQueries
+		types   int
+		want    string
+	}{
+		{
+			name:    "mysql single type",
+			queries: MySQLQueries("jobs"),
+			types:   1,
+			want:    "AND job_type IN (?) ORDER BY",
+		},
+		{
+			name:    "mysql many types",
+			queries: MySQLQueries("jobs"),
+			types:   3,
+			want:    "AND job_type IN (?, ?, ?) ORDER BY",
+		},
+		{
+			name:    "postgresql many types",
+			queries: PostgreSQLQueries("jobs"),
+			types:   2,
+			want:    "AND job_type IN ($4, $5) ORDER BY",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := tt.queries.claimQuery(tt.types)
+
+			require.Contains(t, got, tt.want)
+			require.NotContains(t, got, JobTypesMarker)
+		})
+	}
+}
+
+func TestDollarPlaceholders(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{name: "empty", query: "", want: ""},
+		{name: "none", query: "SELECT 1", want: "SELECT 1"},
+		{name: "many", query: "UPDATE t SET a = ?, b = ? WHERE id = ?", want: "UPDATE t SET a = $1, b = $2 WHERE id = $3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, dollarPlaceholders(tt.query))
+		})
+	}
+}
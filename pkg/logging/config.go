@@ -15,6 +15,7 @@ type config struct {
 	outputPaths       []string
 	errorOutputPaths  []string
 	incMetricLogLevel IncrementLogMetricsFunc
+	levelController   *LevelController
 }
 
 func defaultConfig() *config {
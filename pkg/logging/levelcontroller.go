@@ -0,0 +1,272 @@
+package logging
+
+import (
+	"errors"
+	"maps"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// componentKey is the name of the field set by WithComponent.
+const componentKey = "component"
+
+// LevelInfo contains a log level and the optional time when the previous level is restored.
+type LevelInfo struct {
+	// Level is the log level (e.g. "debug", "info", "warn", "error").
+	Level string `json:"level"`
+
+	// RevertAt is the time when the previous level is restored, if set with a timeout.
+	RevertAt *time.Time `json:"revert_at,omitempty"`
+}
+
+// LevelState contains the global log level and the per-component overrides.
+type LevelState struct {
+	// Level is the global log level (e.g. "debug", "info", "warn", "error").
+	Level string `json:"level"`
+
+	// RevertAt is the time when the previous global level is restored, if set with a timeout.
+	RevertAt *time.Time `json:"revert_at,omitempty"`
+
+	// Components contains the log level overrides, keyed by the component name set with WithComponent.
+	Components map[string]LevelInfo `json:"components"`
+}
+
+// levelRevert is a pending restore of a previous level.
+type levelRevert struct {
+	timer  *time.Timer
+	at     time.Time
+	level  zapcore.Level
+	remove bool // the component override is removed instead of restored
+}
+
+// LevelController allows changing the log level at runtime, globally (backed by zap.AtomicLevel)
+// and per component, as tagged by WithComponent and WithComponentAndMethod.
+// The levels can be changed temporarily, restoring the previous ones after a timeout.
+//
+// The controller is attached to a logger with the WithLevelController option.
+type LevelController struct {
+	mu         sync.Mutex
+	level      zap.AtomicLevel
+	components atomic.Pointer[map[string]zapcore.Level]
+	reverts    map[string]*levelRevert // keyed by component name, the global level uses the empty name
+}
+
+// NewLevelController returns a new log level controller.
+// The global level is set by NewLogger when the controller is attached with WithLevelController.
+func NewLevelController() *LevelController {
+	c := &LevelController{
+		level:   zap.NewAtomicLevelAt(zap.DebugLevel),
+		reverts: make(map[string]*levelRevert),
+	}
+
+	c.components.Store(&map[string]zapcore.Level{})
+
+	return c
+}
+
+// AtomicLevel returns the global log level.
+func (c *LevelController) AtomicLevel() zap.AtomicLevel {
+	return c.level
+}
+
+// Level returns the global log level.
+func (c *LevelController) Level() zapcore.Level {
+	return c.level.Level()
+}
+
+// ComponentLevel returns the effective log level of the component.
+func (c *LevelController) ComponentLevel(comp string) zapcore.Level {
+	if l, ok := (*c.components.Load())[comp]; ok {
+		return l
+	}
+
+	return c.level.Level()
+}
+
+// SetLevel sets the global log level.
+// If the timeout is positive, the previous level is restored after the timeout.
+func (c *LevelController) SetLevel(level zapcore.Level, timeout time.Duration) {
+	c.set("", level, false, timeout)
+}
+
+// SetComponentLevel overrides the log level of the specified component.
+// If the timeout is positive, the previous level is restored after the timeout.
+func (c *LevelController) SetComponentLevel(comp string, level zapcore.Level, timeout time.Duration) error {
+	if comp == "" {
+		return errors.New("the component name is required")
+	}
+
+	c.set(comp, level, false, timeout)
+
+	return nil
+}
+
+// RemoveComponentLevel removes the log level override of the specified component,
+// including any pending restore.
+func (c *LevelController) RemoveComponentLevel(comp string) {
+	if comp != "" {
+		c.set(comp, 0, true, 0)
+	}
+}
+
+// State returns the current log levels.
+func (c *LevelController) State() LevelState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	components := *c.components.Load()
+
+	state := LevelState{
+		Level:      c.level.Level().String(),
+		RevertAt:   c.revertAt(""),
+		Components: make(map[string]LevelInfo, len(components)),
+	}
+
+	for comp, l := range components {
+		state.Components[comp] = LevelInfo{
+			Level:    l.String(),
+			RevertAt: c.revertAt(comp),
+		}
+	}
+
+	return state
+}
+
+func (c *LevelController) revertAt(comp string) *time.Time {
+	r, ok := c.reverts[comp]
+	if !ok {
+		return nil
+	}
+
+	at := r.at
+
+	return &at
+}
+
+// set changes the level of the component (or the global one for the empty name).
+// A pending restore is replaced, but the level to restore is preserved.
+func (c *LevelController) set(comp string, level zapcore.Level, remove bool, timeout time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	prev, exists := c.current(comp)
+
+	if r, ok := c.reverts[comp]; ok {
+		r.timer.Stop()
+		delete(c.reverts, comp)
+
+		prev, exists = r.level, !r.remove
+	}
+
+	if timeout > 0 {
+		r := &levelRevert{
+			at:     time.Now().UTC().Add(timeout),
+			level:  prev,
+			remove: !exists,
+		}
+
+		r.timer = time.AfterFunc(timeout, func() { c.revert(comp, r) })
+		c.reverts[comp] = r
+	}
+
+	c.apply(comp, level, remove)
+}
+
+// revert restores the previous level, unless the pending restore has been replaced.
+func (c *LevelController) revert(comp string, r *levelRevert) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.reverts[comp] != r {
+		return
+	}
+
+	delete(c.reverts, comp)
+
+	c.apply(comp, r.level, r.remove)
+}
+
+func (c *LevelController) current(comp string) (zapcore.Level, bool) {
+	if comp == "" {
+		return c.level.Level(), true
+	}
+
+	l, ok := (*c.components.Load())[comp]
+
+	return l, ok
+}
+
+func (c *LevelController) apply(comp string, level zapcore.Level, remove bool) {
+	if comp == "" {
+		c.level.SetLevel(level)
+		return
+	}
+
+	// the map is replaced to avoid locking on each log entry
+	m := maps.Clone(*c.components.Load())
+
+	if remove {
+		delete(m, comp)
+	} else {
+		m[comp] = level
+	}
+
+	c.components.Store(&m)
+}
+
+func (c *LevelController) enabled(comp string, level zapcore.Level) bool {
+	return c.ComponentLevel(comp).Enabled(level)
+}
+
+// wrapCore returns a core filtering the entries with the controller levels.
+func (c *LevelController) wrapCore(core zapcore.Core) zapcore.Core {
+	return &levelCore{Core: core, ctrl: c}
+}
+
+// levelCore is a zapcore.Core filtering the entries by the level of the component field.
+type levelCore struct {
+	zapcore.Core
+
+	ctrl      *LevelController
+	component string
+}
+
+// Enabled returns true if the level is enabled for the component of this core.
+func (c *levelCore) Enabled(level zapcore.Level) bool {
+	return c.ctrl.enabled(c.component, level)
+}
+
+// Level returns the minimum enabled level for the component of this core.
+func (c *levelCore) Level() zapcore.Level {
+	return c.ctrl.ComponentLevel(c.component)
+}
+
+// With adds the fields to the core, tracking the component name.
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	comp := c.component
+
+	for _, f := range fields {
+		if f.Key == componentKey && f.Type == zapcore.StringType {
+			comp = f.String
+		}
+	}
+
+	return &levelCore{
+		Core:      c.Core.With(fields),
+		ctrl:      c.ctrl,
+		component: comp,
+	}
+}
+
+// Check adds the wrapped core to the checked entry if the level is enabled.
+func (c *levelCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(entry.Level) {
+		return ce
+	}
+
+	return c.Core.Check(entry, ce)
+}
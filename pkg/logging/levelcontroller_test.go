@@ -0,0 +1,140 @@
+package logging
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLevelController(t *testing.T) {
+	t.Parallel()
+
+	c := NewLevelController()
+	core, logs := observer.New(zap.DebugLevel)
+	l := zap.New(c.wrapCore(core))
+
+	c.SetLevel(zap.InfoLevel, 0)
+	require.Equal(t, zap.InfoLevel, c.AtomicLevel().Level())
+	require.Equal(t, zap.InfoLevel, l.Level())
+
+	ctx := WithLogger(context.Background(), l)
+	db := WithComponent(ctx, "db")
+	api := WithComponentAndMethod(ctx, "api", "get")
+
+	l.Debug("root debug")
+	db.Debug("db debug")
+
+	err := c.SetComponentLevel("db", zap.DebugLevel, 0)
+	require.NoError(t, err)
+	require.Equal(t, zap.DebugLevel, db.Level())
+	require.Equal(t, zap.InfoLevel, api.Level())
+
+	err = c.SetComponentLevel("", zap.DebugLevel, 0)
+	require.Error(t, err)
+
+	l.Debug("root debug")
+	db.Debug("db debug")
+	db.With(zap.String("other", "x")).Debug("db child debug")
+	db.With(zap.String(componentKey, "api")).Debug("api debug")
+	api.Debug("api debug")
+	api.Info("api info")
+
+	messages := []string{}
+	for _, e := range logs.All() {
+		messages = append(messages, e.Message)
+	}
+
+	require.Equal(t, []string{"db debug", "db child debug", "api info"}, messages)
+
+	require.Equal(t, LevelState{
+		Level:      "info",
+		Components: map[string]LevelInfo{"db": {Level: "debug"}},
+	}, c.State())
+
+	c.RemoveComponentLevel("db")
+	c.RemoveComponentLevel("")
+	require.Equal(t, zap.InfoLevel, db.Level())
+	require.Empty(t, c.State().Components)
+}
+
+func TestLevelController_revert(t *testing.T) {
+	t.Parallel()
+
+	c := NewLevelController()
+	c.SetLevel(zap.WarnLevel, 0)
+
+	require.NoError(t, c.SetComponentLevel("db", zap.ErrorLevel, 0))
+
+	// the pending restore is replaced, but the original level is restored
+	c.SetLevel(zap.InfoLevel, time.Hour)
+	c.SetLevel(zap.DebugLevel, 50*time.Millisecond)
+
+	require.NoError(t, c.SetComponentLevel("db", zap.DebugLevel, 50*time.Millisecond))
+	require.NoError(t, c.SetComponentLevel("cache", zap.DebugLevel, 50*time.Millisecond))
+
+	state := c.State()
+	require.Equal(t, "debug", state.Level)
+	require.NotNil(t, state.RevertAt)
+	require.NotNil(t, state.Components["db"].RevertAt)
+	require.NotNil(t, state.Components["cache"].RevertAt)
+
+	require.Eventually(t, func() bool {
+		return c.Level() == zap.WarnLevel &&
+			c.ComponentLevel("db") == zap.ErrorLevel &&
+			c.ComponentLevel("cache") == zap.WarnLevel
+	}, time.Second, 10*time.Millisecond)
+
+	require.Equal(t, LevelState{
+		Level:      "warn",
+		Components: map[string]LevelInfo{"db": {Level: "error"}},
+	}, c.State())
+
+	// a permanent change cancels the pending restore
+	c.SetLevel(zap.DebugLevel, 10*time.Millisecond)
+	c.SetLevel(zap.ErrorLevel, 0)
+
+	// a stale restore is ignored
+	c.revert("", &levelRevert{level: zap.DebugLevel})
+
+	time.Sleep(30 * time.Millisecond)
+	require.Equal(t, zapcore.ErrorLevel, c.Level())
+	require.Nil(t, c.State().RevertAt)
+}
+
+func TestNewLogger_levelController(t *testing.T) {
+	t.Parallel()
+
+	out := filepath.Join(t.TempDir(), "out.log")
+	c := NewLevelController()
+
+	l, err := NewLogger(
+		WithLevelStr("info"),
+		WithLevelController(c),
+		WithOutputPaths([]string{out}),
+	)
+	require.NoError(t, err)
+	require.Equal(t, zap.InfoLevel, c.Level())
+
+	ctx := WithLogger(context.Background(), l)
+
+	WithComponent(ctx, "db").Debug("hidden")
+
+	require.NoError(t, c.SetComponentLevel("db", zap.DebugLevel, 0))
+
+	WithComponent(ctx, "db").Debug("visible")
+	l.Debug("hidden")
+
+	Sync(l)
+
+	data, err := os.ReadFile(out) //nolint:gosec
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"msg":"visible"`)
+	require.NotContains(t, string(data), "hidden")
+}
@@ -10,6 +10,8 @@ It includes the following features:
   - Default logger configuration with program name, version, and release.
   - Custom logger configuration with additional fields.
   - Context-based logging with component and method tags.
+  - Runtime log level changes, globally and per component, with optional
+    automatic revert (see LevelController).
   - Log level function hook for incrementing log metrics.
   - Log sync function to flush the logger and ignore the error.
   - Log close function to close an object and log an error in case of failure.
@@ -105,8 +107,19 @@ func NewLogger(opts ...Option) (*zap.Logger, error) {
 		hostname = ""
 	}
 
+	level := zap.NewAtomicLevelAt(cfg.level)
+	buildOpts := []zap.Option{}
+
+	if cfg.levelController != nil {
+		cfg.levelController.SetLevel(cfg.level, 0)
+
+		// the entries are filtered by the controller levels
+		level = zap.NewAtomicLevelAt(zap.DebugLevel)
+		buildOpts = append(buildOpts, zap.WrapCore(cfg.levelController.wrapCore))
+	}
+
 	zapCfg := zap.Config{
-		Level:    zap.NewAtomicLevelAt(cfg.level),
+		Level:    level,
 		Encoding: encoding,
 		EncoderConfig: zapcore.EncoderConfig{
 			MessageKey:   "msg",
@@ -125,7 +138,7 @@ func NewLogger(opts ...Option) (*zap.Logger, error) {
 		},
 	}
 
-	l, err := zapCfg.Build()
+	l, err := zapCfg.Build(buildOpts...)
 	if err != nil {
 		return nil, err //nolint:wrapcheck
 	}
@@ -149,13 +162,13 @@ func Sync(s Syncer) {
 
 // WithComponent creates a child logger with an extra "component" tag.
 func WithComponent(ctx context.Context, comp string) *zap.Logger {
-	return FromContext(ctx).With(zap.String("component", comp))
+	return FromContext(ctx).With(zap.String(componentKey, comp))
 }
 
 // WithComponentAndMethod creates a child logger with extra "component" and "method" tags.
 func WithComponentAndMethod(ctx context.Context, comp, method string) *zap.Logger {
 	return FromContext(ctx).With(
-		zap.String("component", comp),
+		zap.String(componentKey, comp),
 		zap.String("method", method),
 	)
 }
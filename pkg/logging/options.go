@@ -1,6 +1,8 @@
 package logging
 
 import (
+	"errors"
+
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -83,3 +85,18 @@ func WithErrorOutputPaths(paths []string) Option {
 		return nil
 	}
 }
+
+// WithLevelController attaches a controller to change the log levels at runtime,
+// globally and per component (see WithComponent).
+// The global level of the controller is set to the logger level.
+func WithLevelController(c *LevelController) Option {
+	return func(cfg *config) error {
+		if c == nil {
+			return errors.New("levelController is required")
+		}
+
+		cfg.levelController = c
+
+		return nil
+	}
+}
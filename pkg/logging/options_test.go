@@ -136,3 +136,16 @@ func TestWithErrorOutputPaths(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, v, cfg.errorOutputPaths)
 }
+
+func TestWithLevelController(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config{}
+	err := WithLevelController(nil)(cfg)
+	require.Error(t, err)
+
+	c := NewLevelController()
+	err = WithLevelController(c)(cfg)
+	require.NoError(t, err)
+	require.Same(t, c, cfg.levelController)
+}
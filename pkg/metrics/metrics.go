@@ -37,6 +37,36 @@ type Client interface {
 	Close() error
 }
 
+// EventCounter is the optional interface of the Client implementations
+// counting the events by task, operation and outcome.
+type EventCounter interface {
+	// IncEventCounter increments the number of events by task, operation and outcome.
+	IncEventCounter(task, operation, outcome string)
+}
+
+// IncEventCounter increments the number of events by task, operation and outcome
+// if the client implements the EventCounter interface.
+func IncEventCounter(c Client, task, operation, outcome string) {
+	if e, ok := c.(EventCounter); ok {
+		e.IncEventCounter(task, operation, outcome)
+	}
+}
+
+// HealthCheckStatusSetter is the optional interface of the Client implementations
+// reporting the status of the health checks.
+type HealthCheckStatusSetter interface {
+	// SetHealthCheckStatus sets the status gauge of a health check (1 = healthy, 0 = failing).
+	SetHealthCheckStatus(check string, healthy bool)
+}
+
+// SetHealthCheckStatus sets the status gauge of a health check
+// if the client implements the HealthCheckStatusSetter interface.
+func SetHealthCheckStatus(c Client, check string, healthy bool) {
+	if s, ok := c.(HealthCheckStatusSetter); ok {
+		s.SetHealthCheckStatus(check, healthy)
+	}
+}
+
 // Default is the default implementation for the Client interface.
 type Default struct{}
 
@@ -73,6 +103,18 @@ func (c *Default) IncErrorCounter(_, _, _ string) {
 	_ = 0
 }
 
+// IncEventCounter is an empty function.
+func (c *Default) IncEventCounter(_, _, _ string) {
+	// Do nothing.
+	_ = 0
+}
+
+// SetHealthCheckStatus is an empty function.
+func (c *Default) SetHealthCheckStatus(_ string, _ bool) {
+	// Do nothing.
+	_ = 0
+}
+
 // Close method.
 func (c *Default) Close() error {
 	return nil
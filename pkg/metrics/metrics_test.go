@@ -89,6 +89,70 @@ func TestIncErrorCounter(t *testing.T) {
 	c.IncErrorCounter("test_task", "test_operation", "3791")
 }
 
+func TestIncEventCounter(t *testing.T) {
+	t.Parallel()
+
+	c := &Default{}
+
+	c.IncEventCounter("test_task", "test_operation", "test_outcome")
+}
+
+func TestSetHealthCheckStatus(t *testing.T) {
+	t.Parallel()
+
+	c := &Default{}
+
+	c.SetHealthCheckStatus("test_check", true)
+}
+
+type testHealthCheckStatusClient struct {
+	Default
+
+	status map[string]bool
+}
+
+func (c *testHealthCheckStatusClient) SetHealthCheckStatus(check string, healthy bool) {
+	c.status[check] = healthy
+}
+
+type testEventCounterClient struct {
+	Default
+
+	events []string
+}
+
+func (c *testEventCounterClient) IncEventCounter(task, operation, outcome string) {
+	c.events = append(c.events, task+":"+operation+":"+outcome)
+}
+
+type testBasicClient struct {
+	Client
+}
+
+func TestSetHealthCheckStatusFunc(t *testing.T) {
+	t.Parallel()
+
+	c := &testHealthCheckStatusClient{status: make(map[string]bool)}
+
+	SetHealthCheckStatus(c, "test_check", true)
+	require.Equal(t, map[string]bool{"test_check": true}, c.status)
+
+	// clients without the optional method are ignored
+	SetHealthCheckStatus(&testBasicClient{}, "test_check", true)
+}
+
+func TestIncEventCounterFunc(t *testing.T) {
+	t.Parallel()
+
+	c := &testEventCounterClient{}
+
+	IncEventCounter(c, "test_task", "test_operation", "test_outcome")
+	require.Equal(t, []string{"test_task:test_operation:test_outcome"}, c.events)
+
+	// clients without the optional method are ignored
+	IncEventCounter(&testBasicClient{}, "test_task", "test_operation", "test_outcome")
+}
+
 func TestClose(t *testing.T) {
 	t.Parallel()
 
@@ -42,11 +42,19 @@ const (
 	// NameErrorCode is the name of the collector that counts the number of errors by task, operation and error code.
 	NameErrorCode = "error_code_total"
 
+	// NameEvent is the name of the collector that counts the number of events by task, operation and outcome.
+	NameEvent = "event_total"
+
+	// NameHealthCheckStatus is the name of the collector that reports the status of each health check (1 = healthy, 0 = failing).
+	NameHealthCheckStatus = "health_check_status"
+
+	labelCheck     = "check"
 	labelCode      = "code"
 	labelHandler   = "handler"
 	labelLevel     = "level"
 	labelMethod    = "method"
 	labelOperation = "operation"
+	labelOutcome   = "outcome"
 	labelTask      = "task"
 )
 
@@ -68,6 +76,8 @@ type Client struct {
 	collectorOutboundInFlightRequests prometheus.Gauge
 	collectorErrorLevel               *prometheus.CounterVec
 	collectorErrorCode                *prometheus.CounterVec
+	collectorEvent                    *prometheus.CounterVec
+	collectorHealthCheckStatus        *prometheus.GaugeVec
 }
 
 // New creates a new metrics instance with default collectors.
@@ -144,6 +154,22 @@ func (c *Client) IncErrorCounter(task, operation, code string) {
 	c.collectorErrorCode.With(prometheus.Labels{labelTask: task, labelOperation: operation, labelCode: code}).Inc()
 }
 
+// IncEventCounter increments the number of events by task, operation and outcome.
+func (c *Client) IncEventCounter(task, operation, outcome string) {
+	c.collectorEvent.With(prometheus.Labels{labelTask: task, labelOperation: operation, labelOutcome: outcome}).Inc()
+}
+
+// SetHealthCheckStatus sets the status gauge of a health check (1 = healthy, 0 = failing).
+func (c *Client) SetHealthCheckStatus(check string, healthy bool) {
+	var v float64
+
+	if healthy {
+		v = 1
+	}
+
+	c.collectorHealthCheckStatus.With(prometheus.Labels{labelCheck: check}).Set(v)
+}
+
 // Close method.
 func (c *Client) Close() error {
 	return nil
@@ -233,6 +259,22 @@ func (c *Client) defaultCollectors() error {
 		[]string{labelTask, labelOperation, labelCode},
 	)
 
+	c.collectorEvent = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: NameEvent,
+			Help: "Number of events by task, operation and outcome.",
+		},
+		[]string{labelTask, labelOperation, labelOutcome},
+	)
+
+	c.collectorHealthCheckStatus = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: NameHealthCheckStatus,
+			Help: "Status of each health check (1 = healthy, 0 = failing).",
+		},
+		[]string{labelCheck},
+	)
+
 	colls := []prometheus.Collector{
 		collectors.NewGoCollector(),
 		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
@@ -246,6 +288,8 @@ func (c *Client) defaultCollectors() error {
 		c.collectorOutboundInFlightRequests,
 		c.collectorErrorLevel,
 		c.collectorErrorCode,
+		c.collectorEvent,
+		c.collectorHealthCheckStatus,
 	}
 
 	for _, m := range colls {
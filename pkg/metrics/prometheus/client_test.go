@@ -168,6 +168,36 @@ func TestIncErrorCounter(t *testing.T) {
 	}
 }
 
+func TestIncEventCounter(t *testing.T) {
+	t.Parallel()
+
+	c, err := New()
+	require.NoError(t, err, "unexpected error = %v", err)
+
+	c.IncEventCounter("test_task", "test_operation", "test_outcome")
+
+	i, err := testutil.GatherAndCount(c.registry, NameEvent)
+	require.NoError(t, err, "failed to gather metrics: %s", err)
+	require.Equal(t, 1, i)
+}
+
+func TestSetHealthCheckStatus(t *testing.T) {
+	t.Parallel()
+
+	c, err := New()
+	require.NoError(t, err, "unexpected error = %v", err)
+
+	c.SetHealthCheckStatus("test_check_ok", true)
+	c.SetHealthCheckStatus("test_check_ko", false)
+
+	i, err := testutil.GatherAndCount(c.registry, NameHealthCheckStatus)
+	require.NoError(t, err, "failed to gather metrics: %s", err)
+	require.Equal(t, 2, i)
+
+	require.InDelta(t, 1.0, testutil.ToFloat64(c.collectorHealthCheckStatus.WithLabelValues("test_check_ok")), 0)
+	require.InDelta(t, 0.0, testutil.ToFloat64(c.collectorHealthCheckStatus.WithLabelValues("test_check_ko")), 0)
+}
+
 func TestClose(t *testing.T) {
 	t.Parallel()
 
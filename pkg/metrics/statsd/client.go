@@ -28,6 +28,8 @@ const (
 
 	labelCount        = "count"
 	labelError        = "error"
+	labelEvent        = "event"
+	labelHealthCheck  = "health_check"
 	labelIn           = "in"
 	labelInbound      = "inbound"
 	labelLevel        = "level"
@@ -151,6 +153,22 @@ func (c *Client) IncErrorCounter(task, operation, code string) {
 	c.statsd.Increment(labelError + labelSeparator + task + labelSeparator + operation + labelSeparator + code)
 }
 
+// IncEventCounter increments the number of events by task, operation and outcome.
+func (c *Client) IncEventCounter(task, operation, outcome string) {
+	c.statsd.Increment(labelEvent + labelSeparator + task + labelSeparator + operation + labelSeparator + outcome)
+}
+
+// SetHealthCheckStatus sets the status gauge of a health check (1 = healthy, 0 = failing).
+func (c *Client) SetHealthCheckStatus(check string, healthy bool) {
+	var v int
+
+	if healthy {
+		v = 1
+	}
+
+	c.statsd.Gauge(labelHealthCheck+labelSeparator+check, v)
+}
+
 // Close method.
 func (c *Client) Close() error {
 	c.statsd.Close()
@@ -184,6 +184,25 @@ func TestIncErrorCounter(t *testing.T) {
 	c.IncErrorCounter("test_task", "test_operation", "3791")
 }
 
+func TestIncEventCounter(t *testing.T) {
+	t.Parallel()
+
+	c, err := New()
+	require.NoError(t, err, "unexpected error = %v", err)
+
+	c.IncEventCounter("test_task", "test_operation", "test_outcome")
+}
+
+func TestSetHealthCheckStatus(t *testing.T) {
+	t.Parallel()
+
+	c, err := New()
+	require.NoError(t, err, "unexpected error = %v", err)
+
+	c.SetHealthCheckStatus("test_check", true)
+	c.SetHealthCheckStatus("test_check", false)
+}
+
 func TestInstrumentDB(t *testing.T) {
 	t.Parallel()
 
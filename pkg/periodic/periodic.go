@@ -6,6 +6,9 @@ It allows for the configuration of a maximum random jitter time between each
 function call and a timeout applied to each function call via Context. The
 jitter is useful for avoiding the Thundering herd problem
 (https://en.wikipedia.org/wiki/Thundering_herd_problem).
+
+To execute functions at wall clock times defined by cron expressions see the
+github.com/Vonage/gosrvlib/pkg/cron package.
 */
 package periodic
 
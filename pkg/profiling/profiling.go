@@ -26,8 +26,12 @@ import (
 // If option is "cmdline", "profile", "symbol" or "trace", the respective pprof handler is called.
 // For any other value of option, the pprof.Handler is called with the option as argument.
 func PProfHandler(w http.ResponseWriter, r *http.Request) {
-	ps := httprouter.ParamsFromContext(r.Context())
-	profile := strings.TrimPrefix(ps.ByName("option"), "/")
+	option := httprouter.ParamsFromContext(r.Context()).ByName("option")
+	if option == "" {
+		option = r.PathValue("option")
+	}
+
+	profile := strings.TrimPrefix(option, "/")
 
 	var handler http.HandlerFunc
 
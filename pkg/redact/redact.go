@@ -10,17 +10,18 @@ import (
 	"regexp"
 )
 
-const (
-	redacted = `@~REDACTED~@`
+// Redacted is the string replacing the sensitive data.
+const Redacted = `@~REDACTED~@`
 
+const (
 	regexPatternAuthorizationHeader = `(?i)(authorization[\s]*:[\s]*).*`
-	redactAuthorizationHeader       = `$1` + redacted
+	redactAuthorizationHeader       = `$1` + Redacted
 
 	regexPatternJSONKey = `(?i)"([^"]*)(key|password|secret)([^"]*)"([\s]*:[\s]*)"[^"]*"`
-	redactJSONKey       = `"$1$2$3"$4"` + redacted + `"`
+	redactJSONKey       = `"$1$2$3"$4"` + Redacted + `"`
 
 	regexPatternURLEncodedKey = `(?i)([^=&\n]*)(key|password|secret)([^=]*)=[^=&\n]*`
-	redactURLEncodedKey       = `$1$2$3=` + redacted
+	redactURLEncodedKey       = `$1$2$3=` + Redacted
 )
 
 var (
@@ -0,0 +1,138 @@
+package retrier
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// Backoff is the interface of the strategies used to compute the delay before each retry.
+type Backoff interface {
+	// Delay returns the delay before the specified retry (starting from 1),
+	// given the delay applied before the previous retry (zero for the first retry).
+	Delay(retry uint, prev time.Duration) time.Duration
+}
+
+// Jitter is the type of randomization applied to the ExponentialBackoff delays.
+// See: https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/
+type Jitter int
+
+const (
+	// NoJitter applies the exponential delay as is.
+	NoJitter Jitter = iota
+
+	// FullJitter applies a random delay between zero and the exponential delay.
+	FullJitter
+
+	// EqualJitter applies half of the exponential delay plus a random delay up to the other half.
+	EqualJitter
+
+	// DecorrelatedJitter applies a random delay between the initial delay and three times the previous delay.
+	// The factor is not used.
+	DecorrelatedJitter
+)
+
+// constantBackoff applies the same delay before each retry.
+type constantBackoff struct {
+	delay time.Duration
+}
+
+// ConstantBackoff returns a Backoff that applies the same delay before each retry.
+func ConstantBackoff(delay time.Duration) Backoff {
+	return &constantBackoff{delay: delay}
+}
+
+// Delay implements the Backoff interface.
+func (b *constantBackoff) Delay(_ uint, _ time.Duration) time.Duration {
+	return b.delay
+}
+
+// linearBackoff increases the delay by a fixed amount before each retry.
+type linearBackoff struct {
+	initial   time.Duration
+	increment time.Duration
+}
+
+// LinearBackoff returns a Backoff that applies the initial delay before the first retry,
+// and increases it by the increment before each successive retry.
+func LinearBackoff(initial, increment time.Duration) Backoff {
+	return &linearBackoff{initial: initial, increment: increment}
+}
+
+// Delay implements the Backoff interface.
+func (b *linearBackoff) Delay(retry uint, _ time.Duration) time.Duration {
+	return b.initial + time.Duration(retry-1)*b.increment
+}
+
+// exponentialBackoff multiplies the delay by a factor before each retry, with optional jitter.
+type exponentialBackoff struct {
+	initial time.Duration
+	factor  float64
+	jitter  Jitter
+}
+
+// ExponentialBackoff returns a Backoff that applies the initial delay before the first retry,
+// and multiplies it by the factor before each successive retry, randomized according to the jitter.
+func ExponentialBackoff(initial time.Duration, factor float64, jitter Jitter) Backoff {
+	return &exponentialBackoff{initial: initial, factor: factor, jitter: jitter}
+}
+
+// Delay implements the Backoff interface.
+func (b *exponentialBackoff) Delay(retry uint, prev time.Duration) time.Duration {
+	if b.jitter == DecorrelatedJitter {
+		upper := max(b.initial, 3*prev)
+		return b.initial + randDuration(upper-b.initial)
+	}
+
+	d := durationFromFloat(float64(b.initial) * math.Pow(b.factor, float64(retry-1)))
+
+	switch b.jitter {
+	case FullJitter:
+		return randDuration(d)
+	case EqualJitter:
+		return d/2 + randDuration(d-d/2)
+	default:
+		return d
+	}
+}
+
+// fibonacciBackoff increases the delay following the Fibonacci sequence.
+type fibonacciBackoff struct {
+	initial time.Duration
+}
+
+// FibonacciBackoff returns a Backoff that applies the initial delay multiplied by
+// the Fibonacci sequence numbers (1, 1, 2, 3, 5, 8, ...) before each retry.
+func FibonacciBackoff(initial time.Duration) Backoff {
+	return &fibonacciBackoff{initial: initial}
+}
+
+// Delay implements the Backoff interface.
+func (b *fibonacciBackoff) Delay(retry uint, _ time.Duration) time.Duration {
+	var x, y float64 = 0, 1
+
+	for range retry {
+		x, y = y, x+y
+	}
+
+	return durationFromFloat(float64(b.initial) * x)
+}
+
+// randDuration returns a random duration in the [0, d) interval.
+func randDuration(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+
+	return rand.N(d) //nolint:gosec
+}
+
+// durationFromFloat converts a float number of nanoseconds to a duration,
+// saturating at the maximum duration value.
+func durationFromFloat(f float64) time.Duration {
+	if f >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+
+	return time.Duration(f)
+}
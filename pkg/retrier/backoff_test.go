@@ -0,0 +1,172 @@
+package retrier
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackoff_Delay(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		backoff Backoff
+		want    []time.Duration
+	}{
+		{
+			name:    "constant",
+			backoff: ConstantBackoff(time.Second),
+			want:    []time.Duration{time.Second, time.Second, time.Second, time.Second},
+		},
+		{
+			name:    "linear",
+			backoff: LinearBackoff(time.Second, 500*time.Millisecond),
+			want:    []time.Duration{time.Second, 1500 * time.Millisecond, 2 * time.Second, 2500 * time.Millisecond},
+		},
+		{
+			name:    "exponential",
+			backoff: ExponentialBackoff(time.Second, 2, NoJitter),
+			want:    []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second},
+		},
+		{
+			name:    "fibonacci",
+			backoff: FibonacciBackoff(time.Second),
+			want:    []time.Duration{time.Second, time.Second, 2 * time.Second, 3 * time.Second, 5 * time.Second, 8 * time.Second},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var prev time.Duration
+
+			for i, want := range tt.want {
+				prev = tt.backoff.Delay(uint(i+1), prev)
+				require.Equal(t, want, prev, "retry %d", i+1)
+			}
+		})
+	}
+}
+
+func TestExponentialBackoff_jitter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		jitter Jitter
+		min    []time.Duration
+		max    []time.Duration
+	}{
+		{
+			name:   "full jitter",
+			jitter: FullJitter,
+			min:    []time.Duration{0, 0, 0},
+			max:    []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond},
+		},
+		{
+			name:   "equal jitter",
+			jitter: EqualJitter,
+			min:    []time.Duration{50 * time.Millisecond, 100 * time.Millisecond, 200 * time.Millisecond},
+			max:    []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			b := ExponentialBackoff(100*time.Millisecond, 2, tt.jitter)
+
+			for range 100 {
+				for i := range tt.min {
+					d := b.Delay(uint(i+1), 0)
+					require.GreaterOrEqual(t, d, tt.min[i])
+					require.LessOrEqual(t, d, tt.max[i])
+				}
+			}
+		})
+	}
+}
+
+func TestExponentialBackoff_decorrelatedJitter(t *testing.T) {
+	t.Parallel()
+
+	initial := 100 * time.Millisecond
+	b := ExponentialBackoff(initial, 2, DecorrelatedJitter)
+
+	require.Equal(t, initial, b.Delay(1, 0))
+
+	var prev time.Duration
+
+	for i := range 100 {
+		d := b.Delay(uint(i+1), prev)
+		require.GreaterOrEqual(t, d, initial)
+		require.LessOrEqual(t, d, max(initial, 3*prev))
+
+		prev = d
+	}
+}
+
+func Test_durationFromFloat(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, time.Second, durationFromFloat(float64(time.Second)))
+	require.Equal(t, time.Duration(math.MaxInt64), durationFromFloat(math.Pow(2, 100)))
+	require.Equal(t, time.Duration(math.MaxInt64), ExponentialBackoff(time.Hour, 10, NoJitter).Delay(100, 0))
+}
+
+func TestRetrier_Run_backoff(t *testing.T) {
+	t.Parallel()
+
+	taskErr := errors.New("ERROR")
+
+	tests := []struct {
+		name          string
+		opts          []Option
+		wantDelays    []time.Duration
+		wantRemaining uint
+	}{
+		{
+			name:          "linear backoff",
+			opts:          []Option{WithBackoff(LinearBackoff(time.Second, time.Second))},
+			wantDelays:    []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 4 * time.Second},
+			wantRemaining: 0,
+		},
+		{
+			name:          "max delay",
+			opts:          []Option{WithBackoff(ExponentialBackoff(time.Second, 3, NoJitter)), WithMaxDelay(5 * time.Second)},
+			wantDelays:    []time.Duration{time.Second, 3 * time.Second, 5 * time.Second, 5 * time.Second},
+			wantRemaining: 0,
+		},
+		{
+			name:          "max elapsed time",
+			opts:          []Option{WithBackoff(ConstantBackoff(4 * time.Second)), WithMaxElapsedTime(10 * time.Second)},
+			wantDelays:    []time.Duration{4 * time.Second, 4 * time.Second},
+			wantRemaining: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			clock := &testClock{now: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}
+
+			opts := append([]Option{WithAttempts(5), WithClock(clock)}, tt.opts...)
+
+			r, err := New(opts...)
+			require.NoError(t, err)
+
+			err = r.Run(context.Background(), func(_ context.Context) error { return taskErr })
+			require.ErrorIs(t, err, taskErr)
+			require.Equal(t, tt.wantDelays, clock.delays)
+			require.Equal(t, tt.wantRemaining, r.remainingAttempts)
+		})
+	}
+}
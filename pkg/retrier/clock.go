@@ -0,0 +1,28 @@
+package retrier
+
+import (
+	"time"
+)
+
+// Clock is the interface used to get the current time and wait between the attempts.
+// A deterministic implementation can be injected in tests (see WithClock).
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// After waits for the duration to elapse and then sends the current time on the returned channel.
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock is the default Clock based on the time package.
+type SystemClock struct{}
+
+// Now returns the current local time.
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// After waits for the duration to elapse and then sends the current time on the returned channel.
+func (SystemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
@@ -0,0 +1,47 @@
+package retrier
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testClock is a deterministic Clock that advances the time instantly on After.
+type testClock struct {
+	mux    sync.Mutex
+	now    time.Time
+	delays []time.Duration
+}
+
+func (c *testClock) Now() time.Time {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	return c.now
+}
+
+func (c *testClock) After(d time.Duration) <-chan time.Time {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	c.now = c.now.Add(d)
+	c.delays = append(c.delays, d)
+
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+
+	return ch
+}
+
+func TestSystemClock(t *testing.T) {
+	t.Parallel()
+
+	c := SystemClock{}
+
+	start := c.Now()
+	<-c.After(time.Millisecond)
+
+	require.GreaterOrEqual(t, time.Since(start), time.Millisecond)
+}
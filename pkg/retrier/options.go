@@ -88,3 +88,58 @@ func WithTimeout(timeout time.Duration) Option {
 		return nil
 	}
 }
+
+// WithBackoff sets the strategy used to compute the delay before each retry.
+// It overrides the WithDelay, WithDelayFactor and WithJitter options.
+func WithBackoff(backoff Backoff) Option {
+	return func(r *Retrier) error {
+		if backoff == nil {
+			return errors.New("the backoff is required")
+		}
+
+		r.backoff = backoff
+
+		return nil
+	}
+}
+
+// WithMaxDelay sets the maximum delay between retries.
+func WithMaxDelay(maxDelay time.Duration) Option {
+	return func(r *Retrier) error {
+		if int64(maxDelay) < 1 {
+			return errors.New("the maximum delay must be greater than zero")
+		}
+
+		r.maxDelay = maxDelay
+
+		return nil
+	}
+}
+
+// WithMaxElapsedTime sets the maximum total time spent retrying.
+// No more retries are attempted if the next delay would exceed this time since the first attempt.
+func WithMaxElapsedTime(maxElapsedTime time.Duration) Option {
+	return func(r *Retrier) error {
+		if int64(maxElapsedTime) < 1 {
+			return errors.New("the maximum elapsed time must be greater than zero")
+		}
+
+		r.maxElapsedTime = maxElapsedTime
+
+		return nil
+	}
+}
+
+// WithClock sets the clock used to measure the elapsed time and wait between the attempts.
+// This is mainly useful to inject a deterministic clock in tests.
+func WithClock(clock Clock) Option {
+	return func(r *Retrier) error {
+		if clock == nil {
+			return errors.New("the clock is required")
+		}
+
+		r.clock = clock
+
+		return nil
+	}
+}
@@ -105,3 +105,57 @@ func TestWithTimeout(t *testing.T) {
 	err = WithTimeout(v)(r)
 	require.Error(t, err)
 }
+
+func TestWithBackoff(t *testing.T) {
+	t.Parallel()
+
+	r := defaultRetrier()
+
+	v := ConstantBackoff(time.Second)
+	err := WithBackoff(v)(r)
+	require.NoError(t, err)
+	require.Equal(t, v, r.backoff)
+
+	err = WithBackoff(nil)(r)
+	require.Error(t, err)
+}
+
+func TestWithMaxDelay(t *testing.T) {
+	t.Parallel()
+
+	r := defaultRetrier()
+
+	err := WithMaxDelay(3 * time.Second)(r)
+	require.NoError(t, err)
+	require.Equal(t, 3*time.Second, r.maxDelay)
+
+	err = WithMaxDelay(0)(r)
+	require.Error(t, err)
+}
+
+func TestWithMaxElapsedTime(t *testing.T) {
+	t.Parallel()
+
+	r := defaultRetrier()
+
+	err := WithMaxElapsedTime(time.Minute)(r)
+	require.NoError(t, err)
+	require.Equal(t, time.Minute, r.maxElapsedTime)
+
+	err = WithMaxElapsedTime(0)(r)
+	require.Error(t, err)
+}
+
+func TestWithClock(t *testing.T) {
+	t.Parallel()
+
+	r := defaultRetrier()
+
+	v := &testClock{}
+	err := WithClock(v)(r)
+	require.NoError(t, err)
+	require.Equal(t, v, r.clock)
+
+	err = WithClock(nil)(r)
+	require.Error(t, err)
+}
@@ -9,6 +9,13 @@ most cases. Additionally, it allows to set the maximum number of retries, the
 delay after the first failed attempt, the time multiplication factor to
 determine the successive delay value, and the jitter used to introduce
 randomness and avoid request collisions.
+
+Alternatively, the delays can be computed by a Backoff strategy (WithBackoff):
+ConstantBackoff, LinearBackoff, ExponentialBackoff (with NoJitter, FullJitter,
+EqualJitter or DecorrelatedJitter) and FibonacciBackoff. The delays can be
+capped (WithMaxDelay), and the retries can be limited by the total elapsed time
+(WithMaxElapsedTime). The Clock can be replaced with a deterministic one in
+tests (WithClock).
 */
 package retrier
 
@@ -51,9 +58,11 @@ type Retrier struct {
 	delay             time.Duration
 	jitter            time.Duration
 	timeout           time.Duration
+	maxDelay          time.Duration
+	maxElapsedTime    time.Duration
+	backoff           Backoff
+	clock             Clock
 	retryIfFn         RetryIfFn
-	timer             *time.Timer
-	resetTimer        chan time.Duration
 	taskError         error
 }
 
@@ -66,7 +75,7 @@ func defaultRetrier() *Retrier {
 		jitter:      DefaultJitter,
 		timeout:     DefaultTimeout,
 		retryIfFn:   DefaultRetryIf,
-		resetTimer:  make(chan time.Duration, 1),
+		clock:       SystemClock{},
 	}
 }
 
@@ -94,40 +103,52 @@ func (r *Retrier) Run(ctx context.Context, task TaskFn) error {
 	r.nextDelay = float64(r.delay)
 	r.remainingAttempts = r.attempts
 
-	rctx, cancel := context.WithCancel(ctx)
-	defer cancel()
+	start := r.clock.Now()
+
+	var delay time.Duration
+
+	for retry := uint(1); ; retry++ {
+		if ctx.Err() != nil {
+			return fmt.Errorf("main context has been canceled: %w", ctx.Err())
+		}
+
+		if r.exec(ctx, task) {
+			return r.taskError
+		}
+
+		delay = r.nextRetryDelay(retry, delay)
 
-	r.timer = time.NewTimer(1 * time.Nanosecond)
+		if r.maxElapsedTime > 0 && r.clock.Now().Sub(start)+delay > r.maxElapsedTime {
+			return r.taskError
+		}
 
-	for {
 		select {
-		case <-rctx.Done():
-			return fmt.Errorf("main context has been canceled: %w", rctx.Err())
-		case d := <-r.resetTimer:
-			r.setTimer(d)
-		case <-r.timer.C:
-			if r.exec(rctx, task) {
-				return r.taskError
-			}
+		case <-ctx.Done():
+			return fmt.Errorf("main context has been canceled: %w", ctx.Err())
+		case <-r.clock.After(delay):
 		}
 	}
 }
 
-// setTimer sets the timer for the Retrier with the given duration.
-// If the timer is already running, it is stopped and the timer channel is drained before resetting.
-func (r *Retrier) setTimer(d time.Duration) {
-	if !r.timer.Stop() {
-		// make sure to drain timer channel before reset
-		select {
-		case <-r.timer.C:
-		default:
-		}
+// nextRetryDelay returns the delay before the specified retry, capped to the maximum delay.
+func (r *Retrier) nextRetryDelay(retry uint, prev time.Duration) time.Duration {
+	var d time.Duration
+
+	if r.backoff != nil {
+		d = r.backoff.Delay(retry, prev)
+	} else {
+		d = time.Duration(int64(r.nextDelay) + rand.Int63n(int64(r.jitter))) //nolint:gosec
+		r.nextDelay *= r.delayFactor
+	}
+
+	if r.maxDelay > 0 {
+		d = min(d, r.maxDelay)
 	}
 
-	r.timer.Reset(d)
+	return d
 }
 
-// exec executes the given task function with a timeout and handles retries if necessary.
+// exec executes the given task function with a timeout.
 // It returns true if the task should not be retried or if the maximum number of attempts has been reached.
 // Otherwise, it returns false to indicate that the task should be retried.
 func (r *Retrier) exec(ctx context.Context, task TaskFn) bool {
@@ -137,13 +158,6 @@ func (r *Retrier) exec(ctx context.Context, task TaskFn) bool {
 	cancel()
 
 	r.remainingAttempts--
-	if r.remainingAttempts == 0 || !r.retryIfFn(r.taskError) {
-		return true
-	}
-
-	r.resetTimer <- time.Duration(int64(r.nextDelay) + rand.Int63n(int64(r.jitter))) //nolint:gosec
-
-	r.nextDelay *= r.delayFactor
 
-	return false
+	return r.remainingAttempts == 0 || !r.retryIfFn(r.taskError)
 }
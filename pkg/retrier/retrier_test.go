@@ -164,16 +164,3 @@ func TestDefaultRetryIf(t *testing.T) {
 		})
 	}
 }
-
-func TestRetrier_setTimer(t *testing.T) {
-	t.Parallel()
-
-	r := &Retrier{
-		timer: time.NewTimer(1 * time.Millisecond),
-	}
-
-	time.Sleep(2 * time.Millisecond)
-	r.setTimer(2 * time.Millisecond)
-
-	<-r.timer.C
-}
@@ -0,0 +1,63 @@
+package testutil
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+
+	"github.com/quic-go/quic-go/http3"
+)
+
+// HTTPProtocol identifies the protocol used by the HTTP test client.
+type HTTPProtocol int
+
+const (
+	// HTTP1 is the HTTP/1.1 protocol (with or without TLS).
+	HTTP1 HTTPProtocol = iota
+
+	// HTTP2 is the HTTP/2 protocol over TLS.
+	HTTP2
+
+	// H2C is the unencrypted HTTP/2 protocol with prior knowledge.
+	H2C
+
+	// HTTP3 is the HTTP/3 protocol over QUIC.
+	HTTP3
+)
+
+// NewHTTPClient returns an HTTP client forced to use the specified protocol.
+// The client skips the TLS certificate verification and it is intended to
+// exercise local test servers only.
+func NewHTTPClient(proto HTTPProtocol, timeout time.Duration) *http.Client {
+	//nolint:gosec // test servers use self-signed certificates
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: true,
+	}
+
+	if proto == HTTP3 {
+		return &http.Client{
+			Timeout:   timeout,
+			Transport: &http3.Transport{TLSClientConfig: tlsConfig},
+		}
+	}
+
+	p := &http.Protocols{}
+
+	switch proto {
+	case HTTP2:
+		p.SetHTTP2(true)
+	case H2C:
+		p.SetUnencryptedHTTP2(true)
+	default:
+		p.SetHTTP1(true)
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Protocols:       p,
+			TLSClientConfig: tlsConfig,
+		},
+	}
+}
@@ -0,0 +1,81 @@
+package testutil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go/http3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHTTPClient(t *testing.T) {
+	t.Parallel()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Proto))
+	})
+
+	tlsServer := httptest.NewUnstartedServer(handler)
+	tlsServer.EnableHTTP2 = true
+	tlsServer.StartTLS()
+	t.Cleanup(tlsServer.Close)
+
+	h2cServer := httptest.NewUnstartedServer(handler)
+	h2cServer.Config.Protocols = &http.Protocols{}
+	h2cServer.Config.Protocols.SetUnencryptedHTTP2(true)
+	h2cServer.Start()
+	t.Cleanup(h2cServer.Close)
+
+	tests := []struct {
+		name      string
+		proto     HTTPProtocol
+		url       string
+		wantProto string
+	}{
+		{
+			name:      "HTTP/1.1",
+			proto:     HTTP1,
+			url:       tlsServer.URL,
+			wantProto: "HTTP/1.1",
+		},
+		{
+			name:      "HTTP/2",
+			proto:     HTTP2,
+			url:       tlsServer.URL,
+			wantProto: "HTTP/2.0",
+		},
+		{
+			name:      "h2c",
+			proto:     H2C,
+			url:       h2cServer.URL,
+			wantProto: "HTTP/2.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := NewHTTPClient(tt.proto, 5*time.Second)
+
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, tt.url, nil)
+			require.NoError(t, err)
+
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+
+			defer func() { _ = resp.Body.Close() }()
+
+			require.Equal(t, tt.wantProto, resp.Proto)
+		})
+	}
+}
+
+func TestNewHTTPClient_HTTP3(t *testing.T) {
+	t.Parallel()
+
+	client := NewHTTPClient(HTTP3, time.Second)
+	require.IsType(t, &http3.Transport{}, client.Transport)
+}
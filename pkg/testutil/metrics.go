@@ -0,0 +1,36 @@
+package testutil
+
+import (
+	"sync"
+
+	"github.com/Vonage/gosrvlib/pkg/metrics"
+)
+
+// EventMetrics is a metrics client counting the events by outcome.
+// It can be used to test the components calling metrics.IncEventCounter.
+type EventMetrics struct {
+	metrics.Default
+
+	mu     sync.Mutex
+	events map[string]int
+}
+
+// IncEventCounter increments the number of events with the specified outcome.
+func (m *EventMetrics) IncEventCounter(_, _, outcome string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.events == nil {
+		m.events = make(map[string]int)
+	}
+
+	m.events[outcome]++
+}
+
+// Count returns the number of events with the specified outcome.
+func (m *EventMetrics) Count(outcome string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.events[outcome]
+}
@@ -0,0 +1,23 @@
+package testutil
+
+import (
+	"testing"
+
+	"github.com/Vonage/gosrvlib/pkg/metrics"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventMetrics(t *testing.T) {
+	t.Parallel()
+
+	m := &EventMetrics{}
+
+	require.Equal(t, 0, m.Count("success"))
+
+	metrics.IncEventCounter(m, "task", "operation", "success")
+	metrics.IncEventCounter(m, "task", "operation", "success")
+	metrics.IncEventCounter(m, "task", "operation", "error")
+
+	require.Equal(t, 2, m.Count("success"))
+	require.Equal(t, 1, m.Count("error"))
+}
@@ -0,0 +1,35 @@
+package testutil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTLSCertData(t *testing.T) {
+	t.Parallel()
+
+	certPEM, keyPEM, err := TLSCertData()
+	require.NoError(t, err)
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+	require.Equal(t, []string{"localhost"}, leaf.DNSNames)
+	require.Len(t, leaf.IPAddresses, 1)
+	require.NoError(t, leaf.VerifyHostname("127.0.0.1"))
+
+	certPEM, keyPEM, err = TLSCertData("example.com")
+	require.NoError(t, err)
+
+	cert, err = tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+
+	leaf, err = x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+	require.NoError(t, leaf.VerifyHostname("example.com"))
+}
@@ -3,12 +3,33 @@ package valkey
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Vonage/gosrvlib/pkg/encode"
 	libvalkey "github.com/valkey-io/valkey-go"
 )
 
+// scanCount is the number of keys requested for each SCAN iteration.
+const scanCount = 100
+
+// setIfEqualScript atomically sets the key (with expiration in milliseconds) only if its current value matches.
+const setIfEqualScript = `if redis.call('GET', KEYS[1]) == ARGV[1] then
+  redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+  return 1
+end
+return 0`
+
+// delIfEqualScript atomically deletes the key only if its current value matches.
+const delIfEqualScript = `if redis.call('GET', KEYS[1]) == ARGV[1] then
+  return redis.call('DEL', KEYS[1])
+end
+return 0`
+
+// globEscaper escapes the special characters of the SCAN MATCH glob-style patterns.
+var globEscaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`) //nolint:gochecknoglobals
+
 // TEncodeFunc is the type of function used to replace the default message encoding function used by SendData().
 type TEncodeFunc func(ctx context.Context, data any) (string, error)
 
@@ -85,6 +106,21 @@ func (c *Client) Set(ctx context.Context, key string, value string, exp time.Dur
 	return nil
 }
 
+// SetNX sets a raw string value for the specified key with an expiration time, only if the key does not exist.
+// It returns true if the key was set.
+func (c *Client) SetNX(ctx context.Context, key string, value string, exp time.Duration) (bool, error) {
+	err := c.vkclient.Do(ctx, c.vkclient.B().Set().Key(key).Value(value).Nx().Ex(exp).Build()).Error()
+	if libvalkey.IsValkeyNil(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("cannot set key: %s %w", key, err)
+	}
+
+	return true, nil
+}
+
 // Get retrieves the raw string value of the specified key.
 func (c *Client) Get(ctx context.Context, key string) (string, error) {
 	value, err := c.vkclient.Do(ctx, c.vkclient.B().Get().Key(key).Build()).ToString()
@@ -105,6 +141,63 @@ func (c *Client) Del(ctx context.Context, key string) error {
 	return nil
 }
 
+// SetIfEqual sets a raw string value for the specified key with an expiration time,
+// only if the current value of the key is equal to oldValue.
+// It returns true if the key was set.
+func (c *Client) SetIfEqual(ctx context.Context, key string, oldValue string, value string, exp time.Duration) (bool, error) {
+	cmd := c.vkclient.B().Eval().Script(setIfEqualScript).Numkeys(1).Key(key).
+		Arg(oldValue, value, strconv.FormatInt(exp.Milliseconds(), 10)).Build()
+
+	n, err := c.vkclient.Do(ctx, cmd).AsInt64()
+	if err != nil {
+		return false, fmt.Errorf("cannot set key: %s %w", key, err)
+	}
+
+	return n == 1, nil
+}
+
+// DelIfEqual deletes the specified key only if its current value is equal to the specified value.
+// It returns true if the key was deleted.
+func (c *Client) DelIfEqual(ctx context.Context, key string, value string) (bool, error) {
+	cmd := c.vkclient.B().Eval().Script(delIfEqualScript).Numkeys(1).Key(key).Arg(value).Build()
+
+	n, err := c.vkclient.Do(ctx, cmd).AsInt64()
+	if err != nil {
+		return false, fmt.Errorf("cannot delete key: %s %w", key, err)
+	}
+
+	return n == 1, nil
+}
+
+// DelPrefix deletes all the keys starting with the specified prefix from the datastore.
+// The keys are iterated with SCAN, so the operation is not atomic, its cost grows with
+// the total number of keys in the datastore, and it is not supported with Valkey Cluster.
+func (c *Client) DelPrefix(ctx context.Context, prefix string) error {
+	match := globEscaper.Replace(prefix) + "*"
+
+	var cursor uint64
+
+	for {
+		entry, err := c.vkclient.Do(ctx, c.vkclient.B().Scan().Cursor(cursor).Match(match).Count(scanCount).Build()).AsScanEntry()
+		if err != nil {
+			return fmt.Errorf("cannot scan keys with prefix: %s %w", prefix, err)
+		}
+
+		if len(entry.Elements) > 0 {
+			err = c.vkclient.Do(ctx, c.vkclient.B().Del().Key(entry.Elements...).Build()).Error()
+			if err != nil {
+				return fmt.Errorf("cannot delete keys with prefix: %s %w", prefix, err)
+			}
+		}
+
+		if entry.Cursor == 0 {
+			return nil
+		}
+
+		cursor = entry.Cursor
+	}
+}
+
 // Send publish a raw string value to the specified channel.
 func (c *Client) Send(ctx context.Context, channel string, message string) error {
 	err := c.vkclient.Do(ctx, c.vkclient.B().Publish().Channel(channel).Message(message).Build()).Error()
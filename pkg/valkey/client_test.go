@@ -276,6 +276,365 @@ func TestDel(t *testing.T) {
 	}
 }
 
+func TestSetNX(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		key     string
+		mock    func(ctx context.Context, vkc *mock.Client)
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "set",
+			key:  "key1",
+			mock: func(ctx context.Context, vkc *mock.Client) {
+				vkc.EXPECT().Do(
+					ctx,
+					mock.Match("SET", "key1", "val", "NX", "EX", "1"),
+				).Return(mock.Result(mock.ValkeyString("OK")))
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "exists",
+			key:  "key2",
+			mock: func(ctx context.Context, vkc *mock.Client) {
+				vkc.EXPECT().Do(
+					ctx,
+					mock.Match("SET", "key2", "val", "NX", "EX", "1"),
+				).Return(mock.Result(mock.ValkeyNil()))
+			},
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name: "error",
+			key:  "key3",
+			mock: func(ctx context.Context, vkc *mock.Client) {
+				vkc.EXPECT().Do(
+					ctx,
+					mock.Match("SET", "key3", "val", "NX", "EX", "1"),
+				).Return(mock.ErrorResult(errors.New("error")))
+			},
+			want:    false,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			srvOpts := getTestSrvOptions()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(func() { ctrl.Finish() })
+
+			vkc := mock.NewClient(ctrl)
+			ctx := t.Context()
+
+			cli, err := New(
+				ctx,
+				srvOpts,
+				WithValkeyClient(vkc),
+			)
+
+			require.NoError(t, err)
+			require.NotNil(t, cli)
+
+			tt.mock(ctx, vkc)
+
+			got, err := cli.SetNX(ctx, tt.key, "val", time.Second)
+			require.Equal(t, tt.want, got)
+
+			if tt.wantErr {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestSetIfEqual(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		key     string
+		mock    func(ctx context.Context, vkc *mock.Client)
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "set",
+			key:  "key1",
+			mock: func(ctx context.Context, vkc *mock.Client) {
+				vkc.EXPECT().Do(
+					ctx,
+					mock.Match("EVAL", setIfEqualScript, "1", "key1", "old", "val", "1000"),
+				).Return(mock.Result(mock.ValkeyInt64(1)))
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "different",
+			key:  "key2",
+			mock: func(ctx context.Context, vkc *mock.Client) {
+				vkc.EXPECT().Do(
+					ctx,
+					mock.Match("EVAL", setIfEqualScript, "1", "key2", "old", "val", "1000"),
+				).Return(mock.Result(mock.ValkeyInt64(0)))
+			},
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name: "error",
+			key:  "key3",
+			mock: func(ctx context.Context, vkc *mock.Client) {
+				vkc.EXPECT().Do(
+					ctx,
+					mock.Match("EVAL", setIfEqualScript, "1", "key3", "old", "val", "1000"),
+				).Return(mock.ErrorResult(errors.New("error")))
+			},
+			want:    false,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			srvOpts := getTestSrvOptions()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(func() { ctrl.Finish() })
+
+			vkc := mock.NewClient(ctrl)
+			ctx := t.Context()
+
+			cli, err := New(
+				ctx,
+				srvOpts,
+				WithValkeyClient(vkc),
+			)
+
+			require.NoError(t, err)
+			require.NotNil(t, cli)
+
+			tt.mock(ctx, vkc)
+
+			got, err := cli.SetIfEqual(ctx, tt.key, "old", "val", time.Second)
+			require.Equal(t, tt.want, got)
+
+			if tt.wantErr {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestDelIfEqual(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		key     string
+		mock    func(ctx context.Context, vkc *mock.Client)
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "deleted",
+			key:  "key1",
+			mock: func(ctx context.Context, vkc *mock.Client) {
+				vkc.EXPECT().Do(
+					ctx,
+					mock.Match("EVAL", delIfEqualScript, "1", "key1", "val"),
+				).Return(mock.Result(mock.ValkeyInt64(1)))
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "different",
+			key:  "key2",
+			mock: func(ctx context.Context, vkc *mock.Client) {
+				vkc.EXPECT().Do(
+					ctx,
+					mock.Match("EVAL", delIfEqualScript, "1", "key2", "val"),
+				).Return(mock.Result(mock.ValkeyInt64(0)))
+			},
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name: "error",
+			key:  "key3",
+			mock: func(ctx context.Context, vkc *mock.Client) {
+				vkc.EXPECT().Do(
+					ctx,
+					mock.Match("EVAL", delIfEqualScript, "1", "key3", "val"),
+				).Return(mock.ErrorResult(errors.New("error")))
+			},
+			want:    false,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			srvOpts := getTestSrvOptions()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(func() { ctrl.Finish() })
+
+			vkc := mock.NewClient(ctrl)
+			ctx := t.Context()
+
+			cli, err := New(
+				ctx,
+				srvOpts,
+				WithValkeyClient(vkc),
+			)
+
+			require.NoError(t, err)
+			require.NotNil(t, cli)
+
+			tt.mock(ctx, vkc)
+
+			got, err := cli.DelIfEqual(ctx, tt.key, "val")
+			require.Equal(t, tt.want, got)
+
+			if tt.wantErr {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestDelPrefix(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		prefix  string
+		mock    func(ctx context.Context, vkc *mock.Client)
+		wantErr bool
+	}{
+		{
+			name:   "success",
+			prefix: "key*_",
+			mock: func(ctx context.Context, vkc *mock.Client) {
+				gomock.InOrder(
+					vkc.EXPECT().Do(
+						ctx,
+						mock.Match("SCAN", "0", "MATCH", `key\*_*`, "COUNT", "100"),
+					).Return(mock.Result(mock.ValkeyArray(
+						mock.ValkeyString("7"),
+						mock.ValkeyArray(mock.ValkeyString("key*_1"), mock.ValkeyString("key*_2")),
+					))),
+					vkc.EXPECT().Do(
+						ctx,
+						mock.Match("DEL", "key*_1", "key*_2"),
+					),
+					vkc.EXPECT().Do(
+						ctx,
+						mock.Match("SCAN", "7", "MATCH", `key\*_*`, "COUNT", "100"),
+					).Return(mock.Result(mock.ValkeyArray(
+						mock.ValkeyString("0"),
+						mock.ValkeyArray(),
+					))),
+				)
+			},
+			wantErr: false,
+		},
+		{
+			name:   "scan error",
+			prefix: "key",
+			mock: func(ctx context.Context, vkc *mock.Client) {
+				vkc.EXPECT().Do(
+					ctx,
+					mock.Match("SCAN", "0", "MATCH", "key*", "COUNT", "100"),
+				).Return(mock.ErrorResult(errors.New("error")))
+			},
+			wantErr: true,
+		},
+		{
+			name:   "delete error",
+			prefix: "key",
+			mock: func(ctx context.Context, vkc *mock.Client) {
+				gomock.InOrder(
+					vkc.EXPECT().Do(
+						ctx,
+						mock.Match("SCAN", "0", "MATCH", "key*", "COUNT", "100"),
+					).Return(mock.Result(mock.ValkeyArray(
+						mock.ValkeyString("0"),
+						mock.ValkeyArray(mock.ValkeyString("key1")),
+					))),
+					vkc.EXPECT().Do(
+						ctx,
+						mock.Match("DEL", "key1"),
+					).Return(mock.ErrorResult(errors.New("error"))),
+				)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			srvOpts := getTestSrvOptions()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(func() { ctrl.Finish() })
+
+			vkc := mock.NewClient(ctrl)
+			ctx := t.Context()
+
+			cli, err := New(
+				ctx,
+				srvOpts,
+				WithValkeyClient(vkc),
+			)
+
+			require.NoError(t, err)
+			require.NotNil(t, cli)
+
+			tt.mock(ctx, vkc)
+
+			err = cli.DelPrefix(ctx, tt.prefix)
+			if tt.wantErr {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
 func TestSend(t *testing.T) {
 	t.Parallel()
 